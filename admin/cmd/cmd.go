@@ -23,6 +23,10 @@ const (
 	Restart            Code = "restart"
 	Stop               Code = "stop"
 	Monitor            Code = "monitor"
+	Heartbeat          Code = "heartbeat"
+	PluginFetch        Code = "plugin-fetch"
+	PluginPackage      Code = "plugin-package"
+	PluginStatus       Code = "plugin-status"
 	EventClientLeave   Code = "leave"
 	Error              Code = "error"
 )
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"encoding/json"
+
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//EncodeHeartbeat encode heartbeat
+func EncodeHeartbeat(h *entity.NodeHeartbeat) ([]byte, error) {
+	return json.Marshal(h)
+}
+
+//DecodeHeartbeat decode heartbeat
+func DecodeHeartbeat(data []byte) (*entity.NodeHeartbeat, error) {
+	h := new(entity.NodeHeartbeat)
+	err := json.Unmarshal(data, h)
+	if err != nil {
+		return nil, err
+	}
+	return h, nil
+}
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"encoding/json"
+
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//EncodeMonitorReport encode monitor report
+func EncodeMonitorReport(r *entity.MonitorReport) ([]byte, error) {
+	return json.Marshal(r)
+}
+
+//DecodeMonitorReport decode monitor report
+func DecodeMonitorReport(data []byte) (*entity.MonitorReport, error) {
+	r := new(entity.MonitorReport)
+	err := json.Unmarshal(data, r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+var (
+	ErrorInvalidPluginName = errors.New("invalid plugin name")
+)
+
+//EncodePluginFetch encode plugin fetch
+func EncodePluginFetch(name string) ([]byte, error) {
+	if name == "" {
+		return nil, ErrorInvalidPluginName
+	}
+	return []byte(name), nil
+}
+
+//DecodePluginFetch decode plugin fetch
+func DecodePluginFetch(data []byte) (string, error) {
+	if len(data) == 0 {
+		return "", ErrorInvalidPluginName
+	}
+	return string(data), nil
+}
+
+//EncodePluginPackage encode plugin package
+func EncodePluginPackage(p *entity.PluginPackage) ([]byte, error) {
+	return json.Marshal(p)
+}
+
+//DecodePluginPackage decode plugin package
+func DecodePluginPackage(data []byte) (*entity.PluginPackage, error) {
+	p := new(entity.PluginPackage)
+	err := json.Unmarshal(data, p)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+//EncodePluginStatus encode plugin status
+func EncodePluginStatus(s []*entity.NodePluginStatus) ([]byte, error) {
+	return json.Marshal(s)
+}
+
+//DecodePluginStatus decode plugin status
+func DecodePluginStatus(data []byte) ([]*entity.NodePluginStatus, error) {
+	s := make([]*entity.NodePluginStatus, 0)
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
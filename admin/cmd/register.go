@@ -9,6 +9,8 @@ type RegisterResult struct {
 	Code   int
 	Error  string
 	Config *config.GokuConfig
+	//Plugins 控制台分发的插件包清单
+	Plugins map[string]*config.PluginPackage `json:",omitempty"`
 }
 
 func DecodeRegisterResult(data []byte) (*RegisterResult, error) {
@@ -19,11 +21,12 @@ func DecodeRegisterResult(data []byte) (*RegisterResult, error) {
 	}
 	return r, nil
 }
-func EncodeRegisterResultConfig(c *config.GokuConfig) ([]byte, error) {
+func EncodeRegisterResultConfig(c *config.GokuConfig, plugins map[string]*config.PluginPackage) ([]byte, error) {
 	r := RegisterResult{
-		Code:   0,
-		Error:  "",
-		Config: c,
+		Code:    0,
+		Error:   "",
+		Config:  c,
+		Plugins: plugins,
 	}
 	return json.Marshal(r)
 }
@@ -50,11 +50,34 @@ func (m *ClientManager) Get(instance string) (*Client, bool) {
 	m.locker.RUnlock()
 	return c, has
 }
+
+//Clients 当前在线的节点连接
+func (m *ClientManager) Clients() []*Client {
+	m.locker.RLock()
+	defer m.locker.RUnlock()
+	clients := make([]*Client, 0, len(m.clients))
+	for _, c := range m.clients {
+		clients = append(clients, c)
+	}
+	return clients
+}
 func (m *ClientManager) Remove(instance string) {
 	m.locker.Lock()
 	delete(m.clients, instance)
 	m.locker.Unlock()
 }
+
+//RemoveClient 仅当instance当前对应的连接为client时移除，返回是否移除
+func (m *ClientManager) RemoveClient(client *Client) bool {
+	m.locker.Lock()
+	defer m.locker.Unlock()
+	c, has := m.clients[client.instance]
+	if !has || c != client {
+		return false
+	}
+	delete(m.clients, client.instance)
+	return true
+}
 func InterceptNodeRegister(f func(client *Client) error) {
 	clientManager.intercept.Add(func(v interface{}) error {
 		c := v.(*Client)
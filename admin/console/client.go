@@ -48,3 +48,12 @@ func (c *Client) SendRunCMD(operate string) error {
 	return nil
 
 }
+
+//SendPluginPackage 发送插件包
+func (c *Client) SendPluginPackage(pkg *entity.PluginPackage) error {
+	data, err := cmd.EncodePluginPackage(pkg)
+	if err != nil {
+		return err
+	}
+	return c.Send(cmd.PluginPackage, data)
+}
@@ -95,13 +95,18 @@ func startClient(conn net.Conn) {
 
 	client := NewClient(conn, instance)
 	defer func() {
-		node.UnLock(instance)
-		NodeLeave(client)
+		// 重启时控制台已提前释放该instance，新进程可能已经注册，此时不能再释放
+		if clientManager.RemoveClient(client) {
+			node.UnLock(instance)
+		}
 		_ = client.Close()
 	}()
 
 	e := NodeRegister(client)
 	if e != nil {
+		if _, has := clientManager.Get(instance); !has {
+			node.UnLock(instance)
+		}
 		data, err := cmd.EncodeRegisterResultError(e.Error())
 		if err == nil {
 			cmd.SendFrame(conn, cmd.NodeRegisterResult, data)
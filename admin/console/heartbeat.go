@@ -0,0 +1,20 @@
+package console
+
+import (
+	"github.com/eolinker/goku-api-gateway/admin/cmd"
+	"github.com/eolinker/goku-api-gateway/console/module/node"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+)
+
+//OnHeartbeat 处理节点上报的心跳
+func OnHeartbeat(code cmd.Code, data []byte, client *Client) error {
+	heartbeat, err := cmd.DecodeHeartbeat(data)
+	if err != nil {
+		return err
+	}
+	err = node.Heartbeat(client.Instance(), heartbeat)
+	if err != nil {
+		log.Warn("save heartbeat of ", client.Instance(), ":", err)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package console
+
+import (
+	"github.com/eolinker/goku-api-gateway/admin/cmd"
+	"github.com/eolinker/goku-api-gateway/console/module/alert"
+)
+
+//OnMonitor 处理节点上报的请求统计
+func OnMonitor(code cmd.Code, data []byte, client *Client) error {
+	report, err := cmd.DecodeMonitorReport(data)
+	if err != nil {
+		return err
+	}
+	alert.Report(report)
+	return nil
+}
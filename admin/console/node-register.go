@@ -6,6 +6,7 @@ import (
 	"github.com/eolinker/goku-api-gateway/admin/cmd"
 	"github.com/eolinker/goku-api-gateway/config"
 	"github.com/eolinker/goku-api-gateway/console/module/node"
+	"github.com/eolinker/goku-api-gateway/console/module/plugin"
 	"github.com/eolinker/goku-api-gateway/console/module/versionConfig"
 	log "github.com/eolinker/goku-api-gateway/goku-log"
 	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
@@ -33,7 +34,11 @@ func NodeRegister(client *Client) error {
 		return err
 	}
 	nodeConf := toNodeConfig(result, nodeInfo)
-	data, _ := cmd.EncodeRegisterResultConfig(nodeConf)
+	plugins, err := plugin.GetPluginManifest()
+	if err != nil {
+		log.Warn("get plugin manifest:", err)
+	}
+	data, _ := cmd.EncodeRegisterResultConfig(nodeConf, plugins)
 
 	return client.Send(cmd.NodeRegisterResult, data)
 }
@@ -80,9 +85,8 @@ func StopNode(nodeKey string) {
 
 	client, has := clientManager.Get(nodeKey)
 	if has {
+		// 节点处理完请求后断开连接，由连接的退出流程释放instance
 		_ = client.SendRunCMD("stop")
-		NodeLeave(client)
-
 	}
 }
 
@@ -90,8 +94,10 @@ func RestartNode(nodeKey string) {
 
 	client, has := clientManager.Get(nodeKey)
 	if has {
-		NodeLeave(client)
-		node.UnLock(nodeKey)
+		// 提前释放instance，使fork出的新进程可以立即注册
+		if clientManager.RemoveClient(client) {
+			node.UnLock(nodeKey)
+		}
 		_ = client.SendRunCMD("restart")
 	}
 }
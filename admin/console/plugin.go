@@ -0,0 +1,48 @@
+package console
+
+import (
+	"github.com/eolinker/goku-api-gateway/admin/cmd"
+	"github.com/eolinker/goku-api-gateway/console/module/plugin"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//OnPluginFetch 返回节点拉取的插件包
+func OnPluginFetch(code cmd.Code, data []byte, client *Client) error {
+	name, err := cmd.DecodePluginFetch(data)
+	if err != nil {
+		return err
+	}
+	pkg, err := plugin.GetPluginPackage(name)
+	if err != nil {
+		log.Warn("node ", client.Instance(), " fetch plugin ", name, ":", err)
+		return nil
+	}
+	return client.SendPluginPackage(pkg)
+}
+
+//OnPluginStatus 保存节点上报的插件加载状态
+func OnPluginStatus(code cmd.Code, data []byte, client *Client) error {
+	status, err := cmd.DecodePluginStatus(data)
+	if err != nil {
+		return err
+	}
+	err = plugin.SetNodePluginStatus(client.Instance(), status)
+	if err != nil {
+		log.Warn("save plugin status of ", client.Instance(), ":", err)
+	}
+	return nil
+}
+
+//PushPluginPackage 将插件包推送到所有在线节点，返回推送的节点数
+func PushPluginPackage(pkg *entity.PluginPackage) int {
+	count := 0
+	for _, client := range clientManager.Clients() {
+		if err := client.SendPluginPackage(pkg); err != nil {
+			log.Warn("push plugin ", pkg.PluginName, " to ", client.Instance(), ":", err)
+			continue
+		}
+		count++
+	}
+	return count
+}
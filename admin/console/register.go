@@ -15,6 +15,10 @@ func doRegister()*Register{
 
 	r:=callbacksInit
 	callbacksInit = nil
+	r.RegisterFunc(cmd.Heartbeat, OnHeartbeat)
+	r.RegisterFunc(cmd.Monitor, OnMonitor)
+	r.RegisterFunc(cmd.PluginFetch, OnPluginFetch)
+	r.RegisterFunc(cmd.PluginStatus, OnPluginStatus)
 	versionConfig.AddCallback(OnConfigChange)
 	return r
 }
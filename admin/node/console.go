@@ -30,9 +30,25 @@ type TcpConsole struct {
 }
 
 func (c *TcpConsole) SendMonitor(data []byte) error {
+	conn := c.getConn()
+	if conn == nil {
+		return ErrorNotRegister
+	}
+	return conn.Send(cmd.Monitor, data)
 
-	return c.conn.Send(cmd.Monitor, data)
+}
 
+func (c *TcpConsole) getConn() *cmd.Connect {
+	c.lock.Lock()
+	conn := c.conn
+	c.lock.Unlock()
+	return conn
+}
+
+func (c *TcpConsole) setConn(conn *cmd.Connect) {
+	c.lock.Lock()
+	c.conn = conn
+	c.lock.Unlock()
 }
 
 func (c *TcpConsole) GetConfig() (*config.GokuConfig, error) {
@@ -46,6 +62,9 @@ func (c *TcpConsole) GetConfig() (*config.GokuConfig, error) {
 
 func (c *TcpConsole) Close() {
 	c.cancel()
+	if conn := c.getConn(); conn != nil {
+		conn.Close()
+	}
 }
 
 func (c *TcpConsole) AddListen(callback console.ConfigCallbackFunc) {
@@ -56,6 +75,7 @@ func (c *TcpConsole) AddListen(callback console.ConfigCallbackFunc) {
 }
 
 func NewConsole(addr string, instance string) *TcpConsole {
+	ctx, cancel := context.WithCancel(context.Background())
 	c := &TcpConsole{
 		addr:     addr,
 		instance: instance,
@@ -64,10 +84,13 @@ func NewConsole(addr string, instance string) *TcpConsole {
 		listener: listener.New(),
 
 		lastConfig: manager.NewValue(),
+		ctx:        ctx,
+		cancel:     cancel,
 	}
 	c.register.RegisterFunc(cmd.Config, c.OnConfigChange)
 	c.register.RegisterFunc(cmd.Restart, Restart)
 	c.register.RegisterFunc(cmd.Stop, Stop)
+	c.register.RegisterFunc(cmd.PluginPackage, c.OnPluginPackage)
 
 	return c
 }
@@ -135,7 +158,9 @@ func (c *TcpConsole) RegisterToConsole() (*config.GokuConfig, error) {
 			return nil, errors.New(result.Error)
 		}
 
-		c.conn = cmd.NewConnect(conn)
+		c.setConn(cmd.NewConnect(conn))
+		c.lastConfig.Set(result.Config)
+		c.syncPlugins(result.Plugins)
 
 		return result.Config, nil
 	}
@@ -147,20 +172,27 @@ func (c *TcpConsole) Listen() {
 			go func() {
 				for {
 					c.listenRead()
+					select {
+					case <-c.ctx.Done():
+						return
+					default:
+					}
 					c.RegisterToConsole()
 				}
 			}()
+			go c.heartbeat()
 		})
 
 }
 
 func (c *TcpConsole) listenRead() {
-	defer c.conn.Close()
+	conn := c.getConn()
+	defer conn.Close()
 	for {
 		select {
-		case <-c.conn.Done():
+		case <-conn.Done():
 			return
-		case frame, ok := <-c.conn.ReadC():
+		case frame, ok := <-conn.ReadC():
 			{
 				if !ok {
 					return
@@ -6,4 +6,5 @@ var (
 	ErrorReadRegisterResultTimeOut = errors.New("read register result timeout")
 	ErrorNeedReadRegisterResult    = errors.New("need register-result but not")
 	ErrorConsoleRefuse             = errors.New("console refuse")
+	ErrorNotRegister               = errors.New("not register to console")
 )
@@ -0,0 +1,93 @@
+package node
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/admin/cmd"
+	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/node/gateway"
+	"github.com/eolinker/goku-api-gateway/node/monitor"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//HeartbeatInterval 心跳上报间隔
+const HeartbeatInterval = time.Second * 5
+
+var (
+	startTime = time.Now()
+)
+
+func (c *TcpConsole) heartbeat() {
+	ticker := time.NewTicker(HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			err := c.SendHeartbeat()
+			if err != nil {
+				log.Warn("send heartbeat to console:", err)
+			}
+			err = c.SendMonitorReport()
+			if err != nil {
+				log.Warn("send monitor report to console:", err)
+			}
+		}
+	}
+}
+
+//SendHeartbeat 上报心跳
+func (c *TcpConsole) SendHeartbeat() error {
+	conn := c.getConn()
+	if conn == nil {
+		return ErrorNotRegister
+	}
+	data, err := cmd.EncodeHeartbeat(c.collect())
+	if err != nil {
+		return err
+	}
+	return conn.Send(cmd.Heartbeat, data)
+}
+
+//SendMonitorReport 上报按接口、策略、负载统计的请求数据，供控制台告警
+func (c *TcpConsole) SendMonitorReport() error {
+	report := monitor.CollapseReport()
+	if len(report.Items) == 0 {
+		return nil
+	}
+	report.ReportTime = time.Now().Format("2006-01-02 15:04:05")
+	data, err := cmd.EncodeMonitorReport(report)
+	if err != nil {
+		return err
+	}
+	return c.SendMonitor(data)
+}
+
+func (c *TcpConsole) collect() *entity.NodeHeartbeat {
+	now := time.Now()
+	snapshot := monitor.CollapseStatistics()
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	h := &entity.NodeHeartbeat{
+		Uptime:     int64(now.Sub(startTime) / time.Second),
+		Requests:   snapshot.Requests,
+		Errors:     snapshot.Errors,
+		P50:        snapshot.P50,
+		P99:        snapshot.P99,
+		Goroutines: runtime.NumGoroutine(),
+		MemAlloc:   mem.Alloc,
+		MemSys:     mem.Sys,
+		ReportTime: now.Format("2006-01-02 15:04:05"),
+
+		PluginErrors: gateway.PluginErrors(),
+	}
+	if conf, has := c.lastConfig.Get(); has {
+		h.ConfigVersion = conf.(*config.GokuConfig).Version
+	}
+	return h
+}
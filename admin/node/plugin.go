@@ -0,0 +1,106 @@
+package node
+
+import (
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/admin/cmd"
+	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	plugin_loader "github.com/eolinker/goku-api-gateway/node/plugin-loader"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//syncPlugins 按控制台下发的清单检查本地插件包，不一致的向控制台拉取，并上报加载状态
+func (c *TcpConsole) syncPlugins(plugins map[string]*config.PluginPackage) {
+	plugin_loader.SetPackages(plugins)
+	conn := c.getConn()
+	if conn == nil {
+		return
+	}
+	for name, pkg := range plugins {
+		if pkg == nil || plugin_loader.Checksum(name) == pkg.Checksum {
+			continue
+		}
+		data, err := cmd.EncodePluginFetch(name)
+		if err != nil {
+			continue
+		}
+		if err := conn.Send(cmd.PluginFetch, data); err != nil {
+			log.Warn("fetch plugin ", name, " from console:", err)
+		}
+	}
+	if err := c.SendPluginStatus(); err != nil {
+		log.Warn("send plugin status to console:", err)
+	}
+}
+
+//OnPluginPackage 安装控制台推送或拉取到的插件包，并使用当前配置重新加载
+func (c *TcpConsole) OnPluginPackage(code cmd.Code, data []byte) error {
+	p, err := cmd.DecodePluginPackage(data)
+	if err != nil {
+		return err
+	}
+	pkg := &config.PluginPackage{
+		Version:  p.Version,
+		Checksum: p.Checksum,
+	}
+	if plugin_loader.Checksum(p.PluginName) != p.Checksum {
+		err = plugin_loader.Install(p.PluginName, pkg, p.Content)
+		if err != nil {
+			log.Warn("install plugin ", p.PluginName, ":", err)
+			return c.SendPluginStatus(&entity.NodePluginStatus{
+				PluginName: p.PluginName,
+				Version:    p.Version,
+				Checksum:   plugin_loader.Checksum(p.PluginName),
+				Code:       plugin_loader.LoadFileError,
+				Error:      err.Error(),
+			})
+		}
+		log.Info("install plugin ", p.PluginName, " version ", p.Version)
+		if conf, has := c.lastConfig.Get(); has {
+			c.listener.Call(conf)
+		}
+	}
+	return c.SendPluginStatus()
+}
+
+//SendPluginStatus 上报清单内插件的加载状态，extra为未能进入清单的插件状态
+func (c *TcpConsole) SendPluginStatus(extra ...*entity.NodePluginStatus) error {
+	conn := c.getConn()
+	if conn == nil {
+		return ErrorNotRegister
+	}
+	now := time.Now().Format("2006-01-02 15:04:05")
+	status := make([]*entity.NodePluginStatus, 0, len(extra))
+	for _, s := range extra {
+		s.UpdateTime = now
+		status = append(status, s)
+	}
+	for name, pkg := range plugin_loader.Packages() {
+		s := &entity.NodePluginStatus{
+			PluginName: name,
+			Version:    pkg.Version,
+			Checksum:   plugin_loader.Checksum(name),
+			UpdateTime: now,
+		}
+		if s.Checksum != pkg.Checksum {
+			s.Code = plugin_loader.LoadFileError
+			s.Error = "plugin package is not synced from console"
+		} else {
+			code, needRestart, err := plugin_loader.Status(name)
+			s.Code, s.NeedRestart = code, needRestart
+			if err != nil {
+				s.Error = err.Error()
+			}
+		}
+		status = append(status, s)
+	}
+	if len(status) == 0 {
+		return nil
+	}
+	data, err := cmd.EncodePluginStatus(status)
+	if err != nil {
+		return err
+	}
+	return conn.Send(cmd.PluginStatus, data)
+}
@@ -1,6 +1,7 @@
 package node
 
 import (
+	"github.com/eolinker/goku-api-gateway/common/endless"
 	goku_log "github.com/eolinker/goku-api-gateway/goku-log"
 
 	"github.com/eolinker/goku-api-gateway/admin/cmd"
@@ -8,14 +9,10 @@ import (
 
 func Restart(code cmd.Code, data []byte) error {
 	goku_log.Info("restart")
-	//endless.RestartServer()
-
-	return nil
+	return endless.RestartServer()
 }
 
 func Stop(code cmd.Code, data []byte) error {
 	goku_log.Info("stop")
-	//endless.StopServer()
-
-	return nil
+	return endless.StopServer()
 }
@@ -3,10 +3,12 @@ package main
 import (
 	graphite "github.com/eolinker/goku-api-gateway/module/graphite/config"
 	prometheus "github.com/eolinker/goku-api-gateway/module/prometheus/config"
+	tracing "github.com/eolinker/goku-api-gateway/module/tracing/config"
 )
 
 func moduleRegister() {
 	prometheus.Register()
 	graphite.Register()
+	tracing.Register()
 
 }
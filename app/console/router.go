@@ -5,11 +5,13 @@ import (
 
 	account_default "github.com/eolinker/goku-api-gateway/console/account"
 	"github.com/eolinker/goku-api-gateway/console/controller/account"
+	"github.com/eolinker/goku-api-gateway/console/controller/alert"
 	"github.com/eolinker/goku-api-gateway/console/controller/api"
 	"github.com/eolinker/goku-api-gateway/console/controller/auth"
 	"github.com/eolinker/goku-api-gateway/console/controller/balance"
 	"github.com/eolinker/goku-api-gateway/console/controller/cluster"
 	config_log "github.com/eolinker/goku-api-gateway/console/controller/config-log"
+	"github.com/eolinker/goku-api-gateway/console/controller/declarative"
 	"github.com/eolinker/goku-api-gateway/console/controller/discovery"
 	"github.com/eolinker/goku-api-gateway/console/controller/gateway"
 	"github.com/eolinker/goku-api-gateway/console/controller/monitor"
@@ -35,6 +37,7 @@ func router() http.Handler {
 	s.Add("/apis", api.NewAPIHandlers())
 	s.Add("/apis/group", api.NewGroupHandlers())
 	s.Add("/import/ams", api.NewImportHandlers())
+	s.Add("/apis/openapi", api.NewOpenAPIHandlers())
 	s.Add("/plugin/api", api.NewPluginHandlers())
 
 	// 鉴权模块
@@ -58,6 +61,7 @@ func router() http.Handler {
 
 	// 监控模块
 	s.Add("/monitor/module/config", monitor.NewHandlers())
+	s.Add("/monitor/alert", alert.NewHandlers())
 
 	// 节点模块
 	s.Add("/node", node.NewNodeHandlers())
@@ -75,6 +79,9 @@ func router() http.Handler {
 	s.Add("/strategy/api", strategy.NewAPIStrategyHandlers())
 	s.Add("/plugin/strategy", strategy.NewPluginHandlers())
 
+	// 声明式配置模块
+	s.Add("/declarative", declarative.NewHandlers())
+
 	// 前端接入
 	s.Add("/", new(staticHandlers))
 	return s
@@ -2,12 +2,14 @@ package main
 
 import (
 	"github.com/eolinker/goku-api-gateway/goku-service/driver/consul"
+	"github.com/eolinker/goku-api-gateway/goku-service/driver/dns"
 	"github.com/eolinker/goku-api-gateway/goku-service/driver/eureka"
 	"github.com/eolinker/goku-api-gateway/goku-service/driver/static"
 )
 
 func init() {
 	consul.Register()
+	dns.Register()
 	eureka.Register()
 	static.Register()
 }
@@ -3,15 +3,17 @@ package main
 import "flag"
 
 //ParseFlag 获取命令行参数
-func ParseFlag() (instance string, admin string, staticConfigFile string, isDebug bool) {
+func ParseFlag() (instance string, admin string, staticConfigFile string, isDebug bool, gracefulTimeout int, zone string) {
 	adminP := flag.String("admin", "", "Please provide a valid host!")
 	instanceP := flag.String("instance", "", "Please provide a valid instance!")
 	staticConfigFileP := flag.String("config", "", "Please provide a config file")
+	gracefulTimeoutP := flag.Int("graceful", 60, "Seconds to wait for in-flight requests when restarting or stopping, negative to wait forever")
+	zoneP := flag.String("zone", "", "Zone of this node, instances with the same zone metadata are preferred")
 
 	isDebugP := flag.Bool("debug", false, "")
 
 	flag.Parse()
 
-	return *instanceP, *adminP, *staticConfigFileP, *isDebugP
+	return *instanceP, *adminP, *staticConfigFileP, *isDebugP, *gracefulTimeoutP, *zoneP
 
 }
@@ -3,10 +3,12 @@ package main
 import (
 	"github.com/eolinker/goku-api-gateway/module/graphite"
 	"github.com/eolinker/goku-api-gateway/module/prometheus"
+	"github.com/eolinker/goku-api-gateway/module/tracing"
 )
 
 func init() {
 
 	prometheus.Register()
 	graphite.Register()
+	tracing.Register()
 }
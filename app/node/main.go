@@ -3,27 +3,34 @@ package main
 import (
 	"flag"
 	"github.com/eolinker/goku-api-gateway/admin/node"
+	"github.com/eolinker/goku-api-gateway/common/endless"
 	"github.com/eolinker/goku-api-gateway/config"
 	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/goku-service/common"
 	"github.com/eolinker/goku-api-gateway/node/server"
 	"runtime"
+	"time"
 )
 
 func main() {
 	runtime.GOMAXPROCS(runtime.NumCPU())
 
-	instance, admin, staticConfigFile, isDebug := ParseFlag()
+	instance, admin, staticConfigFile, isDebug, gracefulTimeout, zone := ParseFlag()
 
 	if isDebug {
 		log.StartDebug()
 	}
+	endless.SetHammerTime(time.Duration(gracefulTimeout) * time.Second)
+	common.SetLocalZone(zone)
 
 	if admin != "" && instance != ""{
 
 		console := node.NewConsole(admin,instance)
 
 		ser := server.NewServer()
-		log.Fatal(ser.ServerWidthConsole(console))
+		if err := ser.ServerWidthConsole(console); err != nil {
+			log.Fatal(err)
+		}
 		return
 	}
 
@@ -35,7 +42,10 @@ func main() {
 			log.Panic("read config from :", staticConfigFile, "\t", err)
 		}
 		ser := server.NewServer()
-		log.Fatal(ser.ServerWidthConfig(c))
+		if err := ser.ServerWidthConfig(c); err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
 
 	flag.Usage()
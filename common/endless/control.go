@@ -0,0 +1,28 @@
+package endless
+
+import (
+	"syscall"
+	"time"
+)
+
+//SetHammerTime 设置优雅关闭时等待处理中请求的最长时间，负数表示一直等待
+func SetHammerTime(d time.Duration) {
+	DefaultHammerTime = d
+}
+
+/*
+RestartServer forks a new process that inherits the listening sockets of all
+running servers. The current process stops accepting connections once the child
+is serving and exits after draining in-flight requests.
+*/
+func RestartServer() error {
+	return syscall.Kill(syscall.Getpid(), syscall.SIGHUP)
+}
+
+/*
+StopServer closes the listeners of all running servers and lets them drain
+in-flight requests for at most DefaultHammerTime.
+*/
+func StopServer() error {
+	return syscall.Kill(syscall.Getpid(), syscall.SIGTERM)
+}
@@ -194,6 +194,10 @@ func (srv *endlessServer) Serve() (err error) {
 	defer log.Info(syscall.Getpid(), "Serve() returning...")
 	srv.setState(STATE_RUNNING)
 	err = srv.Server.Serve(srv.EndlessListener)
+	if srv.getState() == STATE_SHUTTING_DOWN {
+		// the listener was closed by shutdown, not by an accept failure
+		err = http.ErrServerClosed
+	}
 	log.Info(syscall.Getpid(), "Waiting for connections to finish...")
 	srv.wg.Wait()
 	srv.setState(STATE_TERMINATE)
@@ -332,8 +336,13 @@ func (srv *endlessServer) handleSignals() {
 		case syscall.SIGUSR1:
 			log.Info(pid, "Received SIGUSR1.")
 		case syscall.SIGUSR2:
-			log.Info(pid, "Received SIGUSR2.")
-			srv.hammerTime(0 * time.Second)
+			// 二进制升级：替换可执行文件后发送SIGUSR2，由新的二进制接管监听socket
+			log.Info(pid, "Received SIGUSR2. forking.")
+			err := srv.fork()
+			if err != nil {
+				log.Info("Fork err:", err)
+			}
+			isStop = false
 		case syscall.SIGINT:
 			log.Info(pid, "Received SIGINT.")
 			srv.shutdown()
@@ -0,0 +1,416 @@
+package jmespath
+
+import (
+	"reflect"
+	"sort"
+)
+
+type node interface {
+	eval(v interface{}) (interface{}, error)
+}
+
+type field string
+
+func (n field) eval(v interface{}) (interface{}, error) {
+	if m, ok := v.(map[string]interface{}); ok {
+		return m[string(n)], nil
+	}
+	return nil, nil
+}
+
+type current struct{}
+
+func (current) eval(v interface{}) (interface{}, error) {
+	return v, nil
+}
+
+type literal struct {
+	value interface{}
+}
+
+func (n literal) eval(interface{}) (interface{}, error) {
+	return n.value, nil
+}
+
+type subexpression struct {
+	left, right node
+}
+
+func (n *subexpression) eval(v interface{}) (interface{}, error) {
+	l, err := n.left.eval(v)
+	if err != nil || l == nil {
+		return nil, err
+	}
+	return n.right.eval(l)
+}
+
+type index int
+
+func (n index) eval(v interface{}) (interface{}, error) {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, nil
+	}
+	i := int(n)
+	if i < 0 {
+		i += len(list)
+	}
+	if i < 0 || i >= len(list) {
+		return nil, nil
+	}
+	return list[i], nil
+}
+
+type slice struct {
+	start, stop, step *int
+}
+
+func (n *slice) eval(v interface{}) (interface{}, error) {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, nil
+	}
+	step := 1
+	if n.step != nil {
+		step = *n.step
+	}
+	length := len(list)
+	bound := func(p *int, def int) int {
+		if p == nil {
+			return def
+		}
+		i := *p
+		if i < 0 {
+			i += length
+			if i < 0 {
+				if step < 0 {
+					return -1
+				}
+				return 0
+			}
+		} else if i >= length {
+			if step < 0 {
+				return length - 1
+			}
+			return length
+		}
+		return i
+	}
+	result := make([]interface{}, 0)
+	if step > 0 {
+		for i := bound(n.start, 0); i < bound(n.stop, length); i += step {
+			result = append(result, list[i])
+		}
+	} else {
+		for i := bound(n.start, length-1); i > bound(n.stop, -1); i += step {
+			result = append(result, list[i])
+		}
+	}
+	return result, nil
+}
+
+type projection struct {
+	left, right node
+}
+
+func (n *projection) eval(v interface{}) (interface{}, error) {
+	l, err := n.left.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	list, ok := l.([]interface{})
+	if !ok {
+		return nil, nil
+	}
+	return project(list, n.right)
+}
+
+func project(list []interface{}, right node) (interface{}, error) {
+	result := make([]interface{}, 0, len(list))
+	for _, e := range list {
+		r, err := right.eval(e)
+		if err != nil {
+			return nil, err
+		}
+		if r != nil {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+type valueProjection struct {
+	left, right node
+}
+
+func (n *valueProjection) eval(v interface{}) (interface{}, error) {
+	l, err := n.left.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := l.(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	return project(sortedValues(m), n.right)
+}
+
+// sortedValues 按key排序的值，保证结果稳定
+func sortedValues(m map[string]interface{}) []interface{} {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	values := make([]interface{}, 0, len(m))
+	for _, k := range keys {
+		values = append(values, m[k])
+	}
+	return values
+}
+
+type flatten struct {
+	node node
+}
+
+func (n *flatten) eval(v interface{}) (interface{}, error) {
+	l, err := n.node.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	list, ok := l.([]interface{})
+	if !ok {
+		return nil, nil
+	}
+	result := make([]interface{}, 0, len(list))
+	for _, e := range list {
+		if sub, ok := e.([]interface{}); ok {
+			result = append(result, sub...)
+		} else {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+type filterProjection struct {
+	left, condition, right node
+}
+
+func (n *filterProjection) eval(v interface{}) (interface{}, error) {
+	l, err := n.left.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	list, ok := l.([]interface{})
+	if !ok {
+		return nil, nil
+	}
+	matched := make([]interface{}, 0, len(list))
+	for _, e := range list {
+		c, err := n.condition.eval(e)
+		if err != nil {
+			return nil, err
+		}
+		if truthy(c) {
+			matched = append(matched, e)
+		}
+	}
+	return project(matched, n.right)
+}
+
+type pipe struct {
+	left, right node
+}
+
+func (n *pipe) eval(v interface{}) (interface{}, error) {
+	l, err := n.left.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	return n.right.eval(l)
+}
+
+type or struct {
+	left, right node
+}
+
+func (n *or) eval(v interface{}) (interface{}, error) {
+	l, err := n.left.eval(v)
+	if err != nil || truthy(l) {
+		return l, err
+	}
+	return n.right.eval(v)
+}
+
+type and struct {
+	left, right node
+}
+
+func (n *and) eval(v interface{}) (interface{}, error) {
+	l, err := n.left.eval(v)
+	if err != nil || !truthy(l) {
+		return l, err
+	}
+	return n.right.eval(v)
+}
+
+type not struct {
+	node node
+}
+
+func (n *not) eval(v interface{}) (interface{}, error) {
+	r, err := n.node.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	return !truthy(r), nil
+}
+
+type comparator struct {
+	op          tokenType
+	left, right node
+}
+
+func (n *comparator) eval(v interface{}) (interface{}, error) {
+	l, err := n.left.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	r, err := n.right.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case tEQ:
+		return equal(l, r), nil
+	case tNE:
+		return !equal(l, r), nil
+	}
+	// 数字之间及字符串之间可比较大小，其余为null
+	var c int
+	if a, ok := toNumber(l); ok {
+		b, ok := toNumber(r)
+		if !ok {
+			return nil, nil
+		}
+		c = compareFloat(a, b)
+	} else if a, ok := l.(string); ok {
+		b, ok := r.(string)
+		if !ok {
+			return nil, nil
+		}
+		c = compareString(a, b)
+	} else {
+		return nil, nil
+	}
+	switch n.op {
+	case tLT:
+		return c < 0, nil
+	case tLTE:
+		return c <= 0, nil
+	case tGT:
+		return c > 0, nil
+	}
+	return c >= 0, nil
+}
+
+type multiSelectList []node
+
+func (n multiSelectList) eval(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	result := make([]interface{}, 0, len(n))
+	for _, item := range n {
+		r, err := item.eval(v)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+type multiSelectHash struct {
+	keys   []string
+	values []node
+}
+
+func (n *multiSelectHash) eval(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	result := make(map[string]interface{}, len(n.keys))
+	for i, key := range n.keys {
+		r, err := n.values[i].eval(v)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = r
+	}
+	return result, nil
+}
+
+type function struct {
+	name string
+	fn   *functionEntry
+	args []node
+}
+
+func (n *function) eval(v interface{}) (interface{}, error) {
+	args := make([]interface{}, 0, len(n.args))
+	for _, arg := range n.args {
+		r, err := arg.eval(v)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, r)
+	}
+	return n.fn.call(n.name, args)
+}
+
+// truthy false、null、空字符串、空数组及空对象为假
+func truthy(v interface{}) bool {
+	switch value := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return value
+	case string:
+		return value != ""
+	case []interface{}:
+		return len(value) > 0
+	case map[string]interface{}:
+		return len(value) > 0
+	}
+	return true
+}
+
+func equal(a, b interface{}) bool {
+	if x, ok := toNumber(a); ok {
+		y, ok := toNumber(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareString(a, b string) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
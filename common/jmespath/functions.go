@@ -0,0 +1,393 @@
+package jmespath
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type functionEntry struct {
+	min, max int
+	call     func(name string, args []interface{}) (interface{}, error)
+}
+
+var functions map[string]*functionEntry
+
+func init() {
+	functions = map[string]*functionEntry{
+		"length":      {1, 1, fnLength},
+		"keys":        {1, 1, fnKeys},
+		"values":      {1, 1, fnValues},
+		"join":        {2, 2, fnJoin},
+		"contains":    {2, 2, fnContains},
+		"starts_with": {2, 2, fnStartsWith},
+		"ends_with":   {2, 2, fnEndsWith},
+		"to_string":   {1, 1, fnToString},
+		"to_number":   {1, 1, fnToNumber},
+		"to_array":    {1, 1, fnToArray},
+		"type":        {1, 1, fnType},
+		"not_null":    {1, -1, fnNotNull},
+		"sort":        {1, 1, fnSort},
+		"reverse":     {1, 1, fnReverse},
+		"max":         {1, 1, fnMax},
+		"min":         {1, 1, fnMin},
+		"sum":         {1, 1, fnSum},
+		"avg":         {1, 1, fnAvg},
+		"abs":         {1, 1, fnAbs},
+		"floor":       {1, 1, fnFloor},
+		"ceil":        {1, 1, fnCeil},
+		"merge":       {1, -1, fnMerge},
+	}
+}
+
+func typeError(name string, v interface{}) error {
+	return fmt.Errorf("invalid type %s for function %s", typeOf(v), name)
+}
+
+// toNumber 将JSON解析出的数字统一转换为float64
+func toNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func typeOf(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	if _, ok := toNumber(v); ok {
+		return "number"
+	}
+	return "unknown"
+}
+
+func fnLength(name string, args []interface{}) (interface{}, error) {
+	switch v := args[0].(type) {
+	case string:
+		return float64(len([]rune(v))), nil
+	case []interface{}:
+		return float64(len(v)), nil
+	case map[string]interface{}:
+		return float64(len(v)), nil
+	}
+	return nil, typeError(name, args[0])
+}
+
+func fnKeys(name string, args []interface{}) (interface{}, error) {
+	m, ok := args[0].(map[string]interface{})
+	if !ok {
+		return nil, typeError(name, args[0])
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	result := make([]interface{}, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, k)
+	}
+	return result, nil
+}
+
+func fnValues(name string, args []interface{}) (interface{}, error) {
+	m, ok := args[0].(map[string]interface{})
+	if !ok {
+		return nil, typeError(name, args[0])
+	}
+	return sortedValues(m), nil
+}
+
+func fnJoin(name string, args []interface{}) (interface{}, error) {
+	sep, ok := args[0].(string)
+	if !ok {
+		return nil, typeError(name, args[0])
+	}
+	list, ok := args[1].([]interface{})
+	if !ok {
+		return nil, typeError(name, args[1])
+	}
+	parts := make([]string, 0, len(list))
+	for _, e := range list {
+		s, ok := e.(string)
+		if !ok {
+			return nil, typeError(name, e)
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, sep), nil
+}
+
+func fnContains(name string, args []interface{}) (interface{}, error) {
+	switch v := args[0].(type) {
+	case string:
+		s, ok := args[1].(string)
+		return ok && strings.Contains(v, s), nil
+	case []interface{}:
+		for _, e := range v {
+			if equal(e, args[1]) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return nil, typeError(name, args[0])
+}
+
+func twoStrings(name string, args []interface{}) (string, string, error) {
+	a, ok := args[0].(string)
+	if !ok {
+		return "", "", typeError(name, args[0])
+	}
+	b, ok := args[1].(string)
+	if !ok {
+		return "", "", typeError(name, args[1])
+	}
+	return a, b, nil
+}
+
+func fnStartsWith(name string, args []interface{}) (interface{}, error) {
+	a, b, err := twoStrings(name, args)
+	if err != nil {
+		return nil, err
+	}
+	return strings.HasPrefix(a, b), nil
+}
+
+func fnEndsWith(name string, args []interface{}) (interface{}, error) {
+	a, b, err := twoStrings(name, args)
+	if err != nil {
+		return nil, err
+	}
+	return strings.HasSuffix(a, b), nil
+}
+
+func fnToString(name string, args []interface{}) (interface{}, error) {
+	if s, ok := args[0].(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func fnToNumber(name string, args []interface{}) (interface{}, error) {
+	if n, ok := toNumber(args[0]); ok {
+		return n, nil
+	}
+	if s, ok := args[0].(string); ok {
+		if n, err := strconv.ParseFloat(s, 64); err == nil {
+			return n, nil
+		}
+	}
+	return nil, nil
+}
+
+func fnToArray(name string, args []interface{}) (interface{}, error) {
+	if list, ok := args[0].([]interface{}); ok {
+		return list, nil
+	}
+	return []interface{}{args[0]}, nil
+}
+
+func fnType(name string, args []interface{}) (interface{}, error) {
+	return typeOf(args[0]), nil
+}
+
+func fnNotNull(name string, args []interface{}) (interface{}, error) {
+	for _, a := range args {
+		if a != nil {
+			return a, nil
+		}
+	}
+	return nil, nil
+}
+
+// sortable 数组须全部为数字或全部为字符串
+func sortable(name string, v interface{}) ([]interface{}, bool, error) {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, false, typeError(name, v)
+	}
+	if len(list) == 0 {
+		return list, true, nil
+	}
+	_, isNumber := toNumber(list[0])
+	for _, e := range list {
+		if _, ok := toNumber(e); ok != isNumber {
+			return nil, false, typeError(name, e)
+		}
+		if _, ok := e.(string); !isNumber && !ok {
+			return nil, false, typeError(name, e)
+		}
+	}
+	return list, isNumber, nil
+}
+
+func less(isNumber bool, a, b interface{}) bool {
+	if isNumber {
+		x, _ := toNumber(a)
+		y, _ := toNumber(b)
+		return x < y
+	}
+	return a.(string) < b.(string)
+}
+
+func fnSort(name string, args []interface{}) (interface{}, error) {
+	list, isNumber, err := sortable(name, args[0])
+	if err != nil {
+		return nil, err
+	}
+	sorted := append([]interface{}{}, list...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return less(isNumber, sorted[i], sorted[j])
+	})
+	return sorted, nil
+}
+
+func fnReverse(name string, args []interface{}) (interface{}, error) {
+	switch v := args[0].(type) {
+	case string:
+		r := []rune(v)
+		for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+			r[i], r[j] = r[j], r[i]
+		}
+		return string(r), nil
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, e := range v {
+			result[len(v)-1-i] = e
+		}
+		return result, nil
+	}
+	return nil, typeError(name, args[0])
+}
+
+func extreme(name string, v interface{}, max bool) (interface{}, error) {
+	list, isNumber, err := sortable(name, v)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+	result := list[0]
+	for _, e := range list[1:] {
+		if less(isNumber, result, e) == max {
+			result = e
+		}
+	}
+	return result, nil
+}
+
+func fnMax(name string, args []interface{}) (interface{}, error) {
+	return extreme(name, args[0], true)
+}
+
+func fnMin(name string, args []interface{}) (interface{}, error) {
+	return extreme(name, args[0], false)
+}
+
+func numbers(name string, v interface{}) ([]float64, error) {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, typeError(name, v)
+	}
+	result := make([]float64, 0, len(list))
+	for _, e := range list {
+		n, ok := toNumber(e)
+		if !ok {
+			return nil, typeError(name, e)
+		}
+		result = append(result, n)
+	}
+	return result, nil
+}
+
+func fnSum(name string, args []interface{}) (interface{}, error) {
+	list, err := numbers(name, args[0])
+	if err != nil {
+		return nil, err
+	}
+	sum := 0.0
+	for _, n := range list {
+		sum += n
+	}
+	return sum, nil
+}
+
+func fnAvg(name string, args []interface{}) (interface{}, error) {
+	list, err := numbers(name, args[0])
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+	sum := 0.0
+	for _, n := range list {
+		sum += n
+	}
+	return sum / float64(len(list)), nil
+}
+
+func number(name string, v interface{}, fn func(float64) float64) (interface{}, error) {
+	n, ok := toNumber(v)
+	if !ok {
+		return nil, typeError(name, v)
+	}
+	return fn(n), nil
+}
+
+func fnAbs(name string, args []interface{}) (interface{}, error) {
+	return number(name, args[0], math.Abs)
+}
+
+func fnFloor(name string, args []interface{}) (interface{}, error) {
+	return number(name, args[0], math.Floor)
+}
+
+func fnCeil(name string, args []interface{}) (interface{}, error) {
+	return number(name, args[0], math.Ceil)
+}
+
+func fnMerge(name string, args []interface{}) (interface{}, error) {
+	result := make(map[string]interface{})
+	for _, a := range args {
+		m, ok := a.(map[string]interface{})
+		if !ok {
+			return nil, typeError(name, a)
+		}
+		for k, v := range m {
+			result[k] = v
+		}
+	}
+	return result, nil
+}
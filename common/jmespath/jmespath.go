@@ -0,0 +1,79 @@
+package jmespath
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//Expression 编译后的JMESPath表达式
+// 支持字段、下标、切片、投影([*]、*、[])、过滤([?条件])、管道、多选([a,b]、{k:a})、
+// 比较及逻辑运算、`JSON`与'字符串'字面量，以及length、keys、join、contains等常用函数
+type Expression struct {
+	expr string
+	root node
+}
+
+//Compile 编译表达式
+func Compile(expr string) (*Expression, error) {
+	root, err := parse(strings.TrimSpace(expr))
+	if err != nil {
+		return nil, err
+	}
+	return &Expression{expr: expr, root: root}, nil
+}
+
+//MustCompile 编译表达式，出错时panic
+func MustCompile(expr string) *Expression {
+	e, err := Compile(expr)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+//Search 对数据执行表达式，数据为JSON解析得到的map[string]interface{}、[]interface{}等结构
+func (e *Expression) Search(data interface{}) (interface{}, error) {
+	return e.root.eval(data)
+}
+
+func (e *Expression) String() string {
+	return e.expr
+}
+
+//Search 编译并执行表达式
+func Search(expr string, data interface{}) (interface{}, error) {
+	e, err := Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return e.Search(data)
+}
+
+//IsExpression 判断路径是否需要按表达式解析，只包含字段名、"."及"*"的路径仍按原有路径规则处理
+func IsExpression(path string) bool {
+	return strings.ContainsAny(path, "[]?|(){}@!&=<>'\"`,")
+}
+
+//ToString 将表达式结果转换为模版中输出的字符串，null为空，对象及数组输出JSON
+func ToString(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case bool:
+		return strconv.FormatBool(value)
+	case []interface{}, map[string]interface{}:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+	if n, ok := toNumber(v); ok {
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
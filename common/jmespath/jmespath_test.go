@@ -0,0 +1,84 @@
+package jmespath
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const testData = `{
+	"code": 200,
+	"items": [
+		{"id": 1, "status": "ok", "tags": ["a", "b"], "price": 10},
+		{"id": 2, "status": "fail", "tags": ["c"], "price": 5.5},
+		{"id": 3, "status": "ok", "tags": [], "price": 20}
+	],
+	"user": {"name": "goku", "first-name": "sun", "age": 18}
+}`
+
+func TestSearch(t *testing.T) {
+	var data interface{}
+	if err := json.Unmarshal([]byte(testData), &data); err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		expr, want string
+	}{
+		{"code", "200"},
+		{"user.name", "goku"},
+		{`user."first-name"`, "sun"},
+		{"items[0].id", "1"},
+		{"items[-1].id", "3"},
+		{"items[*].id", "[1,2,3]"},
+		{"items[?status=='ok'].id", "[1,3]"},
+		{"items[?price > `6`].id", "[1,3]"},
+		{"items[?status=='ok' && price < `15`].id | [0]", "1"},
+		{"items[].tags[]", `["a","b","c"]`},
+		{"items[:2].id", "[1,2]"},
+		{"items[::-1].id", "[3,2,1]"},
+		{"user.*", `[18,"sun","goku"]`},
+		{"{n: user.name, count: length(items)}", `{"count":3,"n":"goku"}`},
+		{"[code, user.age]", "[200,18]"},
+		{"join(',', items[?status=='ok'].status)", "ok,ok"},
+		{"max(items[*].price)", "20"},
+		{"sum(items[*].price)", "35.5"},
+		{"sort_missing || 'default'", "default"},
+		{"!contains(items[*].status, 'ok')", "false"},
+		{"missing.field", ""},
+	}
+	for _, c := range cases {
+		e, err := Compile(c.expr)
+		if err != nil {
+			t.Errorf("%s: %v", c.expr, err)
+			continue
+		}
+		v, err := e.Search(data)
+		if err != nil {
+			t.Errorf("%s: %v", c.expr, err)
+			continue
+		}
+		if got := ToString(v); got != c.want {
+			t.Errorf("%s: got %s, want %s", c.expr, got, c.want)
+		}
+	}
+}
+
+func TestCompileError(t *testing.T) {
+	for _, expr := range []string{"", "items[", "items[?status=='ok'", "a.", "unknown(a)", "length(a, b)", "'unclosed", "a[0:1:0]", "a ^ b"} {
+		if _, err := Compile(expr); err == nil {
+			t.Errorf("%s: expected compile error", expr)
+		}
+	}
+}
+
+func TestIsExpression(t *testing.T) {
+	for expr, want := range map[string]bool{
+		"a.b.c":                false,
+		"a.*.c":                false,
+		"items[?status=='ok']": true,
+		"length(items)":        true,
+	} {
+		if got := IsExpression(expr); got != want {
+			t.Errorf("%s: got %v, want %v", expr, got, want)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package jmespath
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type tokenType int
+
+const (
+	tEOF tokenType = iota
+	tUnquoted
+	tQuoted
+	tRawString
+	tJSONLiteral
+	tNumber
+	tDot
+	tStar
+	tLbracket
+	tRbracket
+	tFlatten
+	tFilter
+	tLbrace
+	tRbrace
+	tComma
+	tColon
+	tLparen
+	tRparen
+	tPipe
+	tOr
+	tAnd
+	tNot
+	tEQ
+	tNE
+	tLT
+	tLTE
+	tGT
+	tGTE
+	tCurrent
+)
+
+type token struct {
+	typ   tokenType
+	value string
+	pos   int
+}
+
+var simpleTokens = map[byte]tokenType{
+	'.': tDot,
+	'*': tStar,
+	']': tRbracket,
+	'{': tLbrace,
+	'}': tRbrace,
+	',': tComma,
+	':': tColon,
+	'(': tLparen,
+	')': tRparen,
+	'@': tCurrent,
+}
+
+func isIdentifierStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentifier(c byte) bool {
+	return isIdentifierStart(c) || (c >= '0' && c <= '9')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// lex 将表达式拆分为token
+func lex(expr string) ([]token, error) {
+	tokens := make([]token, 0, len(expr)/2+1)
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		if t, has := simpleTokens[c]; has {
+			tokens = append(tokens, token{typ: t, value: string(c), pos: i})
+			i++
+			continue
+		}
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case isIdentifierStart(c):
+			start := i
+			for i < len(expr) && isIdentifier(expr[i]) {
+				i++
+			}
+			tokens = append(tokens, token{typ: tUnquoted, value: expr[start:i], pos: start})
+		case isDigit(c) || (c == '-' && i+1 < len(expr) && isDigit(expr[i+1])):
+			start := i
+			i++
+			for i < len(expr) && isDigit(expr[i]) {
+				i++
+			}
+			tokens = append(tokens, token{typ: tNumber, value: expr[start:i], pos: start})
+		case c == '[':
+			switch {
+			case strings.HasPrefix(expr[i:], "[]"):
+				tokens = append(tokens, token{typ: tFlatten, value: "[]", pos: i})
+				i += 2
+			case strings.HasPrefix(expr[i:], "[?"):
+				tokens = append(tokens, token{typ: tFilter, value: "[?", pos: i})
+				i += 2
+			default:
+				tokens = append(tokens, token{typ: tLbracket, value: "[", pos: i})
+				i++
+			}
+		case c == '"':
+			end, err := closing(expr, i, '"')
+			if err != nil {
+				return nil, err
+			}
+			var s string
+			if err := json.Unmarshal([]byte(expr[i:end+1]), &s); err != nil {
+				return nil, syntaxError(expr, i, "invalid quoted identifier")
+			}
+			tokens = append(tokens, token{typ: tQuoted, value: s, pos: i})
+			i = end + 1
+		case c == '\'':
+			end, err := closing(expr, i, '\'')
+			if err != nil {
+				return nil, err
+			}
+			s := strings.Replace(expr[i+1:end], "\\'", "'", -1)
+			tokens = append(tokens, token{typ: tRawString, value: s, pos: i})
+			i = end + 1
+		case c == '`':
+			end, err := closing(expr, i, '`')
+			if err != nil {
+				return nil, err
+			}
+			s := strings.Replace(expr[i+1:end], "\\`", "`", -1)
+			tokens = append(tokens, token{typ: tJSONLiteral, value: s, pos: i})
+			i = end + 1
+		default:
+			t, n := operator(expr[i:])
+			if n == 0 {
+				return nil, syntaxError(expr, i, fmt.Sprintf("unexpected character %q", c))
+			}
+			tokens = append(tokens, token{typ: t, value: expr[i : i+n], pos: i})
+			i += n
+		}
+	}
+	return append(tokens, token{typ: tEOF, pos: len(expr)}), nil
+}
+
+func operator(s string) (tokenType, int) {
+	two := map[string]tokenType{"||": tOr, "&&": tAnd, "==": tEQ, "!=": tNE, "<=": tLTE, ">=": tGTE}
+	if len(s) > 1 {
+		if t, has := two[s[:2]]; has {
+			return t, 2
+		}
+	}
+	switch s[0] {
+	case '|':
+		return tPipe, 1
+	case '!':
+		return tNot, 1
+	case '<':
+		return tLT, 1
+	case '>':
+		return tGT, 1
+	}
+	return tEOF, 0
+}
+
+// closing 查找未转义的结束符
+func closing(expr string, start int, quote byte) (int, error) {
+	for i := start + 1; i < len(expr); i++ {
+		switch expr[i] {
+		case '\\':
+			i++
+		case quote:
+			return i, nil
+		}
+	}
+	return 0, syntaxError(expr, start, "unclosed "+string(quote))
+}
+
+//SyntaxError 表达式语法错误
+type SyntaxError struct {
+	Expression string
+	Offset     int
+	Msg        string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at %d in %s", e.Msg, e.Offset, e.Expression)
+}
+
+func syntaxError(expr string, offset int, msg string) error {
+	return &SyntaxError{Expression: expr, Offset: offset, Msg: msg}
+}
@@ -0,0 +1,398 @@
+package jmespath
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// 左结合优先级，投影右侧遇到小于projectionStop的token时结束投影
+var bindingPowers = map[tokenType]int{
+	tPipe:     1,
+	tOr:       2,
+	tAnd:      3,
+	tEQ:       5,
+	tNE:       5,
+	tLT:       5,
+	tLTE:      5,
+	tGT:       5,
+	tGTE:      5,
+	tFlatten:  9,
+	tStar:     20,
+	tFilter:   21,
+	tDot:      40,
+	tNot:      45,
+	tLbrace:   50,
+	tLbracket: 55,
+	tLparen:   60,
+}
+
+const projectionStop = 10
+
+type parser struct {
+	expr   string
+	tokens []token
+	index  int
+}
+
+func parse(expr string) (node, error) {
+	tokens, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{expr: expr, tokens: tokens}
+	n, err := p.parseExpression(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.current() != tEOF {
+		return nil, p.errorf("unexpected token %q", p.tokens[p.index].value)
+	}
+	return n, nil
+}
+
+func (p *parser) current() tokenType {
+	return p.lookahead(0)
+}
+
+func (p *parser) lookahead(n int) tokenType {
+	if p.index+n >= len(p.tokens) {
+		return tEOF
+	}
+	return p.tokens[p.index+n].typ
+}
+
+func (p *parser) advance() token {
+	t := p.tokens[p.index]
+	if p.index < len(p.tokens)-1 {
+		p.index++
+	}
+	return t
+}
+
+func (p *parser) match(t tokenType) error {
+	if p.current() != t {
+		return p.errorf("unexpected token %q", p.tokens[p.index].value)
+	}
+	p.advance()
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return syntaxError(p.expr, p.tokens[p.index].pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) parseExpression(bp int) (node, error) {
+	left, err := p.nud(p.advance())
+	if err != nil {
+		return nil, err
+	}
+	for bp < bindingPowers[p.current()] {
+		left, err = p.led(p.advance().typ, left)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) nud(t token) (node, error) {
+	switch t.typ {
+	case tJSONLiteral:
+		var v interface{}
+		if err := json.Unmarshal([]byte(t.value), &v); err != nil {
+			return nil, syntaxError(p.expr, t.pos, "invalid json literal")
+		}
+		return literal{v}, nil
+	case tRawString:
+		return literal{t.value}, nil
+	case tUnquoted:
+		if p.current() == tLparen {
+			p.advance()
+			return p.parseFunction(t)
+		}
+		return field(t.value), nil
+	case tQuoted:
+		if p.current() == tLparen {
+			return nil, p.errorf("quoted identifier can not be a function name")
+		}
+		return field(t.value), nil
+	case tCurrent:
+		return current{}, nil
+	case tStar:
+		right, err := p.parseProjectionRHS(bindingPowers[tStar])
+		if err != nil {
+			return nil, err
+		}
+		return &valueProjection{left: current{}, right: right}, nil
+	case tFilter:
+		return p.parseFilter(current{})
+	case tFlatten:
+		right, err := p.parseProjectionRHS(bindingPowers[tFlatten])
+		if err != nil {
+			return nil, err
+		}
+		return &projection{left: &flatten{current{}}, right: right}, nil
+	case tLbrace:
+		return p.parseMultiSelectHash()
+	case tLbracket:
+		switch {
+		case p.current() == tNumber || p.current() == tColon:
+			right, err := p.parseIndexExpression()
+			if err != nil {
+				return nil, err
+			}
+			return p.projectIfSlice(current{}, right)
+		case p.current() == tStar && p.lookahead(1) == tRbracket:
+			p.advance()
+			p.advance()
+			right, err := p.parseProjectionRHS(bindingPowers[tStar])
+			if err != nil {
+				return nil, err
+			}
+			return &projection{left: current{}, right: right}, nil
+		}
+		return p.parseMultiSelectList()
+	case tNot:
+		n, err := p.parseExpression(bindingPowers[tNot])
+		if err != nil {
+			return nil, err
+		}
+		return &not{n}, nil
+	case tLparen:
+		n, err := p.parseExpression(0)
+		if err != nil {
+			return nil, err
+		}
+		return n, p.match(tRparen)
+	case tEOF:
+		return nil, syntaxError(p.expr, t.pos, "unexpected end of expression")
+	}
+	return nil, syntaxError(p.expr, t.pos, fmt.Sprintf("unexpected token %q", t.value))
+}
+
+func (p *parser) led(t tokenType, left node) (node, error) {
+	switch t {
+	case tDot:
+		if p.current() != tStar {
+			right, err := p.parseDotRHS(bindingPowers[tDot])
+			if err != nil {
+				return nil, err
+			}
+			return &subexpression{left: left, right: right}, nil
+		}
+		p.advance()
+		right, err := p.parseProjectionRHS(bindingPowers[tDot])
+		if err != nil {
+			return nil, err
+		}
+		return &valueProjection{left: left, right: right}, nil
+	case tPipe, tOr, tAnd:
+		right, err := p.parseExpression(bindingPowers[t])
+		if err != nil {
+			return nil, err
+		}
+		switch t {
+		case tPipe:
+			return &pipe{left: left, right: right}, nil
+		case tOr:
+			return &or{left: left, right: right}, nil
+		}
+		return &and{left: left, right: right}, nil
+	case tEQ, tNE, tLT, tLTE, tGT, tGTE:
+		right, err := p.parseExpression(bindingPowers[t])
+		if err != nil {
+			return nil, err
+		}
+		return &comparator{op: t, left: left, right: right}, nil
+	case tFilter:
+		return p.parseFilter(left)
+	case tFlatten:
+		right, err := p.parseProjectionRHS(bindingPowers[tFlatten])
+		if err != nil {
+			return nil, err
+		}
+		return &projection{left: &flatten{left}, right: right}, nil
+	case tLbracket:
+		if p.current() == tNumber || p.current() == tColon {
+			right, err := p.parseIndexExpression()
+			if err != nil {
+				return nil, err
+			}
+			return p.projectIfSlice(left, right)
+		}
+		if err := p.match(tStar); err != nil {
+			return nil, err
+		}
+		if err := p.match(tRbracket); err != nil {
+			return nil, err
+		}
+		right, err := p.parseProjectionRHS(bindingPowers[tStar])
+		if err != nil {
+			return nil, err
+		}
+		return &projection{left: left, right: right}, nil
+	}
+	return nil, p.errorf("unexpected token")
+}
+
+func (p *parser) parseFunction(name token) (node, error) {
+	fn, has := functions[name.value]
+	if !has {
+		return nil, syntaxError(p.expr, name.pos, "unknown function "+name.value)
+	}
+	args := make([]node, 0, 2)
+	for p.current() != tRparen {
+		arg, err := p.parseExpression(0)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if p.current() == tComma {
+			p.advance()
+		} else if p.current() != tRparen {
+			return nil, p.errorf("expected , or )")
+		}
+	}
+	p.advance()
+	if len(args) < fn.min || (fn.max >= 0 && len(args) > fn.max) {
+		return nil, syntaxError(p.expr, name.pos, "invalid number of arguments for "+name.value)
+	}
+	return &function{name: name.value, fn: fn, args: args}, nil
+}
+
+func (p *parser) parseFilter(left node) (node, error) {
+	condition, err := p.parseExpression(0)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.match(tRbracket); err != nil {
+		return nil, err
+	}
+	var right node = current{}
+	if p.current() != tFlatten {
+		right, err = p.parseProjectionRHS(bindingPowers[tFilter])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &filterProjection{left: left, condition: condition, right: right}, nil
+}
+
+func (p *parser) parseIndexExpression() (node, error) {
+	if p.lookahead(0) == tColon || p.lookahead(1) == tColon {
+		return p.parseSliceExpression()
+	}
+	i, err := strconv.Atoi(p.advance().value)
+	if err != nil {
+		return nil, p.errorf("invalid index")
+	}
+	return index(i), p.match(tRbracket)
+}
+
+func (p *parser) parseSliceExpression() (node, error) {
+	var parts [3]*int
+	i := 0
+	for p.current() != tRbracket && i < 3 {
+		switch p.current() {
+		case tColon:
+			i++
+			p.advance()
+		case tNumber:
+			n, err := strconv.Atoi(p.advance().value)
+			if err != nil {
+				return nil, p.errorf("invalid slice")
+			}
+			parts[i] = &n
+		default:
+			return nil, p.errorf("invalid slice")
+		}
+	}
+	if parts[2] != nil && *parts[2] == 0 {
+		return nil, p.errorf("slice step can not be 0")
+	}
+	return &slice{start: parts[0], stop: parts[1], step: parts[2]}, p.match(tRbracket)
+}
+
+func (p *parser) projectIfSlice(left, right node) (node, error) {
+	expr := &subexpression{left: left, right: right}
+	if _, ok := right.(*slice); !ok {
+		return expr, nil
+	}
+	rhs, err := p.parseProjectionRHS(bindingPowers[tStar])
+	if err != nil {
+		return nil, err
+	}
+	return &projection{left: expr, right: rhs}, nil
+}
+
+func (p *parser) parseProjectionRHS(bp int) (node, error) {
+	switch {
+	case bindingPowers[p.current()] < projectionStop:
+		return current{}, nil
+	case p.current() == tLbracket || p.current() == tFilter:
+		return p.parseExpression(bp)
+	case p.current() == tDot:
+		p.advance()
+		return p.parseDotRHS(bp)
+	}
+	return nil, p.errorf("unexpected token %q", p.tokens[p.index].value)
+}
+
+func (p *parser) parseDotRHS(bp int) (node, error) {
+	switch p.current() {
+	case tUnquoted, tQuoted, tStar:
+		return p.parseExpression(bp)
+	case tLbracket:
+		p.advance()
+		return p.parseMultiSelectList()
+	case tLbrace:
+		p.advance()
+		return p.parseMultiSelectHash()
+	}
+	return nil, p.errorf("expected identifier, [ or { after .")
+}
+
+func (p *parser) parseMultiSelectList() (node, error) {
+	list := make(multiSelectList, 0, 2)
+	for {
+		n, err := p.parseExpression(0)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, n)
+		if p.current() == tRbracket {
+			p.advance()
+			return list, nil
+		}
+		if err := p.match(tComma); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseMultiSelectHash() (node, error) {
+	hash := &multiSelectHash{}
+	for {
+		key := p.advance()
+		if key.typ != tUnquoted && key.typ != tQuoted {
+			return nil, syntaxError(p.expr, key.pos, "expected key name")
+		}
+		if err := p.match(tColon); err != nil {
+			return nil, err
+		}
+		n, err := p.parseExpression(0)
+		if err != nil {
+			return nil, err
+		}
+		hash.keys = append(hash.keys, key.value)
+		hash.values = append(hash.values, n)
+		if p.current() == tRbrace {
+			p.advance()
+			return hash, nil
+		}
+		if err := p.match(tComma); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,451 @@
+package json_schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//Schema 解析后的JSON Schema
+// 支持type、enum、const、properties、required、additionalProperties、items、
+// minimum、maximum、exclusiveMinimum、exclusiveMaximum、multipleOf、minLength、maxLength、pattern、
+// minItems、maxItems、uniqueItems、minProperties、maxProperties、allOf、anyOf、oneOf、not
+type Schema struct {
+	always *bool
+
+	types    []string
+	enum     []interface{}
+	constant *interface{}
+
+	properties           map[string]*Schema
+	required             []string
+	additionalProperties *Schema
+	minProperties        *int
+	maxProperties        *int
+
+	items       *Schema
+	minItems    *int
+	maxItems    *int
+	uniqueItems bool
+
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	multipleOf       *float64
+
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+
+	allOf []*Schema
+	anyOf []*Schema
+	oneOf []*Schema
+	not   *Schema
+}
+
+//Errors 校验失败的位置及原因
+type Errors []string
+
+func (e Errors) Error() string {
+	return strings.Join(e, "; ")
+}
+
+//Compile 解析JSON Schema
+func Compile(data []byte) (*Schema, error) {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return parse(v, "#")
+}
+
+//Validate 校验json数据
+func (s *Schema) Validate(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return errors.New("invalid json: unexpected data after top-level value")
+	}
+	return s.ValidateValue(v)
+}
+
+//ValidateValue 校验json.Unmarshal得到的值
+func (s *Schema) ValidateValue(v interface{}) error {
+	errs := s.validate(v, "", nil)
+	if len(errs) > 0 {
+		return Errors(errs)
+	}
+	return nil
+}
+
+func parse(v interface{}, path string) (*Schema, error) {
+	switch v := v.(type) {
+	case bool:
+		return &Schema{always: &v}, nil
+	case map[string]interface{}:
+		return parseObject(v, path)
+	}
+	return nil, fmt.Errorf("%s: schema must be an object or boolean", path)
+}
+
+func parseObject(m map[string]interface{}, path string) (*Schema, error) {
+	s := new(Schema)
+	var err error
+
+	if t, has := m["type"]; has {
+		switch t := t.(type) {
+		case string:
+			s.types = []string{t}
+		case []interface{}:
+			for _, i := range t {
+				name, ok := i.(string)
+				if !ok {
+					return nil, fmt.Errorf("%s/type: must be string or array of string", path)
+				}
+				s.types = append(s.types, name)
+			}
+		default:
+			return nil, fmt.Errorf("%s/type: must be string or array of string", path)
+		}
+		for _, t := range s.types {
+			switch t {
+			case "null", "boolean", "object", "array", "number", "integer", "string":
+			default:
+				return nil, fmt.Errorf("%s/type: unknown type %s", path, t)
+			}
+		}
+	}
+	if e, has := m["enum"]; has {
+		list, ok := e.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s/enum: must be array", path)
+		}
+		s.enum = list
+	}
+	if c, has := m["const"]; has {
+		s.constant = &c
+	}
+
+	if p, has := m["properties"]; has {
+		props, ok := p.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s/properties: must be object", path)
+		}
+		s.properties = make(map[string]*Schema, len(props))
+		for name, sub := range props {
+			s.properties[name], err = parse(sub, path+"/properties/"+name)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if r, has := m["required"]; has {
+		list, ok := r.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s/required: must be array of string", path)
+		}
+		for _, i := range list {
+			name, ok := i.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s/required: must be array of string", path)
+			}
+			s.required = append(s.required, name)
+		}
+	}
+	if a, has := m["additionalProperties"]; has {
+		if s.additionalProperties, err = parse(a, path+"/additionalProperties"); err != nil {
+			return nil, err
+		}
+	}
+	if i, has := m["items"]; has {
+		if s.items, err = parse(i, path+"/items"); err != nil {
+			return nil, err
+		}
+	}
+	if n, has := m["not"]; has {
+		if s.not, err = parse(n, path+"/not"); err != nil {
+			return nil, err
+		}
+	}
+	for key, target := range map[string]*[]*Schema{"allOf": &s.allOf, "anyOf": &s.anyOf, "oneOf": &s.oneOf} {
+		l, has := m[key]
+		if !has {
+			continue
+		}
+		list, ok := l.([]interface{})
+		if !ok || len(list) == 0 {
+			return nil, fmt.Errorf("%s/%s: must be non-empty array", path, key)
+		}
+		for i, sub := range list {
+			schema, err := parse(sub, fmt.Sprintf("%s/%s/%d", path, key, i))
+			if err != nil {
+				return nil, err
+			}
+			*target = append(*target, schema)
+		}
+	}
+
+	for key, target := range map[string]**float64{
+		"minimum": &s.minimum, "maximum": &s.maximum,
+		"exclusiveMinimum": &s.exclusiveMinimum, "exclusiveMaximum": &s.exclusiveMaximum,
+		"multipleOf": &s.multipleOf,
+	} {
+		n, has := m[key]
+		if !has {
+			continue
+		}
+		f, ok := n.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%s/%s: must be number", path, key)
+		}
+		*target = &f
+	}
+	if s.multipleOf != nil && *s.multipleOf <= 0 {
+		return nil, fmt.Errorf("%s/multipleOf: must be greater than 0", path)
+	}
+	for key, target := range map[string]**int{
+		"minLength": &s.minLength, "maxLength": &s.maxLength,
+		"minItems": &s.minItems, "maxItems": &s.maxItems,
+		"minProperties": &s.minProperties, "maxProperties": &s.maxProperties,
+	} {
+		n, has := m[key]
+		if !has {
+			continue
+		}
+		f, ok := n.(float64)
+		if !ok || f < 0 || f != math.Trunc(f) {
+			return nil, fmt.Errorf("%s/%s: must be non-negative integer", path, key)
+		}
+		i := int(f)
+		*target = &i
+	}
+	if u, has := m["uniqueItems"]; has {
+		b, ok := u.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s/uniqueItems: must be boolean", path)
+		}
+		s.uniqueItems = b
+	}
+	if p, has := m["pattern"]; has {
+		expr, ok := p.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s/pattern: must be string", path)
+		}
+		if s.pattern, err = regexp.Compile(expr); err != nil {
+			return nil, fmt.Errorf("%s/pattern: %s", path, err)
+		}
+	}
+	return s, nil
+}
+
+func typeOf(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	}
+	return "unknown"
+}
+
+func matchType(types []string, v interface{}) bool {
+	t := typeOf(v)
+	for _, name := range types {
+		if name == t || (name == "number" && t == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func equal(a, b interface{}) bool {
+	x, _ := json.Marshal(a)
+	y, _ := json.Marshal(b)
+	return bytes.Equal(x, y)
+}
+
+func location(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+func (s *Schema) validate(v interface{}, path string, errs []string) []string {
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, location(path)+": "+fmt.Sprintf(format, args...))
+	}
+	if s.always != nil {
+		if !*s.always {
+			fail("not allowed")
+		}
+		return errs
+	}
+	if len(s.types) > 0 && !matchType(s.types, v) {
+		fail("expected %s, got %s", strings.Join(s.types, " or "), typeOf(v))
+		return errs
+	}
+	if s.enum != nil {
+		found := false
+		for _, e := range s.enum {
+			if equal(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("must be one of the enum values")
+		}
+	}
+	if s.constant != nil && !equal(*s.constant, v) {
+		fail("must be equal to const value")
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		errs = s.validateObject(v, path, errs)
+	case []interface{}:
+		errs = s.validateArray(v, path, errs)
+	case float64:
+		errs = s.validateNumber(v, path, errs)
+	case string:
+		n := len([]rune(v))
+		if s.minLength != nil && n < *s.minLength {
+			fail("length must be >= %d", *s.minLength)
+		}
+		if s.maxLength != nil && n > *s.maxLength {
+			fail("length must be <= %d", *s.maxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			fail("must match pattern %s", s.pattern.String())
+		}
+	}
+
+	for _, sub := range s.allOf {
+		errs = sub.validate(v, path, errs)
+	}
+	if len(s.anyOf) > 0 {
+		matched := false
+		for _, sub := range s.anyOf {
+			if len(sub.validate(v, path, nil)) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("must match at least one schema of anyOf")
+		}
+	}
+	if len(s.oneOf) > 0 {
+		count := 0
+		for _, sub := range s.oneOf {
+			if len(sub.validate(v, path, nil)) == 0 {
+				count++
+			}
+		}
+		if count != 1 {
+			fail("must match exactly one schema of oneOf, matched %d", count)
+		}
+	}
+	if s.not != nil && len(s.not.validate(v, path, nil)) == 0 {
+		fail("must not match schema of not")
+	}
+	return errs
+}
+
+func (s *Schema) validateObject(v map[string]interface{}, path string, errs []string) []string {
+	for _, name := range s.required {
+		if _, has := v[name]; !has {
+			errs = append(errs, location(path)+": missing required property "+name)
+		}
+	}
+	if s.minProperties != nil && len(v) < *s.minProperties {
+		errs = append(errs, fmt.Sprintf("%s: must have at least %d properties", location(path), *s.minProperties))
+	}
+	if s.maxProperties != nil && len(v) > *s.maxProperties {
+		errs = append(errs, fmt.Sprintf("%s: must have at most %d properties", location(path), *s.maxProperties))
+	}
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if sub, has := s.properties[name]; has {
+			errs = sub.validate(v[name], path+"/"+name, errs)
+			continue
+		}
+		if s.additionalProperties != nil {
+			errs = s.additionalProperties.validate(v[name], path+"/"+name, errs)
+		}
+	}
+	return errs
+}
+
+func (s *Schema) validateArray(v []interface{}, path string, errs []string) []string {
+	if s.minItems != nil && len(v) < *s.minItems {
+		errs = append(errs, fmt.Sprintf("%s: must have at least %d items", location(path), *s.minItems))
+	}
+	if s.maxItems != nil && len(v) > *s.maxItems {
+		errs = append(errs, fmt.Sprintf("%s: must have at most %d items", location(path), *s.maxItems))
+	}
+	if s.uniqueItems {
+		seen := make(map[string]bool, len(v))
+		for _, item := range v {
+			key, _ := json.Marshal(item)
+			if seen[string(key)] {
+				errs = append(errs, location(path)+": items must be unique")
+				break
+			}
+			seen[string(key)] = true
+		}
+	}
+	if s.items != nil {
+		for i, item := range v {
+			errs = s.items.validate(item, fmt.Sprintf("%s/%d", path, i), errs)
+		}
+	}
+	return errs
+}
+
+func (s *Schema) validateNumber(v float64, path string, errs []string) []string {
+	if s.minimum != nil && v < *s.minimum {
+		errs = append(errs, fmt.Sprintf("%s: must be >= %v", location(path), *s.minimum))
+	}
+	if s.maximum != nil && v > *s.maximum {
+		errs = append(errs, fmt.Sprintf("%s: must be <= %v", location(path), *s.maximum))
+	}
+	if s.exclusiveMinimum != nil && v <= *s.exclusiveMinimum {
+		errs = append(errs, fmt.Sprintf("%s: must be > %v", location(path), *s.exclusiveMinimum))
+	}
+	if s.exclusiveMaximum != nil && v >= *s.exclusiveMaximum {
+		errs = append(errs, fmt.Sprintf("%s: must be < %v", location(path), *s.exclusiveMaximum))
+	}
+	if s.multipleOf != nil {
+		q := v / *s.multipleOf
+		if math.Abs(q-math.Round(q)) > 1e-9 {
+			errs = append(errs, fmt.Sprintf("%s: must be a multiple of %v", location(path), *s.multipleOf))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,62 @@
+package json_schema
+
+import (
+	"strings"
+	"testing"
+)
+
+const testSchema = `{
+	"type": "object",
+	"required": ["ipListType"],
+	"additionalProperties": false,
+	"properties": {
+		"ipListType": {"enum": ["white", "black", "none"]},
+		"ipWhiteList": {"type": "array", "items": {"type": "string", "pattern": "^[0-9./*]+$"}, "uniqueItems": true},
+		"limit": {"type": "integer", "minimum": 1, "maximum": 100}
+	}
+}`
+
+func TestValidate(t *testing.T) {
+	s, err := Compile([]byte(testSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		data string
+		errs []string
+	}{
+		{`{"ipListType":"white","ipWhiteList":["127.0.0.1"],"limit":10}`, nil},
+		{`{"ipWhiteList":["127.0.0.1"]}`, []string{"/: missing required property ipListType"}},
+		{`{"ipListType":"gray"}`, []string{"/ipListType: must be one of the enum values"}},
+		{`{"ipListType":"none","limit":1.5}`, []string{"/limit: expected integer, got number"}},
+		{`{"ipListType":"none","limit":0}`, []string{"/limit: must be >= 1"}},
+		{`{"ipListType":"none","ipWhiteList":["a","a"]}`, []string{"/ipWhiteList: items must be unique", "/ipWhiteList/0: must match pattern"}},
+		{`{"ipListType":"none","other":1}`, []string{"/other: not allowed"}},
+	}
+	for _, c := range cases {
+		err := s.Validate([]byte(c.data))
+		if len(c.errs) == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", c.data, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%s: expected error", c.data)
+			continue
+		}
+		for _, e := range c.errs {
+			if !strings.Contains(err.Error(), e) {
+				t.Errorf("%s: error %q does not contain %q", c.data, err, e)
+			}
+		}
+	}
+}
+
+func TestCompileError(t *testing.T) {
+	for _, schema := range []string{`{"type":"float"}`, `{"pattern":"("}`, `{"minLength":-1}`, `[]`} {
+		if _, err := Compile([]byte(schema)); err == nil {
+			t.Errorf("%s: expected compile error", schema)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package wsdl
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	nsWSDL   = "http://schemas.xmlsoap.org/wsdl/"
+	nsSOAP11 = "http://schemas.xmlsoap.org/wsdl/soap/"
+	nsSOAP12 = "http://schemas.xmlsoap.org/wsdl/soap12/"
+
+	//SOAP11 SOAP 1.1
+	SOAP11 = "1.1"
+	//SOAP12 SOAP 1.2
+	SOAP12 = "1.2"
+)
+
+var (
+	//ErrNoOperation WSDL中没有可用的SOAP操作
+	ErrNoOperation = errors.New("wsdl: no soap operation found")
+)
+
+//Document 解析后的WSDL
+type Document struct {
+	TargetNamespace string       `json:"targetNamespace"`
+	Operations      []*Operation `json:"operations"`
+}
+
+//Operation SOAP操作，同一操作在SOAP 1.1及1.2绑定中各有一项
+type Operation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Action  string `json:"action"`
+	Style   string `json:"style"`
+	//Element 请求体中的根元素名称，document风格为输入消息part的element，rpc风格为操作名
+	Element string `json:"element"`
+	//Namespace 根元素的命名空间
+	Namespace string `json:"namespace"`
+	//Qualified 子元素是否需要带命名空间（schema的elementFormDefault为qualified）
+	Qualified bool `json:"qualified"`
+	//Location 服务地址
+	Location string `json:"location,omitempty"`
+}
+
+//Path 服务地址的路径部分
+func (o *Operation) Path() string {
+	u, err := url.Parse(o.Location)
+	if err != nil {
+		return ""
+	}
+	return u.RequestURI()
+}
+
+//Find 按名称及版本查找操作，版本为空时优先返回SOAP 1.1
+func (d *Document) Find(name, version string) (*Operation, error) {
+	var found *Operation
+	for _, o := range d.Operations {
+		if o.Name != name || (version != "" && o.Version != version) {
+			continue
+		}
+		if found == nil || o.Version == SOAP11 {
+			found = o
+		}
+	}
+	if found == nil {
+		if version == "" {
+			return nil, fmt.Errorf("wsdl: operation %s not found", name)
+		}
+		return nil, fmt.Errorf("wsdl: operation %s for soap %s not found", name, version)
+	}
+	return found, nil
+}
+
+type definitions struct {
+	TargetNamespace string     `xml:"targetNamespace,attr"`
+	Attrs           []xml.Attr `xml:",any,attr"`
+	Schemas         []struct {
+		TargetNamespace    string `xml:"targetNamespace,attr"`
+		ElementFormDefault string `xml:"elementFormDefault,attr"`
+	} `xml:"types>schema"`
+	Messages []struct {
+		Name  string `xml:"name,attr"`
+		Parts []struct {
+			Name    string `xml:"name,attr"`
+			Element string `xml:"element,attr"`
+		} `xml:"http://schemas.xmlsoap.org/wsdl/ part"`
+	} `xml:"http://schemas.xmlsoap.org/wsdl/ message"`
+	PortTypes []struct {
+		Name       string `xml:"name,attr"`
+		Operations []struct {
+			Name  string `xml:"name,attr"`
+			Input struct {
+				Message string `xml:"message,attr"`
+			} `xml:"http://schemas.xmlsoap.org/wsdl/ input"`
+		} `xml:"http://schemas.xmlsoap.org/wsdl/ operation"`
+	} `xml:"http://schemas.xmlsoap.org/wsdl/ portType"`
+	Bindings []binding `xml:"http://schemas.xmlsoap.org/wsdl/ binding"`
+	Services []struct {
+		Ports []struct {
+			Binding   string   `xml:"binding,attr"`
+			Address   *address `xml:"http://schemas.xmlsoap.org/wsdl/soap/ address"`
+			Address12 *address `xml:"http://schemas.xmlsoap.org/wsdl/soap12/ address"`
+		} `xml:"http://schemas.xmlsoap.org/wsdl/ port"`
+	} `xml:"http://schemas.xmlsoap.org/wsdl/ service"`
+}
+
+type address struct {
+	Location string `xml:"location,attr"`
+}
+
+type soapBinding struct {
+	Style string `xml:"style,attr"`
+}
+
+type soapOperation struct {
+	Action string `xml:"soapAction,attr"`
+	Style  string `xml:"style,attr"`
+}
+
+type soapBody struct {
+	Namespace string `xml:"namespace,attr"`
+}
+
+type binding struct {
+	Name          string       `xml:"name,attr"`
+	Type          string       `xml:"type,attr"`
+	SOAPBinding   *soapBinding `xml:"http://schemas.xmlsoap.org/wsdl/soap/ binding"`
+	SOAP12Binding *soapBinding `xml:"http://schemas.xmlsoap.org/wsdl/soap12/ binding"`
+	Operations    []struct {
+		Name            string         `xml:"name,attr"`
+		SOAPOperation   *soapOperation `xml:"http://schemas.xmlsoap.org/wsdl/soap/ operation"`
+		SOAP12Operation *soapOperation `xml:"http://schemas.xmlsoap.org/wsdl/soap12/ operation"`
+		Input           struct {
+			SOAPBody   *soapBody `xml:"http://schemas.xmlsoap.org/wsdl/soap/ body"`
+			SOAP12Body *soapBody `xml:"http://schemas.xmlsoap.org/wsdl/soap12/ body"`
+		} `xml:"http://schemas.xmlsoap.org/wsdl/ input"`
+	} `xml:"http://schemas.xmlsoap.org/wsdl/ operation"`
+}
+
+// localName 去掉QName的前缀
+func localName(qname string) string {
+	if i := strings.Index(qname, ":"); i >= 0 {
+		return qname[i+1:]
+	}
+	return qname
+}
+
+//Parse 解析WSDL 1.1文档，提取SOAP 1.1及1.2绑定的操作
+func Parse(data []byte) (*Document, error) {
+	def := new(definitions)
+	if err := xml.Unmarshal(data, def); err != nil {
+		return nil, err
+	}
+	prefixes := make(map[string]string)
+	for _, attr := range def.Attrs {
+		if attr.Name.Space == "xmlns" {
+			prefixes[attr.Name.Local] = attr.Value
+		} else if attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+			prefixes[""] = attr.Value
+		}
+	}
+	namespaceOf := func(qname string) string {
+		prefix := ""
+		if i := strings.Index(qname, ":"); i >= 0 {
+			prefix = qname[:i]
+		}
+		if ns, has := prefixes[prefix]; has && ns != nsWSDL {
+			return ns
+		}
+		return def.TargetNamespace
+	}
+	qualified := make(map[string]bool)
+	for _, s := range def.Schemas {
+		qualified[s.TargetNamespace] = s.ElementFormDefault == "qualified"
+	}
+	// 操作名 -> 输入消息第一个part的element
+	inputs := make(map[string]map[string]string)
+	for _, pt := range def.PortTypes {
+		ops := make(map[string]string)
+		for _, op := range pt.Operations {
+			message := localName(op.Input.Message)
+			for _, m := range def.Messages {
+				if m.Name == message && len(m.Parts) > 0 {
+					ops[op.Name] = m.Parts[0].Element
+				}
+			}
+		}
+		inputs[pt.Name] = ops
+	}
+	locations := make(map[string]string)
+	for _, s := range def.Services {
+		for _, p := range s.Ports {
+			switch {
+			case p.Address != nil:
+				locations[localName(p.Binding)] = p.Address.Location
+			case p.Address12 != nil:
+				locations[localName(p.Binding)] = p.Address12.Location
+			}
+		}
+	}
+
+	doc := &Document{TargetNamespace: def.TargetNamespace}
+	for _, b := range def.Bindings {
+		version, bindingStyle := SOAP11, ""
+		switch {
+		case b.SOAPBinding != nil:
+			bindingStyle = b.SOAPBinding.Style
+		case b.SOAP12Binding != nil:
+			version, bindingStyle = SOAP12, b.SOAP12Binding.Style
+		default:
+			continue
+		}
+		for _, op := range b.Operations {
+			o := &Operation{
+				Name:     op.Name,
+				Version:  version,
+				Style:    bindingStyle,
+				Location: locations[b.Name],
+			}
+			soapOp, body := op.SOAPOperation, op.Input.SOAPBody
+			if version == SOAP12 {
+				soapOp, body = op.SOAP12Operation, op.Input.SOAP12Body
+			}
+			if soapOp != nil {
+				o.Action = soapOp.Action
+				if soapOp.Style != "" {
+					o.Style = soapOp.Style
+				}
+			}
+			if o.Style == "" {
+				o.Style = "document"
+			}
+			element := inputs[localName(b.Type)][op.Name]
+			if o.Style == "rpc" || element == "" {
+				o.Element, o.Namespace = op.Name, def.TargetNamespace
+				if body != nil && body.Namespace != "" {
+					o.Namespace = body.Namespace
+				}
+			} else {
+				o.Element, o.Namespace = localName(element), namespaceOf(element)
+				o.Qualified = qualified[o.Namespace]
+			}
+			doc.Operations = append(doc.Operations, o)
+		}
+	}
+	if len(doc.Operations) == 0 {
+		return nil, ErrNoOperation
+	}
+	return doc, nil
+}
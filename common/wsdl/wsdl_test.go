@@ -0,0 +1,76 @@
+package wsdl
+
+import "testing"
+
+const testWSDL = `<?xml version="1.0" encoding="utf-8"?>
+<wsdl:definitions xmlns:wsdl="http://schemas.xmlsoap.org/wsdl/" xmlns:soap="http://schemas.xmlsoap.org/wsdl/soap/"
+	xmlns:soap12="http://schemas.xmlsoap.org/wsdl/soap12/" xmlns:s="http://www.w3.org/2001/XMLSchema"
+	xmlns:tns="http://example.com/user" targetNamespace="http://example.com/user">
+	<wsdl:types>
+		<s:schema elementFormDefault="qualified" targetNamespace="http://example.com/user">
+			<s:element name="GetUser"/>
+			<s:element name="GetUserResponse"/>
+		</s:schema>
+	</wsdl:types>
+	<wsdl:message name="GetUserSoapIn"><wsdl:part name="parameters" element="tns:GetUser"/></wsdl:message>
+	<wsdl:message name="GetUserSoapOut"><wsdl:part name="parameters" element="tns:GetUserResponse"/></wsdl:message>
+	<wsdl:portType name="UserSoap">
+		<wsdl:operation name="GetUser">
+			<wsdl:input message="tns:GetUserSoapIn"/>
+			<wsdl:output message="tns:GetUserSoapOut"/>
+		</wsdl:operation>
+	</wsdl:portType>
+	<wsdl:binding name="UserSoap" type="tns:UserSoap">
+		<soap:binding transport="http://schemas.xmlsoap.org/soap/http"/>
+		<wsdl:operation name="GetUser">
+			<soap:operation soapAction="http://example.com/user/GetUser" style="document"/>
+			<wsdl:input><soap:body use="literal"/></wsdl:input>
+		</wsdl:operation>
+	</wsdl:binding>
+	<wsdl:binding name="UserSoap12" type="tns:UserSoap">
+		<soap12:binding transport="http://schemas.xmlsoap.org/soap/http" style="rpc"/>
+		<wsdl:operation name="GetUser">
+			<soap12:operation soapAction="urn:GetUser"/>
+			<wsdl:input><soap12:body use="literal" namespace="urn:user"/></wsdl:input>
+		</wsdl:operation>
+	</wsdl:binding>
+	<wsdl:service name="User">
+		<wsdl:port name="UserSoap" binding="tns:UserSoap"><soap:address location="http://127.0.0.1:8080/user.asmx"/></wsdl:port>
+		<wsdl:port name="UserSoap12" binding="tns:UserSoap12"><soap12:address location="http://127.0.0.1:8080/user12.asmx"/></wsdl:port>
+	</wsdl:service>
+</wsdl:definitions>`
+
+func TestParse(t *testing.T) {
+	doc, err := Parse([]byte(testWSDL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Operations) != 2 {
+		t.Fatalf("expected 2 operations, got %d", len(doc.Operations))
+	}
+	op, err := doc.Find("GetUser", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Operation{Name: "GetUser", Version: SOAP11, Action: "http://example.com/user/GetUser", Style: "document", Element: "GetUser", Namespace: "http://example.com/user", Qualified: true, Location: "http://127.0.0.1:8080/user.asmx"}
+	if *op != want {
+		t.Errorf("soap 1.1: got %+v", *op)
+	}
+	if op.Path() != "/user.asmx" {
+		t.Errorf("path: got %s", op.Path())
+	}
+	op, err = doc.Find("GetUser", SOAP12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = Operation{Name: "GetUser", Version: SOAP12, Action: "urn:GetUser", Style: "rpc", Element: "GetUser", Namespace: "urn:user", Location: "http://127.0.0.1:8080/user12.asmx"}
+	if *op != want {
+		t.Errorf("soap 1.2: got %+v", *op)
+	}
+	if _, err := doc.Find("DeleteUser", ""); err == nil {
+		t.Error("expected error for unknown operation")
+	}
+	if _, err := Parse([]byte(`<definitions/>`)); err != ErrNoOperation {
+		t.Errorf("expected ErrNoOperation, got %v", err)
+	}
+}
@@ -12,6 +12,7 @@ type GokuConfig struct {
 	DiscoverConfig      map[string]*DiscoverConfig `json:"discover,omitempty"`
 	Balance             map[string]*BalanceConfig  `json:"balance,omitempty"`
 	Plugins             GatewayPluginConfig        `json:"plugins,omitempty"`
+	Scripts             map[string]*PluginScript   `json:"scripts,omitempty"`
 	APIS                []*APIContent              `json:"apis,omitempty"`
 	Strategy            []*StrategyConfig          `json:"strategy,omitempty"`
 	AnonymousStrategyID string                     `json:"anonymousStrategyID,omitempty"`
@@ -41,13 +42,33 @@ type RouterRule struct {
 
 //AccessLogConfig access日志配置
 type AccessLogConfig struct {
-	Name   string   `json:"name"`
-	Enable int      `json:"enable"`
-	Dir    string   `json:"dir"`
-	File   string   `json:"file"`
-	Period string   `json:"period"`
-	Expire int      `json:"expire"`
-	Fields []string `json:"fields"`
+	Name   string           `json:"name"`
+	Enable int              `json:"enable"`
+	Dir    string           `json:"dir"`
+	File   string           `json:"file"`
+	Period string           `json:"period"`
+	Expire int              `json:"expire"`
+	Fields []string         `json:"fields"`
+	Format string           `json:"format,omitempty"`
+	Sinks  []*AccessLogSink `json:"sinks,omitempty"`
+}
+
+//AccessLogSink access日志的额外输出目标
+type AccessLogSink struct {
+	//Type 输出类型：syslog、http、kafka
+	Type string `json:"type"`
+	//Network syslog使用的协议：udp、tcp
+	Network string `json:"network,omitempty"`
+	//Address syslog、kafka为host:port，http为完整url
+	Address   string `json:"address"`
+	Topic     string `json:"topic,omitempty"`
+	Partition int    `json:"partition,omitempty"`
+	//Tag syslog的APP-NAME
+	Tag string `json:"tag,omitempty"`
+	//BufferSize 缓冲队列长度，队列满时丢弃
+	BufferSize int `json:"bufferSize,omitempty"`
+	//BatchSize 单次发送的最大条数
+	BatchSize int `json:"batchSize,omitempty"`
 }
 
 //LogConfig log日志配置
@@ -82,6 +103,7 @@ type HealthCheckConfig struct {
 	Second        int    `json:"second"`
 	TimeOutMill   int    `json:"timeoutMill"`
 	StatusCode    string `json:"statusCode"`
+	HealthCheckOptions
 }
 
 //BalanceConfig 负载配置
@@ -89,6 +111,10 @@ type BalanceConfig struct {
 	Name         string `json:"name"`
 	DiscoverName string `json:"discover"`
 	Config       string `json:"config"` // appName(for discovery) or  address (for static)
+	//Subset 实例子集
+	Subset *SubsetConfig `json:"subset,omitempty"`
+	//Transport 转发连接配置
+	Transport *TransportConfig `json:"transport,omitempty"`
 }
 
 //PluginConfig 插件配置
@@ -98,6 +124,24 @@ type PluginConfig struct {
 	Config    string `json:"config"`
 	UpdateTag string `json:"updateTag"`
 	IsAuth    bool   `json:"isAuth"`
+	//Policy 插件执行出错时的处理策略，为空时按IsStop处理
+	Policy *PluginPolicy `json:"policy,omitempty"`
+}
+
+//PluginScript JavaScript插件
+type PluginScript struct {
+	Source string `json:"source"`
+	//Timeout 单次执行的期限，按实际经过的时间计算，包含调用原生方法的耗时，单位毫秒，0为默认值
+	Timeout int `json:"timeout,omitempty"`
+	//MemoryLimit 脚本变量占用的内存上限，单位MB，0为默认值
+	MemoryLimit int `json:"memoryLimit,omitempty"`
+}
+
+//PluginPackage 控制台分发的插件包，节点本地插件包校验不一致时从控制台拉取
+type PluginPackage struct {
+	Version string `json:"version"`
+	//Checksum 插件包的sha256
+	Checksum string `json:"checksum"`
 }
 
 //APIContent api详情
@@ -111,10 +155,22 @@ type APIContent struct {
 	Methods        []string         `json:"methods"`
 	TimeOutTotal   int              `json:"timeoutTotal"`
 	AlertThreshold int              `json:"alert_threshold"`
+	Priority       int              `json:"priority,omitempty"`
 	Steps          []*APIStepConfig `json:"steps"`
 
 	StaticResponseStrategy string `json:"static_respone_strategy"`
 	StaticResponse         string `json:"staticResponse"`
+
+	//Transform 请求及响应转换
+	Transform *TransformConfig `json:"transform,omitempty"`
+	//Traffic 流量拆分
+	Traffic *TrafficConfig `json:"traffic,omitempty"`
+	//Mirror 流量镜像
+	Mirror *MirrorConfig `json:"mirror,omitempty"`
+	//RetryPolicy 重试策略
+	RetryPolicy *RetryConfig `json:"retryPolicy,omitempty"`
+	//Hedge 对冲请求
+	Hedge *HedgeConfig `json:"hedge,omitempty"`
 }
 
 //APIStepConfig 链路配置
@@ -125,8 +181,8 @@ type APIStepConfig struct {
 	Path    string   `json:"path"`
 	Body    string   `json:"body"`
 	Headers []string `json:"headers,omitempty"`
-	Decode  string   `json:"decode"` // origin | json
-	Encode  string   `json:"encode"` // origin | form | json
+	Decode  string   `json:"decode"` // origin | json | json-noquote | string | xml | form | multipart | msgpack
+	Encode  string   `json:"encode"` // origin | json | string | xml | form | multipart | msgpack
 
 	Actions   []*ActionConfig `json:"actions"`
 	BlackList []string        `json:"blackList"`
@@ -136,6 +192,8 @@ type APIStepConfig struct {
 	Group   string `json:"group"`
 	Retry   int    `json:"retry"`
 	TimeOut int    `json:"timeout"`
+
+	SOAP *SOAPConfig `json:"soap,omitempty"`
 }
 
 //APIStepUIConfig 链路UI配置
@@ -146,8 +204,8 @@ type APIStepUIConfig struct {
 	Path    string   `json:"path"`
 	Body    string   `json:"body"`
 	Headers []string `json:"headers,omitempty"`
-	Decode  string   `json:"decode"` // origin | json
-	Encode  string   `json:"encode"` // origin | form | json
+	Decode  string   `json:"decode"` // origin | json | json-noquote | string | xml | form | multipart | msgpack
+	Encode  string   `json:"encode"` // origin | json | string | xml | form | multipart | msgpack
 
 	BlackList []string `json:"blackList"`
 	WhiteList []string `json:"whiteList"`
@@ -155,10 +213,13 @@ type APIStepUIConfig struct {
 	Move    []MoveConfig   `json:"move"`
 	Delete  []DeleteConfig `json:"delete"`
 	Rename  []RenameConfig `json:"rename"`
+	Set     []SetConfig    `json:"set,omitempty"`
 	Target  string         `json:"target"`
 	Group   string         `json:"group"`
 	Retry   int            `json:"retry"`
 	TimeOut int            `json:"timeout"`
+
+	SOAP *SOAPUIConfig `json:"soap,omitempty"`
 }
 
 //MoveConfig move配置
@@ -178,6 +239,12 @@ type RenameConfig struct {
 	Target string `json:"target"`
 }
 
+//SetConfig set配置，Origin为JMESPath表达式，结果写入Target字段
+type SetConfig struct {
+	Origin string `json:"origin"`
+	Target string `json:"target"`
+}
+
 //ActionConfig action配置
 type ActionConfig struct {
 	ActionType string `json:"type"`
@@ -197,7 +264,8 @@ type StrategyConfig struct {
 
 //Gateway 网关配置
 type Gateway struct {
-	SkipCertificate int `json:"skipCertificate"`
+	SkipCertificate int    `json:"skipCertificate"`
+	Router          string `json:"router,omitempty"`
 }
 
 //APIOfStrategy 策略接口配置
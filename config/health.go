@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const (
+	//HealthCheckHTTP 发送http请求检查
+	HealthCheckHTTP = "http"
+	//HealthCheckHTTPS 发送https请求检查
+	HealthCheckHTTPS = "https"
+	//HealthCheckTCP 只检查能否建立TCP连接
+	HealthCheckTCP = "tcp"
+	//HealthCheckGRPC 使用grpc.health.v1协议检查，明文HTTP/2
+	HealthCheckGRPC = "grpc"
+)
+
+//HealthCheckOptions 健康检查的扩展配置
+type HealthCheckOptions struct {
+	//Protocol 检查方式：http、https、tcp、grpc，为空时端口为443使用https，其余使用http
+	Protocol string `json:"protocol,omitempty"`
+	//Method http检查的请求方法，默认GET
+	Method string `json:"method,omitempty"`
+	//Host http检查的Host请求头，grpc检查的authority
+	Host string `json:"host,omitempty"`
+	//Headers http检查的请求头
+	Headers map[string]string `json:"headers,omitempty"`
+	//Body http检查的响应体须包含的内容
+	Body string `json:"body,omitempty"`
+	//BodyRegexp http检查的响应体须匹配的正则表达式
+	BodyRegexp string `json:"bodyRegexp,omitempty"`
+	//GRPCService grpc检查的服务名，为空时检查实例整体状态
+	GRPCService string `json:"grpcService,omitempty"`
+	//HealthyThreshold 连续检查成功多少次后恢复实例，默认1
+	HealthyThreshold int `json:"healthyThreshold,omitempty"`
+	//UnhealthyThreshold 连续转发出错多少次后摘除实例并开始检查，默认1
+	UnhealthyThreshold int `json:"unhealthyThreshold,omitempty"`
+}
+
+//Check 检查健康检查扩展配置是否合法
+func (o *HealthCheckOptions) Check() error {
+	switch o.Protocol {
+	case "", HealthCheckHTTP, HealthCheckHTTPS, HealthCheckTCP, HealthCheckGRPC:
+	default:
+		return fmt.Errorf("unknown protocol %s", o.Protocol)
+	}
+	switch strings.ToUpper(o.Method) {
+	case "", http.MethodGet, http.MethodHead, http.MethodPost, http.MethodOptions:
+	default:
+		return fmt.Errorf("unsupported method %s", o.Method)
+	}
+	if o.BodyRegexp != "" {
+		if _, err := regexp.Compile(o.BodyRegexp); err != nil {
+			return fmt.Errorf("bodyRegexp:%s", err.Error())
+		}
+	}
+	if o.HealthyThreshold < 0 || o.UnhealthyThreshold < 0 {
+		return fmt.Errorf("healthyThreshold and unhealthyThreshold must not be negative")
+	}
+	return nil
+}
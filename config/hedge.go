@@ -0,0 +1,40 @@
+package config
+
+import (
+	"fmt"
+)
+
+//HedgeConfig 接口的对冲请求，只对GET、HEAD、OPTIONS请求生效：
+//转发超过延迟仍未响应时向负载的另一实例再发一次请求，采用最先成功的响应并取消其余请求
+type HedgeConfig struct {
+	//Delay 发起对冲请求前的等待时间，单位毫秒，为0时使用接口转发耗时的分位值
+	Delay int `json:"delay,omitempty"`
+	//Percentile 未配置等待时间时使用的转发耗时分位值，1~99，默认95
+	Percentile int `json:"percentile,omitempty"`
+	//MinDelay 等待时间的下限，单位毫秒，转发耗时统计不足时以此为等待时间，为0时不对冲
+	MinDelay int `json:"minDelay,omitempty"`
+	//MaxHedges 每次转发最多额外发送的请求数，默认1
+	MaxHedges int `json:"maxHedges,omitempty"`
+	//Budget 对冲预算，负载进行中的对冲请求数不超过进行中请求数的百分比，1~100，默认10
+	Budget int `json:"budget,omitempty"`
+}
+
+//Check 检查对冲请求配置是否合法
+func (h *HedgeConfig) Check() error {
+	if h.Delay < 0 || h.MinDelay < 0 {
+		return fmt.Errorf("delay and minDelay must not be negative")
+	}
+	if h.Delay == 0 && h.MinDelay == 0 {
+		return fmt.Errorf("delay or minDelay is required")
+	}
+	if h.Percentile < 0 || h.Percentile > 99 {
+		return fmt.Errorf("percentile must be between 1 and 99")
+	}
+	if h.MaxHedges < 0 {
+		return fmt.Errorf("maxHedges must not be negative")
+	}
+	if h.Budget < 0 || h.Budget > 100 {
+		return fmt.Errorf("budget must be between 1 and 100")
+	}
+	return nil
+}
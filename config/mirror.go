@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+//MirrorConfig 接口的流量镜像，按比例将转发的请求异步复制到影子负载，影子负载的响应不返回给客户端
+type MirrorConfig struct {
+	//Source 被镜像的负载，为空时镜像接口的全部负载
+	Source string `json:"source,omitempty"`
+	//Balance 影子负载
+	Balance string `json:"balance"`
+	//Percent 采样百分比，1~100
+	Percent int `json:"percent"`
+	//Compare 是否将影子响应与原响应逐字段比较
+	Compare bool `json:"compare,omitempty"`
+	//Ignore 比较时忽略的字段，多级以"."分隔，忽略该字段及其子字段
+	Ignore []string `json:"ignore,omitempty"`
+	//TimeOut 影子请求的超时时间，单位毫秒，为0时与原请求相同
+	TimeOut int `json:"timeout,omitempty"`
+}
+
+//Balances 镜像使用的全部负载
+func (m *MirrorConfig) Balances() []string {
+	if m.Source == "" {
+		return []string{m.Balance}
+	}
+	return []string{m.Source, m.Balance}
+}
+
+//Check 检查流量镜像配置是否合法，不检查负载是否存在
+func (m *MirrorConfig) Check() error {
+	if m.Balance == "" {
+		return fmt.Errorf("empty balance")
+	}
+	if m.Source == m.Balance {
+		return fmt.Errorf("balance must be different from source")
+	}
+	if m.Percent < 1 || m.Percent > 100 {
+		return fmt.Errorf("percent must be between 1 and 100")
+	}
+	if m.TimeOut < 0 {
+		return fmt.Errorf("illegal timeout")
+	}
+	for _, field := range m.Ignore {
+		if strings.Trim(field, ".") == "" {
+			return fmt.Errorf("ignore:empty field")
+		}
+	}
+	return nil
+}
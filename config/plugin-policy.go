@@ -0,0 +1,41 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const (
+	//PluginOnErrorContinue 出错时忽略错误继续执行（fail-open）
+	PluginOnErrorContinue = "continue"
+	//PluginOnErrorStop 出错时中断请求并返回错误响应（fail-closed）
+	PluginOnErrorStop = "stop"
+)
+
+//PluginPolicy 插件执行策略
+type PluginPolicy struct {
+	//Timeout 单次执行时间上限，单位毫秒，0为不限制
+	Timeout int `json:"timeout,omitempty"`
+	//OnError 出错时的处理方式，continue或stop，为空时IsStop的插件按stop处理，其余按continue处理
+	OnError string `json:"onError,omitempty"`
+	//ErrorStatus 出错中断时返回的状态码，0时超时返回504、崩溃返回500，其余保留插件设置的响应
+	ErrorStatus int `json:"errorStatus,omitempty"`
+	//ErrorBody 出错中断时返回的响应体，支持$plugin、$phase、$error变量
+	ErrorBody string `json:"errorBody,omitempty"`
+}
+
+//Check 检查策略是否合法
+func (p *PluginPolicy) Check() error {
+	if p.Timeout < 0 {
+		return fmt.Errorf("invalid timeout:%d", p.Timeout)
+	}
+	switch p.OnError {
+	case "", PluginOnErrorContinue, PluginOnErrorStop:
+	default:
+		return fmt.Errorf("invalid onError:%s", p.OnError)
+	}
+	if p.ErrorStatus != 0 && http.StatusText(p.ErrorStatus) == "" {
+		return fmt.Errorf("invalid errorStatus:%d", p.ErrorStatus)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+)
+
+const (
+	//RetryErrorConnect 建立连接失败，如连接被拒绝、无法路由
+	RetryErrorConnect = "connect"
+	//RetryErrorTimeout 连接或读取响应超时
+	RetryErrorTimeout = "timeout"
+	//RetryErrorReset 连接被重置或提前关闭
+	RetryErrorReset = "reset"
+)
+
+//RetryConfig 接口的重试策略，未配置时沿用链路的重试次数，仅在转发出错时重试
+type RetryConfig struct {
+	//Retries 最大重试次数，为0时使用链路配置的重试次数
+	Retries int `json:"retries,omitempty"`
+	//Statuses 需要重试的响应状态码，如502、503、504
+	Statuses []int `json:"statuses,omitempty"`
+	//Errors 需要重试的错误类型：connect、timeout、reset，为空时任意错误均重试
+	Errors []string `json:"errors,omitempty"`
+	//NonIdempotent 是否重试POST、PATCH等非幂等请求，默认只重试幂等请求
+	NonIdempotent bool `json:"nonIdempotent,omitempty"`
+	//Backoff 首次重试前的等待时间，单位毫秒，之后每次翻倍并加入随机抖动
+	Backoff int `json:"backoff,omitempty"`
+	//MaxBackoff 重试等待时间的上限，单位毫秒，为0时不限制
+	MaxBackoff int `json:"maxBackoff,omitempty"`
+	//TryTimeOut 每次尝试的超时时间，单位毫秒，为0时使用链路的超时时间
+	TryTimeOut int `json:"tryTimeout,omitempty"`
+	//Budget 重试预算，负载进行中的重试数不超过进行中请求数的百分比，为0时不限制
+	Budget int `json:"budget,omitempty"`
+	//MinRetries 不受重试预算限制的并发重试数
+	MinRetries int `json:"minRetries,omitempty"`
+}
+
+//Check 检查重试策略是否合法
+func (r *RetryConfig) Check() error {
+	if r.Retries < 0 {
+		return fmt.Errorf("retries must not be negative")
+	}
+	for _, status := range r.Statuses {
+		if status < 100 || status > 599 {
+			return fmt.Errorf("statuses:illegal status %d", status)
+		}
+	}
+	for _, e := range r.Errors {
+		switch e {
+		case RetryErrorConnect, RetryErrorTimeout, RetryErrorReset:
+		default:
+			return fmt.Errorf("errors:unknown error %s", e)
+		}
+	}
+	if r.Backoff < 0 || r.MaxBackoff < 0 || r.TryTimeOut < 0 {
+		return fmt.Errorf("backoff, maxBackoff and tryTimeout must not be negative")
+	}
+	if r.MaxBackoff > 0 && r.MaxBackoff < r.Backoff {
+		return fmt.Errorf("maxBackoff must not be less than backoff")
+	}
+	if r.Budget < 0 || r.Budget > 100 {
+		return fmt.Errorf("budget must be between 0 and 100")
+	}
+	if r.MinRetries < 0 {
+		return fmt.Errorf("minRetries must not be negative")
+	}
+	return nil
+}
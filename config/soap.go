@@ -0,0 +1,31 @@
+package config
+
+//SOAPUIConfig 编排步骤的SOAP配置，操作从负载上传的WSDL中选择
+type SOAPUIConfig struct {
+	Operation string `json:"operation"`
+	//Version 1.1 | 1.2，为空时优先使用1.1绑定
+	Version string `json:"version,omitempty"`
+	//Username WS-Security UsernameToken，为空时不添加
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	//PasswordType text | digest
+	PasswordType string `json:"passwordType,omitempty"`
+}
+
+//SOAPConfig 节点使用的SOAP配置，由WSDL解析得到
+type SOAPConfig struct {
+	Version   string `json:"version"`
+	Action    string `json:"action"`
+	Element   string `json:"element"`
+	Namespace string `json:"namespace"`
+	Qualified bool   `json:"qualified"`
+
+	Security *WSSecurityConfig `json:"security,omitempty"`
+}
+
+//WSSecurityConfig WS-Security UsernameToken配置
+type WSSecurityConfig struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Digest   bool   `json:"digest"`
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	//MetadataZone 实例所在可用区的元数据名称
+	MetadataZone = "zone"
+	//MetadataTag 子集条件中匹配实例标签的名称，如tag=canary
+	MetadataTag = "tag"
+)
+
+//SubsetConfig 负载的实例子集，只转发到元数据满足条件的实例
+type SubsetConfig struct {
+	//Selector 静态条件，如{"version":"v2","zone":"a"}
+	Selector map[string]string `json:"selector,omitempty"`
+	//Header 从该请求头读取动态条件，格式为version=v2,zone=a，与静态条件合并且优先
+	Header string `json:"header,omitempty"`
+	//Fallback 子集中没有可用实例时是否转发到其他实例
+	Fallback bool `json:"fallback,omitempty"`
+}
+
+//Check 检查子集配置是否合法
+func (s *SubsetConfig) Check() error {
+	for k := range s.Selector {
+		if strings.TrimSpace(k) == "" {
+			return fmt.Errorf("selector:empty key")
+		}
+	}
+	if len(s.Selector) == 0 && s.Header == "" {
+		return fmt.Errorf("selector or header is required")
+	}
+	return nil
+}
+
+//ParseSelector 解析version=v2,zone=a格式的子集条件，也支持以";"分隔
+func ParseSelector(text string) map[string]string {
+	selector := make(map[string]string)
+	for _, pair := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ';' }) {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		k, v := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		if k != "" {
+			selector[k] = v
+		}
+	}
+	return selector
+}
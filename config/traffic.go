@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	//StickyHeader 按请求头取粘性键
+	StickyHeader = "header"
+	//StickyCookie 按cookie取粘性键
+	StickyCookie = "cookie"
+	//StickyQuery 按query参数取粘性键
+	StickyQuery = "query"
+	//StickyConsumer 按调用方（应用或策略）取粘性键
+	StickyConsumer = "consumer"
+	//StickyIP 按客户端IP取粘性键
+	StickyIP = "ip"
+
+	//TrafficDefaultLabel 未命中任何分流时的标签
+	TrafficDefaultLabel = "default"
+)
+
+//TrafficConfig 接口的流量拆分，将转发到某个负载的请求按权重或匹配条件切换到其他负载
+type TrafficConfig struct {
+	//Balance 被拆分的负载，为空时拆分接口的全部负载
+	Balance string          `json:"balance,omitempty"`
+	Splits  []*TrafficSplit `json:"splits"`
+	//Sticky 按权重拆分时的粘性键，为空时随机拆分
+	Sticky *TrafficSticky `json:"sticky,omitempty"`
+}
+
+//TrafficSplit 流量拆分项，先按顺序检查匹配条件，均未命中时按权重拆分
+type TrafficSplit struct {
+	//Label 拆分标签，记录到访问日志及监控
+	Label   string `json:"label"`
+	Balance string `json:"balance"`
+	//Weight 按权重拆分的百分比，全部拆分项之和不超过100，剩余流量仍转发到原负载
+	Weight int           `json:"weight,omitempty"`
+	Match  *TrafficMatch `json:"match,omitempty"`
+}
+
+//TrafficMatch 拆分的匹配条件，各条件须同时满足，值为空时只要求存在
+type TrafficMatch struct {
+	Headers map[string]string `json:"headers,omitempty"`
+	Cookies map[string]string `json:"cookies,omitempty"`
+	Query   map[string]string `json:"query,omitempty"`
+	//Consumers 调用方，匹配应用ID或策略ID
+	Consumers []string `json:"consumers,omitempty"`
+}
+
+//TrafficSticky 粘性拆分配置，同一个键始终落到同一个拆分项
+type TrafficSticky struct {
+	Source string `json:"source"`
+	//Key 取值的名称，source为consumer或ip时不需要
+	Key string `json:"key,omitempty"`
+}
+
+//Balances 拆分使用的全部负载
+func (t *TrafficConfig) Balances() []string {
+	balances := make([]string, 0, len(t.Splits)+1)
+	if t.Balance != "" {
+		balances = append(balances, t.Balance)
+	}
+	for _, s := range t.Splits {
+		balances = append(balances, s.Balance)
+	}
+	return balances
+}
+
+func (m *TrafficMatch) empty() bool {
+	return m == nil || len(m.Headers)+len(m.Cookies)+len(m.Query)+len(m.Consumers) == 0
+}
+
+//Check 检查流量拆分配置是否合法，不检查负载是否存在
+func (t *TrafficConfig) Check() error {
+	labels := make(map[string]bool)
+	total := 0
+	for i, s := range t.Splits {
+		if s == nil {
+			return fmt.Errorf("splits[%d]:empty split", i)
+		}
+		s.Label = strings.TrimSpace(s.Label)
+		if s.Label == "" || s.Label == TrafficDefaultLabel {
+			return fmt.Errorf("splits[%d]:illegal label", i)
+		}
+		if labels[s.Label] {
+			return fmt.Errorf("splits[%d]:duplicate label %s", i, s.Label)
+		}
+		labels[s.Label] = true
+		if s.Balance == "" {
+			return fmt.Errorf("splits[%d]:empty balance", i)
+		}
+		if s.Weight < 0 || s.Weight > 100 {
+			return fmt.Errorf("splits[%d]:weight must be between 0 and 100", i)
+		}
+		if s.Weight == 0 && s.Match.empty() {
+			return fmt.Errorf("splits[%d]:weight or match is required", i)
+		}
+		total += s.Weight
+	}
+	if total > 100 {
+		return fmt.Errorf("splits:total weight exceeds 100")
+	}
+	if t.Sticky != nil {
+		switch t.Sticky.Source {
+		case StickyHeader, StickyCookie, StickyQuery:
+			if t.Sticky.Key == "" {
+				return fmt.Errorf("sticky:empty key")
+			}
+		case StickyConsumer, StickyIP:
+		default:
+			return fmt.Errorf("sticky:illegal source %s", t.Sticky.Source)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	//BodyFieldString 注入的字段为字符串
+	BodyFieldString = "string"
+	//BodyFieldNumber 注入的字段为数字
+	BodyFieldNumber = "number"
+	//BodyFieldBoolean 注入的字段为布尔值
+	BodyFieldBoolean = "boolean"
+	//BodyFieldJSON 注入的字段为JSON
+	BodyFieldJSON = "json"
+)
+
+//TransformConfig 接口的请求及响应转换，值支持{{header.x}}、{{query.y}}等模版变量
+type TransformConfig struct {
+	Request  *RequestTransform  `json:"request,omitempty"`
+	Response *ResponseTransform `json:"response,omitempty"`
+}
+
+//RequestTransform 转发前对请求的转换
+type RequestTransform struct {
+	Headers *FieldTransform `json:"headers,omitempty"`
+	Query   *FieldTransform `json:"query,omitempty"`
+	Cookies *FieldTransform `json:"cookies,omitempty"`
+	//Body 注入到JSON请求体的字段，请求体不是JSON时忽略
+	Body []*BodyField `json:"body,omitempty"`
+}
+
+//ResponseTransform 返回前对响应的转换，模版中header1、body1等变量为后端响应
+type ResponseTransform struct {
+	Headers *FieldTransform `json:"headers,omitempty"`
+	//Cookies 对Set-Cookie的转换
+	Cookies *FieldTransform `json:"cookies,omitempty"`
+	//StatusCodes 状态码映射，key为后端返回的状态码
+	StatusCodes map[int]int `json:"statusCodes,omitempty"`
+}
+
+//FieldTransform 键值转换，按remove、rename、set、add的顺序执行
+type FieldTransform struct {
+	Remove []string          `json:"remove,omitempty"`
+	Rename map[string]string `json:"rename,omitempty"`
+	//Set 设置值，已存在时覆盖
+	Set map[string]string `json:"set,omitempty"`
+	//Add 追加值，已存在时保留原有值
+	Add map[string]string `json:"add,omitempty"`
+}
+
+//BodyField JSON请求体字段注入
+type BodyField struct {
+	//Path 字段路径，多级以"."分隔
+	Path  string `json:"path"`
+	Value string `json:"value"`
+	//Type 值类型，string、number、boolean或json，为空时为string
+	Type string `json:"type,omitempty"`
+}
+
+//Templates 转换中使用的全部模版
+func (t *TransformConfig) Templates() []string {
+	templates := make([]string, 0)
+	if t.Request != nil {
+		for _, f := range []*FieldTransform{t.Request.Headers, t.Request.Query, t.Request.Cookies} {
+			templates = append(templates, f.templates()...)
+		}
+		for _, b := range t.Request.Body {
+			templates = append(templates, b.Value)
+		}
+	}
+	if t.Response != nil {
+		for _, f := range []*FieldTransform{t.Response.Headers, t.Response.Cookies} {
+			templates = append(templates, f.templates()...)
+		}
+	}
+	return templates
+}
+
+func (f *FieldTransform) templates() []string {
+	if f == nil {
+		return nil
+	}
+	templates := make([]string, 0, len(f.Set)+len(f.Add))
+	for _, v := range f.Set {
+		templates = append(templates, v)
+	}
+	for _, v := range f.Add {
+		templates = append(templates, v)
+	}
+	return templates
+}
+
+func (f *FieldTransform) check(name string) error {
+	if f == nil {
+		return nil
+	}
+	for _, k := range f.Remove {
+		if k == "" {
+			return fmt.Errorf("%s:empty name to remove", name)
+		}
+	}
+	for k, v := range f.Rename {
+		if k == "" || v == "" {
+			return fmt.Errorf("%s:empty name to rename", name)
+		}
+	}
+	for k := range f.Set {
+		if k == "" {
+			return fmt.Errorf("%s:empty name to set", name)
+		}
+	}
+	for k := range f.Add {
+		if k == "" {
+			return fmt.Errorf("%s:empty name to add", name)
+		}
+	}
+	return nil
+}
+
+//Check 检查转换配置是否合法，不检查模版语法
+func (t *TransformConfig) Check() error {
+	if t.Request != nil {
+		if err := t.Request.Headers.check("request.headers"); err != nil {
+			return err
+		}
+		if err := t.Request.Query.check("request.query"); err != nil {
+			return err
+		}
+		if err := t.Request.Cookies.check("request.cookies"); err != nil {
+			return err
+		}
+		for _, b := range t.Request.Body {
+			if b == nil || strings.Trim(b.Path, ".") == "" {
+				return fmt.Errorf("request.body:empty path")
+			}
+			switch b.Type {
+			case "", BodyFieldString, BodyFieldNumber, BodyFieldBoolean, BodyFieldJSON:
+			default:
+				return fmt.Errorf("request.body:invalid type %s", b.Type)
+			}
+		}
+	}
+	if t.Response != nil {
+		if err := t.Response.Headers.check("response.headers"); err != nil {
+			return err
+		}
+		if err := t.Response.Cookies.check("response.cookies"); err != nil {
+			return err
+		}
+		for from, to := range t.Response.StatusCodes {
+			if http.StatusText(from) == "" || http.StatusText(to) == "" {
+				return fmt.Errorf("response.statusCodes:invalid status %d:%d", from, to)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+)
+
+//TransportConfig 负载的转发连接配置，时间单位均为毫秒，为0时使用默认值
+type TransportConfig struct {
+	MaxIdleConns        int `json:"maxIdleConns,omitempty"`
+	MaxIdleConnsPerHost int `json:"maxIdleConnsPerHost,omitempty"`
+	//MaxConnsPerHost 每个实例的最大连接数，为0时不限制
+	MaxConnsPerHost       int `json:"maxConnsPerHost,omitempty"`
+	IdleConnTimeout       int `json:"idleConnTimeout,omitempty"`
+	DialTimeout           int `json:"dialTimeout,omitempty"`
+	TLSHandshakeTimeout   int `json:"tlsHandshakeTimeout,omitempty"`
+	ResponseHeaderTimeout int `json:"responseHeaderTimeout,omitempty"`
+	//HTTP2 https时是否尝试使用HTTP/2
+	HTTP2 bool `json:"http2,omitempty"`
+	//CA 校验实例证书的CA，PEM格式，为空时使用系统CA
+	CA string `json:"ca,omitempty"`
+	//Cert 双向认证时网关使用的客户端证书及私钥，PEM格式
+	Cert string `json:"cert,omitempty"`
+	Key  string `json:"key,omitempty"`
+	//ServerName 覆盖TLS握手中的SNI及证书校验的域名
+	ServerName         string `json:"serverName,omitempty"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify,omitempty"`
+}
+
+//Check 检查连接配置是否合法，证书须能解析
+func (t *TransportConfig) Check() error {
+	for name, v := range map[string]int{
+		"maxIdleConns":          t.MaxIdleConns,
+		"maxIdleConnsPerHost":   t.MaxIdleConnsPerHost,
+		"maxConnsPerHost":       t.MaxConnsPerHost,
+		"idleConnTimeout":       t.IdleConnTimeout,
+		"dialTimeout":           t.DialTimeout,
+		"tlsHandshakeTimeout":   t.TLSHandshakeTimeout,
+		"responseHeaderTimeout": t.ResponseHeaderTimeout,
+	} {
+		if v < 0 {
+			return fmt.Errorf("%s must not be negative", name)
+		}
+	}
+	_, err := t.TLSConfig(false)
+	return err
+}
+
+//TLSConfig 生成TLS配置，skip为网关全局的跳过证书校验
+func (t *TransportConfig) TLSConfig(skip bool) (*tls.Config, error) {
+	c := &tls.Config{
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: skip || t.InsecureSkipVerify,
+	}
+	if t.CA != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(t.CA)) {
+			return nil, fmt.Errorf("ca:no valid certificate")
+		}
+		c.RootCAs = pool
+	}
+	if t.Cert != "" || t.Key != "" {
+		cert, err := tls.X509KeyPair([]byte(t.Cert), []byte(t.Key))
+		if err != nil {
+			return nil, fmt.Errorf("cert:%s", err.Error())
+		}
+		c.Certificates = []tls.Certificate{cert}
+	}
+	return c, nil
+}
@@ -0,0 +1,248 @@
+package alert
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/alert"
+	goku_handler "github.com/eolinker/goku-api-gateway/goku-handler"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const operationAlert = "monitorModuleManagement"
+
+//Handlers handlers
+type Handlers struct {
+}
+
+//Handlers handlers
+func (h *Handlers) Handlers(factory *goku_handler.AccountHandlerFactory) map[string]http.Handler {
+	return map[string]http.Handler{
+		"/rule/add":     factory.NewAccountHandleFunction(operationAlert, true, AddRule),
+		"/rule/edit":    factory.NewAccountHandleFunction(operationAlert, true, EditRule),
+		"/rule/delete":  factory.NewAccountHandleFunction(operationAlert, true, DeleteRule),
+		"/rule/get":     factory.NewAccountHandleFunction(operationAlert, false, GetRule),
+		"/rule/getList": factory.NewAccountHandleFunction(operationAlert, false, GetRuleList),
+		"/rule/silence": factory.NewAccountHandleFunction(operationAlert, true, SilenceRule),
+		"/history":      factory.NewAccountHandleFunction(operationAlert, false, GetHistory),
+		"/config/get":   factory.NewAccountHandleFunction(operationAlert, false, GetConfig),
+		"/config/set":   factory.NewAccountHandleFunction(operationAlert, true, SetConfig),
+	}
+}
+
+//NewHandlers new handlers
+func NewHandlers() *Handlers {
+	return &Handlers{}
+}
+
+func readRule(httpRequest *http.Request) (*entity.AlertRule, error) {
+	httpRequest.ParseForm()
+	rule := &entity.AlertRule{
+		RuleName:   httpRequest.Form.Get("ruleName"),
+		TargetType: httpRequest.Form.Get("targetType"),
+		Target:     httpRequest.Form.Get("target"),
+		Metric:     httpRequest.Form.Get("metric"),
+		Webhook:    httpRequest.Form.Get("webhook"),
+		Receivers:  httpRequest.Form.Get("receivers"),
+		Enable:     1,
+	}
+	threshold, err := strconv.ParseFloat(httpRequest.Form.Get("threshold"), 64)
+	if err != nil {
+		return nil, errors.New("[ERROR]Illegal threshold!")
+	}
+	rule.Threshold = threshold
+	window, err := strconv.Atoi(httpRequest.Form.Get("window"))
+	if err != nil {
+		return nil, errors.New("[ERROR]Illegal window!")
+	}
+	rule.Window = window
+	if enable := httpRequest.Form.Get("enable"); enable != "" {
+		rule.Enable, err = strconv.Atoi(enable)
+		if err != nil {
+			return nil, errors.New("[ERROR]Illegal enable!")
+		}
+	}
+	return rule, nil
+}
+
+func readRuleID(httpRequest *http.Request) (int, error) {
+	httpRequest.ParseForm()
+	ruleID, err := strconv.Atoi(httpRequest.Form.Get("ruleID"))
+	if err != nil {
+		return 0, errors.New("[ERROR]Illegal ruleID!")
+	}
+	return ruleID, nil
+}
+
+//AddRule 新增告警规则
+func AddRule(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	rule, err := readRule(httpRequest)
+	if err != nil {
+		controller.WriteError(httpResponse, "420001", "alert", err.Error(), err)
+		return
+	}
+	ruleID, err := alert.AddRule(rule)
+	if err != nil {
+		controller.WriteError(httpResponse, "420000", "alert", err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "alert", "ruleID", ruleID)
+}
+
+//EditRule 修改告警规则
+func EditRule(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	ruleID, err := readRuleID(httpRequest)
+	if err != nil {
+		controller.WriteError(httpResponse, "420002", "alert", err.Error(), err)
+		return
+	}
+	rule, err := readRule(httpRequest)
+	if err != nil {
+		controller.WriteError(httpResponse, "420001", "alert", err.Error(), err)
+		return
+	}
+	rule.RuleID = ruleID
+	err = alert.EditRule(rule)
+	if err != nil {
+		controller.WriteError(httpResponse, "420000", "alert", err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "alert", "", nil)
+}
+
+//DeleteRule 删除告警规则
+func DeleteRule(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	ruleID, err := readRuleID(httpRequest)
+	if err != nil {
+		controller.WriteError(httpResponse, "420002", "alert", err.Error(), err)
+		return
+	}
+	err = alert.DeleteRule(ruleID)
+	if err != nil {
+		controller.WriteError(httpResponse, "420000", "alert", err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "alert", "", nil)
+}
+
+//GetRule 获取告警规则
+func GetRule(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	ruleID, err := readRuleID(httpRequest)
+	if err != nil {
+		controller.WriteError(httpResponse, "420002", "alert", err.Error(), err)
+		return
+	}
+	rule, err := alert.GetRule(ruleID)
+	if err != nil {
+		controller.WriteError(httpResponse, "420003", "alert", "[ERROR]alert rule does not exist", err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "alert", "rule", rule)
+}
+
+//GetRuleList 获取告警规则列表及可选的统计对象、指标
+func GetRuleList(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	rules, err := alert.GetRules()
+	if err != nil {
+		controller.WriteError(httpResponse, "420000", "alert", err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "alert", "", map[string]interface{}{
+		"ruleList":    rules,
+		"targetTypes": alert.TargetTypes(),
+		"metrics":     alert.Metrics(),
+	})
+}
+
+//SilenceRule 静默告警规则，minutes为0时取消静默
+func SilenceRule(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	ruleID, err := readRuleID(httpRequest)
+	if err != nil {
+		controller.WriteError(httpResponse, "420002", "alert", err.Error(), err)
+		return
+	}
+	minutes, err := strconv.Atoi(httpRequest.Form.Get("minutes"))
+	if err != nil || minutes < 0 {
+		controller.WriteError(httpResponse, "420004", "alert", "[ERROR]Illegal minutes!", err)
+		return
+	}
+	err = alert.Silence(ruleID, minutes)
+	if err != nil {
+		controller.WriteError(httpResponse, "420000", "alert", err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "alert", "", nil)
+}
+
+//GetHistory 获取告警记录
+func GetHistory(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	ruleID, _ := strconv.Atoi(httpRequest.Form.Get("ruleID"))
+	page, err := strconv.Atoi(httpRequest.Form.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(httpRequest.Form.Get("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 15
+	}
+	histories, count, err := alert.GetHistory(ruleID, page, pageSize)
+	if err != nil {
+		controller.WriteError(httpResponse, "420000", "alert", err.Error(), err)
+		return
+	}
+	controller.WriteResultInfoWithPage(httpResponse, "alert", "historyList", histories, &controller.PageInfo{
+		ItemNum:  len(histories),
+		TotalNum: count,
+		Page:     page,
+		PageSize: pageSize,
+	})
+}
+
+//GetConfig 获取告警通知配置
+func GetConfig(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	conf, err := alert.GetConfig()
+	if err != nil {
+		controller.WriteError(httpResponse, "420000", "alert", err.Error(), err)
+		return
+	}
+	conf.Password = ""
+	controller.WriteResultInfo(httpResponse, "alert", "config", conf)
+}
+
+//SetConfig 设置告警通知配置，未传入密码时保持原密码
+func SetConfig(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	conf := &entity.AlertConfig{
+		Webhook:     httpRequest.Form.Get("webhook"),
+		Receivers:   httpRequest.Form.Get("receivers"),
+		SMTPAddress: httpRequest.Form.Get("smtpAddress"),
+		Sender:      httpRequest.Form.Get("sender"),
+		Password:    httpRequest.Form.Get("senderPassword"),
+	}
+	var err error
+	for name, v := range map[string]*int{"alertStatus": &conf.AlertStatus, "smtpPort": &conf.SMTPPort, "smtpProtocol": &conf.SMTPProtocol} {
+		value := httpRequest.Form.Get(name)
+		if value == "" {
+			continue
+		}
+		*v, err = strconv.Atoi(value)
+		if err != nil {
+			controller.WriteError(httpResponse, "420005", "alert", "[ERROR]Illegal "+name+"!", err)
+			return
+		}
+	}
+	if conf.Password == "" {
+		if old, err := alert.GetConfig(); err == nil {
+			conf.Password = old.Password
+		}
+	}
+	err = alert.SetConfig(conf)
+	if err != nil {
+		controller.WriteError(httpResponse, "420000", "alert", err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "alert", "", nil)
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/api"
+)
+
+// policyErrors 接口策略修改失败时的错误码及描述
+var policyErrors = map[string]struct {
+	code  string
+	label string
+}{
+	api.PolicyTransform: {"190023", "transform"},
+	api.PolicyTraffic:   {"190025", "traffic"},
+	api.PolicyMirror:    {"190026", "mirror"},
+	api.PolicyRetry:     {"190027", "retry policy"},
+	api.PolicyHedge:     {"190028", "hedge"},
+}
+
+// getAPIPolicy 获取接口的策略，以策略名称作为返回的字段名
+func getAPIPolicy(name string) func(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	return func(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+
+		apiID, err := strconv.Atoi(httpRequest.FormValue("apiID"))
+		if err != nil {
+			controller.WriteError(httpResponse, "190001", "api", "[ERROR]Illegal apiID!", err)
+			return
+		}
+		policy, err := api.GetAPIPolicy(name, apiID)
+		if err != nil {
+			controller.WriteError(httpResponse, "190000", "api", "[ERROR]Fail to get "+policyErrors[name].label+"!", err)
+			return
+		}
+		controller.WriteResultInfo(httpResponse, "api", name, policy)
+	}
+}
+
+// editAPIPolicy 修改接口的策略，以策略名称作为提交的参数名，为空时清除
+func editAPIPolicy(name string) func(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	return func(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+
+		apiID, err := strconv.Atoi(httpRequest.PostFormValue("apiID"))
+		if err != nil {
+			controller.WriteError(httpResponse, "190001", "api", "[ERROR]Illegal apiID!", err)
+			return
+		}
+		err = api.SetAPIPolicy(name, apiID, httpRequest.PostFormValue(name))
+		if err != nil {
+			e := policyErrors[name]
+			controller.WriteError(httpResponse, e.code, "api", "[ERROR]Fail to edit "+e.label+":"+err.Error(), err)
+			return
+		}
+		controller.WriteResultInfo(httpResponse, "api", "", nil)
+	}
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/api"
+)
+
+//EditAPIRoutePriority 修改接口的路由优先级，优先级高的接口先匹配
+func EditAPIRoutePriority(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+
+	apiID, err := strconv.Atoi(httpRequest.PostFormValue("apiID"))
+	if err != nil {
+		controller.WriteError(httpResponse, "190001", "api", "[ERROR]Illegal apiID!", err)
+		return
+	}
+	priority, err := strconv.Atoi(httpRequest.PostFormValue("routePriority"))
+	if err != nil {
+		controller.WriteError(httpResponse, "190020", "api", "[ERROR]Illegal routePriority!", err)
+		return
+	}
+	err = api.EditAPIRoutePriority(apiID, priority)
+	if err != nil {
+		controller.WriteError(httpResponse, "190000", "api", "[ERROR]Fail to edit route priority!", err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "api", "", nil)
+}
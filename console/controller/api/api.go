@@ -14,6 +14,7 @@ import (
 
 	"github.com/eolinker/goku-api-gateway/console/controller"
 	"github.com/eolinker/goku-api-gateway/console/module/api"
+	"github.com/eolinker/goku-api-gateway/node/gateway/response"
 )
 
 const operationAPI = "apiManagement"
@@ -25,15 +26,26 @@ type Handlers struct {
 //Handlers handlers
 func (h *Handlers) Handlers(factory *goku_handler.AccountHandlerFactory) map[string]http.Handler {
 	return map[string]http.Handler{
-		"/add":              factory.NewAccountHandleFunction(operationAPI, true, AddAPI),
-		"/edit":             factory.NewAccountHandleFunction(operationAPI, true, EditAPI),
-		"/copy":             factory.NewAccountHandleFunction(operationAPI, true, CopyAPI),
-		"/getInfo":          factory.NewAccountHandleFunction(operationAPI, false, GetAPIInfo),
-		"/getList":          factory.NewAccountHandleFunction(operationAPI, false, GetAPIList),
-		"/id/getList":       factory.NewAccountHandleFunction(operationAPI, false, GetAPIIDList),
-		"/batchEditGroup":   factory.NewAccountHandleFunction(operationAPI, true, BatchEditAPIGroup),
-		"/batchDelete":      factory.NewAccountHandleFunction(operationAPI, true, BatchDeleteAPI),
-		"/batchEditBalance": factory.NewAccountHandleFunction(operationAPI, true, BatchSetBalanceAPI),
+		"/add":               factory.NewAccountHandleFunction(operationAPI, true, AddAPI),
+		"/edit":              factory.NewAccountHandleFunction(operationAPI, true, EditAPI),
+		"/copy":              factory.NewAccountHandleFunction(operationAPI, true, CopyAPI),
+		"/getInfo":           factory.NewAccountHandleFunction(operationAPI, false, GetAPIInfo),
+		"/getList":           factory.NewAccountHandleFunction(operationAPI, false, GetAPIList),
+		"/id/getList":        factory.NewAccountHandleFunction(operationAPI, false, GetAPIIDList),
+		"/batchEditGroup":    factory.NewAccountHandleFunction(operationAPI, true, BatchEditAPIGroup),
+		"/batchDelete":       factory.NewAccountHandleFunction(operationAPI, true, BatchDeleteAPI),
+		"/batchEditBalance":  factory.NewAccountHandleFunction(operationAPI, true, BatchSetBalanceAPI),
+		"/editRoutePriority": factory.NewAccountHandleFunction(operationAPI, true, EditAPIRoutePriority),
+		"/transform/get":     factory.NewAccountHandleFunction(operationAPI, false, getAPIPolicy(api.PolicyTransform)),
+		"/transform/edit":    factory.NewAccountHandleFunction(operationAPI, true, editAPIPolicy(api.PolicyTransform)),
+		"/traffic/get":       factory.NewAccountHandleFunction(operationAPI, false, getAPIPolicy(api.PolicyTraffic)),
+		"/traffic/edit":      factory.NewAccountHandleFunction(operationAPI, true, editAPIPolicy(api.PolicyTraffic)),
+		"/mirror/get":        factory.NewAccountHandleFunction(operationAPI, false, getAPIPolicy(api.PolicyMirror)),
+		"/mirror/edit":       factory.NewAccountHandleFunction(operationAPI, true, editAPIPolicy(api.PolicyMirror)),
+		"/retry/get":         factory.NewAccountHandleFunction(operationAPI, false, getAPIPolicy(api.PolicyRetry)),
+		"/retry/edit":        factory.NewAccountHandleFunction(operationAPI, true, editAPIPolicy(api.PolicyRetry)),
+		"/hedge/get":         factory.NewAccountHandleFunction(operationAPI, false, getAPIPolicy(api.PolicyHedge)),
+		"/hedge/edit":        factory.NewAccountHandleFunction(operationAPI, true, editAPIPolicy(api.PolicyHedge)),
 	}
 }
 
@@ -92,7 +104,7 @@ func AddAPI(httpResponse http.ResponseWriter, httpRequest *http.Request) {
 		controller.WriteError(httpResponse, "190022", "api", "[ERROR]Illegal requestURL!", nil)
 		return
 	}
-	if responseDataType != "origin" && responseDataType != "json" && responseDataType != "xml" {
+	if responseDataType != "origin" && !response.HasEncoder(responseDataType) {
 		controller.WriteError(httpResponse, "190013", "api", "[ERROR]Illegal responseDataType!", err)
 		return
 	}
@@ -139,6 +151,10 @@ func AddAPI(httpResponse http.ResponseWriter, httpRequest *http.Request) {
 	if managerID == "" {
 		mgID = userID
 	}
+	if err := api.CheckLinkApis(linkApis); err != nil {
+		controller.WriteError(httpResponse, "190024", "api", "[ERROR]Illegal linkApis!", err)
+		return
+	}
 	if api.CheckAliasIsExist(0, alias) {
 		errInfo := "[ERROR]duplicate alias!"
 		controller.WriteError(httpResponse, "190020", "api", errInfo, errors.New(errInfo))
@@ -191,7 +207,7 @@ func EditAPI(httpResponse http.ResponseWriter, httpRequest *http.Request) {
 		controller.WriteError(httpResponse, "190001", "api", "[ERROR]Illegal apiID!", nil)
 		return
 	}
-	if responseDataType != "origin" && responseDataType != "json" && responseDataType != "xml" {
+	if responseDataType != "origin" && !response.HasEncoder(responseDataType) {
 		controller.WriteError(httpResponse, "190013", "api", "[ERROR]Illegal responseDataType!", err)
 		return
 	}
@@ -248,6 +264,10 @@ func EditAPI(httpResponse http.ResponseWriter, httpRequest *http.Request) {
 	if managerID == "" {
 		mgID = userID
 	}
+	if err := api.CheckLinkApis(linkApis); err != nil {
+		controller.WriteError(httpResponse, "190024", "api", "[ERROR]Illegal linkApis!", err)
+		return
+	}
 	if api.CheckAliasIsExist(aID, alias) {
 		errInfo := "[ERROR]duplicate alias!"
 		controller.WriteError(httpResponse, "190020", "api", errInfo, errors.New(errInfo))
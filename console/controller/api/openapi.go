@@ -0,0 +1,109 @@
+package api
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/api"
+	goku_handler "github.com/eolinker/goku-api-gateway/goku-handler"
+)
+
+//OpenAPIHandlers OpenAPI/Swagger导入导出处理器
+type OpenAPIHandlers struct {
+}
+
+//Handlers handlers
+func (h *OpenAPIHandlers) Handlers(factory *goku_handler.AccountHandlerFactory) map[string]http.Handler {
+	return map[string]http.Handler{
+		"/import": factory.NewAccountHandleFunction(operationImportAMS, true, ImportOpenAPI),
+		"/export": factory.NewAccountHandleFunction(operationAPI, false, ExportOpenAPI),
+	}
+}
+
+//NewOpenAPIHandlers new OpenAPI导入导出处理器
+func NewOpenAPIHandlers() *OpenAPIHandlers {
+	return &OpenAPIHandlers{}
+}
+
+//ImportOpenAPI 导入OpenAPI 3/Swagger 2文档，dryRun为true时只返回冲突检查结果
+func ImportOpenAPI(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	userID := goku_handler.UserIDFromRequest(httpRequest)
+	projectID, err := strconv.Atoi(httpRequest.FormValue("projectID"))
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"310002",
+			"import",
+			"[ERROR]Illegal projectID!",
+			err)
+		return
+	}
+	file, _, err := httpRequest.FormFile("file")
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"310004",
+			"import",
+			"[ERROR]Param file does not exist!",
+			err)
+		return
+	}
+	defer file.Close()
+	body, err := ioutil.ReadAll(file)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"310005",
+			"import",
+			"[ERROR]Fail to read file!",
+			err)
+		return
+	}
+	dryRun := httpRequest.FormValue("dryRun") == "true"
+	serviceName := httpRequest.FormValue("serviceName")
+
+	result, err := api.ImportOpenAPI(projectID, userID, serviceName, body, dryRun)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"310008",
+			"import",
+			"[ERROR]Fail to import openapi:"+err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "import", "result", result)
+}
+
+//ExportOpenAPI 将项目导出为OpenAPI 3文档
+func ExportOpenAPI(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	projectID, err := strconv.Atoi(httpRequest.Form.Get("projectID"))
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"310002",
+			"import",
+			"[ERROR]Illegal projectID!",
+			err)
+		return
+	}
+	format := httpRequest.Form.Get("format")
+	if format != "yaml" {
+		format = "json"
+	}
+	data, err := api.ExportOpenAPI(projectID, format)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"310009",
+			"import",
+			"[ERROR]Fail to export openapi!",
+			err)
+		return
+	}
+	if format == "yaml" {
+		httpResponse.Header().Set("Content-Type", "application/x-yaml")
+	} else {
+		httpResponse.Header().Set("Content-Type", "application/json")
+	}
+	httpResponse.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=openapi-%d.%s", projectID, format))
+	httpResponse.Write(data)
+}
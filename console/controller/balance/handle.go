@@ -3,6 +3,7 @@ package balance
 import (
 	"net/http"
 
+	"github.com/eolinker/goku-api-gateway/console/module/balance"
 	goku_handler "github.com/eolinker/goku-api-gateway/goku-handler"
 )
 
@@ -22,6 +23,14 @@ func (h *Handlers) Handlers(factory *goku_handler.AccountHandlerFactory) map[str
 		"/getList":     factory.NewAccountHandleFunction(operationBalance, false, GetBalanceList),
 		"/batchDelete": factory.NewAccountHandleFunction(operationBalance, true, BatchDeleteBalance),
 		"/simple":      factory.NewAccountHandleFunction(operationBalance, true, GetSimpleList),
+
+		"/wsdl/edit":       factory.NewAccountHandleFunction(operationBalance, true, SaveWSDL),
+		"/wsdl/get":        factory.NewAccountHandleFunction(operationBalance, false, GetWSDL),
+		"/wsdl/operations": factory.NewAccountHandleFunction(operationBalance, false, GetWSDLOperations),
+		"/subset/edit":     factory.NewAccountHandleFunction(operationBalance, true, savePolicy(balance.PolicySubset)),
+		"/subset/get":      factory.NewAccountHandleFunction(operationBalance, false, getPolicy(balance.PolicySubset)),
+		"/transport/edit":  factory.NewAccountHandleFunction(operationBalance, true, savePolicy(balance.PolicyTransport)),
+		"/transport/get":   factory.NewAccountHandleFunction(operationBalance, false, getPolicy(balance.PolicyTransport)),
 	}
 }
 
@@ -0,0 +1,40 @@
+package balance
+
+import (
+	"net/http"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/balance"
+)
+
+// policyCodes 负载策略保存失败时的错误码
+var policyCodes = map[string]string{
+	balance.PolicySubset:    "260006",
+	balance.PolicyTransport: "260007",
+}
+
+// savePolicy 设置负载的策略，以策略名称作为提交的参数名，为空时清除
+func savePolicy(name string) func(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	return func(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+		balanceName := httpRequest.FormValue("balanceName")
+		err := balance.SavePolicy(name, balanceName, httpRequest.FormValue(name))
+		if err != nil {
+			controller.WriteError(httpResponse, policyCodes[name], "balance", "[ERROR]Illegal "+name+":"+err.Error(), err)
+			return
+		}
+		controller.WriteResultInfo(httpResponse, "balance", "", nil)
+	}
+}
+
+// getPolicy 获取负载的策略，以策略名称作为返回的字段名
+func getPolicy(name string) func(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	return func(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+		balanceName := httpRequest.FormValue("balanceName")
+		policy, err := balance.GetPolicy(name, balanceName)
+		if err != nil {
+			controller.WriteError(httpResponse, "260000", "balance", "[ERROR]The balance does not exist!", err)
+			return
+		}
+		controller.WriteResultInfo(httpResponse, "balance", name, policy)
+	}
+}
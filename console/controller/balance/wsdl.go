@@ -0,0 +1,52 @@
+package balance
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/balance"
+)
+
+//SaveWSDL 上传负载的WSDL文档，支持文件file或参数wsdl，返回解析出的SOAP操作
+func SaveWSDL(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	balanceName := httpRequest.FormValue("balanceName")
+	text := httpRequest.FormValue("wsdl")
+	if file, _, err := httpRequest.FormFile("file"); err == nil {
+		defer file.Close()
+		data, err := ioutil.ReadAll(file)
+		if err != nil {
+			controller.WriteError(httpResponse, "260003", "balance", "[ERROR]Fail to read file!", err)
+			return
+		}
+		text = string(data)
+	}
+	operations, err := balance.SaveWSDL(balanceName, text)
+	if err != nil {
+		controller.WriteError(httpResponse, "260004", "balance", "[ERROR]Illegal wsdl:"+err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "balance", "operations", operations)
+}
+
+//GetWSDL 获取负载的WSDL文档
+func GetWSDL(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	balanceName := httpRequest.FormValue("balanceName")
+	text, err := balance.GetWSDL(balanceName)
+	if err != nil {
+		controller.WriteError(httpResponse, "260000", "balance", "[ERROR]The balance does not exist!", err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "balance", "wsdl", text)
+}
+
+//GetWSDLOperations 获取负载WSDL中的SOAP操作列表
+func GetWSDLOperations(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	balanceName := httpRequest.FormValue("balanceName")
+	doc, err := balance.GetWSDLDocument(balanceName)
+	if err != nil {
+		controller.WriteError(httpResponse, "260005", "balance", "[ERROR]The balance has no valid wsdl!", err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "balance", "operations", doc.Operations)
+}
@@ -70,7 +70,11 @@ func AddVersionConfig(httpResponse http.ResponseWriter, httpRequest *http.Reques
 	}
 
 	if p == 1 {
-		versionConfig.PublishVersion(id, userID, now)
+		err = versionConfig.PublishVersion(id, userID, now)
+		if err != nil {
+			controller.WriteError(httpResponse, "380000", "versionConfig", err.Error(), err)
+			return
+		}
 	}
 	controller.WriteResultInfo(httpResponse,
 		"versionConfig",
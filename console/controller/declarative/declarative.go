@@ -0,0 +1,128 @@
+package declarative
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/declarative"
+	goku_handler "github.com/eolinker/goku-api-gateway/goku-handler"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const operationDeclarative = "versionManagement"
+
+//Handlers 声明式配置处理器
+type Handlers struct {
+}
+
+//Handlers handlers
+func (h *Handlers) Handlers(factory *goku_handler.AccountHandlerFactory) map[string]http.Handler {
+	return map[string]http.Handler{
+		"/export": factory.NewAccountHandleFunction(operationDeclarative, false, Export),
+		"/diff":   factory.NewAccountHandleFunction(operationDeclarative, false, Diff),
+		"/apply":  factory.NewAccountHandleFunction(operationDeclarative, true, Apply),
+	}
+}
+
+//NewHandlers new声明式配置处理器
+func NewHandlers() *Handlers {
+	return &Handlers{}
+}
+
+//Export 导出当前全部配置
+func Export(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	format := httpRequest.Form.Get("format")
+	if format != "json" {
+		format = "yaml"
+	}
+	data, err := declarative.Export(format)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"320000",
+			"declarative",
+			"[ERROR]Fail to export config!",
+			err)
+		return
+	}
+	if format == "yaml" {
+		httpResponse.Header().Set("Content-Type", "application/x-yaml")
+	} else {
+		httpResponse.Header().Set("Content-Type", "application/json")
+	}
+	httpResponse.Header().Set("Content-Disposition", "attachment; filename=goku."+format)
+	httpResponse.Write(data)
+}
+
+// readDocument 读取请求中的声明式配置，支持file文件或config字段
+func readDocument(httpResponse http.ResponseWriter, httpRequest *http.Request) (*entity.Declarative, bool) {
+	body := []byte(httpRequest.FormValue("config"))
+	if file, _, err := httpRequest.FormFile("file"); err == nil {
+		defer file.Close()
+		body, err = ioutil.ReadAll(file)
+		if err != nil {
+			controller.WriteError(httpResponse,
+				"320001",
+				"declarative",
+				"[ERROR]Fail to read file!",
+				err)
+			return nil, false
+		}
+	}
+	if len(body) == 0 {
+		controller.WriteError(httpResponse,
+			"320002",
+			"declarative",
+			"[ERROR]Param config or file does not exist!",
+			nil)
+		return nil, false
+	}
+	doc, err := declarative.Parse(body)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"320003",
+			"declarative",
+			"[ERROR]Illegal config:"+err.Error(),
+			err)
+		return nil, false
+	}
+	return doc, true
+}
+
+//Diff 对比提交的声明式配置与当前配置，不写入
+func Diff(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	doc, ok := readDocument(httpResponse, httpRequest)
+	if !ok {
+		return
+	}
+	result, err := declarative.Diff(doc, httpRequest.FormValue("prune") == "true")
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"320004",
+			"declarative",
+			"[ERROR]"+err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "declarative", "result", result)
+}
+
+//Apply 在同一事务中应用声明式配置，prune为true时删除未声明的配置
+func Apply(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	userID := goku_handler.UserIDFromRequest(httpRequest)
+	doc, ok := readDocument(httpResponse, httpRequest)
+	if !ok {
+		return
+	}
+	result, err := declarative.Apply(doc, httpRequest.FormValue("prune") == "true", userID)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"320005",
+			"declarative",
+			"[ERROR]Fail to apply config:"+err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "declarative", "result", result)
+}
@@ -21,6 +21,9 @@ func (h *Handlers) Handlers(factory *goku_handler.AccountHandlerFactory) map[str
 		"/default": factory.NewAccountHandleFunction(operationDiscovery, true, setDefault),
 		"/drivers": factory.NewAccountHandleFunction(operationDiscovery, false, getDrivices),
 		"/simple":  factory.NewAccountHandleFunction(operationDiscovery, false, simple),
+
+		"/healthCheck/get":  factory.NewAccountHandleFunction(operationDiscovery, false, getHealthCheck),
+		"/healthCheck/edit": factory.NewAccountHandleFunction(operationDiscovery, true, editHealthCheck),
 	}
 }
 
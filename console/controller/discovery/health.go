@@ -0,0 +1,37 @@
+package discovery
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/service"
+)
+
+func getHealthCheck(w http.ResponseWriter, r *http.Request) {
+	name := r.FormValue("name")
+	if !service.ValidateName(name) {
+		controller.WriteError(w, "260000", "data", fmt.Sprintf("[param_check] invalid  [name]=%s", name), nil)
+		return
+	}
+	options, err := service.GetHealthCheckOptions(name)
+	if err != nil {
+		controller.WriteError(w, "260000", "data", "[ERROR]The service discovery does not exist!", err)
+		return
+	}
+	controller.WriteResultInfo(w, "serviceDiscovery", "healthCheckOptions", options)
+}
+
+func editHealthCheck(w http.ResponseWriter, r *http.Request) {
+	name := r.FormValue("name")
+	if !service.ValidateName(name) {
+		controller.WriteError(w, "260000", "data", fmt.Sprintf("[param_check] invalid  [name]=%s", name), nil)
+		return
+	}
+	err := service.SaveHealthCheckOptions(name, r.FormValue("healthCheckOptions"))
+	if err != nil {
+		controller.WriteError(w, "260000", "data", "[ERROR]Illegal healthCheckOptions:"+err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(w, "serviceDiscovery", "", nil)
+}
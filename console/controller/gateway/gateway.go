@@ -20,6 +20,8 @@ type Handlers struct {
 func (h *Handlers) Handlers(factory *goku_handler.AccountHandlerFactory) map[string]http.Handler {
 	return map[string]http.Handler{
 		"/getSummaryInfo": factory.NewAccountHandleFunction(operationGateway, false, GetGatewayBasicInfo),
+		"/router/get":     factory.NewAccountHandleFunction(operationGateway, false, GetRouter),
+		"/router/set":     factory.NewAccountHandleFunction(operationGateway, true, SetRouter),
 	}
 }
 
@@ -52,3 +54,27 @@ func GetGatewayBasicInfo(httpResponse http.ResponseWriter, httpRequest *http.Req
 	return
 
 }
+
+//GetRouter 获取节点使用的路由实现
+func GetRouter(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	router, routers, err := gateway.GetRouter()
+	if err != nil {
+		controller.WriteError(httpResponse, "340000", "gateway", "[ERROR]The gateway basic information does not exist!", err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "gateway", "", map[string]interface{}{
+		"router":  router,
+		"routers": routers,
+	})
+}
+
+//SetRouter 设置节点使用的路由实现
+func SetRouter(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	err := gateway.SetRouter(httpRequest.Form.Get("router"))
+	if err != nil {
+		controller.WriteError(httpResponse, "340001", "gateway", err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "gateway", "", nil)
+}
@@ -35,6 +35,8 @@ func (h *Handlers) Handlers(factory *goku_handler.AccountHandlerFactory) map[str
 		"/getList":        factory.NewAccountHandleFunction(operationNode, false, GetNodeList),
 		"/batchEditGroup": factory.NewAccountHandleFunction(operationNode, true, BatchEditNodeGroup),
 		"/batchDelete":    factory.NewAccountHandleFunction(operationNode, true, BatchDeleteNode),
+		"/restart":        factory.NewAccountHandleFunction(operationNode, true, RestartNode),
+		"/stop":           factory.NewAccountHandleFunction(operationNode, true, StopNode),
 	}
 }
 
@@ -0,0 +1,63 @@
+package node
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	admin_console "github.com/eolinker/goku-api-gateway/admin/console"
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/node"
+)
+
+func getRunningNodeKey(httpResponse http.ResponseWriter, httpRequest *http.Request) (string, bool) {
+	nodeID := httpRequest.PostFormValue("nodeID")
+
+	id, err := strconv.Atoi(nodeID)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"230001",
+			"node",
+			"[ERROR]Illegal nodeID!",
+			err)
+		return "", false
+	}
+	info, err := node.GetNodeInfo(id)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"330000",
+			"node",
+			"[ERROR]The node does not exist!",
+			err)
+		return "", false
+	}
+	if !admin_console.IsLive(info.NodeKey) {
+		controller.WriteError(httpResponse,
+			"330004",
+			"node",
+			"[ERROR]The node is not running!",
+			errors.New("node is not running"))
+		return "", false
+	}
+	return info.NodeKey, true
+}
+
+//RestartNode 平滑重启节点
+func RestartNode(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	nodeKey, ok := getRunningNodeKey(httpResponse, httpRequest)
+	if !ok {
+		return
+	}
+	admin_console.RestartNode(nodeKey)
+	controller.WriteResultInfo(httpResponse, "node", "", nil)
+}
+
+//StopNode 优雅停止节点
+func StopNode(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	nodeKey, ok := getRunningNodeKey(httpResponse, httpRequest)
+	if !ok {
+		return
+	}
+	admin_console.StopNode(nodeKey)
+	controller.WriteResultInfo(httpResponse, "node", "", nil)
+}
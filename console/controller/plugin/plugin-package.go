@@ -0,0 +1,99 @@
+package plugin
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	admin_console "github.com/eolinker/goku-api-gateway/admin/console"
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/plugin"
+)
+
+//GetPluginPackageList 获取插件包列表
+func GetPluginPackageList(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	result, err := plugin.GetPluginPackages()
+	if err != nil {
+		controller.WriteError(httpResponse, "210016", "plugin", "[ERROR]Fail to get plugin packages!", err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "plugin", "packageList", result)
+}
+
+//UploadPluginPackage 上传插件包并推送到在线节点，可同时声明插件配置的JSON Schema
+func UploadPluginPackage(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.Body = http.MaxBytesReader(httpResponse, httpRequest.Body, plugin.MaxPackageSize+1<<20)
+	pluginName := httpRequest.PostFormValue("pluginName")
+	version := httpRequest.PostFormValue("version")
+
+	file, _, err := httpRequest.FormFile("file")
+	if err != nil {
+		controller.WriteError(httpResponse, "210017", "plugin", "[ERROR]Param file does not exist!", err)
+		return
+	}
+	defer file.Close()
+	content, err := ioutil.ReadAll(io.LimitReader(file, plugin.MaxPackageSize+1))
+	if err != nil {
+		controller.WriteError(httpResponse, "210018", "plugin", "[ERROR]Fail to read file!", err)
+		return
+	}
+
+	schema := httpRequest.PostFormValue("configSchema")
+	if err := plugin.CheckPluginSchema(schema); err != nil {
+		controller.WriteError(httpResponse, "210023", "plugin", "[ERROR]Illegal configSchema:"+err.Error(), err)
+		return
+	}
+	pkg, err := plugin.UploadPluginPackage(pluginName, version, content)
+	if err != nil {
+		controller.WriteError(httpResponse, "210019", "plugin", err.Error(), err)
+		return
+	}
+	// 插件包保存成功后再更新Schema，上传被拒绝时保留原有的Schema
+	if schema != "" {
+		if err := plugin.SetPluginSchema(pluginName, schema); err != nil {
+			controller.WriteError(httpResponse, "210023", "plugin", "[ERROR]Fail to set configSchema:"+err.Error(), err)
+			return
+		}
+	}
+	admin_console.PushPluginPackage(pkg)
+	pkg.Content = nil
+	controller.WriteResultInfo(httpResponse, "plugin", "package", pkg)
+}
+
+//PushPluginPackage 重新推送插件包到在线节点
+func PushPluginPackage(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	pluginName := httpRequest.PostFormValue("pluginName")
+
+	pkg, err := plugin.GetPluginPackage(pluginName)
+	if err != nil {
+		controller.WriteError(httpResponse, "210020", "plugin", "[ERROR]The plugin package does not exist!", err)
+		return
+	}
+	count := admin_console.PushPluginPackage(pkg)
+	controller.WriteResultInfo(httpResponse, "plugin", "nodeCount", count)
+}
+
+//DeletePluginPackage 删除插件包
+func DeletePluginPackage(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	pluginName := httpRequest.PostFormValue("pluginName")
+
+	err := plugin.DeletePluginPackage(pluginName)
+	if err != nil {
+		controller.WriteError(httpResponse, "210021", "plugin", "[ERROR]Fail to delete plugin package!", err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "plugin", "", nil)
+}
+
+//GetPluginPackageStatus 获取各节点上插件的加载状态
+func GetPluginPackageStatus(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	pluginName := httpRequest.Form.Get("pluginName")
+
+	result, err := plugin.GetNodePluginStatus(pluginName)
+	if err != nil {
+		controller.WriteError(httpResponse, "210022", "plugin", "[ERROR]Fail to get plugin status!", err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "plugin", "statusList", result)
+}
@@ -0,0 +1,46 @@
+package plugin
+
+import (
+	"net/http"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/plugin"
+)
+
+//GetPluginPolicy 获取插件的执行策略
+func GetPluginPolicy(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	pluginName := httpRequest.Form.Get("pluginName")
+	strategyID := httpRequest.Form.Get("strategyID")
+	apiID, err := optionalInt(httpRequest.Form.Get("apiID"))
+	if err != nil {
+		controller.WriteError(httpResponse, "210024", "plugin", "[ERROR]Illegal apiID!", err)
+		return
+	}
+
+	result, err := plugin.GetPluginPolicy(pluginName, strategyID, apiID)
+	if err != nil {
+		controller.WriteError(httpResponse, "210004", "plugin", "[ERROR]Plugin name does not exist!", err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "plugin", "errorPolicy", result)
+}
+
+//SetPluginPolicy 设置插件的执行策略
+func SetPluginPolicy(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	pluginName := httpRequest.PostFormValue("pluginName")
+	strategyID := httpRequest.PostFormValue("strategyID")
+	apiID, err := optionalInt(httpRequest.PostFormValue("apiID"))
+	if err != nil {
+		controller.WriteError(httpResponse, "210024", "plugin", "[ERROR]Illegal apiID!", err)
+		return
+	}
+	policy := httpRequest.PostFormValue("errorPolicy")
+
+	err = plugin.SetPluginPolicy(pluginName, strategyID, apiID, policy)
+	if err != nil {
+		controller.WriteError(httpResponse, "210025", "plugin", "[ERROR]Illegal errorPolicy:"+err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "plugin", "", nil)
+}
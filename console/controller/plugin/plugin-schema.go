@@ -0,0 +1,34 @@
+package plugin
+
+import (
+	"net/http"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/plugin"
+)
+
+//GetPluginSchema 获取插件配置的JSON Schema
+func GetPluginSchema(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	pluginName := httpRequest.Form.Get("pluginName")
+
+	result, err := plugin.GetPluginSchema(pluginName)
+	if err != nil {
+		controller.WriteError(httpResponse, "210004", "plugin", "[ERROR]Plugin name does not exist!", err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "plugin", "configSchema", result)
+}
+
+//SetPluginSchema 设置插件配置的JSON Schema
+func SetPluginSchema(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	pluginName := httpRequest.PostFormValue("pluginName")
+	schema := httpRequest.PostFormValue("configSchema")
+
+	err := plugin.SetPluginSchema(pluginName, schema)
+	if err != nil {
+		controller.WriteError(httpResponse, "210023", "plugin", "[ERROR]Illegal configSchema:"+err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "plugin", "", nil)
+}
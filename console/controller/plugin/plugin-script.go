@@ -0,0 +1,53 @@
+package plugin
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/plugin"
+)
+
+//GetPluginScript 获取插件的JavaScript脚本
+func GetPluginScript(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	pluginName := httpRequest.Form.Get("pluginName")
+
+	result, err := plugin.GetPluginScript(pluginName)
+	if err != nil {
+		controller.WriteError(httpResponse, "210012", "plugin", "[ERROR]The plugin script does not exist!", err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "plugin", "pluginScript", result)
+}
+
+//SetPluginScript 设置插件的JavaScript脚本
+func SetPluginScript(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	pluginName := httpRequest.PostFormValue("pluginName")
+	script := httpRequest.PostFormValue("script")
+
+	timeout, err := optionalInt(httpRequest.PostFormValue("timeout"))
+	if err != nil {
+		controller.WriteError(httpResponse, "210013", "plugin", "[ERROR]Illegal timeout!", err)
+		return
+	}
+	memoryLimit, err := optionalInt(httpRequest.PostFormValue("memoryLimit"))
+	if err != nil {
+		controller.WriteError(httpResponse, "210014", "plugin", "[ERROR]Illegal memoryLimit!", err)
+		return
+	}
+
+	err = plugin.SetPluginScript(pluginName, script, timeout, memoryLimit)
+	if err != nil {
+		controller.WriteError(httpResponse, "210015", "plugin", err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "plugin", "", nil)
+}
+
+func optionalInt(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
@@ -34,6 +34,17 @@ func (h *Handlers) Handlers(factory *goku_handler.AccountHandlerFactory) map[str
 		"/batchStop":         factory.NewAccountHandleFunction(operationPlugin, true, BatchStopPlugin),
 		"/batchStart":        factory.NewAccountHandleFunction(operationPlugin, true, BatchStartPlugin),
 		"/availiable/check":  factory.NewAccountHandleFunction(operationPlugin, false, CheckPluginIsAvailable),
+		"/script/get":        factory.NewAccountHandleFunction(operationPlugin, false, GetPluginScript),
+		"/script/set":        factory.NewAccountHandleFunction(operationPlugin, true, SetPluginScript),
+		"/schema/get":        factory.NewAccountHandleFunction(operationPlugin, false, GetPluginSchema),
+		"/schema/set":        factory.NewAccountHandleFunction(operationPlugin, true, SetPluginSchema),
+		"/policy/get":        factory.NewAccountHandleFunction(operationPlugin, false, GetPluginPolicy),
+		"/policy/set":        factory.NewAccountHandleFunction(operationPlugin, true, SetPluginPolicy),
+		"/package/getList":   factory.NewAccountHandleFunction(operationPlugin, false, GetPluginPackageList),
+		"/package/upload":    factory.NewAccountHandleFunction(operationPlugin, true, UploadPluginPackage),
+		"/package/push":      factory.NewAccountHandleFunction(operationPlugin, true, PushPluginPackage),
+		"/package/delete":    factory.NewAccountHandleFunction(operationPlugin, true, DeletePluginPackage),
+		"/package/status":    factory.NewAccountHandleFunction(operationPlugin, false, GetPluginPackageStatus),
 	}
 }
 
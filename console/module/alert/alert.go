@@ -0,0 +1,194 @@
+package alert
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/general"
+	"github.com/eolinker/goku-api-gateway/common/pdao"
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const (
+	//TargetAPI 按接口统计
+	TargetAPI = "api"
+	//TargetStrategy 按策略统计
+	TargetStrategy = "strategy"
+	//TargetBalance 按负载统计
+	TargetBalance = "balance"
+
+	//MetricErrorCount 窗口内5xx请求数
+	MetricErrorCount = "error_count"
+	//MetricErrorRate 窗口内5xx请求占比，单位%
+	MetricErrorRate = "error_rate"
+	//MetricP99 窗口内p99耗时，单位ms
+	MetricP99 = "p99"
+	//MetricUpstreamUnavailable 窗口内转发失败或负载返回502~504的占比，单位%
+	MetricUpstreamUnavailable = "upstream_unavailable"
+
+	//MinWindow 最小统计窗口，单位秒
+	MinWindow = 10
+	//MaxWindow 最大统计窗口，单位秒
+	MaxWindow = 3600
+
+	timeFormat = "2006-01-02 15:04:05"
+)
+
+var (
+	alertDao dao.AlertDao
+
+	targetTypes = []string{TargetAPI, TargetStrategy, TargetBalance}
+	metrics     = []string{MetricErrorCount, MetricErrorRate, MetricP99, MetricUpstreamUnavailable}
+)
+
+func init() {
+	pdao.Need(&alertDao)
+	general.RegeditLater(start)
+}
+
+//TargetTypes 支持的统计对象
+func TargetTypes() []string {
+	return targetTypes
+}
+
+//Metrics 支持的告警指标
+func Metrics() []string {
+	return metrics
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+//CheckRule 检查告警规则
+func CheckRule(rule *entity.AlertRule) error {
+	if rule.RuleName == "" {
+		return errors.New("[ERROR]ruleName is required")
+	}
+	if !contains(targetTypes, rule.TargetType) {
+		return fmt.Errorf("[ERROR]illegal targetType:%s", rule.TargetType)
+	}
+	if !contains(metrics, rule.Metric) {
+		return fmt.Errorf("[ERROR]illegal metric:%s", rule.Metric)
+	}
+	if rule.Threshold < 0 {
+		return errors.New("[ERROR]threshold must not be negative")
+	}
+	if rule.Window < MinWindow || rule.Window > MaxWindow {
+		return fmt.Errorf("[ERROR]window must be between %d and %d seconds", MinWindow, MaxWindow)
+	}
+	if rule.Webhook != "" {
+		u, err := url.Parse(rule.Webhook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return errors.New("[ERROR]illegal webhook")
+		}
+	}
+	for _, r := range splitReceivers(rule.Receivers) {
+		if !strings.Contains(r, "@") {
+			return fmt.Errorf("[ERROR]illegal receiver:%s", r)
+		}
+	}
+	return nil
+}
+
+//AddRule 新增告警规则
+func AddRule(rule *entity.AlertRule) (int, error) {
+	if err := CheckRule(rule); err != nil {
+		return 0, err
+	}
+	return alertDao.AddAlertRule(rule)
+}
+
+//EditRule 修改告警规则
+func EditRule(rule *entity.AlertRule) error {
+	if err := CheckRule(rule); err != nil {
+		return err
+	}
+	if _, err := alertDao.GetAlertRule(rule.RuleID); err != nil {
+		return errors.New("[ERROR]alert rule does not exist")
+	}
+	return alertDao.EditAlertRule(rule)
+}
+
+//DeleteRule 删除告警规则
+func DeleteRule(ruleID int) error {
+	err := alertDao.DeleteAlertRule(ruleID)
+	if err != nil {
+		return err
+	}
+	evaluator.forget(ruleID)
+	return nil
+}
+
+//GetRule 获取告警规则
+func GetRule(ruleID int) (*entity.AlertRule, error) {
+	return alertDao.GetAlertRule(ruleID)
+}
+
+//GetRules 获取告警规则列表
+func GetRules() ([]*entity.AlertRule, error) {
+	return alertDao.GetAlertRules()
+}
+
+//Silence 静默告警规则，minutes为0时取消静默
+func Silence(ruleID int, minutes int) error {
+	if _, err := alertDao.GetAlertRule(ruleID); err != nil {
+		return errors.New("[ERROR]alert rule does not exist")
+	}
+	until := ""
+	if minutes > 0 {
+		until = time.Now().Add(time.Duration(minutes) * time.Minute).Format(timeFormat)
+	}
+	return alertDao.SetAlertRuleSilence(ruleID, until)
+}
+
+//GetHistory 获取告警记录
+func GetHistory(ruleID, page, pageSize int) ([]*entity.AlertHistory, int, error) {
+	return alertDao.GetAlertHistory(ruleID, page, pageSize)
+}
+
+//GetConfig 获取告警通知配置
+func GetConfig() (*entity.AlertConfig, error) {
+	return alertDao.GetAlertConfig()
+}
+
+//SetConfig 设置告警通知配置
+func SetConfig(c *entity.AlertConfig) error {
+	if c.Webhook != "" {
+		u, err := url.Parse(c.Webhook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return errors.New("[ERROR]illegal webhook")
+		}
+	}
+	return alertDao.SetAlertConfig(c)
+}
+
+func splitReceivers(receivers string) []string {
+	list := make([]string, 0)
+	for _, r := range strings.FieldsFunc(receivers, func(c rune) bool { return c == ',' || c == ';' || c == ' ' }) {
+		if r != "" {
+			list = append(list, r)
+		}
+	}
+	return list
+}
+
+func silenced(rule *entity.AlertRule, now time.Time) bool {
+	if rule.SilenceUntil == "" {
+		return false
+	}
+	until, err := time.ParseInLocation(timeFormat, rule.SilenceUntil, time.Local)
+	if err != nil {
+		return false
+	}
+	return now.Before(until)
+}
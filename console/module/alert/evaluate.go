@@ -0,0 +1,190 @@
+package alert
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const (
+	//EvaluateInterval 告警规则计算间隔
+	EvaluateInterval = time.Second * 10
+
+	//StatusFiring 触发告警
+	StatusFiring = "firing"
+	//StatusResolved 告警恢复
+	StatusResolved = "resolved"
+
+	//ValveWindow 接口告警阈值(alertValve)的统计窗口，单位秒
+	ValveWindow = 60
+)
+
+var (
+	evaluator = newEvaluator()
+)
+
+type stateKey struct {
+	ruleID int
+	target string
+}
+
+// ruleEvaluator 记录每个规则、统计对象的告警状态，只在状态变化时记录和通知，避免重复告警
+type ruleEvaluator struct {
+	firing map[stateKey]float64
+	locker sync.Mutex
+}
+
+func newEvaluator() *ruleEvaluator {
+	return &ruleEvaluator{
+		firing: make(map[stateKey]float64),
+	}
+}
+
+func start() error {
+	go func() {
+		ticker := time.NewTicker(EvaluateInterval)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			evaluator.evaluateAll(now)
+		}
+	}()
+	return nil
+}
+
+func (e *ruleEvaluator) forget(ruleID int) {
+	e.locker.Lock()
+	for key := range e.firing {
+		if key.ruleID == ruleID {
+			delete(e.firing, key)
+		}
+	}
+	e.locker.Unlock()
+}
+
+// forgetValves 清除已取消告警阈值的接口的告警状态
+func (e *ruleEvaluator) forgetValves(valves map[int]int) {
+	e.locker.Lock()
+	for key := range e.firing {
+		if key.ruleID < 0 && valves[-key.ruleID] <= 0 {
+			delete(e.firing, key)
+		}
+	}
+	e.locker.Unlock()
+}
+
+// valveRules 由接口的告警阈值生成隐含的规则：窗口内5xx请求数超过阈值时告警
+// 规则ID取接口ID的相反数，与告警规则表中的规则区分
+func valveRules(valves map[int]int) []*entity.AlertRule {
+	rules := make([]*entity.AlertRule, 0, len(valves))
+	for apiID, valve := range valves {
+		if valve <= 0 {
+			continue
+		}
+		rules = append(rules, &entity.AlertRule{
+			RuleID:     -apiID,
+			RuleName:   fmt.Sprintf("api %d alertValve", apiID),
+			TargetType: TargetAPI,
+			Target:     strconv.Itoa(apiID),
+			Metric:     MetricErrorCount,
+			Threshold:  float64(valve),
+			Window:     ValveWindow,
+			Enable:     1,
+		})
+	}
+	return rules
+}
+
+func (e *ruleEvaluator) evaluateAll(now time.Time) {
+	rules, err := alertDao.GetAlertRules()
+	if err != nil {
+		log.Warn("get alert rules:", err)
+		return
+	}
+	valves, err := alertDao.GetAPIAlertValves()
+	if err != nil {
+		log.Warn("get api alert valves:", err)
+	} else {
+		e.forgetValves(valves)
+		rules = append(rules, valveRules(valves)...)
+	}
+	conf, err := alertDao.GetAlertConfig()
+	if err != nil {
+		conf = new(entity.AlertConfig)
+	}
+	for _, rule := range rules {
+		if rule.Enable != 1 {
+			e.forget(rule.RuleID)
+			continue
+		}
+		stats, buckets := reports.aggregate(rule.TargetType, rule.Window, now)
+		for _, history := range e.evaluate(rule, stats, buckets, now) {
+			if history.Silenced == 0 {
+				history.NotifyResult = notify(conf, rule, history)
+			}
+			err := alertDao.AddAlertHistory(history)
+			if err != nil {
+				log.Warn("save alert history:", err)
+			}
+		}
+	}
+}
+
+// evaluate 计算单个规则，返回状态发生变化的告警记录
+func (e *ruleEvaluator) evaluate(rule *entity.AlertRule, stats map[string]*stat, buckets []float64, now time.Time) []*entity.AlertHistory {
+	isSilenced := 0
+	if silenced(rule, now) {
+		isSilenced = 1
+	}
+	newHistory := func(target string, value float64, status string) *entity.AlertHistory {
+		return &entity.AlertHistory{
+			RuleID:     rule.RuleID,
+			RuleName:   rule.RuleName,
+			TargetType: rule.TargetType,
+			Target:     target,
+			Metric:     rule.Metric,
+			Value:      value,
+			Threshold:  rule.Threshold,
+			Status:     status,
+			Silenced:   isSilenced,
+			CreateTime: now.Format(timeFormat),
+		}
+	}
+
+	e.locker.Lock()
+	defer e.locker.Unlock()
+
+	histories := make([]*entity.AlertHistory, 0)
+	for target, s := range stats {
+		if rule.Target != "" && rule.Target != target {
+			continue
+		}
+		value := s.value(rule.Metric, buckets)
+		key := stateKey{ruleID: rule.RuleID, target: target}
+		_, isFiring := e.firing[key]
+		if value > rule.Threshold {
+			e.firing[key] = value
+			if !isFiring {
+				histories = append(histories, newHistory(target, value, StatusFiring))
+			}
+		} else if isFiring {
+			delete(e.firing, key)
+			histories = append(histories, newHistory(target, value, StatusResolved))
+		}
+	}
+	// 窗口内已无数据的统计对象视为恢复
+	for key := range e.firing {
+		if key.ruleID != rule.RuleID {
+			continue
+		}
+		if _, has := stats[key.target]; has && (rule.Target == "" || rule.Target == key.target) {
+			continue
+		}
+		delete(e.firing, key)
+		histories = append(histories, newHistory(key.target, 0, StatusResolved))
+	}
+	return histories
+}
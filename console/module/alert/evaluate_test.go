@@ -0,0 +1,78 @@
+package alert
+
+import (
+	"testing"
+	"time"
+
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+func TestRuleEvaluator(t *testing.T) {
+	w := newSlidingWindow(SlotSeconds, MaxWindow)
+	e := newEvaluator()
+	rule := &entity.AlertRule{RuleID: 1, RuleName: "errors", TargetType: TargetAPI, Metric: MetricErrorRate, Threshold: 10, Window: 60}
+	now := time.Now()
+
+	w.add(&entity.MonitorReport{Items: []*entity.MonitorItem{
+		{API: 1, Requests: 10, Errors: 5},
+		{API: 2, Requests: 10, Errors: 0},
+	}}, now)
+	stats, buckets := w.aggregate(rule.TargetType, rule.Window, now)
+	histories := e.evaluate(rule, stats, buckets, now)
+	if len(histories) != 1 || histories[0].Target != "1" || histories[0].Status != StatusFiring || histories[0].Value != 50 {
+		t.Fatalf("api 1 should fire once, got %+v", histories)
+	}
+	if histories := e.evaluate(rule, stats, buckets, now); len(histories) != 0 {
+		t.Fatalf("firing alert should not repeat, got %+v", histories)
+	}
+
+	later := now.Add(time.Second * 70)
+	w.add(&entity.MonitorReport{Items: []*entity.MonitorItem{{API: 2, Requests: 10}}}, later)
+	stats, buckets = w.aggregate(rule.TargetType, rule.Window, later)
+	histories = e.evaluate(rule, stats, buckets, later)
+	if len(histories) != 1 || histories[0].Target != "1" || histories[0].Status != StatusResolved {
+		t.Fatalf("api 1 should be resolved after leaving the window, got %+v", histories)
+	}
+}
+
+func TestStatP99(t *testing.T) {
+	s := new(stat)
+	s.add(&entity.MonitorItem{Requests: 100, Values: []uint64{98, 98, 100}, Max: 80})
+	p99 := s.value(MetricP99, []float64{10, 50, 100})
+	if p99 <= 50 || p99 > 100 {
+		t.Fatalf("p99 want in (50,100], got %f", p99)
+	}
+}
+
+func TestValveRules(t *testing.T) {
+	w := newSlidingWindow(SlotSeconds, MaxWindow)
+	e := newEvaluator()
+	rules := valveRules(map[int]int{1: 3, 2: 0})
+	if len(rules) != 1 || rules[0].RuleID != -1 || rules[0].Target != "1" || rules[0].Metric != MetricErrorCount {
+		t.Fatalf("valve rules: got %+v", rules)
+	}
+	rule := rules[0]
+	now := time.Now()
+
+	w.add(&entity.MonitorReport{Items: []*entity.MonitorItem{
+		{API: 1, Requests: 10, Errors: 3},
+		{API: 2, Requests: 10, Errors: 10},
+	}}, now)
+	stats, buckets := w.aggregate(rule.TargetType, rule.Window, now)
+	if histories := e.evaluate(rule, stats, buckets, now); len(histories) != 0 {
+		t.Fatalf("errors not above valve should not fire, got %+v", histories)
+	}
+
+	w.add(&entity.MonitorReport{Items: []*entity.MonitorItem{{API: 1, Requests: 1, Errors: 1}}}, now)
+	stats, buckets = w.aggregate(rule.TargetType, rule.Window, now)
+	histories := e.evaluate(rule, stats, buckets, now)
+	if len(histories) != 1 || histories[0].Target != "1" || histories[0].Status != StatusFiring || histories[0].Value != 4 {
+		t.Fatalf("api 1 should fire, got %+v", histories)
+	}
+
+	// 取消阈值后清除告警状态
+	e.forgetValves(map[int]int{})
+	if len(e.firing) != 0 {
+		t.Fatalf("firing state should be forgotten, got %v", e.firing)
+	}
+}
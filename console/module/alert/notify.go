@@ -0,0 +1,109 @@
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/server/entity"
+	console_entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+	"github.com/eolinker/goku-api-gateway/utils"
+)
+
+const mailSender = "GoKu API Gateway"
+
+var (
+	webhookClient = &http.Client{Timeout: time.Second * 5}
+)
+
+// webhookMessage webhook推送的内容
+type webhookMessage struct {
+	RuleID     int     `json:"ruleID"`
+	RuleName   string  `json:"ruleName"`
+	Status     string  `json:"status"`
+	TargetType string  `json:"targetType"`
+	Target     string  `json:"target"`
+	Metric     string  `json:"metric"`
+	Value      float64 `json:"value"`
+	Threshold  float64 `json:"threshold"`
+	Window     int     `json:"window"`
+	Time       string  `json:"time"`
+}
+
+// notify 发送告警通知，规则未配置通知方式时使用全局配置，返回各通知方式的结果
+func notify(conf *console_entity.AlertConfig, rule *console_entity.AlertRule, history *console_entity.AlertHistory) string {
+	webhook := rule.Webhook
+	receivers := splitReceivers(rule.Receivers)
+	if conf.AlertStatus == 1 {
+		if webhook == "" {
+			webhook = conf.Webhook
+		}
+		if len(receivers) == 0 {
+			receivers = splitReceivers(conf.Receivers)
+		}
+	}
+
+	results := make([]string, 0, 2)
+	if webhook != "" {
+		results = append(results, "webhook:"+result(sendWebhook(webhook, rule, history)))
+	}
+	if len(receivers) > 0 {
+		results = append(results, "mail:"+result(sendMail(conf, receivers, rule, history)))
+	}
+	return strings.Join(results, ";")
+}
+
+func result(err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return "ok"
+}
+
+func sendWebhook(webhook string, rule *console_entity.AlertRule, history *console_entity.AlertHistory) error {
+	data, err := json.Marshal(&webhookMessage{
+		RuleID:     rule.RuleID,
+		RuleName:   rule.RuleName,
+		Status:     history.Status,
+		TargetType: history.TargetType,
+		Target:     history.Target,
+		Metric:     history.Metric,
+		Value:      history.Value,
+		Threshold:  history.Threshold,
+		Window:     rule.Window,
+		Time:       history.CreateTime,
+	})
+	if err != nil {
+		return err
+	}
+	resp, err := webhookClient.Post(webhook, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook response status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func sendMail(conf *console_entity.AlertConfig, receivers []string, rule *console_entity.AlertRule, history *console_entity.AlertHistory) error {
+	if conf.SMTPAddress == "" || conf.Sender == "" {
+		return fmt.Errorf("smtp is not configured")
+	}
+	smtpInfo := entity.SMTPInfo{
+		Address:  conf.SMTPAddress,
+		Port:     conf.SMTPPort,
+		Protocol: conf.SMTPProtocol,
+		Sender:   mailSender,
+		Account:  conf.Sender,
+		Password: conf.Password,
+	}
+	subject := fmt.Sprintf("[%s] %s %s:%s", strings.ToUpper(history.Status), rule.RuleName, history.TargetType, history.Target)
+	body := fmt.Sprintf("<p>告警规则：%s</p><p>状态：%s</p><p>对象：%s %s</p><p>指标：%s = %.2f，阈值 %.2f，统计窗口 %d 秒</p><p>时间：%s</p>",
+		rule.RuleName, history.Status, history.TargetType, history.Target, history.Metric, history.Value, history.Threshold, rule.Window, history.CreateTime)
+	return utils.SendMails(smtpInfo, receivers, subject, body)
+}
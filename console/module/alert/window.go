@@ -0,0 +1,147 @@
+package alert
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	observe "github.com/eolinker/goku-api-gateway/goku-observe"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//SlotSeconds 滑动窗口的时间片长度，与节点上报间隔一致
+const SlotSeconds = 5
+
+var (
+	reports = newSlidingWindow(SlotSeconds, MaxWindow)
+)
+
+// stat 某个统计对象在一段时间内的汇总
+type stat struct {
+	requests       uint64
+	errors         uint64
+	upstreamErrors uint64
+	values         []uint64
+	max            float64
+}
+
+func (s *stat) add(item *entity.MonitorItem) {
+	s.requests += item.Requests
+	s.errors += item.Errors
+	s.upstreamErrors += item.UpstreamErrors
+	if item.Max > s.max {
+		s.max = item.Max
+	}
+	if len(s.values) == 0 {
+		s.values = make([]uint64, len(item.Values))
+	}
+	if len(s.values) != len(item.Values) {
+		return
+	}
+	for i, v := range item.Values {
+		s.values[i] += v
+	}
+}
+
+func (s *stat) value(metric string, buckets []float64) float64 {
+	switch metric {
+	case MetricErrorCount:
+		return float64(s.errors)
+	case MetricErrorRate:
+		if s.requests == 0 {
+			return 0
+		}
+		return float64(s.errors) * 100 / float64(s.requests)
+	case MetricUpstreamUnavailable:
+		if s.requests == 0 {
+			return 0
+		}
+		return float64(s.upstreamErrors) * 100 / float64(s.requests)
+	case MetricP99:
+		if len(s.values) != len(buckets) {
+			return s.max
+		}
+		return observe.Quantile(0.99, buckets, s.values, s.max, s.requests)
+	}
+	return 0
+}
+
+// slidingWindow 按时间片保存所有节点上报的数据，超出最大窗口的时间片被丢弃
+type slidingWindow struct {
+	slot    int64
+	size    int64
+	slots   map[int64][]*entity.MonitorItem
+	buckets []float64
+	locker  sync.RWMutex
+}
+
+func newSlidingWindow(slotSeconds, maxWindow int) *slidingWindow {
+	return &slidingWindow{
+		slot:  int64(slotSeconds),
+		size:  int64(maxWindow / slotSeconds),
+		slots: make(map[int64][]*entity.MonitorItem),
+	}
+}
+
+func (w *slidingWindow) add(report *entity.MonitorReport, now time.Time) {
+	index := now.Unix() / w.slot
+
+	w.locker.Lock()
+	defer w.locker.Unlock()
+	if len(report.Buckets) > 0 {
+		w.buckets = report.Buckets
+	}
+	w.slots[index] = append(w.slots[index], report.Items...)
+	for i := range w.slots {
+		if i <= index-w.size {
+			delete(w.slots, i)
+		}
+	}
+}
+
+// aggregate 汇总最近window秒内的数据，按统计对象分组
+func (w *slidingWindow) aggregate(targetType string, window int, now time.Time) (map[string]*stat, []float64) {
+	from := (now.Unix() - int64(window)) / w.slot
+
+	w.locker.RLock()
+	defer w.locker.RUnlock()
+	stats := make(map[string]*stat)
+	for index, items := range w.slots {
+		if index <= from {
+			continue
+		}
+		for _, item := range items {
+			target, ok := targetOf(targetType, item)
+			if !ok {
+				continue
+			}
+			s, has := stats[target]
+			if !has {
+				s = new(stat)
+				stats[target] = s
+			}
+			s.add(item)
+		}
+	}
+	return stats, w.buckets
+}
+
+func targetOf(targetType string, item *entity.MonitorItem) (string, bool) {
+	switch targetType {
+	case TargetAPI:
+		return strconv.Itoa(item.API), item.API > 0
+	case TargetStrategy:
+		return item.Strategy, item.Strategy != ""
+	case TargetBalance:
+		return item.Balance, item.Balance != ""
+	}
+	return "", false
+}
+
+//Report 接收节点上报的请求统计
+func Report(report *entity.MonitorReport) {
+	if report == nil || len(report.Items) == 0 {
+		return
+	}
+	reports.add(report, time.Now())
+}
@@ -64,6 +64,11 @@ func BatchEditAPIBalance(apiIDList []string, balance string) (string, error) {
 	return r, err
 }
 
+//EditAPIRoutePriority 修改接口的路由优先级
+func EditAPIRoutePriority(apiID, priority int) error {
+	return apiDao.EditAPIRoutePriority(apiID, priority)
+}
+
 //BatchDeleteAPI 批量删除接口
 func BatchDeleteAPI(apiIDList string) (bool, string, error) {
 
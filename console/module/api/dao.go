@@ -11,8 +11,11 @@ var(
 	apiPluginDao dao.APIPluginDao
 	apiStrategyDao dao.APIStrategyDao
 	importDao dao.ImportDao
+	exportDao dao.ExportDao
+	projectDao dao.ProjectDao
+	gatewayDao dao.GatewayDao
 )
 
 func init() {
-	pdao.Need(&apiDao,&apiGroupDao,&apiPluginDao,&apiStrategyDao,&importDao)
-}
\ No newline at end of file
+	pdao.Need(&apiDao,&apiGroupDao,&apiPluginDao,&apiStrategyDao,&importDao,&exportDao,&projectDao,&gatewayDao)
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/common/jmespath"
+	"github.com/eolinker/goku-api-gateway/common/wsdl"
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/console/module/balance"
+	"github.com/eolinker/goku-api-gateway/node/gateway/application/interpreter"
+	"github.com/eolinker/goku-api-gateway/node/gateway/response"
+)
+
+//CheckLinkApis 检查编排接口的配置，路径、请求体及头部模版和JMESPath表达式须能正确编译
+func CheckLinkApis(linkApis string) error {
+	linkApis = strings.TrimSpace(linkApis)
+	if linkApis == "" {
+		return nil
+	}
+	steps := make([]config.APIStepUIConfig, 0)
+	if err := json.Unmarshal([]byte(linkApis), &steps); err != nil {
+		return err
+	}
+	for i, step := range steps {
+		if err := checkStep(&step); err != nil {
+			return fmt.Errorf("step %d: %s", i+1, err)
+		}
+	}
+	return nil
+}
+
+func checkStep(step *config.APIStepUIConfig) error {
+	if step.Decode != "" && step.Decode != "origin" && !response.HasDecoder(step.Decode) {
+		return fmt.Errorf("unknown decode %s", step.Decode)
+	}
+	if step.Encode != "" && step.Encode != "origin" && !response.HasEncoder(step.Encode) {
+		return fmt.Errorf("unknown encode %s", step.Encode)
+	}
+	if step.SOAP != nil {
+		if err := checkSOAP(step); err != nil {
+			return err
+		}
+	}
+	if _, err := interpreter.ParsePath(step.Path); err != nil {
+		return fmt.Errorf("path %s", err)
+	}
+	if step.Encode != "origin" {
+		if _, err := interpreter.Parse(strings.TrimSpace(step.Body)); err != nil {
+			return fmt.Errorf("body %s", err)
+		}
+	}
+	for _, h := range step.Headers {
+		i := strings.Index(h, ":")
+		if i < 1 {
+			return fmt.Errorf("invalid header %s", h)
+		}
+		if _, err := interpreter.Parse(strings.TrimSpace(h[i+1:])); err != nil {
+			return fmt.Errorf("header %s", err)
+		}
+	}
+	if jmespath.IsExpression(step.Target) {
+		if _, err := jmespath.Compile(step.Target); err != nil {
+			return fmt.Errorf("target %s", err)
+		}
+	}
+	if jmespath.IsExpression(step.Group) {
+		if _, err := jmespath.Compile(step.Group); err != nil {
+			return fmt.Errorf("group %s", err)
+		}
+	}
+	for _, set := range step.Set {
+		if set.Target == "" {
+			return fmt.Errorf("set %s without target", set.Origin)
+		}
+		if _, err := jmespath.Compile(set.Origin); err != nil {
+			return fmt.Errorf("set %s", err)
+		}
+	}
+	return nil
+}
+
+// checkSOAP 检查SOAP步骤选择的操作是否存在于负载的WSDL中
+func checkSOAP(step *config.APIStepUIConfig) error {
+	if step.SOAP.Operation == "" {
+		return fmt.Errorf("soap operation is required")
+	}
+	if step.SOAP.Version != "" && step.SOAP.Version != wsdl.SOAP11 && step.SOAP.Version != wsdl.SOAP12 {
+		return fmt.Errorf("invalid soap version %s", step.SOAP.Version)
+	}
+	if t := step.SOAP.PasswordType; t != "" && t != "text" && t != "digest" {
+		return fmt.Errorf("invalid soap passwordType %s", t)
+	}
+	doc, err := balance.GetWSDLDocument(step.Balance)
+	if err != nil {
+		return fmt.Errorf("balance %s: %s", step.Balance, err)
+	}
+	if _, err := doc.Find(step.SOAP.Operation, step.SOAP.Version); err != nil {
+		return err
+	}
+	if strings.TrimSpace(step.Body) != "" {
+		if _, err := interpreter.Parse(strings.TrimSpace(step.Body)); err != nil {
+			return fmt.Errorf("body %s", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/eolinker/goku-api-gateway/console/module/api/openapi"
+	"github.com/eolinker/goku-api-gateway/console/module/balance"
+	"github.com/eolinker/goku-api-gateway/console/module/service"
+	driver2 "github.com/eolinker/goku-api-gateway/server/driver"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//OpenAPIImportResult OpenAPI/Swagger导入结果
+type OpenAPIImportResult struct {
+	DryRun bool `json:"dryRun"`
+	//APIList 导入或将要导入的接口
+	APIList []*entity.ImportAPI `json:"apiList"`
+	//Conflicts 与项目中已有接口或文档内其它接口路径冲突、未导入的接口
+	Conflicts []*entity.ImportAPI `json:"conflicts"`
+	//Groups 新建的分组
+	Groups []string `json:"groups"`
+	//Balances 新建的负载
+	Balances []*openapi.Balance `json:"balances"`
+	Warnings []string           `json:"warnings"`
+}
+
+//ImportOpenAPI 导入OpenAPI 3/Swagger 2文档到项目，dryRun时只返回导入结果不写入
+func ImportOpenAPI(projectID, userID int, serviceName string, data []byte, dryRun bool) (*OpenAPIImportResult, error) {
+	if has, _ := projectDao.CheckProjectIsExist(projectID); !has {
+		return nil, errors.New("[ERROR]The project does not exist")
+	}
+	doc, err := openapi.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	defaultGroup := doc.Title
+	if defaultGroup == "" {
+		defaultGroup = "default"
+	}
+	routerName, err := gatewayDao.GetRouter()
+	if err != nil {
+		return nil, err
+	}
+	apis, balances := doc.ImportAPIs(defaultGroup, routerName)
+
+	result := &OpenAPIImportResult{
+		DryRun:    dryRun,
+		APIList:   make([]*entity.ImportAPI, 0, len(apis)),
+		Conflicts: make([]*entity.ImportAPI, 0),
+		Groups:    make([]string, 0),
+		Balances:  make([]*openapi.Balance, 0, len(balances)),
+	}
+	seen := make(map[string]bool)
+	for _, api := range apis {
+		key := api.RequestMethod + " " + api.RequestURL
+		if seen[key] || apiDao.CheckURLIsExist(api.RequestURL, api.RequestMethod, projectID, 0) {
+			result.Conflicts = append(result.Conflicts, api)
+			continue
+		}
+		seen[key] = true
+		result.APIList = append(result.APIList, api)
+	}
+
+	_, groupList, err := apiGroupDao.GetAPIGroupList(projectID)
+	if err != nil {
+		return nil, err
+	}
+	groups := make(map[string]bool)
+	for _, g := range groupList {
+		if g["parentGroupID"] == 0 {
+			groups[fmt.Sprint(g["groupName"])] = true
+		}
+	}
+	for _, api := range result.APIList {
+		if !groups[api.GroupName] {
+			groups[api.GroupName] = true
+			result.Groups = append(result.Groups, api.GroupName)
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, api := range result.APIList {
+		used[api.BalanceName] = true
+	}
+	for _, b := range balances {
+		if !used[b.Name] {
+			continue
+		}
+		if _, err := balance.Get(b.Name); err == nil {
+			continue
+		}
+		result.Balances = append(result.Balances, b)
+	}
+	result.Warnings = doc.Warnings
+	if dryRun || len(result.APIList) == 0 {
+		return result, nil
+	}
+
+	if len(result.Balances) > 0 {
+		serviceName, err = staticServiceName(serviceName)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, b := range result.Balances {
+		_, err := balance.Add(&balance.Param{
+			Name:        b.Name,
+			ServiceName: serviceName,
+			Static:      b.Static,
+			Desc:        "imported from " + doc.Title,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("add balance %s:%s", b.Name, err.Error())
+		}
+	}
+	if _, info, err := importDao.ImportAPIList(projectID, userID, result.APIList); err != nil {
+		if info != "" {
+			return nil, errors.New(info)
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// staticServiceName 导入时新建负载使用的静态服务，未指定时使用默认服务或第一个静态服务
+func staticServiceName(serviceName string) (string, error) {
+	if serviceName != "" {
+		info, err := service.Get(serviceName)
+		if err != nil {
+			return "", err
+		}
+		if info.Type != driver2.Static {
+			return "", fmt.Errorf("service %s is not static", serviceName)
+		}
+		return serviceName, nil
+	}
+	list, defaultName, err := service.SimpleList()
+	if err != nil {
+		return "", err
+	}
+	name := ""
+	for _, s := range list {
+		if s.Type != driver2.Static {
+			continue
+		}
+		if s.Name == defaultName {
+			return s.Name, nil
+		}
+		if name == "" {
+			name = s.Name
+		}
+	}
+	if name == "" {
+		return "", errors.New("no static service to create balance")
+	}
+	return name, nil
+}
+
+//ExportOpenAPI 将项目导出为OpenAPI 3文档，format为json或yaml
+func ExportOpenAPI(projectID int, format string) ([]byte, error) {
+	has, project, err := projectDao.GetProjectInfo(projectID)
+	if err != nil || !has {
+		return nil, errors.New("[ERROR]The project does not exist")
+	}
+	apiList, err := exportDao.GetExportAPIList(projectID)
+	if err != nil {
+		return nil, err
+	}
+	version := project.UpdateTime
+	if version == "" {
+		version = "1.0.0"
+	}
+	return openapi.Export(project.ProjectName, version, apiList, format)
+}
@@ -0,0 +1,48 @@
+package openapi
+
+import entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+
+//Document 解析后的接口文档，OpenAPI 3与Swagger 2统一为该结构
+type Document struct {
+	Title      string
+	Version    string
+	Servers    []*Server
+	Tags       []string
+	Operations []*Operation
+	//Warnings 无法完整转换的内容
+	Warnings []string
+}
+
+//Server 文档中的服务地址
+type Server struct {
+	URL      string
+	Protocol string
+	//Host 带端口的主机地址，相对地址时为空
+	Host     string
+	BasePath string
+}
+
+//Operation 文档中的接口
+type Operation struct {
+	Name    string
+	Tag     string
+	Path    string
+	Method  string
+	Servers []*Server
+	//Goku 由网关导出的文档中携带的转发配置
+	Goku *Extension
+}
+
+//Extension 导出文档中接口的x-goku扩展
+type Extension struct {
+	APIID         int                `json:"apiID,omitempty"`
+	RequestURL    string             `json:"requestURL,omitempty"`
+	TargetURL     string             `json:"targetURL,omitempty"`
+	TargetMethod  string             `json:"targetMethod,omitempty"`
+	BalanceName   string             `json:"balanceName,omitempty"`
+	Protocol      string             `json:"protocol,omitempty"`
+	Timeout       int                `json:"timeout,omitempty"`
+	RetryCount    int                `json:"retryCount,omitempty"`
+	RoutePriority int                `json:"routePriority,omitempty"`
+	Routes        []*entity.APIRoute `json:"routes,omitempty"`
+}
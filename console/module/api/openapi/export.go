@@ -0,0 +1,152 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+	"gopkg.in/yaml.v2"
+)
+
+type exportDocument struct {
+	OpenAPI string `json:"openapi"`
+	Info    struct {
+		Title   string `json:"title"`
+		Version string `json:"version"`
+	} `json:"info"`
+	Tags  []*exportTag                           `json:"tags,omitempty"`
+	Paths map[string]map[string]*exportOperation `json:"paths"`
+}
+
+type exportTag struct {
+	Name string `json:"name"`
+}
+
+type exportOperation struct {
+	OperationID string                     `json:"operationId"`
+	Summary     string                     `json:"summary"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Parameters  []*exportParameter         `json:"parameters,omitempty"`
+	Responses   map[string]*exportResponse `json:"responses"`
+	Goku        *Extension                 `json:"x-goku"`
+}
+
+type exportParameter struct {
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Required bool   `json:"required"`
+	Schema   struct {
+		Type    string `json:"type"`
+		Pattern string `json:"pattern,omitempty"`
+	} `json:"schema"`
+}
+
+type exportResponse struct {
+	Description string `json:"description"`
+}
+
+//Export 将项目的接口导出为OpenAPI 3文档，format为json或yaml，网关路由信息写入x-goku扩展
+func Export(title, version string, apis []*entity.ExportAPI, format string) ([]byte, error) {
+	doc := &exportDocument{
+		OpenAPI: "3.0.3",
+		Paths:   make(map[string]map[string]*exportOperation),
+	}
+	doc.Info.Title = title
+	doc.Info.Version = version
+
+	tags := make(map[string]bool)
+	for _, api := range apis {
+		if api.GroupName != "" && !tags[api.GroupName] {
+			tags[api.GroupName] = true
+			doc.Tags = append(doc.Tags, &exportTag{Name: api.GroupName})
+		}
+		path, params := exportPath(api.RequestURL)
+		item, has := doc.Paths[path]
+		if !has {
+			item = make(map[string]*exportOperation)
+			doc.Paths[path] = item
+		}
+		apiMethods := strings.Split(api.RequestMethod, ",")
+		for _, m := range apiMethods {
+			m = strings.ToLower(strings.TrimSpace(m))
+			if _, has := item[m]; has || m == "" {
+				// 不同host下的同路径接口只保留第一个
+				continue
+			}
+			op := &exportOperation{
+				OperationID: fmt.Sprintf("api%d", api.APIID),
+				Summary:     api.APIName,
+				Parameters:  params,
+				Responses: map[string]*exportResponse{
+					"default": {Description: "upstream response"},
+				},
+				Goku: &Extension{
+					APIID:         api.APIID,
+					RequestURL:    api.RequestURL,
+					TargetURL:     api.TargetURL,
+					TargetMethod:  api.TargetMethod,
+					BalanceName:   api.BalanceName,
+					Protocol:      api.Protocol,
+					Timeout:       api.Timeout,
+					RetryCount:    api.RetryCount,
+					RoutePriority: api.RoutePriority,
+					Routes:        api.Routes,
+				},
+			}
+			if len(apiMethods) > 1 {
+				op.OperationID = fmt.Sprintf("api%d_%s", api.APIID, m)
+			}
+			if api.GroupName != "" {
+				op.Tags = []string{api.GroupName}
+			}
+			item[m] = op
+		}
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil || format != "yaml" {
+		return data, err
+	}
+	var v yaml.MapSlice
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(v)
+}
+
+// exportPath 将网关路由规则转换为OpenAPI路径，host前缀仅保留在x-goku的requestURL中
+func exportPath(requestURL string) (string, []*exportParameter) {
+	path := requestURL
+	if !strings.HasPrefix(path, "/") {
+		if i := strings.Index(path, "/"); i >= 0 {
+			path = path[i:]
+		} else {
+			path = "/"
+		}
+	}
+	parts := strings.Split(path, "/")
+	params := make([]*exportParameter, 0)
+	for i, part := range parts {
+		var name, pattern string
+		switch {
+		case strings.HasPrefix(part, ":") && len(part) > 1:
+			name = part[1:]
+		case strings.HasPrefix(part, "*") && len(part) > 1:
+			name = part[1:]
+		case strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}"):
+			name = part[1 : len(part)-1]
+			if j := strings.Index(name, ":"); j >= 0 {
+				name, pattern = name[:j], name[j+1:]
+			}
+		default:
+			continue
+		}
+		parts[i] = "{" + name + "}"
+		p := &exportParameter{Name: name, In: "path", Required: true}
+		p.Schema.Type = "string"
+		p.Schema.Pattern = pattern
+		params = append(params, p)
+	}
+	return strings.Join(parts, "/"), params
+}
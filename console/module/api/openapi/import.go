@@ -0,0 +1,157 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/node/router"
+	"github.com/eolinker/goku-api-gateway/node/router/tolerant"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const defaultTimeout = 2000
+
+//Balance 由服务地址生成的静态负载
+type Balance struct {
+	Name   string `json:"balanceName"`
+	Static string `json:"static"`
+}
+
+//BalanceName 服务地址对应的负载名称，只包含字母、数字及下划线
+func (s *Server) BalanceName() string {
+	return "openapi_" + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, s.Host)
+}
+
+//ImportAPIs 将文档转换为待导入的接口及需要的负载，未设置tag的接口归入defaultGroup；
+//routerName为节点使用的路由实现，路径参数转换为其支持的写法
+func (doc *Document) ImportAPIs(defaultGroup, routerName string) ([]*entity.ImportAPI, []*Balance) {
+	apis := make([]*entity.ImportAPI, 0, len(doc.Operations))
+	balances := make([]*Balance, 0, len(doc.Servers))
+	hasBalance := make(map[string]bool)
+	for _, op := range doc.Operations {
+		requestURL := op.Path
+		if op.Goku != nil && op.Goku.RequestURL != "" {
+			// 网关导出的文档按原路由规则还原
+			requestURL = op.Goku.RequestURL
+		}
+		api := &entity.ImportAPI{
+			APIName:       op.Name,
+			GroupName:     op.Tag,
+			RequestURL:    doc.routePath(op, requestURL, routerName),
+			RequestMethod: op.Method,
+			TargetURL:     targetPath(op.Path),
+			TargetMethod:  op.Method,
+			Protocol:      "http",
+			Timeout:       defaultTimeout,
+		}
+		if api.GroupName == "" {
+			api.GroupName = defaultGroup
+		}
+		apis = append(apis, api)
+
+		if g := op.Goku; g != nil {
+			// 网关导出的文档按原转发配置还原
+			if g.TargetURL != "" {
+				api.TargetURL = g.TargetURL
+			}
+			if g.TargetMethod != "" {
+				api.TargetMethod = g.TargetMethod
+			}
+			if g.Protocol != "" {
+				api.Protocol = g.Protocol
+			}
+			if g.Timeout > 0 {
+				api.Timeout = g.Timeout
+			}
+			api.BalanceName = g.BalanceName
+			api.RetryCount = g.RetryCount
+			continue
+		}
+
+		servers := op.Servers
+		if len(servers) == 0 {
+			servers = doc.Servers
+		}
+		if len(servers) == 0 {
+			doc.Warnings = append(doc.Warnings, fmt.Sprintf("%s %s: no server, balance is empty", op.Method, op.Path))
+			continue
+		}
+		server := servers[0]
+		api.TargetURL = server.BasePath + targetPath(op.Path)
+		if server.Host == "" {
+			doc.Warnings = append(doc.Warnings, fmt.Sprintf("%s %s: server %s has no host, balance is empty", op.Method, op.Path, server.URL))
+			continue
+		}
+		api.Protocol = server.Protocol
+		api.BalanceName = server.BalanceName()
+		if !hasBalance[api.BalanceName] {
+			hasBalance[api.BalanceName] = true
+			balances = append(balances, &Balance{Name: api.BalanceName, Static: server.Host})
+		}
+	}
+	return apis, balances
+}
+
+// routePath 将{name}、{name:正则}形式的路径参数转换为路由规则，
+// 只有tolerant路由支持{name:正则}及host前缀，其它路由（默认的httprouter）使用:name并去掉host，正则及host约束无法生效
+func (doc *Document) routePath(op *Operation, path string, routerName string) string {
+	if routerName != tolerant.Name && !strings.HasPrefix(path, "/") {
+		host := path
+		path = "/"
+		if i := strings.Index(host, "/"); i >= 0 {
+			host, path = host[:i], host[i:]
+		}
+		doc.Warnings = append(doc.Warnings, fmt.Sprintf("%s %s: host %s is not supported by router %s, any host is matched", op.Method, op.Path, host, routerNameOrDefault(routerName)))
+	}
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if !strings.HasPrefix(part, "{") || !strings.HasSuffix(part, "}") {
+			continue
+		}
+		name, expr := part[1:len(part)-1], ""
+		if j := strings.Index(name, ":"); j >= 0 {
+			name, expr = name[:j], name[j+1:]
+		}
+		if expr != "" && routerName == tolerant.Name {
+			continue
+		}
+		if expr != "" {
+			doc.Warnings = append(doc.Warnings, fmt.Sprintf("%s %s: pattern %s of parameter %s is not supported by router %s, any value is matched", op.Method, op.Path, expr, name, routerNameOrDefault(routerName)))
+		}
+		parts[i] = ":" + name
+	}
+	return strings.Join(parts, "/")
+}
+
+func routerNameOrDefault(name string) string {
+	if name == "" {
+		return router.DefaultFactory
+	}
+	return name
+}
+
+// targetPath 将路由中的路径参数转换为转发路径中的{{restful.name}}
+func targetPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		name := ""
+		switch {
+		case strings.HasPrefix(part, ":"):
+			name = part[1:]
+		case strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}"):
+			name = part[1 : len(part)-1]
+			if j := strings.Index(name, ":"); j >= 0 {
+				name = name[:j]
+			}
+		default:
+			continue
+		}
+		parts[i] = "{{restful." + name + "}}"
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,181 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	"github.com/eolinker/goku-api-gateway/node/router"
+	"github.com/eolinker/goku-api-gateway/node/router/httprouter"
+	"github.com/eolinker/goku-api-gateway/node/router/tolerant"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const swagger = `
+swagger: "2.0"
+info:
+  title: pets
+  version: "1.0"
+host: pets.example.com
+basePath: /v1
+schemes: [https]
+paths:
+  /pets/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        type: string
+        pattern: "^[0-9]+$"
+    get:
+      summary: get pet
+      tags: [pet]
+    delete:
+      operationId: deletePet
+`
+
+const openapi3 = `{
+  "openapi": "3.0.0",
+  "info": {"title": "store", "version": "1"},
+  "servers": [{"url": "{scheme}://store.example.com:8080/api", "variables": {"scheme": {"default": "http"}}}],
+  "paths": {"/orders": {"post": {"summary": "create order", "tags": ["order"]}}}
+}`
+
+func TestParseSwagger(t *testing.T) {
+	doc, err := Parse([]byte(swagger))
+	if err != nil {
+		t.Fatal(err)
+	}
+	apis, balances := doc.ImportAPIs("default", tolerant.Name)
+	if len(apis) != 2 || len(balances) != 1 {
+		t.Fatalf("got %d apis %d balances", len(apis), len(balances))
+	}
+	get := apis[0]
+	if get.RequestMethod != "GET" || get.RequestURL != "/pets/{id:[0-9]+}" || get.GroupName != "pet" {
+		t.Errorf("unexpected api %+v", get)
+	}
+	if len(doc.Warnings) != 0 {
+		t.Errorf("unexpected warnings %v", doc.Warnings)
+	}
+	if get.TargetURL != "/v1/pets/{{restful.id}}" || get.Protocol != "https" {
+		t.Errorf("unexpected target %s %s", get.Protocol, get.TargetURL)
+	}
+	if apis[1].APIName != "deletePet" || apis[1].GroupName != "default" {
+		t.Errorf("unexpected api %+v", apis[1])
+	}
+	if balances[0].Name != "openapi_pets_example_com_443" || balances[0].Static != "pets.example.com:443" {
+		t.Errorf("unexpected balance %+v", balances[0])
+	}
+}
+
+func TestParseOpenAPI3(t *testing.T) {
+	doc, err := Parse([]byte(openapi3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	apis, balances := doc.ImportAPIs("default", "")
+	if len(apis) != 1 || apis[0].TargetURL != "/api/orders" || balances[0].Static != "store.example.com:8080" {
+		t.Fatalf("unexpected result %+v %+v", apis, balances)
+	}
+	if _, err := Parse([]byte(`{"info":{}}`)); err != ErrUnknownVersion {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestExport(t *testing.T) {
+	data, err := Export("store", "1", []*entity.ExportAPI{{
+		APIID:         1,
+		APIName:       "get order",
+		GroupName:     "order",
+		RequestURL:    "api.example.com/orders/{id:[0-9]+}",
+		RequestMethod: "GET,POST",
+		TargetURL:     "/orders/{{restful.id}}",
+		BalanceName:   "store",
+		Routes:        []*entity.APIRoute{{StrategyID: "s1", StrategyName: "open"}},
+	}}, "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Operations) != 2 || doc.Operations[0].Path != "/orders/{id:[0-9]+}" {
+		t.Fatalf("unexpected operations %+v", doc.Operations)
+	}
+	apis, _ := doc.ImportAPIs("default", tolerant.Name)
+	if apis[0].RequestURL != "api.example.com/orders/{id:[0-9]+}" || apis[0].BalanceName != "store" || apis[0].GroupName != "order" {
+		t.Errorf("unexpected api %+v", apis[0])
+	}
+}
+
+func TestImportHostRoute(t *testing.T) {
+	data, err := Export("store", "1", []*entity.ExportAPI{{
+		APIID:         1,
+		APIName:       "get order",
+		RequestURL:    "api.example.com/orders/{id:[0-9]+}",
+		RequestMethod: "GET",
+		TargetURL:     "/orders/{{restful.id}}",
+	}}, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		router     string
+		requestURL string
+		warnings   int
+	}{
+		{tolerant.Name, "api.example.com/orders/{id:[0-9]+}", 0},
+		{httprouter.Name, "/orders/:id", 2},
+	} {
+		doc, err := Parse(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		apis, _ := doc.ImportAPIs("default", c.router)
+		if apis[0].RequestURL != c.requestURL || len(doc.Warnings) != c.warnings {
+			t.Errorf("%s: got %s %v", c.router, apis[0].RequestURL, doc.Warnings)
+			continue
+		}
+
+		f, _ := router.GetFactory(c.router)
+		r := f.New()
+		id := ""
+		r.AddRouter(apis[0].RequestMethod, apis[0].RequestURL, router.HandleFunc(func(ctx *common.Context) {
+			id = ctx.RestfulParam["id"]
+		}))
+		req := httptest.NewRequest(http.MethodGet, "http://api.example.com/orders/7", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req, common.NewContext(req, "test", w))
+		if id != "7" {
+			t.Errorf("%s: imported path not matched, id=%q", c.router, id)
+		}
+	}
+}
+
+func TestImportDefaultRouter(t *testing.T) {
+	doc, err := Parse([]byte(swagger))
+	if err != nil {
+		t.Fatal(err)
+	}
+	apis, _ := doc.ImportAPIs("default", "")
+	if apis[0].RequestURL != "/pets/:id" || apis[0].TargetURL != "/v1/pets/{{restful.id}}" {
+		t.Fatalf("unexpected api %+v", apis[0])
+	}
+	if len(doc.Warnings) != 2 {
+		t.Errorf("pattern should be warned: %v", doc.Warnings)
+	}
+
+	r := httprouter.Factory().New()
+	id := ""
+	r.AddRouter(apis[0].RequestMethod, apis[0].RequestURL, router.HandleFunc(func(ctx *common.Context) {
+		id = ctx.RestfulParam["id"]
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/pets/12", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req, common.NewContext(req, "test", w))
+	if id != "12" {
+		t.Errorf("imported path not matched, id=%q", id)
+	}
+}
@@ -0,0 +1,291 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	//ErrUnknownVersion 不是OpenAPI 3或Swagger 2文档
+	ErrUnknownVersion = errors.New("document is neither openapi 3 nor swagger 2")
+
+	methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+	variableExp = regexp.MustCompile(`\{([^{}]+)\}`)
+)
+
+type rawDocument struct {
+	Swagger string `json:"swagger"`
+	OpenAPI string `json:"openapi"`
+	Info    struct {
+		Title   string `json:"title"`
+		Version string `json:"version"`
+	} `json:"info"`
+	Servers  []*rawServer `json:"servers"`
+	Host     string       `json:"host"`
+	BasePath string       `json:"basePath"`
+	Schemes  []string     `json:"schemes"`
+	Tags     []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+	Paths map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+type rawServer struct {
+	URL       string `json:"url"`
+	Variables map[string]struct {
+		Default string `json:"default"`
+	} `json:"variables"`
+}
+
+type rawParameter struct {
+	Name    string `json:"name"`
+	In      string `json:"in"`
+	Pattern string `json:"pattern"`
+	Schema  struct {
+		Pattern string `json:"pattern"`
+	} `json:"schema"`
+}
+
+type rawOperation struct {
+	OperationID string          `json:"operationId"`
+	Summary     string          `json:"summary"`
+	Tags        []string        `json:"tags"`
+	Parameters  []*rawParameter `json:"parameters"`
+	Servers     []*rawServer    `json:"servers"`
+	Goku        *Extension      `json:"x-goku"`
+}
+
+//Parse 解析JSON或YAML格式的OpenAPI 3/Swagger 2文档
+func Parse(data []byte) (*Document, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] != '{' {
+		var v interface{}
+		if err := yaml.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		d, err := json.Marshal(toJSONValue(v))
+		if err != nil {
+			return nil, err
+		}
+		data = d
+	}
+	raw := new(rawDocument)
+	if err := json.Unmarshal(data, raw); err != nil {
+		return nil, err
+	}
+
+	doc := &Document{
+		Title:   raw.Info.Title,
+		Version: raw.Info.Version,
+	}
+	switch {
+	case strings.HasPrefix(raw.OpenAPI, "3."):
+		doc.Servers = doc.parseServers(raw.Servers)
+	case strings.HasPrefix(raw.Swagger, "2."):
+		doc.Servers = doc.swaggerServers(raw)
+	default:
+		return nil, ErrUnknownVersion
+	}
+
+	tags := make(map[string]bool)
+	for _, t := range raw.Tags {
+		if t.Name != "" && !tags[t.Name] {
+			tags[t.Name] = true
+			doc.Tags = append(doc.Tags, t.Name)
+		}
+	}
+
+	paths := make([]string, 0, len(raw.Paths))
+	for p := range raw.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		item := raw.Paths[p]
+		var common []*rawParameter
+		if v, has := item["parameters"]; has {
+			json.Unmarshal(v, &common)
+		}
+		var servers []*rawServer
+		if v, has := item["servers"]; has {
+			json.Unmarshal(v, &servers)
+		}
+		for _, m := range methods {
+			v, has := item[m]
+			if !has {
+				continue
+			}
+			op := new(rawOperation)
+			if err := json.Unmarshal(v, op); err != nil {
+				return nil, fmt.Errorf("%s %s: %s", strings.ToUpper(m), p, err.Error())
+			}
+			if len(op.Servers) == 0 {
+				op.Servers = servers
+			}
+			o := doc.operation(p, m, op, append(common, op.Parameters...))
+			if o.Tag != "" && !tags[o.Tag] {
+				tags[o.Tag] = true
+				doc.Tags = append(doc.Tags, o.Tag)
+			}
+			doc.Operations = append(doc.Operations, o)
+		}
+	}
+	return doc, nil
+}
+
+func (doc *Document) operation(path, method string, op *rawOperation, params []*rawParameter) *Operation {
+	o := &Operation{
+		Name:   op.Summary,
+		Path:   doc.convertPath(path, params),
+		Method: strings.ToUpper(method),
+		Goku:   op.Goku,
+	}
+	if o.Name == "" {
+		o.Name = op.OperationID
+	}
+	if o.Name == "" {
+		o.Name = o.Method + " " + path
+	}
+	if len(op.Tags) > 0 {
+		o.Tag = op.Tags[0]
+	}
+	if len(op.Servers) > 0 {
+		o.Servers = doc.parseServers(op.Servers)
+	}
+	return o
+}
+
+// convertPath 将路径参数转换为网关路由规则，参数带pattern时转换为{name:正则}
+func (doc *Document) convertPath(path string, params []*rawParameter) string {
+	patterns := make(map[string]string)
+	for _, p := range params {
+		if p == nil || p.In != "path" {
+			continue
+		}
+		if p.Schema.Pattern != "" {
+			patterns[p.Name] = p.Schema.Pattern
+		} else if p.Pattern != "" {
+			patterns[p.Name] = p.Pattern
+		}
+	}
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if !strings.Contains(part, "{") {
+			continue
+		}
+		if !strings.HasPrefix(part, "{") || !strings.HasSuffix(part, "}") || strings.Count(part, "{") > 1 {
+			doc.Warnings = append(doc.Warnings, fmt.Sprintf("path %s: parameter inside segment %s is matched literally", path, part))
+			continue
+		}
+		name := part[1 : len(part)-1]
+		if pattern, has := patterns[name]; has {
+			pattern = strings.TrimSuffix(strings.TrimPrefix(pattern, "^"), "$")
+			parts[i] = "{" + name + ":" + pattern + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+func (doc *Document) parseServers(servers []*rawServer) []*Server {
+	list := make([]*Server, 0, len(servers))
+	for _, s := range servers {
+		if s == nil || s.URL == "" {
+			continue
+		}
+		u := variableExp.ReplaceAllStringFunc(s.URL, func(v string) string {
+			name := v[1 : len(v)-1]
+			if variable, has := s.Variables[name]; has {
+				return variable.Default
+			}
+			return v
+		})
+		server, err := parseServer(u)
+		if err != nil {
+			doc.Warnings = append(doc.Warnings, fmt.Sprintf("server %s: %s", s.URL, err.Error()))
+			continue
+		}
+		list = append(list, server)
+	}
+	return list
+}
+
+func (doc *Document) swaggerServers(raw *rawDocument) []*Server {
+	if raw.Host == "" {
+		if raw.BasePath == "" {
+			return nil
+		}
+		return []*Server{{URL: raw.BasePath, BasePath: strings.TrimSuffix(raw.BasePath, "/")}}
+	}
+	schemes := raw.Schemes
+	if len(schemes) == 0 {
+		schemes = []string{"http"}
+	}
+	list := make([]*Server, 0, len(schemes))
+	for _, scheme := range schemes {
+		server, err := parseServer(scheme + "://" + raw.Host + raw.BasePath)
+		if err != nil {
+			doc.Warnings = append(doc.Warnings, fmt.Sprintf("host %s: %s", raw.Host, err.Error()))
+			continue
+		}
+		list = append(list, server)
+	}
+	return list
+}
+
+func parseServer(s string) (*Server, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	server := &Server{
+		URL:      s,
+		Protocol: strings.ToLower(u.Scheme),
+		Host:     strings.ToLower(u.Host),
+		BasePath: strings.TrimSuffix(u.Path, "/"),
+	}
+	if server.Host == "" {
+		return server, nil
+	}
+	switch server.Protocol {
+	case "http", "https":
+	case "":
+		server.Protocol = "http"
+	default:
+		return nil, fmt.Errorf("unsupported protocol %s", u.Scheme)
+	}
+	if u.Port() == "" {
+		if server.Protocol == "https" {
+			server.Host += ":443"
+		} else {
+			server.Host += ":80"
+		}
+	}
+	return server, nil
+}
+
+// toJSONValue 将yaml解析出的map[interface{}]interface{}转换为可JSON序列化的结构
+func toJSONValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			m[fmt.Sprint(k)] = toJSONValue(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range value {
+			value[i] = toJSONValue(item)
+		}
+		return value
+	}
+	return v
+}
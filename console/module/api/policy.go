@@ -0,0 +1,154 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/console/module/balance"
+	"github.com/eolinker/goku-api-gateway/console/module/jsonconf"
+	"github.com/eolinker/goku-api-gateway/node/gateway/application/transform"
+)
+
+//接口上以JSON保存的策略，名称同时为保存的列名、提交的参数名及返回的字段名
+const (
+	//PolicyTransform 请求及响应转换
+	PolicyTransform = "transform"
+	//PolicyTraffic 流量拆分
+	PolicyTraffic = "traffic"
+	//PolicyMirror 流量镜像
+	PolicyMirror = "mirror"
+	//PolicyRetry 重试策略
+	PolicyRetry = "retryPolicy"
+	//PolicyHedge 对冲请求
+	PolicyHedge = "hedge"
+)
+
+// apiPolicy 接口策略的配置类型，empty不为空时，整理后没有生效内容的配置按未设置保存
+type apiPolicy struct {
+	new   func() jsonconf.Checker
+	empty func(v jsonconf.Checker) bool
+}
+
+var policies = map[string]*apiPolicy{
+	PolicyTransform: {
+		new: func() jsonconf.Checker { return new(config.TransformConfig) },
+		empty: func(v jsonconf.Checker) bool {
+			t := v.(*config.TransformConfig)
+			return t.Request == nil && t.Response == nil
+		},
+	},
+	PolicyTraffic: {
+		new: func() jsonconf.Checker { return new(config.TrafficConfig) },
+		empty: func(v jsonconf.Checker) bool {
+			return len(v.(*config.TrafficConfig).Splits) == 0
+		},
+	},
+	PolicyMirror: {
+		new: func() jsonconf.Checker { return new(config.MirrorConfig) },
+	},
+	PolicyRetry: {
+		new: func() jsonconf.Checker { return new(config.RetryConfig) },
+	},
+	PolicyHedge: {
+		new: func() jsonconf.Checker { return new(config.HedgeConfig) },
+	},
+}
+
+// balanceReferrer 引用了负载的策略
+type balanceReferrer interface {
+	Balances() []string
+}
+
+// templateHolder 包含模版的策略
+type templateHolder interface {
+	Templates() []string
+}
+
+func getPolicy(name string) (*apiPolicy, error) {
+	p, has := policies[name]
+	if !has {
+		return nil, fmt.Errorf("unknown api policy %s", name)
+	}
+	return p, nil
+}
+
+//GetAPIPolicy 获取接口的策略，未设置时返回空配置
+func GetAPIPolicy(name string, apiID int) (interface{}, error) {
+	p, err := getPolicy(name)
+	if err != nil {
+		return nil, err
+	}
+	text, err := apiDao.GetAPIPolicy(apiID, name)
+	if err != nil {
+		return nil, err
+	}
+	v := p.new()
+	if err := jsonconf.Load(text, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+//CheckAPIPolicy 检查接口的策略及其中的模版、引用的负载是否存在，返回整理后的配置
+func CheckAPIPolicy(name, text string, balances map[string]bool) (string, error) {
+	p, err := getPolicy(name)
+	if err != nil {
+		return "", err
+	}
+	v := p.new()
+	has, err := jsonconf.Parse(text, v)
+	if err != nil || !has {
+		return "", err
+	}
+	if t, ok := v.(templateHolder); ok {
+		for _, tpl := range t.Templates() {
+			if _, err := transform.Parse(tpl); err != nil {
+				return "", err
+			}
+		}
+	}
+	if b, ok := v.(balanceReferrer); ok {
+		for _, name := range b.Balances() {
+			if !balances[name] {
+				return "", fmt.Errorf("balance %s does not exist", name)
+			}
+		}
+	}
+	if p.empty != nil && p.empty(v) {
+		return "", nil
+	}
+	return jsonconf.Encode(v), nil
+}
+
+//SetAPIPolicy 设置接口的策略，为空时清除
+func SetAPIPolicy(name string, apiID int, text string) error {
+	p, err := getPolicy(name)
+	if err != nil {
+		return err
+	}
+	var balances map[string]bool
+	if _, ok := p.new().(balanceReferrer); ok {
+		balances, err = balanceSet()
+		if err != nil {
+			return err
+		}
+	}
+	text, err = CheckAPIPolicy(name, text, balances)
+	if err != nil {
+		return err
+	}
+	return apiDao.EditAPIPolicy(apiID, name, text)
+}
+
+// balanceSet 当前全部负载名称
+func balanceSet() (map[string]bool, error) {
+	_, names, err := balance.GetBalancNames()
+	if err != nil {
+		return nil, err
+	}
+	balances := make(map[string]bool, len(names))
+	for _, name := range names {
+		balances[name] = true
+	}
+	return balances, nil
+}
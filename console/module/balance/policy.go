@@ -0,0 +1,68 @@
+package balance
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/console/module/jsonconf"
+)
+
+//负载上以JSON保存的策略，名称同时为保存的列名、提交的参数名及返回的字段名
+const (
+	//PolicySubset 实例子集
+	PolicySubset = "subset"
+	//PolicyTransport 转发连接配置
+	PolicyTransport = "transport"
+)
+
+var policies = map[string]func() jsonconf.Checker{
+	PolicySubset:    func() jsonconf.Checker { return new(config.SubsetConfig) },
+	PolicyTransport: func() jsonconf.Checker { return new(config.TransportConfig) },
+}
+
+func newPolicy(name string) (jsonconf.Checker, error) {
+	create, has := policies[name]
+	if !has {
+		return nil, fmt.Errorf("unknown balance policy %s", name)
+	}
+	return create(), nil
+}
+
+//GetPolicy 获取负载的策略，未设置时返回空配置
+func GetPolicy(name, balanceName string) (interface{}, error) {
+	v, err := newPolicy(name)
+	if err != nil {
+		return nil, err
+	}
+	text, err := balanceDao.GetPolicy(balanceName, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := jsonconf.Load(text, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+//CheckPolicy 检查负载的策略，返回整理后的配置
+func CheckPolicy(name, text string) (string, error) {
+	v, err := newPolicy(name)
+	if err != nil {
+		return "", err
+	}
+	return jsonconf.Normalize(text, v)
+}
+
+//SavePolicy 保存负载的策略，为空时清除
+func SavePolicy(name, balanceName, text string) error {
+	if _, err := balanceDao.Get(balanceName); err != nil {
+		return err
+	}
+	text, err := CheckPolicy(name, text)
+	if err != nil {
+		return err
+	}
+	now := time.Now().Format("2006-01-02 15:04:05")
+	return balanceDao.SavePolicy(balanceName, name, text, now)
+}
@@ -0,0 +1,46 @@
+package balance
+
+import (
+	"strings"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/wsdl"
+)
+
+//GetWSDL 获取负载的WSDL文档
+func GetWSDL(name string) (string, error) {
+	return balanceDao.GetWSDL(name)
+}
+
+//SaveWSDL 保存负载的WSDL文档，文档须能解析出SOAP操作，为空时清除
+func SaveWSDL(name, text string) ([]*wsdl.Operation, error) {
+	if _, err := balanceDao.Get(name); err != nil {
+		return nil, err
+	}
+	text = strings.TrimSpace(text)
+	operations := make([]*wsdl.Operation, 0)
+	if text != "" {
+		doc, err := wsdl.Parse([]byte(text))
+		if err != nil {
+			return nil, err
+		}
+		operations = doc.Operations
+	}
+	now := time.Now().Format("2006-01-02 15:04:05")
+	if err := balanceDao.SaveWSDL(name, text, now); err != nil {
+		return nil, err
+	}
+	return operations, nil
+}
+
+//GetWSDLDocument 获取负载解析后的WSDL
+func GetWSDLDocument(name string) (*wsdl.Document, error) {
+	text, err := balanceDao.GetWSDL(name)
+	if err != nil {
+		return nil, err
+	}
+	if text == "" {
+		return nil, wsdl.ErrNoOperation
+	}
+	return wsdl.Parse([]byte(text))
+}
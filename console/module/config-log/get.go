@@ -39,9 +39,12 @@ func GetAccess() (*AccessConfig, error) {
 	c := new(AccessConfig)
 	c.Periods = Periods
 	c.Expires = Expires
+	c.Formats = Formats
+	c.InitDynamicFields()
 	if e != nil || config == nil {
 		auto.SetDefaults(c)
 		c.Name = AccessLog
+		c.Format = Formats[0].Name
 
 		c.Period = log.PeriodHour.String()
 		c.Expire = ExpireDefault
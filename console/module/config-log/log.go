@@ -3,8 +3,13 @@ package config_log
 import "C"
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/eolinker/goku-api-gateway/config"
+
 	log "github.com/eolinker/goku-api-gateway/goku-log"
 	access_field "github.com/eolinker/goku-api-gateway/server/access-field"
 	entity "github.com/eolinker/goku-api-gateway/server/entity/config-log"
@@ -61,6 +66,27 @@ var (
 			Title: "小时",
 		},
 	}
+	//Formats access日志输出格式
+	Formats = []NameTitle{
+		{
+			Name:  "text",
+			Title: "文本",
+		},
+		{
+			Name:  "json",
+			Title: "JSON",
+		},
+		{
+			Name:  "logfmt",
+			Title: "logfmt",
+		},
+	}
+	//SinkTypes access日志外部输出类型
+	SinkTypes = map[string]int{
+		"syslog": 1,
+		"http":   1,
+		"kafka":  1,
+	}
 	//Levels 日志级别
 	Levels = []NameTitle{
 		{
@@ -102,6 +128,8 @@ type Param struct {
 	Period string
 	Fields string
 	Expire int
+	Format string
+	Sinks  string
 }
 
 //PutParam put方式所需参数
@@ -144,6 +172,8 @@ type AccessParam struct {
 	Period string `opt:"period,require"`
 	Fields string `opt:"fields,require"`
 	Expire int    `opt:"expire,require"`
+	Output string `opt:"format"`
+	Sinks  string `opt:"sinks"`
 }
 
 //Format 格式化
@@ -152,6 +182,27 @@ func (p *AccessParam) Format() (*Param, error) {
 	if err != nil {
 		return nil, err
 	}
+	output := p.Output
+	if output == "" {
+		output = Formats[0].Name
+	}
+	if !isFormat(output) {
+		return nil, fmt.Errorf("invalid format:%s", output)
+	}
+	sinks, err := parseSinks(p.Sinks)
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]*AccessField, 0)
+	err = json.Unmarshal([]byte(p.Fields), &fields)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fields:%s", err.Error())
+	}
+	for _, f := range fields {
+		if f == nil || !access_field.IsValid(f.Name) {
+			return nil, errors.New("invalid field")
+		}
+	}
 	return &Param{
 		Enable: p.Enable,
 		Dir:    p.Dir,
@@ -160,9 +211,57 @@ func (p *AccessParam) Format() (*Param, error) {
 		Period: period.String(),
 		Fields: p.Fields,
 		Expire: p.Expire,
+		Format: output,
+		Sinks:  sinks,
 	}, nil
 }
 
+func isFormat(format string) bool {
+	for _, f := range Formats {
+		if f.Name == format {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSinks 检查外部输出配置，返回规范化后的json
+func parseSinks(data string) (string, error) {
+	if strings.TrimSpace(data) == "" {
+		return "", nil
+	}
+	sinks := make([]*config.AccessLogSink, 0)
+	err := json.Unmarshal([]byte(data), &sinks)
+	if err != nil {
+		return "", fmt.Errorf("invalid sinks:%s", err.Error())
+	}
+	for _, s := range sinks {
+		if s == nil {
+			return "", errors.New("invalid sinks")
+		}
+		if _, has := SinkTypes[s.Type]; !has {
+			return "", fmt.Errorf("invalid sink type:%s", s.Type)
+		}
+		if s.Address == "" {
+			return "", fmt.Errorf("%s sink address is required", s.Type)
+		}
+		if s.Type == "syslog" && s.Network != "" && s.Network != "udp" && s.Network != "tcp" {
+			return "", fmt.Errorf("invalid syslog network:%s", s.Network)
+		}
+		if s.Type == "kafka" && s.Topic == "" {
+			return "", errors.New("kafka sink topic is required")
+		}
+		if s.BufferSize < 0 || s.BatchSize < 0 {
+			return "", errors.New("invalid sink buffer size")
+		}
+	}
+	if len(sinks) == 0 {
+		return "", nil
+	}
+	d, _ := json.Marshal(sinks)
+	return string(d), nil
+}
+
 //LogConfig 日志配置
 type LogConfig struct {
 	Name    string       `json:"-"`
@@ -193,15 +292,37 @@ func (c *LogConfig) Read(ent *entity.LogConfig) {
 
 //AccessConfig access配置
 type AccessConfig struct {
-	Name    string         `json:"-"`
-	Enable  bool           `json:"enable" opt:"enable" default:"true"`
-	Dir     string         `json:"dir" opt:"dir" default:"work/logs/"`
-	File    string         `json:"file" opt:"file" default:"access"`
-	Period  string         `json:"period"`
-	Periods []NameTitle    `json:"periods"`
-	Expire  int            `json:"expire"`
-	Expires []ValueTitle   `json:"expires"`
-	Fields  []*AccessField `json:"fields"`
+	Name    string                  `json:"-"`
+	Enable  bool                    `json:"enable" opt:"enable" default:"true"`
+	Dir     string                  `json:"dir" opt:"dir" default:"work/logs/"`
+	File    string                  `json:"file" opt:"file" default:"access"`
+	Period  string                  `json:"period"`
+	Periods []NameTitle             `json:"periods"`
+	Expire  int                     `json:"expire"`
+	Expires []ValueTitle            `json:"expires"`
+	Fields  []*AccessField          `json:"fields"`
+	Format  string                  `json:"format"`
+	Formats []NameTitle             `json:"formats"`
+	Sinks   []*config.AccessLogSink `json:"sinks"`
+	//DynamicFields 可添加的动态域前缀
+	DynamicFields []*AccessField `json:"dynamicFields"`
+}
+
+//InitDynamicFields 动态域前缀说明
+func (c *AccessConfig) InitDynamicFields() {
+	infos := access_field.DynamicInfos()
+	prefixes := make([]string, 0, len(infos))
+	for prefix := range infos {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	c.DynamicFields = make([]*AccessField, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		c.DynamicFields = append(c.DynamicFields, &AccessField{
+			Name: prefix + "<name>",
+			Desc: infos[prefix],
+		})
+	}
 }
 
 //AccessField access域
@@ -233,6 +354,14 @@ func (c *AccessConfig) Read(ent *entity.LogConfig) {
 	c.File = ent.File
 	c.Period = ent.Period
 	c.Expire = ent.Expire
+	c.Format = ent.Format
+	if c.Format == "" {
+		c.Format = Formats[0].Name
+	}
+	c.Sinks = make([]*config.AccessLogSink, 0)
+	if ent.Sinks != "" {
+		_ = json.Unmarshal([]byte(ent.Sinks), &c.Sinks)
+	}
 	fields := make([]*AccessField, 0, access_field.Size())
 	e := json.Unmarshal([]byte(ent.Fields), &fields)
 	if e != nil {
@@ -249,6 +378,9 @@ func (c *AccessConfig) Read(ent *entity.LogConfig) {
 			field.Desc = desc
 			fieldsTmp = append(fieldsTmp, field)
 			delete(all, field.Name)
+		} else if _, _, ok := access_field.AccessFieldKey(field.Name).Dynamic(); ok {
+			field.Desc = access_field.AccessFieldKey(field.Name).Info()
+			fieldsTmp = append(fieldsTmp, field)
 		}
 	}
 
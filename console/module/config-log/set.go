@@ -26,6 +26,8 @@ func Set(name string, param *Param) error {
 	}
 	c.Fields = param.Fields
 	c.Expire = param.Expire
+	c.Format = param.Format
+	c.Sinks = param.Sinks
 	err := configLogDao.Set(c)
 	if err != nil {
 		return err
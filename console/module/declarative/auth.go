@@ -0,0 +1,62 @@
+package declarative
+
+import (
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+// authPlugins 鉴权方式对应的鉴权插件
+var authPlugins = map[string]string{
+	"Oauth2": "goku-oauth2_auth",
+	"Apikey": "goku-apikey_auth",
+	"Basic":  "goku-basic_auth",
+	"Jwt":    "goku-jwt_auth",
+}
+
+// authType 鉴权插件对应的鉴权方式
+func authType(pluginName string) (string, bool) {
+	for t, name := range authPlugins {
+		if name == pluginName {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+// exportAuth 将策略绑定的鉴权插件移到auth中
+func exportAuth(doc *entity.Declarative) *entity.Declarative {
+	for _, s := range doc.Strategies {
+		plugins := make([]*entity.DeclarativeBinding, 0, len(s.Plugins))
+		for _, b := range s.Plugins {
+			t, ok := authType(b.Name)
+			if !ok {
+				plugins = append(plugins, b)
+				continue
+			}
+			s.Auth = append(s.Auth, &entity.DeclarativeAuth{Type: t, Enable: b.Enable, Config: b.Config, Policy: b.Policy})
+		}
+		if len(plugins) == 0 {
+			plugins = nil
+		}
+		s.Plugins = plugins
+	}
+	return doc
+}
+
+// importAuth 将策略的auth转换为鉴权插件绑定，返回未知的鉴权方式
+func importAuth(s *entity.DeclarativeStrategy) []string {
+	unknown := make([]string, 0)
+	bindings := make([]*entity.DeclarativeBinding, 0, len(s.Auth)+len(s.Plugins))
+	for _, a := range s.Auth {
+		name, has := authPlugins[a.Type]
+		if !has {
+			unknown = append(unknown, a.Type)
+			continue
+		}
+		bindings = append(bindings, &entity.DeclarativeBinding{Name: name, Enable: a.Enable, Config: a.Config, Policy: a.Policy})
+	}
+	if len(bindings) > 0 {
+		s.Plugins = append(bindings, s.Plugins...)
+	}
+	s.Auth = nil
+	return unknown
+}
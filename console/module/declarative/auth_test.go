@@ -0,0 +1,63 @@
+package declarative
+
+import (
+	"testing"
+
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+	"gopkg.in/yaml.v2"
+)
+
+func TestAuthRoundTrip(t *testing.T) {
+	newDoc := func() *entity.Declarative {
+		return &entity.Declarative{
+			Version: 1,
+			Strategies: []*entity.DeclarativeStrategy{{
+				ID:     "s1",
+				Name:   "s1",
+				Enable: true,
+				Auth: []*entity.DeclarativeAuth{
+					{Type: "Basic", Enable: true, Config: `[{"userName":"u","password":"p"}]`},
+					{Type: "Apikey", Config: `[{"Apikey":"k"}]`},
+				},
+				Plugins: []*entity.DeclarativeBinding{{Name: "goku-rate_limiting", Enable: true}},
+			}},
+		}
+	}
+	current := &entity.Declarative{Plugins: []*entity.DeclarativePlugin{
+		{Name: "goku-basic_auth"}, {Name: "goku-apikey_auth"}, {Name: "goku-rate_limiting"},
+	}}
+
+	doc := newDoc()
+	if err := Validate(doc, current, false); err != nil {
+		t.Fatal(err)
+	}
+	s := doc.Strategies[0]
+	if s.Auth != nil || len(s.Plugins) != 3 || s.Plugins[0].Name != "goku-basic_auth" || s.Plugins[1].Name != "goku-apikey_auth" {
+		t.Fatalf("auth is not saved as plugin bindings:%+v", s.Plugins)
+	}
+	changes := diff(flatten(&entity.Declarative{Plugins: current.Plugins}), flatten(doc), false, "")
+	check(t, changes, []string{"create strategy s1", "create strategyAuth s1:Basic", "create strategyAuth s1:Apikey", "create strategyPlugin s1:goku-rate_limiting"})
+
+	// 保存后的插件绑定导出为原来的auth
+	want, _ := yaml.Marshal(newDoc())
+	got, _ := yaml.Marshal(exportAuth(doc))
+	if string(got) != string(want) {
+		t.Fatalf("want:\n%s\ngot:\n%s", want, got)
+	}
+	parsed, err := Parse(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := newDoc()
+	importAuth(saved.Strategies[0])
+	if err := Validate(parsed, current, false); err != nil {
+		t.Fatal(err)
+	}
+	check(t, diff(flatten(saved), flatten(parsed), true, ""), nil)
+
+	doc = newDoc()
+	doc.Strategies[0].Auth = append(doc.Strategies[0].Auth, &entity.DeclarativeAuth{Type: "Digest"})
+	if err := Validate(doc, current, false); err == nil {
+		t.Fatal("unknown auth type is accepted")
+	}
+}
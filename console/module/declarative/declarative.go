@@ -0,0 +1,308 @@
+package declarative
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/console/module/api"
+	"github.com/eolinker/goku-api-gateway/console/module/balance"
+	plugin_config "github.com/eolinker/goku-api-gateway/console/module/plugin/plugin-config"
+	"github.com/eolinker/goku-api-gateway/console/module/service"
+
+	"github.com/eolinker/goku-api-gateway/common/pdao"
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	declarativeDao dao.DeclarativeDao
+	strategyDao    dao.StrategyDao
+)
+
+func init() {
+	pdao.Need(&declarativeDao, &strategyDao)
+}
+
+//Result 对比或应用的结果
+type Result struct {
+	DryRun  bool                        `json:"dryRun"`
+	Prune   bool                        `json:"prune"`
+	Changes []*entity.DeclarativeChange `json:"changes"`
+}
+
+//Parse 解析JSON或YAML格式的声明式配置
+func Parse(data []byte) (*entity.Declarative, error) {
+	data = bytes.TrimSpace(data)
+	doc := new(entity.Declarative)
+	var err error
+	if len(data) > 0 && data[0] == '{' {
+		err = json.Unmarshal(data, doc)
+	} else {
+		err = yaml.UnmarshalStrict(data, doc)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if doc.Version != 1 {
+		return nil, fmt.Errorf("unsupported version:%d", doc.Version)
+	}
+	return doc, nil
+}
+
+//Export 导出当前全部配置，format为json或yaml，策略绑定的鉴权插件导出到auth中
+func Export(format string) ([]byte, error) {
+	doc, err := declarativeDao.ExportDeclarative()
+	if err != nil {
+		return nil, err
+	}
+	exportAuth(doc)
+	if format == "yaml" {
+		return yaml.Marshal(doc)
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+//Diff 对比声明式配置与当前配置，prune时包含将被删除的配置
+func Diff(doc *entity.Declarative, prune bool) (*Result, error) {
+	current, err := declarativeDao.ExportDeclarative()
+	if err != nil {
+		return nil, err
+	}
+	return compare(doc, current, prune)
+}
+
+//Apply 在同一事务中对比并应用声明式配置，返回变更内容，无变更时不写入
+func Apply(doc *entity.Declarative, prune bool, userID int) (*Result, error) {
+	var result *Result
+	err := declarativeDao.ApplyDeclarative(doc, prune, userID, func(current *entity.Declarative) (bool, error) {
+		r, err := compare(doc, current, prune)
+		if err != nil {
+			return false, err
+		}
+		result = r
+		return len(r.Changes) > 0, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.DryRun = false
+	return result, nil
+}
+
+// compare 检查声明式配置并与当前配置对比
+func compare(doc, current *entity.Declarative, prune bool) (*Result, error) {
+	if err := Validate(doc, current, prune); err != nil {
+		return nil, err
+	}
+	openStrategyID := ""
+	if has, s, err := strategyDao.GetOpenStrategy(); err == nil && has {
+		openStrategyID = s.StrategyID
+	}
+	return &Result{
+		DryRun:  true,
+		Prune:   prune,
+		Changes: diff(flatten(current), flatten(doc), prune, openStrategyID),
+	}, nil
+}
+
+//Validate 检查声明式配置，引用的服务、负载及接口须在文档中声明，不删除未声明配置时也可引用当前配置
+func Validate(doc, current *entity.Declarative, prune bool) error {
+	errs := make([]string, 0)
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Sprintf(format, args...))
+	}
+	if prune {
+		current = &entity.Declarative{Plugins: current.Plugins}
+	}
+
+	services := make(map[string]bool)
+	for _, s := range current.Services {
+		services[s.Name] = true
+	}
+	declared := make(map[string]bool)
+	for _, s := range doc.Services {
+		if s.Name == "" || s.Driver == "" {
+			fail("service: name and driver are required")
+			continue
+		}
+		if declared[s.Name] {
+			fail("service %s: duplicate", s.Name)
+		}
+		declared[s.Name] = true
+		services[s.Name] = true
+		options, err := service.CheckHealthCheckOptions(s.HealthCheckOptions)
+		if err != nil {
+			fail("service %s: healthCheckOptions:%s", s.Name, err.Error())
+		}
+		s.HealthCheckOptions = options
+	}
+
+	balances := make(map[string]bool)
+	for _, b := range current.Balances {
+		balances[b.Name] = true
+	}
+	declared = make(map[string]bool)
+	for _, b := range doc.Balances {
+		if b.Name == "" {
+			fail("balance: name is required")
+			continue
+		}
+		if declared[b.Name] {
+			fail("balance %s: duplicate", b.Name)
+		}
+		declared[b.Name] = true
+		balances[b.Name] = true
+		if !services[b.ServiceName] {
+			fail("balance %s: service %s does not exist", b.Name, b.ServiceName)
+		}
+		for _, policy := range []struct {
+			name  string
+			value *string
+		}{
+			{balance.PolicySubset, &b.Subset},
+			{balance.PolicyTransport, &b.Transport},
+		} {
+			value, err := balance.CheckPolicy(policy.name, *policy.value)
+			if err != nil {
+				fail("balance %s: %s:%s", b.Name, policy.name, err.Error())
+			}
+			*policy.value = value
+		}
+	}
+
+	plugins := make(map[string]bool)
+	for _, p := range current.Plugins {
+		plugins[p.Name] = true
+	}
+	checkPlugin := func(owner, name, conf, policy string) {
+		if !plugins[name] {
+			fail("%s: plugin %s does not exist", owner, name)
+			return
+		}
+		if conf != "" {
+			if _, err := plugin_config.CheckConfig(name, []byte(conf)); err != nil {
+				fail("%s: plugin %s config:%s", owner, name, err.Error())
+			}
+		}
+		if policy != "" {
+			p := new(config.PluginPolicy)
+			if err := json.Unmarshal([]byte(policy), p); err != nil {
+				fail("%s: plugin %s policy:%s", owner, name, err.Error())
+			} else if err := p.Check(); err != nil {
+				fail("%s: plugin %s policy:%s", owner, name, err.Error())
+			}
+		}
+	}
+	for _, p := range doc.Plugins {
+		checkPlugin("plugins", p.Name, p.Config, p.Policy)
+	}
+
+	apis := make(map[string]bool)
+	for _, p := range current.Projects {
+		for _, a := range p.APIs {
+			apis[p.Name+":"+a.Key()] = true
+		}
+	}
+	declared = make(map[string]bool)
+	for _, p := range doc.Projects {
+		if p.Name == "" {
+			fail("project: name is required")
+			continue
+		}
+		if declared[p.Name] {
+			fail("project %s: duplicate", p.Name)
+		}
+		declared[p.Name] = true
+		keys := make(map[string]bool)
+		for _, a := range p.APIs {
+			a.RequestMethod = strings.ToUpper(a.RequestMethod)
+			key := a.Key()
+			if a.Name == "" || a.RequestURL == "" || a.RequestMethod == "" {
+				fail("project %s api %s: name, requestURL and requestMethod are required", p.Name, key)
+				continue
+			}
+			if keys[key] {
+				fail("project %s api %s: duplicate", p.Name, key)
+			}
+			keys[key] = true
+			apis[p.Name+":"+key] = true
+			if a.Balance != "" && !balances[a.Balance] {
+				fail("project %s api %s: balance %s does not exist", p.Name, key, a.Balance)
+			}
+			for _, policy := range []struct {
+				name  string
+				value *string
+			}{
+				{api.PolicyTransform, &a.Transform},
+				{api.PolicyTraffic, &a.Traffic},
+				{api.PolicyMirror, &a.Mirror},
+				{api.PolicyRetry, &a.RetryPolicy},
+				{api.PolicyHedge, &a.Hedge},
+			} {
+				value, err := api.CheckAPIPolicy(policy.name, *policy.value, balances)
+				if err != nil {
+					fail("project %s api %s: %s:%s", p.Name, key, policy.name, err.Error())
+				}
+				*policy.value = value
+			}
+			if err := api.CheckLinkApis(a.LinkAPIs); err != nil {
+				fail("project %s api %s: linkApis:%s", p.Name, key, err.Error())
+			}
+		}
+	}
+
+	declared = make(map[string]bool)
+	for _, s := range doc.Strategies {
+		if s.ID == "" || s.Name == "" {
+			fail("strategy: id and name are required")
+			continue
+		}
+		if declared[s.ID] {
+			fail("strategy %s: duplicate", s.ID)
+		}
+		declared[s.ID] = true
+		owner := "strategy " + s.ID
+		for _, t := range importAuth(s) {
+			fail("%s: unknown auth type %s", owner, t)
+		}
+		bound := make(map[string]bool)
+		for _, b := range s.Plugins {
+			if bound[b.Name] {
+				fail("%s: plugin %s is bound twice", owner, b.Name)
+			}
+			bound[b.Name] = true
+			checkPlugin(owner, b.Name, b.Config, b.Policy)
+		}
+		bound = make(map[string]bool)
+		for _, a := range s.APIs {
+			key := a.Project + ":" + a.API
+			if !apis[key] {
+				fail("%s: api %s does not exist", owner, key)
+				continue
+			}
+			if bound[key] {
+				fail("%s: api %s is bound twice", owner, key)
+			}
+			bound[key] = true
+			apiOwner := owner + " api " + key
+			apiPlugins := make(map[string]bool)
+			for _, b := range a.Plugins {
+				if apiPlugins[b.Name] {
+					fail("%s: plugin %s is bound twice", apiOwner, b.Name)
+				}
+				apiPlugins[b.Name] = true
+				checkPlugin(apiOwner, b.Name, b.Config, b.Policy)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
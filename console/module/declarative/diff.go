@@ -0,0 +1,133 @@
+package declarative
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const (
+	actionCreate = "create"
+	actionUpdate = "update"
+	actionDelete = "delete"
+)
+
+// item 展开后的单项配置，value为不含子配置的字段
+type item struct {
+	kind  string
+	key   string
+	value map[string]interface{}
+}
+
+func fields(v interface{}) map[string]interface{} {
+	data, _ := json.Marshal(v)
+	m := make(map[string]interface{})
+	json.Unmarshal(data, &m)
+	return m
+}
+
+// flatten 将声明式配置按类型展开，子配置单独成项
+func flatten(doc *entity.Declarative) []*item {
+	items := make([]*item, 0)
+	add := func(kind, key string, v interface{}) {
+		items = append(items, &item{kind: kind, key: key, value: fields(v)})
+	}
+	for _, s := range doc.Services {
+		add("service", s.Name, s)
+	}
+	for _, b := range doc.Balances {
+		add("balance", b.Name, b)
+	}
+	for _, p := range doc.Plugins {
+		add("plugin", p.Name, p)
+	}
+	for _, p := range doc.Projects {
+		add("project", p.Name, &entity.DeclarativeProject{Name: p.Name})
+		for _, a := range p.APIs {
+			add("api", p.Name+":"+a.Key(), a)
+		}
+	}
+	for _, s := range doc.Strategies {
+		self := *s
+		self.Auth, self.Plugins, self.APIs = nil, nil, nil
+		add("strategy", s.ID, &self)
+		for _, b := range s.Plugins {
+			if t, ok := authType(b.Name); ok {
+				add("strategyAuth", s.ID+":"+t, b)
+				continue
+			}
+			add("strategyPlugin", s.ID+":"+b.Name, b)
+		}
+		for _, a := range s.APIs {
+			key := s.ID + ":" + a.Project + ":" + a.API
+			self := *a
+			self.Plugins = nil
+			add("strategyAPI", key, &self)
+			for _, b := range a.Plugins {
+				add("apiPlugin", key+":"+b.Name, b)
+			}
+		}
+	}
+	return items
+}
+
+// diff 对比展开后的配置，全局插件及开放策略不会被删除
+func diff(current, desired []*item, prune bool, openStrategyID string) []*entity.DeclarativeChange {
+	index := make(map[string]*item, len(current))
+	for _, i := range current {
+		index[i.kind+"\n"+i.key] = i
+	}
+	changes := make([]*entity.DeclarativeChange, 0)
+	seen := make(map[string]bool, len(desired))
+	for _, i := range desired {
+		id := i.kind + "\n" + i.key
+		seen[id] = true
+		old, has := index[id]
+		if !has {
+			changes = append(changes, &entity.DeclarativeChange{Action: actionCreate, Kind: i.kind, Key: i.key})
+			continue
+		}
+		names := make([]string, 0)
+		for name := range merge(old.value, i.value) {
+			if !reflect.DeepEqual(old.value[name], i.value[name]) {
+				names = append(names, name)
+			}
+		}
+		if len(names) > 0 {
+			sort.Strings(names)
+			changes = append(changes, &entity.DeclarativeChange{Action: actionUpdate, Kind: i.kind, Key: i.key, Fields: names})
+		}
+	}
+	if !prune {
+		return changes
+	}
+	// 未声明开放策略时保留其全部绑定
+	openPrefix := ""
+	if openStrategyID != "" && !seen["strategy\n"+openStrategyID] {
+		openPrefix = openStrategyID + ":"
+	}
+	for _, i := range current {
+		if seen[i.kind+"\n"+i.key] || i.kind == "plugin" {
+			continue
+		}
+		if i.kind == "strategy" && i.key == openStrategyID || openPrefix != "" && i.kind != "api" && strings.HasPrefix(i.key, openPrefix) {
+			continue
+		}
+		changes = append(changes, &entity.DeclarativeChange{Action: actionDelete, Kind: i.kind, Key: i.key})
+	}
+	return changes
+}
+
+func merge(a, b map[string]interface{}) map[string]bool {
+	names := make(map[string]bool, len(a)+len(b))
+	for name := range a {
+		names[name] = true
+	}
+	for name := range b {
+		names[name] = true
+	}
+	return names
+}
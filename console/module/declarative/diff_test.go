@@ -0,0 +1,56 @@
+package declarative
+
+import (
+	"testing"
+
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+func TestDiff(t *testing.T) {
+	current := &entity.Declarative{
+		Version:  1,
+		Balances: []*entity.DeclarativeBalance{{Name: "b1", ServiceName: "static", Static: "127.0.0.1:80"}},
+		Plugins:  []*entity.DeclarativePlugin{{Name: "goku-rate_limiting"}},
+		Projects: []*entity.DeclarativeProject{{Name: "p1", APIs: []*entity.DeclarativeAPI{
+			{Name: "a", RequestURL: "/a", RequestMethod: "GET"},
+			{Name: "b", RequestURL: "/b", RequestMethod: "GET"},
+		}}},
+		Strategies: []*entity.DeclarativeStrategy{
+			{ID: "open", Name: "开放策略", Enable: true, APIs: []*entity.DeclarativeStrategyAPI{{Project: "p1", API: "GET /b"}}},
+			{ID: "s1", Name: "s1", Enable: true},
+		},
+	}
+	desired := &entity.Declarative{
+		Version:  1,
+		Balances: []*entity.DeclarativeBalance{{Name: "b1", ServiceName: "static", Static: "127.0.0.1:8080"}},
+		Projects: []*entity.DeclarativeProject{{Name: "p1", APIs: []*entity.DeclarativeAPI{
+			{Name: "a", RequestURL: "/a", RequestMethod: "GET", Balance: "b1"},
+			{Name: "c", RequestURL: "/c", RequestMethod: "POST"},
+		}}},
+	}
+
+	changes := diff(flatten(current), flatten(desired), false, "open")
+	want := []string{"update balance b1", "update api p1:GET /a", "create api p1:POST /c"}
+	check(t, changes, want)
+	if changes[0].Fields[0] != "static" || changes[1].Fields[0] != "balance" {
+		t.Fatalf("unexpected fields:%v %v", changes[0].Fields, changes[1].Fields)
+	}
+
+	changes = diff(flatten(current), flatten(desired), true, "open")
+	check(t, changes, append(want, "delete api p1:GET /b", "delete strategy s1"))
+}
+
+func check(t *testing.T, changes []*entity.DeclarativeChange, want []string) {
+	t.Helper()
+	if len(changes) != len(want) {
+		for _, c := range changes {
+			t.Log(c.Action, c.Kind, c.Key)
+		}
+		t.Fatalf("want %d changes,got %d", len(want), len(changes))
+	}
+	for i, c := range changes {
+		if got := c.Action + " " + c.Kind + " " + c.Key; got != want[i] {
+			t.Fatalf("change %d:want %s,got %s", i, want[i], got)
+		}
+	}
+}
@@ -1,8 +1,11 @@
 package gateway
 
 import (
+	"fmt"
+
 	"github.com/eolinker/goku-api-gateway/common/pdao"
 	v "github.com/eolinker/goku-api-gateway/common/version"
+	"github.com/eolinker/goku-api-gateway/console/module/versionConfig"
 	"github.com/eolinker/goku-api-gateway/server/dao"
 	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
 )
@@ -52,6 +55,29 @@ func EditGatewayBaseConfig(successCode string, nodeUpdatePeriod, monitorUpdatePe
 	return flag, result, err
 }
 
+//GetRouter 获取节点使用的路由实现及可选的路由实现，新的配置在下次发布后生效
+func GetRouter() (string, []string, error) {
+	r, err := gatewayDao.GetRouter()
+	return r, versionConfig.Routers(), err
+}
+
+//SetRouter 设置节点使用的路由实现，为空时使用默认路由
+func SetRouter(router string) error {
+	if router != "" {
+		has := false
+		for _, name := range versionConfig.Routers() {
+			if name == router {
+				has = true
+				break
+			}
+		}
+		if !has {
+			return fmt.Errorf("[ERROR]router %s does not exist", router)
+		}
+	}
+	return gatewayDao.SetRouter(router)
+}
+
 //GetGatewayMonitorSummaryByPeriod 获取监控summary
 func GetGatewayMonitorSummaryByPeriod() (bool, *SystemInfo, error) {
 
@@ -0,0 +1,49 @@
+package jsonconf
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+//Checker 以JSON保存的配置，如接口的转换、重试策略，负载的实例子集等
+type Checker interface {
+	Check() error
+}
+
+//Load 读取已保存的配置，为空时v保持零值
+func Load(text string, v interface{}) error {
+	if text == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(text), v)
+}
+
+//Parse 解析并检查提交的配置，为空时返回false
+func Parse(text string, v Checker) (bool, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return false, nil
+	}
+	if err := json.Unmarshal([]byte(text), v); err != nil {
+		return false, err
+	}
+	if err := v.Check(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+//Normalize 检查提交的配置，返回整理后的JSON，为空时返回空字符串
+func Normalize(text string, v Checker) (string, error) {
+	has, err := Parse(text, v)
+	if err != nil || !has {
+		return "", err
+	}
+	return Encode(v), nil
+}
+
+//Encode 配置编码为保存的JSON
+func Encode(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,37 @@
+package jsonconf
+
+import (
+	"errors"
+	"testing"
+)
+
+type testConfig struct {
+	Percent int `json:"percent"`
+}
+
+func (c *testConfig) Check() error {
+	if c.Percent < 0 || c.Percent > 100 {
+		return errors.New("illegal percent")
+	}
+	return nil
+}
+
+func TestNormalize(t *testing.T) {
+	if text, err := Normalize(" ", new(testConfig)); text != "" || err != nil {
+		t.Errorf("blank: got %q %v", text, err)
+	}
+	if text, err := Normalize(` {"percent": 10, "unknown": 1} `, new(testConfig)); text != `{"percent":10}` || err != nil {
+		t.Errorf("normalize: got %q %v", text, err)
+	}
+	if _, err := Normalize(`{"percent":200}`, new(testConfig)); err == nil {
+		t.Error("check error should be returned")
+	}
+	if _, err := Normalize(`{`, new(testConfig)); err == nil {
+		t.Error("syntax error should be returned")
+	}
+
+	c := new(testConfig)
+	if err := Load("", c); err != nil || c.Percent != 0 {
+		t.Errorf("load blank: got %+v %v", c, err)
+	}
+}
@@ -14,6 +14,7 @@ var (
 	manager = _StatusManager{
 		locker:        sync.RWMutex{},
 		lastHeartBeat: make(map[string]time.Time),
+		heartbeats:    make(map[string]*entity.NodeHeartbeat),
 	}
 	instanceLocker = newInstanceLocker()
 )
@@ -21,6 +22,7 @@ var (
 type _StatusManager struct {
 	locker        sync.RWMutex
 	lastHeartBeat map[string]time.Time
+	heartbeats    map[string]*entity.NodeHeartbeat
 }
 
 func (m *_StatusManager) refresh(id string) {
@@ -37,14 +39,31 @@ func (m *_StatusManager) refresh(id string) {
 	nodeDao.SetHeartBeatTime(id, time.Now())
 }
 
+func (m *_StatusManager) heartbeat(id string, info *entity.NodeHeartbeat) {
+	m.locker.Lock()
+
+	m.lastHeartBeat[id] = time.Now()
+	m.heartbeats[id] = info
+
+	m.locker.Unlock()
+}
+
 func (m *_StatusManager) stop(id string) {
 
 	m.locker.Lock()
 
 	delete(m.lastHeartBeat, id)
+	delete(m.heartbeats, id)
 
 	m.locker.Unlock()
 }
+
+func (m *_StatusManager) getHeartbeat(id string) (*entity.NodeHeartbeat, bool) {
+	m.locker.RLock()
+	h, b := m.heartbeats[id]
+	m.locker.RUnlock()
+	return h, b
+}
 func (m *_StatusManager) get(id string) (time.Time, bool) {
 	m.locker.RLock()
 	t, b := m.lastHeartBeat[id]
@@ -123,10 +142,46 @@ func IsLive(instance string) bool {
 	return true
 }
 
+//IsHealthy 节点在线且在过期时间内上报过心跳
+func IsHealthy(instance string) bool {
+	if !instanceLocker.IsLock(instance) {
+		return false
+	}
+	t, has := manager.get(instance)
+	if !has {
+		return false
+	}
+	return time.Since(t) <= EXPIRE
+}
+
+//Heartbeat 记录节点上报的心跳
+func Heartbeat(instance string, info *entity.NodeHeartbeat) error {
+	manager.heartbeat(instance, info)
+	return nodeDao.SaveHeartbeat(instance, info)
+}
+
+//GetHeartbeat 获取节点最近一次上报的心跳
+func GetHeartbeat(instance string) (*entity.NodeHeartbeat, error) {
+	if h, has := manager.getHeartbeat(instance); has {
+		return h, nil
+	}
+	return nodeDao.GetHeartbeat(instance)
+}
+
 //ResetNodeStatus 重置节点状态
 func ResetNodeStatus(nodes ...*entity.Node) {
 	for _, node := range nodes {
-		if instanceLocker.IsLock(node.NodeKey) || IsLive(node.NodeKey) {
+		if h, err := GetHeartbeat(node.NodeKey); err == nil {
+			node.Heartbeat = h
+		}
+		if instanceLocker.IsLock(node.NodeKey) {
+			if IsHealthy(node.NodeKey) {
+				node.NodeStatus = 1
+			} else {
+				// 连接仍在，但心跳已丢失
+				node.NodeStatus = 2
+			}
+		} else if IsLive(node.NodeKey) {
 			node.NodeStatus = 1
 		} else {
 			if node.NodeStatus == 1 {
@@ -139,7 +194,12 @@ func ResetNodeStatus(nodes ...*entity.Node) {
 }
 
 func Lock(key string) bool {
-	return instanceLocker.Lock(key)
+	if !instanceLocker.Lock(key) {
+		return false
+	}
+	// 注册时刷新心跳时间，避免首个心跳到达前被判定为异常
+	manager.refresh(key)
+	return true
 }
 func UnLock(key string) {
 	instanceLocker.UnLock(key)
@@ -4,6 +4,13 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/eolinker/goku-api-gateway/common/auto-form"
+	json_schema "github.com/eolinker/goku-api-gateway/common/json-schema"
+	"github.com/eolinker/goku-api-gateway/common/pdao"
+	"github.com/eolinker/goku-api-gateway/server/dao"
 )
 
 func isEnd(r rune) bool {
@@ -15,39 +22,103 @@ func isEnd(r rune) bool {
 
 var allConfigOfPlugin map[string]interface{}
 
-//CheckConfig 检查插件配置是否有效
+var (
+	pluginDao dao.PluginDao
+
+	schemaLocker sync.Mutex
+	schemaCache  = make(map[string]*json_schema.Schema)
+)
+
+func init() {
+	pdao.Need(&pluginDao)
+}
+
+//CheckConfig 检查插件配置是否有效，先按内置插件的配置结构检查，再按插件声明的JSON Schema检查
 func CheckConfig(pluginName string, config []byte) (bool, error) {
-	v, has := allConfigOfPlugin[pluginName]
-	if has {
+	if t, has := allConfigOfPlugin[pluginName]; has {
+		v := reflect.New(reflect.TypeOf(t).Elem()).Interface()
 		err := json.Unmarshal(config, v)
 		if err != nil {
-			switch v := err.(type) {
-			case *json.SyntaxError:
-				{
-					end := int64(bytes.IndexFunc(config[v.Offset:], isEnd))
-					if end == -1 {
-						end = int64(len(config) - 1)
-					} else {
-						end = end + v.Offset
-					}
-					start := 0
-					if v.Offset > 0 {
-						start = bytes.LastIndexFunc(config[:v.Offset], isEnd)
-					}
-					if start == -1 {
-						start = 0
-					}
-
-					return false, fmt.Errorf("json格式错误：%s", string(config[start:end]))
-				}
-			case *json.UnmarshalTypeError:
-				{
-					return false, fmt.Errorf("数据类型不正确:\"%s\":%s", v.Field, v.Value)
-				}
+			return false, unmarshalError(config, err)
+		}
+		if h, ok := v.(auto.ConfigValidate); ok {
+			if err := h.Validate(); err != nil {
+				return false, err
 			}
-
-			return false, err
 		}
 	}
+	return CheckSchema(pluginName, config)
+}
+
+//CheckSchema 按插件声明的JSON Schema检查配置，空配置按{}检查，未声明时不检查
+func CheckSchema(pluginName string, config []byte) (bool, error) {
+	if pluginDao == nil {
+		return true, nil
+	}
+	text, err := pluginDao.GetPluginSchema(pluginName)
+	if err != nil || text == "" {
+		return true, nil
+	}
+	schema, err := compile(text)
+	if err != nil {
+		return false, fmt.Errorf("插件配置的JSON Schema无效:%s", err.Error())
+	}
+	if len(bytes.TrimSpace(config)) == 0 {
+		config = []byte("{}")
+	}
+	if err := schema.Validate(config); err != nil {
+		return false, err
+	}
 	return true, nil
 }
+
+//CompileSchema 检查JSON Schema是否有效
+func CompileSchema(text string) error {
+	_, err := compile(text)
+	return err
+}
+
+func compile(text string) (*json_schema.Schema, error) {
+	schemaLocker.Lock()
+	defer schemaLocker.Unlock()
+	if s, has := schemaCache[text]; has {
+		return s, nil
+	}
+	s, err := json_schema.Compile([]byte(text))
+	if err != nil {
+		return nil, err
+	}
+	if len(schemaCache) > 256 {
+		schemaCache = make(map[string]*json_schema.Schema)
+	}
+	schemaCache[text] = s
+	return s, nil
+}
+
+func unmarshalError(config []byte, err error) error {
+	switch v := err.(type) {
+	case *json.SyntaxError:
+		{
+			end := int64(bytes.IndexFunc(config[v.Offset:], isEnd))
+			if end == -1 {
+				end = int64(len(config) - 1)
+			} else {
+				end = end + v.Offset
+			}
+			start := 0
+			if v.Offset > 0 {
+				start = bytes.LastIndexFunc(config[:v.Offset], isEnd)
+			}
+			if start == -1 {
+				start = 0
+			}
+
+			return fmt.Errorf("json格式错误：%s", string(config[start:end]))
+		}
+	case *json.UnmarshalTypeError:
+		{
+			return fmt.Errorf("数据类型不正确:\"%s\":%s", v.Field, v.Value)
+		}
+	}
+	return err
+}
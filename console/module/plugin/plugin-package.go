@@ -0,0 +1,82 @@
+package plugin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//MaxPackageSize 插件包大小上限
+const MaxPackageSize = 64 << 20
+
+//GetPluginPackages 获取插件包列表
+func GetPluginPackages() ([]*entity.PluginPackage, error) {
+	return pluginDao.GetPluginPackages()
+}
+
+//GetPluginPackage 获取插件包，包含插件包内容
+func GetPluginPackage(pluginName string) (*entity.PluginPackage, error) {
+	return pluginDao.GetPluginPackage(pluginName)
+}
+
+//GetPluginManifest 获取下发给节点的插件包清单
+func GetPluginManifest() (map[string]*config.PluginPackage, error) {
+	packages, err := pluginDao.GetPluginPackages()
+	if err != nil {
+		return nil, err
+	}
+	manifest := make(map[string]*config.PluginPackage, len(packages))
+	for _, pkg := range packages {
+		manifest[pkg.PluginName] = &config.PluginPackage{
+			Version:  pkg.Version,
+			Checksum: pkg.Checksum,
+		}
+	}
+	return manifest, nil
+}
+
+//UploadPluginPackage 上传插件包并计算sha256
+func UploadPluginPackage(pluginName, version string, content []byte) (*entity.PluginPackage, error) {
+	if version == "" {
+		return nil, errors.New("[ERROR]Illegal version")
+	}
+	if len(content) == 0 || len(content) > MaxPackageSize {
+		return nil, errors.New("[ERROR]Illegal package size")
+	}
+	if has, _ := pluginDao.CheckNameIsExist(pluginName); !has {
+		return nil, errors.New("[ERROR]The plugin does not exist")
+	}
+	sum := sha256.Sum256(content)
+	pkg := &entity.PluginPackage{
+		PluginName: pluginName,
+		Version:    version,
+		Checksum:   hex.EncodeToString(sum[:]),
+		Size:       len(content),
+		Content:    content,
+		UpdateTime: time.Now().Format("2006-01-02 15:04:05"),
+	}
+	err := pluginDao.SetPluginPackage(pkg)
+	if err != nil {
+		return nil, err
+	}
+	return pkg, nil
+}
+
+//DeletePluginPackage 删除插件包，节点上已安装的插件包保留
+func DeletePluginPackage(pluginName string) error {
+	return pluginDao.DeletePluginPackage(pluginName)
+}
+
+//SetNodePluginStatus 保存节点上报的插件加载状态
+func SetNodePluginStatus(nodeKey string, status []*entity.NodePluginStatus) error {
+	return pluginDao.SetNodePluginStatus(nodeKey, status)
+}
+
+//GetNodePluginStatus 获取各节点上插件的加载状态
+func GetNodePluginStatus(pluginName string) ([]*entity.NodePluginStatus, error) {
+	return pluginDao.GetNodePluginStatus(pluginName)
+}
@@ -0,0 +1,41 @@
+package plugin
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+//GetPluginPolicy 获取插件的执行策略，strategyID为空时为全局插件，apiID为0时为策略插件
+func GetPluginPolicy(pluginName, strategyID string, apiID int) (*config.PluginPolicy, error) {
+	policy, err := pluginDao.GetPluginPolicy(pluginName, strategyID, apiID)
+	if err != nil {
+		return nil, err
+	}
+	p := new(config.PluginPolicy)
+	if policy == "" {
+		return p, nil
+	}
+	if err := json.Unmarshal([]byte(policy), p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+//SetPluginPolicy 设置插件的执行策略，为空时恢复默认
+func SetPluginPolicy(pluginName, strategyID string, apiID int, policy string) error {
+	policy = strings.TrimSpace(policy)
+	if policy == "" {
+		return pluginDao.SetPluginPolicy(pluginName, strategyID, apiID, "")
+	}
+	p := new(config.PluginPolicy)
+	if err := json.Unmarshal([]byte(policy), p); err != nil {
+		return err
+	}
+	if err := p.Check(); err != nil {
+		return err
+	}
+	data, _ := json.Marshal(p)
+	return pluginDao.SetPluginPolicy(pluginName, strategyID, apiID, string(data))
+}
@@ -0,0 +1,34 @@
+package plugin
+
+import (
+	"errors"
+	"strings"
+
+	plugin_config "github.com/eolinker/goku-api-gateway/console/module/plugin/plugin-config"
+)
+
+//GetPluginSchema 获取插件配置的JSON Schema
+func GetPluginSchema(pluginName string) (string, error) {
+	return pluginDao.GetPluginSchema(pluginName)
+}
+
+//SetPluginSchema 设置插件配置的JSON Schema，为空时不再校验
+func SetPluginSchema(pluginName, schema string) error {
+	if has, _ := pluginDao.CheckNameIsExist(pluginName); !has {
+		return errors.New("[ERROR]The plugin does not exist")
+	}
+	schema = strings.TrimSpace(schema)
+	if err := CheckPluginSchema(schema); err != nil {
+		return err
+	}
+	return pluginDao.SetPluginSchema(pluginName, schema)
+}
+
+//CheckPluginSchema 检查插件配置的JSON Schema，为空时不校验
+func CheckPluginSchema(schema string) error {
+	schema = strings.TrimSpace(schema)
+	if schema == "" {
+		return nil
+	}
+	return plugin_config.CompileSchema(schema)
+}
@@ -0,0 +1,46 @@
+package plugin
+
+import (
+	"errors"
+	"time"
+
+	plugin_script "github.com/eolinker/goku-api-gateway/node/plugin-script"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const (
+	//MaxScriptTimeout 脚本单次执行期限的最大值，按实际经过的时间计算，单位毫秒
+	MaxScriptTimeout = 10000
+	//MaxScriptMemoryLimit 脚本变量占用内存上限的最大值，单位MB
+	MaxScriptMemoryLimit = 1024
+)
+
+//GetPluginScript 获取插件的JavaScript脚本
+func GetPluginScript(pluginName string) (*entity.PluginScript, error) {
+	return pluginDao.GetPluginScript(pluginName)
+}
+
+//SetPluginScript 设置插件的JavaScript脚本，脚本为空时插件恢复使用动态库
+func SetPluginScript(pluginName, script string, timeout, memoryLimit int) error {
+	if timeout < 0 || timeout > MaxScriptTimeout {
+		return errors.New("[ERROR]Illegal timeout")
+	}
+	if memoryLimit < 0 || memoryLimit > MaxScriptMemoryLimit {
+		return errors.New("[ERROR]Illegal memoryLimit")
+	}
+	if has, _ := pluginDao.CheckNameIsExist(pluginName); !has {
+		return errors.New("[ERROR]The plugin does not exist")
+	}
+	if script != "" {
+		if err := plugin_script.Check(script); err != nil {
+			return err
+		}
+	}
+	return pluginDao.SetPluginScript(&entity.PluginScript{
+		PluginName:  pluginName,
+		Script:      script,
+		Timeout:     timeout,
+		MemoryLimit: memoryLimit,
+		UpdateTime:  time.Now().Format("2006-01-02 15:04:05"),
+	})
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+//GetHealthCheckOptions 获取健康检查扩展配置
+func GetHealthCheckOptions(name string) (*config.HealthCheckOptions, error) {
+	text, err := serviceDao.GetHealthCheckOptions(name)
+	if err != nil {
+		return nil, err
+	}
+	options := new(config.HealthCheckOptions)
+	if text == "" {
+		return options, nil
+	}
+	if err := json.Unmarshal([]byte(text), options); err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
+//CheckHealthCheckOptions 检查健康检查扩展配置，返回整理后的配置
+func CheckHealthCheckOptions(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", nil
+	}
+	options := new(config.HealthCheckOptions)
+	if err := json.Unmarshal([]byte(text), options); err != nil {
+		return "", err
+	}
+	if err := options.Check(); err != nil {
+		return "", err
+	}
+	data, _ := json.Marshal(options)
+	return string(data), nil
+}
+
+//SaveHealthCheckOptions 保存健康检查扩展配置，为空时清除
+func SaveHealthCheckOptions(name, text string) error {
+	if _, err := serviceDao.Get(name); err != nil {
+		return err
+	}
+	text, err := CheckHealthCheckOptions(text)
+	if err != nil {
+		return err
+	}
+	now := time.Now().Format("2006-01-02 15:04:05")
+	return serviceDao.SaveHealthCheckOptions(name, text, now)
+}
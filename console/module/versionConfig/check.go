@@ -0,0 +1,118 @@
+package versionConfig
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	plugin_config "github.com/eolinker/goku-api-gateway/console/module/plugin/plugin-config"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	plugin_script "github.com/eolinker/goku-api-gateway/node/plugin-script"
+	"github.com/eolinker/goku-api-gateway/node/router"
+	_ "github.com/eolinker/goku-api-gateway/node/router/httprouter"
+	_ "github.com/eolinker/goku-api-gateway/node/router/tolerant"
+	goku_plugin "github.com/eolinker/goku-plugin"
+)
+
+// checkRouters 按节点的路由实现构造每个策略的路由，返回冲突或无效的接口路径
+func checkRouters(c *config.GokuConfig) error {
+	name := ""
+	if c.GatewayBasicInfo != nil {
+		name = c.GatewayBasicInfo.Router
+	}
+	factory, err := router.GetFactory(name)
+	if err != nil {
+		return err
+	}
+
+	apis := make(map[int]*config.APIContent)
+	for _, api := range c.APIS {
+		apis[api.ID] = api
+	}
+	noop := router.HandleFunc(func(ctx *common.Context) {})
+
+	conflicts := make([]string, 0)
+	for _, s := range c.Strategy {
+		if !s.Enable {
+			continue
+		}
+		r := factory.New()
+		for _, a := range s.APIS {
+			api, has := apis[a.ID]
+			if !has {
+				continue
+			}
+			for _, method := range api.Methods {
+				if pr, ok := r.(router.PriorityRouter); ok && api.Priority != 0 {
+					pr.AddRouterWithPriority(strings.ToUpper(method), api.RequestURL, api.Priority, noop)
+					continue
+				}
+				r.AddRouter(strings.ToUpper(method), api.RequestURL, noop)
+			}
+		}
+		if reporter, ok := r.(router.ConflictReporter); ok {
+			for _, conflict := range reporter.Conflicts() {
+				conflicts = append(conflicts, fmt.Sprintf("[%s]%s", s.Name, conflict))
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("[ERROR]router conflict:%s", strings.Join(conflicts, ";"))
+	}
+	return nil
+}
+
+// checkPlugins 按插件声明的JSON Schema检查所有插件配置，JavaScript插件按节点的方式创建一次
+func checkPlugins(c *config.GokuConfig) error {
+	invalid := make([]string, 0)
+	factories := make(map[string]goku_plugin.PluginFactory)
+	check := func(position string, cfg *config.PluginConfig, strategyID string, apiID int) {
+		if ok, err := plugin_config.CheckConfig(cfg.Name, []byte(cfg.Config)); !ok {
+			invalid = append(invalid, fmt.Sprintf("[%s]%s:%s", position, cfg.Name, err))
+			return
+		}
+		script, has := c.Scripts[cfg.Name]
+		if !has {
+			return
+		}
+		factory, has := factories[cfg.Name]
+		if !has {
+			var err error
+			factory, err = plugin_script.NewFactory(cfg.Name, script)
+			if err != nil {
+				invalid = append(invalid, fmt.Sprintf("[%s]%s:%s", position, cfg.Name, err))
+				return
+			}
+			factories[cfg.Name] = factory
+		}
+		if _, err := factory.Create(cfg.Config, c.Cluster, cfg.UpdateTag, strategyID, apiID); err != nil {
+			invalid = append(invalid, fmt.Sprintf("[%s]%s:%s", position, cfg.Name, err))
+		}
+	}
+
+	for _, cfg := range c.Plugins.BeforePlugins {
+		check("before", cfg, "", 0)
+	}
+	for _, cfg := range c.Plugins.GlobalPlugins {
+		check("global", cfg, "", 0)
+	}
+	for _, s := range c.Strategy {
+		for _, cfg := range s.Plugins {
+			check(s.Name, cfg, s.ID, 0)
+		}
+		for _, a := range s.APIS {
+			for _, cfg := range a.Plugins {
+				check(fmt.Sprintf("%s/%d", s.Name, a.ID), cfg, s.ID, a.ID)
+			}
+		}
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("[ERROR]invalid plugin config:%s", strings.Join(invalid, ";"))
+	}
+	return nil
+}
+
+//Routers 可选的路由实现
+func Routers() []string {
+	return router.Names()
+}
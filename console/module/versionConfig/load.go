@@ -132,6 +132,7 @@ func reset(clusters []*entity.Cluster, gokuConfig *config.GokuConfig, balanceCon
 			DiscoverConfig:      df,
 			Balance:             bf,
 			Plugins:             gokuConfig.Plugins,
+			Scripts:             gokuConfig.Scripts,
 			APIS:                gokuConfig.APIS,
 			Strategy:            gokuConfig.Strategy,
 			AuthPlugin:          gokuConfig.AuthPlugin,
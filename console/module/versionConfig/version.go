@@ -36,6 +36,10 @@ func GetVersionList(keyword string) ([]config.VersionConfig, error) {
 //AddVersionConfig 新增版本配置
 func AddVersionConfig(name, version, remark, now string, userID int) (int, error) {
 	config, balanceConfig, discoverConfig := buildVersionConfig(version)
+	err := checkVersionConfig(config)
+	if err != nil {
+		return 0, err
+	}
 	return versionDao.AddVersionConfig(name, version, remark, config, balanceConfig, discoverConfig, now, userID)
 }
 func EditVersionBasicConfig(name, version, remark string, userID, versionID int) error {
@@ -50,13 +54,35 @@ func BatchDeleteVersionConfig(ids []int) error {
 
 //PublishVersion 发布版本
 func PublishVersion(id, userID int, now string) error {
-	err := versionDao.PublishVersion(id, userID, now)
+	c, err := versionDao.GetVersionConfigByID(id)
+	if err != nil {
+		return err
+	}
+	err = checkRouters(c)
+	if err != nil {
+		return err
+	}
+	err = checkPlugins(c)
+	if err != nil {
+		return err
+	}
+	err = versionDao.PublishVersion(id, userID, now)
 	if err == nil {
 		load()
 	}
 	return err
 }
 
+// checkVersionConfig 新建版本时检查插件配置
+func checkVersionConfig(content string) error {
+	c := new(config.GokuConfig)
+	err := json.Unmarshal([]byte(content), c)
+	if err != nil {
+		return err
+	}
+	return checkPlugins(c)
+}
+
 //GetVersionConfigCount 获取版本配置数量
 func GetVersionConfigCount() int {
 	return versionDao.GetVersionConfigCount()
@@ -95,6 +121,10 @@ func buildVersionConfig(v string) (string, string, string) {
 	if err != nil {
 		return "", "", ""
 	}
+	scripts, err := versionConfigDao.GetPluginScripts()
+	if err != nil {
+		return "", "", ""
+	}
 	logCf, accessCf, err := versionConfigDao.GetLogInfo()
 	if err != nil {
 		return "", "", ""
@@ -116,6 +146,7 @@ func buildVersionConfig(v string) (string, string, string) {
 	c := config.GokuConfig{
 		Version:             v,
 		Plugins:             *plugins,
+		Scripts:             scripts,
 		APIS:                apiContents,
 		Strategy:            strategyConfigs,
 		AnonymousStrategyID: openStrategy,
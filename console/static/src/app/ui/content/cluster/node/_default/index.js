@@ -211,6 +211,18 @@
                         key: '状态',
                         html: `<span class="eo-status-warning" ng-if="item.nodeStatus=='0'">未运行</span><span class="eo-status-danger" ng-if="item.nodeStatus=='2'">异常</span><span class="eo-status-success" ng-if="item.nodeStatus=='1'">运行中</span>`,
                         draggableCacheMark: 'status'
+                    }, {
+                        key: '心跳',
+                        html: `<span ng-if="item.heartbeat">{{item.heartbeat.reportTime}}</span><span ng-if="!item.heartbeat">-</span>`,
+                        draggableCacheMark: 'heartbeat'
+                    }, {
+                        key: '请求/错误',
+                        html: `<span ng-if="item.heartbeat">{{item.heartbeat.requests}} / {{item.heartbeat.errors}}</span><span ng-if="!item.heartbeat">-</span>`,
+                        draggableCacheMark: 'requests'
+                    }, {
+                        key: 'P50/P99(ms)',
+                        html: `<span ng-if="item.heartbeat">{{item.heartbeat.p50 | number:0}} / {{item.heartbeat.p99 | number:0}}</span><span ng-if="!item.heartbeat">-</span>`,
+                        draggableCacheMark: 'latency'
                     }, {
                         key: '分组',
                         html: '{{item.groupName}}',
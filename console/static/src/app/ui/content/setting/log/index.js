@@ -79,6 +79,16 @@
                         if(/(desc)|(isHide)/.test(key))return undefined;
                         return val;
                     });
+                    tmpAjaxRequest.format=tmpResponseObj.format;
+                    try{
+                        JSON.parse(tmpResponseObj.sinksText||'[]');
+                        vm.data.sinksInvalid=false;
+                    }catch(e){
+                        vm.data.sinksInvalid=true;
+                        $rootScope.InfoModal('外部输出配置不是合法的JSON！', 'error');
+                        return;
+                    }
+                    tmpAjaxRequest.sinks=tmpResponseObj.sinksText||'';
                     break;
                 }
                 default:{
@@ -99,6 +109,27 @@
             })
             return tmpPromise;
         }
+        vm.fun.addField = function () {
+            let tmpName=(vm.data.customField||'').trim(),tmpFields=vm.ajaxResponse.accessLog.fields||[];
+            let tmpMatch=(vm.ajaxResponse.accessLog.dynamicFields||[]).some((val)=>{
+                let tmpPrefix=val.name.replace('<name>','');
+                return tmpName.toLowerCase().indexOf(tmpPrefix)===0&&tmpName.length>tmpPrefix.length;
+            });
+            if(!tmpMatch){
+                $rootScope.InfoModal('不支持的字段！', 'error');
+                return;
+            }
+            if(tmpFields.some((val)=>val.name===tmpName)){
+                $rootScope.InfoModal('字段已存在！', 'error');
+                return;
+            }
+            tmpFields.push({
+                name:tmpName,
+                select:true,
+                desc:'自定义字段'
+            });
+            vm.data.customField='';
+        }
         vm.fun.cancelEdit = function (inputWhich) {
             vm.data.submitted = false;
             vm.data[inputWhich+'IsEdit']=false;
@@ -113,6 +144,7 @@
                             t: new Date().getTime()
                         }).$promise.then((response) => {
                             vm.ajaxResponse.accessLog = response.data;
+                            vm.ajaxResponse.accessLog.sinksText = JSON.stringify(response.data.sinks || [], null, 2);
                             cache.accessLog = angular.copy(vm.ajaxResponse.accessLog);
                         })
                     }
@@ -12,6 +12,26 @@ const (
 	APIName = "api"
 	//ProxyName proxyName
 	ProxyName = "proxy"
+	//AccessLogName accessLogName
+	AccessLogName = "access_log"
+	//AccessLogQueueName accessLogQueueName
+	AccessLogQueueName = "access_log_queue"
+	//PluginName pluginName
+	PluginName = "plugin"
+	//PluginErrorName pluginErrorName
+	PluginErrorName = "plugin_error"
+	//TrafficSplitName trafficSplitName
+	TrafficSplitName = "traffic_split"
+	//MirrorName mirrorName
+	MirrorName = "mirror"
+	//MirrorCountName mirrorCountName
+	MirrorCountName = "mirror_count"
+	//MirrorDiffName mirrorDiffName
+	MirrorDiffName = "mirror_diff"
+	//RetryName retryName
+	RetryName = "retry"
+	//HedgeName hedgeName
+	HedgeName = "hedge"
 
 	API      = "api"
 	Strategy = "strategy"
@@ -20,6 +40,12 @@ const (
 	Method   = "method"
 	Host     = "host"
 	Path     = "path"
+	Sink     = "sink"
+	Result   = "result"
+	Plugin   = "plugin"
+	Phase    = "phase"
+	Split    = "split"
+	Balance  = "balance"
 )
 
 var (
@@ -27,6 +53,8 @@ var (
 	APIBuckets = []float64{5, 25, 50, 100, 200, 400, 600, 800, 1000, 2500, 5000}
 	//ProxyBuckets proxyBuckets
 	ProxyBuckets = []float64{5, 25, 50, 100, 200, 400, 600, 800, 1000, 2500, 5000}
+	//PluginBuckets pluginBuckets
+	PluginBuckets = []float64{1, 2, 5, 10, 25, 50, 100, 250, 500, 1000}
 
 	//APIDelayLabelNames apiDelayLabelNames
 	APIDelayLabelNames = []string{
@@ -48,4 +76,70 @@ var (
 		Method,
 		Status,
 	}
+	//AccessLogLabelNames accessLogLabelNames
+	AccessLogLabelNames = []string{
+		Cluster,
+		Instance,
+		Sink,
+		Result,
+	}
+	//AccessLogQueueLabelNames accessLogQueueLabelNames
+	AccessLogQueueLabelNames = []string{
+		Cluster,
+		Instance,
+		Sink,
+	}
+	//PluginLabelNames pluginLabelNames
+	PluginLabelNames = []string{
+		Cluster,
+		Instance,
+		Plugin,
+		Phase,
+		Result,
+	}
+	//TrafficSplitLabelNames trafficSplitLabelNames
+	TrafficSplitLabelNames = []string{
+		Cluster,
+		Instance,
+		API,
+		Strategy,
+		Split,
+		Status,
+	}
+	//MirrorLabelNames mirrorLabelNames
+	MirrorLabelNames = []string{
+		Cluster,
+		Instance,
+		API,
+		Strategy,
+		Balance,
+		Status,
+	}
+	//MirrorResultLabelNames mirrorResultLabelNames
+	MirrorResultLabelNames = []string{
+		Cluster,
+		Instance,
+		API,
+		Strategy,
+		Balance,
+		Result,
+	}
+	//RetryLabelNames retryLabelNames
+	RetryLabelNames = []string{
+		Cluster,
+		Instance,
+		API,
+		Strategy,
+		Balance,
+		Result,
+	}
+	//HedgeLabelNames hedgeLabelNames
+	HedgeLabelNames = []string{
+		Cluster,
+		Instance,
+		API,
+		Strategy,
+		Balance,
+		Result,
+	}
 )
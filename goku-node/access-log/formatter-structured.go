@@ -0,0 +1,87 @@
+package access_log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	access_field "github.com/eolinker/goku-api-gateway/server/access-field"
+	"github.com/sirupsen/logrus"
+)
+
+// fieldName 去掉字段的$前缀作为结构化日志的key
+func fieldName(key string) string {
+	return strings.TrimPrefix(key, "$")
+}
+
+// structuredValue 结构化日志中的值：耗时转为毫秒，去掉文本格式附加的引号
+func structuredValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case time.Duration:
+		return float64(v) / float64(time.Millisecond)
+	case string:
+		if len(v) >= 2 && v[0] == '"' && v[len(v)-1] == '"' {
+			return v[1 : len(v)-1]
+		}
+		return v
+	}
+	return value
+}
+
+func (f *AccessLogFormatter) formatJSON(b *bytes.Buffer, fields []access_field.AccessFieldKey, data logrus.Fields) ([]byte, error) {
+	b.WriteByte('{')
+	for i, key := range fields {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, _ := json.Marshal(fieldName(key.Key()))
+		b.Write(name)
+		b.WriteByte(':')
+
+		v, has := data[key.Key()]
+		if !has {
+			b.WriteString("null")
+			continue
+		}
+		value, err := json.Marshal(structuredValue(v))
+		if err != nil {
+			value, _ = json.Marshal(fmt.Sprint(v))
+		}
+		b.Write(value)
+	}
+	b.WriteString("}\n")
+	return b.Bytes(), nil
+}
+
+func (f *AccessLogFormatter) formatLogfmt(b *bytes.Buffer, fields []access_field.AccessFieldKey, data logrus.Fields) ([]byte, error) {
+	for i, key := range fields {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(fieldName(key.Key()))
+		b.WriteByte('=')
+
+		v, has := data[key.Key()]
+		if !has {
+			continue
+		}
+		var s string
+		switch value := structuredValue(v).(type) {
+		case string:
+			s = value
+		case float64:
+			s = strconv.FormatFloat(value, 'f', -1, 64)
+		default:
+			s = fmt.Sprint(value)
+		}
+		if s == "" || strings.ContainsAny(s, " =\"\t\n") {
+			s = strconv.Quote(s)
+		}
+		b.WriteString(s)
+	}
+	b.WriteByte('\n')
+	return b.Bytes(), nil
+}
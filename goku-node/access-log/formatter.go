@@ -10,17 +10,66 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	//FormatText 以tab分隔的文本
+	FormatText = "text"
+	//FormatJSON 每行一个json对象
+	FormatJSON = "json"
+	//FormatLogfmt key=value格式
+	FormatLogfmt = "logfmt"
+)
+
+//IsFormat 判断是否为支持的格式
+func IsFormat(format string) bool {
+	switch format {
+	case FormatText, FormatJSON, FormatLogfmt:
+		return true
+	}
+	return false
+}
+
 //AccessLogFormatter access日志格式器
 type AccessLogFormatter struct {
 	fields          []access_field.AccessFieldKey
+	dynamic         []access_field.AccessFieldKey
+	format          string
 	locker          sync.RWMutex
 	TimestampFormat string
 }
 
 //SetFields 设置域
 func (f *AccessLogFormatter) SetFields(fields []access_field.AccessFieldKey) {
+	dynamic := dynamicFields(fields)
 	f.locker.Lock()
 	f.fields = fields
+	f.dynamic = dynamic
+	f.locker.Unlock()
+}
+
+//DynamicFields 动态域
+func (f *AccessLogFormatter) DynamicFields() []access_field.AccessFieldKey {
+	f.locker.RLock()
+	defer f.locker.RUnlock()
+	return f.dynamic
+}
+
+func dynamicFields(fields []access_field.AccessFieldKey) []access_field.AccessFieldKey {
+	dynamic := make([]access_field.AccessFieldKey, 0)
+	for _, key := range fields {
+		if _, _, ok := key.Dynamic(); ok {
+			dynamic = append(dynamic, key)
+		}
+	}
+	return dynamic
+}
+
+//SetFormat 设置输出格式，不支持的格式按text处理
+func (f *AccessLogFormatter) SetFormat(format string) {
+	if !IsFormat(format) {
+		format = FormatText
+	}
+	f.locker.Lock()
+	f.format = format
 	f.locker.Unlock()
 }
 
@@ -44,10 +93,23 @@ func (f *AccessLogFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 	msec := entry.Time.UnixNano() / int64(time.Millisecond)
 	data[access_field.Msec] = fmt.Sprintf("%d.%d", msec/1000, msec%1000)
 
-	requestTIme := data[access_field.RequestTime].(time.Duration)
-	data[access_field.RequestTime] = fmt.Sprintf("%dms", requestTIme/time.Millisecond)
+	f.locker.RLock()
+	fields := f.fields
+	format := f.format
+	f.locker.RUnlock()
+
+	switch format {
+	case FormatJSON:
+		return f.formatJSON(b, fields, data)
+	case FormatLogfmt:
+		return f.formatLogfmt(b, fields, data)
+	}
+
+	if requestTIme, ok := data[access_field.RequestTime].(time.Duration); ok {
+		data[access_field.RequestTime] = fmt.Sprintf("%dms", requestTIme/time.Millisecond)
+	}
 
-	for _, key := range f.fields {
+	for _, key := range fields {
 		b.WriteByte('\t')
 		if v, has := data[key.Key()]; has {
 			f.appendValue(b, v)
@@ -75,5 +137,5 @@ func (f *AccessLogFormatter) appendValue(b *bytes.Buffer, value interface{}) {
 
 //NewAccessLogFormatter 创建AccessLogFormatter
 func NewAccessLogFormatter(fields []access_field.AccessFieldKey) *AccessLogFormatter {
-	return &AccessLogFormatter{fields: fields}
+	return &AccessLogFormatter{fields: fields, dynamic: dynamicFields(fields)}
 }
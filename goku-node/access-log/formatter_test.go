@@ -0,0 +1,79 @@
+package access_log
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"hash/crc32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	access_field "github.com/eolinker/goku-api-gateway/server/access-field"
+	"github.com/sirupsen/logrus"
+)
+
+func testEntry() *logrus.Entry {
+	return &logrus.Entry{
+		Time: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Data: logrus.Fields{
+			access_field.RemoteAddr:  "192.168.0.1",
+			access_field.Request:     "\"GET /kingsword HTTP/1.1\"",
+			access_field.StatusCode:  200,
+			access_field.RequestTime: 1500 * time.Microsecond,
+		},
+	}
+}
+
+func TestFormatJSON(t *testing.T) {
+	f := NewAccessLogFormatter([]access_field.AccessFieldKey{access_field.RemoteAddr, access_field.Request, access_field.StatusCode, access_field.RequestTime, access_field.Balance})
+	f.SetFormat(FormatJSON)
+	out, err := f.Format(testEntry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := make(map[string]interface{})
+	if err := json.Unmarshal(out, &v); err != nil {
+		t.Fatalf("invalid json %s: %v", out, err)
+	}
+	if v["request"] != "GET /kingsword HTTP/1.1" || v["status_code"] != float64(200) || v["request_time"] != 1.5 || v["balance"] != nil {
+		t.Fatalf("unexpected json: %s", out)
+	}
+}
+
+func TestFormatLogfmt(t *testing.T) {
+	f := NewAccessLogFormatter([]access_field.AccessFieldKey{access_field.RemoteAddr, access_field.Request, access_field.StatusCode, access_field.Balance})
+	f.SetFormat(FormatLogfmt)
+	out, err := f.Format(testEntry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `remote_addr=192.168.0.1 request="GET /kingsword HTTP/1.1" status_code=200 balance=` + "\n"
+	if string(out) != expect {
+		t.Fatalf("got %q, expect %q", out, expect)
+	}
+}
+
+func TestKafkaRecordBatch(t *testing.T) {
+	batch := encodeRecordBatch([][]byte{[]byte("a\n"), []byte("bc\n")}, time.Now())
+	if int(binary.BigEndian.Uint32(batch[8:12])) != len(batch)-12 {
+		t.Fatal("invalid batch length")
+	}
+	if batch[16] != 2 {
+		t.Fatal("record batch magic should be 2")
+	}
+	if binary.BigEndian.Uint32(batch[17:21]) != crc32.Checksum(batch[21:], crc32c) {
+		t.Fatal("invalid crc")
+	}
+	if binary.BigEndian.Uint32(batch[57:61]) != 2 {
+		t.Fatal("invalid record count")
+	}
+}
+
+func TestSyslogMessage(t *testing.T) {
+	s, _ := newSyslogSink(&config.AccessLogSink{Type: SinkSyslog, Address: "127.0.0.1:514"})
+	msg := string(s.message([]byte("hello\n")))
+	if !strings.HasPrefix(msg, "<134>1 ") || !strings.HasSuffix(msg, " goku-node "+s.pid+" access - hello") {
+		t.Fatalf("unexpected syslog message: %q", msg)
+	}
+}
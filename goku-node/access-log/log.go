@@ -1,8 +1,12 @@
 package access_log
 
 import (
+	"encoding/json"
+	"io"
+	"sync"
 	"time"
 
+	"github.com/eolinker/goku-api-gateway/config"
 	log "github.com/eolinker/goku-api-gateway/goku-log"
 	access_field "github.com/eolinker/goku-api-gateway/server/access-field"
 	"github.com/sirupsen/logrus"
@@ -12,6 +16,7 @@ var (
 	logger    *logrus.Logger
 	formatter *AccessLogFormatter
 	writer    *log.FileWriterByPeriod
+	output    = new(outputs)
 )
 
 //Fields 域
@@ -19,7 +24,7 @@ type Fields = logrus.Fields
 
 //Log log
 func Log(fields Fields) {
-	if logger == nil {
+	if logger == nil || !output.enable() {
 		return
 	}
 	logger.WithFields(fields).Info()
@@ -34,6 +39,22 @@ func SetFields(fields []access_field.AccessFieldKey) {
 	}
 }
 
+//DynamicFields 当前配置中需要按请求计算的动态域
+func DynamicFields() []access_field.AccessFieldKey {
+	if formatter == nil {
+		return nil
+	}
+	return formatter.DynamicFields()
+}
+
+//SetFormat 设置输出格式：text、json、logfmt
+func SetFormat(format string) {
+	if formatter == nil {
+		formatter = NewAccessLogFormatter(access_field.Default())
+	}
+	formatter.SetFormat(format)
+}
+
 //SetOutput 设置输出
 func SetOutput(enable bool, dir, file string, period log.LogPeriod, expire int) {
 
@@ -44,14 +65,11 @@ func SetOutput(enable bool, dir, file string, period log.LogPeriod, expire int)
 
 		writer.Set(dir, file, period, time.Duration(expire)*time.Hour*24)
 		writer.Open()
-		if logger == nil {
-			logger = logrus.New()
-			logger.SetFormatter(formatter)
-			logger.SetOutput(writer)
-			logger.SetLevel(logrus.InfoLevel)
-		}
+		output.setFile(writer)
+		initLogger()
 
 	} else {
+		output.setFile(nil)
 		if writer != nil {
 			writer.Close()
 		}
@@ -59,3 +77,97 @@ func SetOutput(enable bool, dir, file string, period log.LogPeriod, expire int)
 	}
 
 }
+
+//SetSinks 设置额外的输出目标，配置未变化时保持原有连接
+func SetSinks(confs []*config.AccessLogSink) {
+	data, _ := json.Marshal(confs)
+	if !output.changed(string(data)) {
+		return
+	}
+
+	sinks := make([]*asyncSink, 0, len(confs))
+	for _, conf := range confs {
+		if err := CheckSink(conf); err != nil {
+			log.Warn("invalid access log sink:", err)
+			continue
+		}
+		sink, err := NewSink(conf)
+		if err != nil {
+			log.Warn("create access log sink:", err)
+			continue
+		}
+		sinks = append(sinks, newAsyncSink(sink, conf.BufferSize, conf.BatchSize))
+	}
+	old := output.setSinks(string(data), sinks)
+	for _, s := range old {
+		go s.close()
+	}
+	if len(sinks) > 0 {
+		initLogger()
+	}
+}
+
+func initLogger() {
+	if logger != nil {
+		return
+	}
+	if formatter == nil {
+		formatter = NewAccessLogFormatter(access_field.Default())
+	}
+	logger = logrus.New()
+	logger.SetFormatter(formatter)
+	logger.SetOutput(output)
+	logger.SetLevel(logrus.InfoLevel)
+}
+
+// outputs 将一条access日志写入本地文件及所有外部输出目标
+type outputs struct {
+	locker sync.RWMutex
+	file   io.Writer
+	sinks  []*asyncSink
+	conf   string
+}
+
+func (o *outputs) enable() bool {
+	o.locker.RLock()
+	defer o.locker.RUnlock()
+	return o.file != nil || len(o.sinks) > 0
+}
+
+func (o *outputs) setFile(w io.Writer) {
+	o.locker.Lock()
+	o.file = w
+	o.locker.Unlock()
+}
+
+func (o *outputs) changed(conf string) bool {
+	o.locker.RLock()
+	defer o.locker.RUnlock()
+	return o.conf != conf
+}
+
+func (o *outputs) setSinks(conf string, sinks []*asyncSink) []*asyncSink {
+	o.locker.Lock()
+	old := o.sinks
+	o.sinks = sinks
+	o.conf = conf
+	o.locker.Unlock()
+	return old
+}
+
+func (o *outputs) Write(p []byte) (int, error) {
+	o.locker.RLock()
+	file := o.file
+	sinks := o.sinks
+	o.locker.RUnlock()
+
+	if file != nil {
+		if _, err := file.Write(p); err != nil {
+			log.Warn("write access log:", err)
+		}
+	}
+	for _, s := range sinks {
+		_, _ = s.Write(p)
+	}
+	return len(p), nil
+}
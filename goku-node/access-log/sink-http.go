@@ -0,0 +1,48 @@
+package access_log
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+// httpSink 将一批日志按行拼接后POST到指定地址
+type httpSink struct {
+	url    string
+	client *http.Client
+}
+
+func newHTTPSink(conf *config.AccessLogSink) (*httpSink, error) {
+	return &httpSink{
+		url:    conf.Address,
+		client: &http.Client{Timeout: time.Second * 10},
+	}, nil
+}
+
+func (s *httpSink) Name() string {
+	return s.url
+}
+
+func (s *httpSink) WriteBatch(lines [][]byte) error {
+	body := bytes.Join(lines, nil)
+	resp, err := s.client.Post(s.url, "application/x-ndjson", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("response status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *httpSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,240 @@
+package access_log
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+const (
+	kafkaProduceKey     = 0
+	kafkaProduceVersion = 3
+	kafkaClientID       = "goku-node"
+	kafkaTimeout        = time.Second * 10
+)
+
+var (
+	crc32c = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// kafkaSink 直接向broker发送Produce请求(v3, RecordBatch v2)，acks=1
+// 不做元数据发现，address需为目标分区leader所在broker
+type kafkaSink struct {
+	address       string
+	topic         string
+	partition     int32
+	correlationID int32
+	conn          net.Conn
+	reader        *bufio.Reader
+}
+
+func newKafkaSink(conf *config.AccessLogSink) (*kafkaSink, error) {
+	return &kafkaSink{
+		address:   conf.Address,
+		topic:     conf.Topic,
+		partition: int32(conf.Partition),
+	}, nil
+}
+
+func (s *kafkaSink) Name() string {
+	return fmt.Sprint(SinkKafka, "://", s.address, "/", s.topic)
+}
+
+func (s *kafkaSink) connect() (net.Conn, error) {
+	if s.conn != nil {
+		return s.conn, nil
+	}
+	conn, err := net.DialTimeout("tcp", s.address, kafkaTimeout)
+	if err != nil {
+		return nil, err
+	}
+	s.conn = conn
+	s.reader = bufio.NewReader(conn)
+	return conn, nil
+}
+
+func (s *kafkaSink) WriteBatch(lines [][]byte) error {
+	conn, err := s.connect()
+	if err != nil {
+		return err
+	}
+	s.correlationID++
+	request := encodeProduceRequest(s.correlationID, s.topic, s.partition, lines, time.Now())
+
+	_ = conn.SetDeadline(time.Now().Add(kafkaTimeout))
+	if _, err = conn.Write(request); err == nil {
+		err = s.readResponse()
+	}
+	if err != nil {
+		_ = conn.Close()
+		s.conn = nil
+	}
+	return err
+}
+
+func (s *kafkaSink) readResponse() error {
+	var size int32
+	if err := binary.Read(s.reader, binary.BigEndian, &size); err != nil {
+		return err
+	}
+	body := make([]byte, size)
+	if _, err := io.ReadFull(s.reader, body); err != nil {
+		return err
+	}
+	return decodeProduceResponse(s.correlationID, body)
+}
+
+func (s *kafkaSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+func encodeProduceRequest(correlationID int32, topic string, partition int32, lines [][]byte, now time.Time) []byte {
+	batch := encodeRecordBatch(lines, now)
+
+	var b bytes.Buffer
+	// request header v1
+	writeInt16(&b, kafkaProduceKey)
+	writeInt16(&b, kafkaProduceVersion)
+	writeInt32(&b, correlationID)
+	writeString(&b, kafkaClientID)
+	// transactional_id: null
+	writeInt16(&b, -1)
+	// acks
+	writeInt16(&b, 1)
+	writeInt32(&b, int32(kafkaTimeout/time.Millisecond))
+	// [topic_data]
+	writeInt32(&b, 1)
+	writeString(&b, topic)
+	// [partition_data]
+	writeInt32(&b, 1)
+	writeInt32(&b, partition)
+	writeInt32(&b, int32(len(batch)))
+	b.Write(batch)
+
+	request := make([]byte, 4, 4+b.Len())
+	binary.BigEndian.PutUint32(request, uint32(b.Len()))
+	return append(request, b.Bytes()...)
+}
+
+func encodeRecordBatch(lines [][]byte, now time.Time) []byte {
+	timestamp := now.UnixNano() / int64(time.Millisecond)
+
+	var records bytes.Buffer
+	for i, line := range lines {
+		line = bytes.TrimRight(line, "\n")
+		var r bytes.Buffer
+		r.WriteByte(0) // attributes
+		writeVarint(&r, 0)
+		writeVarint(&r, int64(i))
+		writeVarint(&r, -1) // key: null
+		writeVarint(&r, int64(len(line)))
+		r.Write(line)
+		writeVarint(&r, 0) // headers
+
+		writeVarint(&records, int64(r.Len()))
+		records.Write(r.Bytes())
+	}
+
+	// crc覆盖attributes之后的全部内容
+	var tail bytes.Buffer
+	writeInt16(&tail, 0) // attributes
+	writeInt32(&tail, int32(len(lines)-1))
+	writeInt64(&tail, timestamp)
+	writeInt64(&tail, timestamp)
+	writeInt64(&tail, -1) // producerId
+	writeInt16(&tail, -1) // producerEpoch
+	writeInt32(&tail, -1) // baseSequence
+	writeInt32(&tail, int32(len(lines)))
+	tail.Write(records.Bytes())
+
+	var b bytes.Buffer
+	writeInt64(&b, 0) // baseOffset
+	// batchLength: partitionLeaderEpoch(4) + magic(1) + crc(4) + tail
+	writeInt32(&b, int32(4+1+4+tail.Len()))
+	writeInt32(&b, -1) // partitionLeaderEpoch
+	b.WriteByte(2)     // magic
+	writeInt32(&b, int32(crc32.Checksum(tail.Bytes(), crc32c)))
+	b.Write(tail.Bytes())
+	return b.Bytes()
+}
+
+// decodeProduceResponse 只检查第一个分区的错误码
+func decodeProduceResponse(correlationID int32, body []byte) error {
+	r := bytes.NewReader(body)
+	var id, topics, partitions, partition int32
+	var errorCode int16
+	if err := binary.Read(r, binary.BigEndian, &id); err != nil {
+		return err
+	}
+	if id != correlationID {
+		return fmt.Errorf("kafka correlation id mismatch: %d != %d", id, correlationID)
+	}
+	if err := binary.Read(r, binary.BigEndian, &topics); err != nil || topics < 1 {
+		return errors.New("kafka empty produce response")
+	}
+	if err := skipString(r); err != nil {
+		return err
+	}
+	if err := binary.Read(r, binary.BigEndian, &partitions); err != nil || partitions < 1 {
+		return errors.New("kafka empty produce response")
+	}
+	if err := binary.Read(r, binary.BigEndian, &partition); err != nil {
+		return err
+	}
+	if err := binary.Read(r, binary.BigEndian, &errorCode); err != nil {
+		return err
+	}
+	if errorCode != 0 {
+		return fmt.Errorf("kafka produce error code %d", errorCode)
+	}
+	return nil
+}
+
+func writeInt16(b *bytes.Buffer, v int16) {
+	_ = binary.Write(b, binary.BigEndian, v)
+}
+
+func writeInt32(b *bytes.Buffer, v int32) {
+	_ = binary.Write(b, binary.BigEndian, v)
+}
+
+func writeInt64(b *bytes.Buffer, v int64) {
+	_ = binary.Write(b, binary.BigEndian, v)
+}
+
+func writeString(b *bytes.Buffer, s string) {
+	writeInt16(b, int16(len(s)))
+	b.WriteString(s)
+}
+
+// writeVarint zigzag varint，与kafka的编码一致
+func writeVarint(b *bytes.Buffer, v int64) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(buf[:], v)
+	b.Write(buf[:n])
+}
+
+func skipString(r *bytes.Reader) error {
+	var l int16
+	if err := binary.Read(r, binary.BigEndian, &l); err != nil {
+		return err
+	}
+	if l > 0 {
+		_, err := r.Seek(int64(l), io.SeekCurrent)
+		return err
+	}
+	return nil
+}
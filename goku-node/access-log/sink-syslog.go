@@ -0,0 +1,115 @@
+package access_log
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+const (
+	// facility local0, severity informational
+	syslogPriority = 16*8 + 6
+	syslogTimeout  = time.Second * 5
+)
+
+// syslogSink RFC5424格式，UDP每条一个报文，TCP使用octet-counting分帧(RFC6587)
+type syslogSink struct {
+	network  string
+	address  string
+	hostname string
+	tag      string
+	pid      string
+	conn     net.Conn
+}
+
+func newSyslogSink(conf *config.AccessLogSink) (*syslogSink, error) {
+	network := conf.Network
+	if network == "" {
+		network = "udp"
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	tag := conf.Tag
+	if tag == "" {
+		tag = "goku-node"
+	}
+	return &syslogSink{
+		network:  network,
+		address:  conf.Address,
+		hostname: hostname,
+		tag:      tag,
+		pid:      strconv.Itoa(os.Getpid()),
+	}, nil
+}
+
+func (s *syslogSink) Name() string {
+	return fmt.Sprint(SinkSyslog, "://", s.address)
+}
+
+func (s *syslogSink) connect() (net.Conn, error) {
+	if s.conn != nil {
+		return s.conn, nil
+	}
+	conn, err := net.DialTimeout(s.network, s.address, syslogTimeout)
+	if err != nil {
+		return nil, err
+	}
+	s.conn = conn
+	return conn, nil
+}
+
+func (s *syslogSink) WriteBatch(lines [][]byte) error {
+	conn, err := s.connect()
+	if err != nil {
+		return err
+	}
+	_ = conn.SetWriteDeadline(time.Now().Add(syslogTimeout))
+
+	var buf bytes.Buffer
+	for _, line := range lines {
+		msg := s.message(line)
+		if s.network == "udp" {
+			_, err = conn.Write(msg)
+		} else {
+			buf.WriteString(strconv.Itoa(len(msg)))
+			buf.WriteByte(' ')
+			buf.Write(msg)
+		}
+		if err != nil {
+			break
+		}
+	}
+	if err == nil && buf.Len() > 0 {
+		_, err = conn.Write(buf.Bytes())
+	}
+	if err != nil {
+		_ = conn.Close()
+		s.conn = nil
+	}
+	return err
+}
+
+// message <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+func (s *syslogSink) message(line []byte) []byte {
+	line = bytes.TrimRight(line, "\n")
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "<%d>1 %s %s %s %s access - ", syslogPriority, time.Now().Format(time.RFC3339Nano), s.hostname, s.tag, s.pid)
+	b.Write(line)
+	return b.Bytes()
+}
+
+func (s *syslogSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
@@ -0,0 +1,207 @@
+package access_log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/diting"
+	goku_labels "github.com/eolinker/goku-api-gateway/goku-labels"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/node/monitor"
+)
+
+const (
+	//SinkSyslog syslog(RFC5424)
+	SinkSyslog = "syslog"
+	//SinkHTTP http批量POST
+	SinkHTTP = "http"
+	//SinkKafka kafka producer
+	SinkKafka = "kafka"
+
+	defaultBufferSize    = 10000
+	defaultBatchSize     = 100
+	defaultFlushInterval = time.Second
+	metricsInterval      = time.Second * 10
+)
+
+//Sink access日志输出目标
+type Sink interface {
+	Name() string
+	WriteBatch(lines [][]byte) error
+	Close() error
+}
+
+//NewSink 根据配置创建输出目标
+func NewSink(conf *config.AccessLogSink) (Sink, error) {
+	switch conf.Type {
+	case SinkSyslog:
+		return newSyslogSink(conf)
+	case SinkHTTP:
+		return newHTTPSink(conf)
+	case SinkKafka:
+		return newKafkaSink(conf)
+	}
+	return nil, fmt.Errorf("unknown access log sink type:%s", conf.Type)
+}
+
+//CheckSink 检查输出目标配置
+func CheckSink(conf *config.AccessLogSink) error {
+	if conf == nil || conf.Address == "" {
+		return errors.New("sink address is required")
+	}
+	switch conf.Type {
+	case SinkSyslog:
+		if conf.Network != "" && conf.Network != "udp" && conf.Network != "tcp" {
+			return fmt.Errorf("invalid syslog network:%s", conf.Network)
+		}
+	case SinkHTTP:
+	case SinkKafka:
+		if conf.Topic == "" {
+			return errors.New("kafka topic is required")
+		}
+	default:
+		return fmt.Errorf("unknown access log sink type:%s", conf.Type)
+	}
+	return nil
+}
+
+// asyncSink 有界队列+后台批量发送，队列满时丢弃并计数，不阻塞请求
+type asyncSink struct {
+	sink      Sink
+	queue     chan []byte
+	batchSize int
+
+	sent    uint64
+	dropped uint64
+	failed  uint64
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newAsyncSink(sink Sink, bufferSize, batchSize int) *asyncSink {
+	if bufferSize <= 0 {
+		bufferSize = defaultBufferSize
+	}
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &asyncSink{
+		sink:      sink,
+		queue:     make(chan []byte, bufferSize),
+		batchSize: batchSize,
+		cancel:    cancel,
+	}
+	s.wg.Add(2)
+	go s.loop(ctx)
+	go s.report(ctx)
+	return s
+}
+
+// Write 实现io.Writer，logrus每次写入一行
+func (s *asyncSink) Write(p []byte) (int, error) {
+	line := make([]byte, len(p))
+	copy(line, p)
+	select {
+	case s.queue <- line:
+	default:
+		atomic.AddUint64(&s.dropped, 1)
+	}
+	return len(p), nil
+}
+
+func (s *asyncSink) close() {
+	s.cancel()
+	s.wg.Wait()
+	err := s.sink.Close()
+	if err != nil {
+		log.Warn("close access log sink ", s.sink.Name(), ":", err)
+	}
+}
+
+func (s *asyncSink) loop(ctx context.Context) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(defaultFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([][]byte, 0, s.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.sink.WriteBatch(batch); err != nil {
+			atomic.AddUint64(&s.failed, uint64(len(batch)))
+			log.Warn("write access log to ", s.sink.Name(), ":", err)
+		} else {
+			atomic.AddUint64(&s.sent, uint64(len(batch)))
+		}
+		batch = make([][]byte, 0, s.batchSize)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			for {
+				select {
+				case line := <-s.queue:
+					batch = append(batch, line)
+					if len(batch) >= s.batchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		case line := <-s.queue:
+			batch = append(batch, line)
+			if len(batch) >= s.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// report 定时上报发送、丢弃、失败计数及队列长度
+func (s *asyncSink) report(ctx context.Context) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(metricsInterval)
+	defer ticker.Stop()
+
+	var sent, dropped, failed uint64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if monitor.AccessLogCounter == nil {
+				continue
+			}
+			sent = s.reportCount("sent", &s.sent, sent)
+			dropped = s.reportCount("dropped", &s.dropped, dropped)
+			failed = s.reportCount("failed", &s.failed, failed)
+
+			labels := make(diting.Labels)
+			labels[goku_labels.Sink] = s.sink.Name()
+			monitor.AccessLogQueue.Set(float64(len(s.queue)), labels)
+		}
+	}
+}
+
+func (s *asyncSink) reportCount(result string, counter *uint64, last uint64) uint64 {
+	current := atomic.LoadUint64(counter)
+	if current > last {
+		labels := make(diting.Labels)
+		labels[goku_labels.Sink] = s.sink.Name()
+		labels[goku_labels.Result] = result
+		monitor.AccessLogCounter.Add(float64(current-last), labels)
+	}
+	return current
+}
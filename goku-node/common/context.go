@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	log "github.com/eolinker/goku-api-gateway/goku-log"
+	goku_trace "github.com/eolinker/goku-api-gateway/goku-trace"
 
 	goku_plugin "github.com/eolinker/goku-plugin"
 )
@@ -18,6 +19,7 @@ type Context struct {
 	*PriorityHeader
 	*StatusHandler
 	*StoreHandler
+	*Timing
 	RequestOrg           *RequestReader
 	ProxyRequest         *Request
 	ProxyResponseHandler *ResponseReader
@@ -28,6 +30,7 @@ type Context struct {
 	requestID            string
 	finalTargetServer    string
 	retryTargetServers   string
+	span                 *goku_trace.Span
 
 	RestfulParam map[string]string
 	LogFields    log.Fields
@@ -43,6 +46,16 @@ func (ctx *Context) SetFinalTargetServer(finalTargetServer string) {
 	ctx.finalTargetServer = finalTargetServer
 }
 
+//Span 当前请求的span，未开启链路追踪时为nil
+func (ctx *Context) Span() *goku_trace.Span {
+	return ctx.span
+}
+
+//SetSpan 设置当前请求的span
+func (ctx *Context) SetSpan(span *goku_trace.Span) {
+	ctx.span = span
+}
+
 //RetryTargetServers 重试转发地址
 func (ctx *Context) RetryTargetServers() string {
 	return ctx.retryTargetServers
@@ -124,6 +137,7 @@ func NewContext(r *http.Request, requestID string, w http.ResponseWriter) *Conte
 		PriorityHeader:       NewPriorityHeader(),
 		StatusHandler:        NewStatusHandler(),
 		StoreHandler:         NewStoreHandler(),
+		Timing:               new(Timing),
 		RequestOrg:           requestReader,
 		ProxyRequest:         NewRequest(requestReader),
 		ProxyResponseHandler: nil,
@@ -0,0 +1,46 @@
+package common
+
+import (
+	"time"
+)
+
+//CacheConsumer 调用方，策略鉴权通过后由网关根据凭证写入，鉴权插件也可通过SetCache写入，用于access日志的$consumer及流量规则
+const CacheConsumer = "consumer"
+
+//PluginTime 插件执行耗时
+type PluginTime struct {
+	Name     string
+	Duration time.Duration
+}
+
+//UpstreamTime 单次转发的耗时
+type UpstreamTime struct {
+	Connect  time.Duration
+	Response time.Duration
+}
+
+//Timing 请求处理过程中的耗时记录
+type Timing struct {
+	plugins   []PluginTime
+	upstreams []UpstreamTime
+}
+
+//AddPluginTime 记录插件执行耗时
+func (t *Timing) AddPluginTime(name string, d time.Duration) {
+	t.plugins = append(t.plugins, PluginTime{Name: name, Duration: d})
+}
+
+//PluginTimes 按执行顺序返回插件耗时
+func (t *Timing) PluginTimes() []PluginTime {
+	return t.plugins
+}
+
+//ObserveUpstream 记录一次转发的连接耗时及响应耗时
+func (t *Timing) ObserveUpstream(connect, response time.Duration) {
+	t.upstreams = append(t.upstreams, UpstreamTime{Connect: connect, Response: response})
+}
+
+//UpstreamTimes 按转发顺序返回每次转发的耗时
+func (t *Timing) UpstreamTimes() []UpstreamTime {
+	return t.upstreams
+}
@@ -0,0 +1,24 @@
+package goku_observe
+
+//Quantile 根据累计的桶计数估算分位值，桶内按线性插值
+//values[i]为小于buckets[i]的样本数，超出最大桶时返回max
+func Quantile(q float64, buckets []float64, values []uint64, max float64, count uint64) float64 {
+	if count == 0 {
+		return 0
+	}
+	rank := q * float64(count)
+	lower := 0.0
+	var lowerCount uint64
+	for i, upper := range buckets {
+		if float64(values[i]) >= rank {
+			inBucket := values[i] - lowerCount
+			if inBucket == 0 {
+				return upper
+			}
+			return lower + (upper-lower)*(rank-float64(lowerCount))/float64(inBucket)
+		}
+		lower = upper
+		lowerCount = values[i]
+	}
+	return max
+}
@@ -9,5 +9,5 @@ import (
 
 //IHttpApplication iHttpApplication
 type IHttpApplication interface {
-	Send(ctx goku_plugin.ContextAccess,Proto string, method string, path string, querys url.Values, header http.Header, body []byte, timeout time.Duration, retry int) (*http.Response, string, []string, error)
+	Send(ctx goku_plugin.ContextAccess,Proto string, method string, path string, querys url.Values, header http.Header, body []byte, timeout time.Duration, retry *RetryPolicy) (*http.Response, string, []string, error)
 }
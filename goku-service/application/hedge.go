@@ -0,0 +1,256 @@
+package application
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	goku_plugin "github.com/eolinker/goku-plugin"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/goku-service/common"
+	goku_trace "github.com/eolinker/goku-api-gateway/goku-trace"
+	"github.com/eolinker/goku-api-gateway/node/monitor"
+)
+
+const (
+	hedgeResultSent   = "sent"
+	hedgeResultWon    = "won"
+	hedgeResultBudget = "budget"
+
+	defaultHedgePercentile = 95
+	defaultHedgeBudget     = 10
+)
+
+// hedgePolicy 对冲请求策略
+type hedgePolicy struct {
+	delay      time.Duration
+	percentile float64
+	minDelay   time.Duration
+	maxHedges  int
+	budget     int
+}
+
+func newHedgePolicy(cfg *config.HedgeConfig) *hedgePolicy {
+	if cfg == nil {
+		return nil
+	}
+	h := &hedgePolicy{
+		delay:      time.Duration(cfg.Delay) * time.Millisecond,
+		percentile: float64(cfg.Percentile) / 100,
+		minDelay:   time.Duration(cfg.MinDelay) * time.Millisecond,
+		maxHedges:  cfg.MaxHedges,
+		budget:     cfg.Budget,
+	}
+	if cfg.Percentile == 0 {
+		h.percentile = defaultHedgePercentile / 100.0
+	}
+	if h.maxHedges == 0 {
+		h.maxHedges = 1
+	}
+	if h.budget == 0 {
+		h.budget = defaultHedgeBudget
+	}
+	return h
+}
+
+// hedging 判断本次转发是否可以对冲，只对冲读请求
+func (p *RetryPolicy) hedging(method string) bool {
+	if p == nil || p.hedge == nil {
+		return false
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// wait 发起对冲请求前的等待时间，未配置时取接口转发耗时的分位值，为0时不对冲
+func (h *hedgePolicy) wait(apiID int) time.Duration {
+	if h.delay > 0 {
+		return h.delay
+	}
+	if v, ok := monitor.ProxyQuantile(apiID, h.percentile); ok {
+		if d := time.Duration(v * float64(time.Millisecond)); d > h.minDelay {
+			return d
+		}
+	}
+	return h.minDelay
+}
+
+// hedgeContext 并发转发时的请求上下文，暂存转发耗时，由发送方决定是否写回原请求
+type hedgeContext struct {
+	goku_plugin.ContextAccess
+	connect  time.Duration
+	response time.Duration
+	observed bool
+}
+
+//ObserveUpstream 暂存本次转发的耗时
+func (c *hedgeContext) ObserveUpstream(connect, response time.Duration) {
+	c.connect, c.response, c.observed = connect, response, true
+}
+
+//Span 原请求的span
+func (c *hedgeContext) Span() *goku_trace.Span {
+	return goku_trace.FromContext(c.ContextAccess)
+}
+
+func (c *hedgeContext) flush() {
+	if o, ok := c.ContextAccess.(upstreamObserver); ok && c.observed {
+		o.ObserveUpstream(c.connect, c.response)
+	}
+}
+
+// attempt 一次并发转发
+type attempt struct {
+	instance *common.Instance
+	ctx      *hedgeContext
+	response *http.Response
+	err      error
+	cancel   context.CancelFunc
+}
+
+func (a *attempt) success() bool {
+	return a.err == nil && a.response.StatusCode < 500
+}
+
+// keep 采用本次转发的响应，响应体关闭时再取消请求
+func (a *attempt) keep() {
+	if a.response == nil {
+		a.cancel()
+		return
+	}
+	a.response.Body = &cancelBody{ReadCloser: a.response.Body, cancel: a.cancel}
+}
+
+// discard 丢弃本次转发的响应并取消请求
+func (a *attempt) discard() {
+	a.cancel()
+	if a.response != nil {
+		io.Copy(ioutil.Discard, a.response.Body)
+		a.response.Body.Close()
+	}
+}
+
+// cancelBody 关闭响应体时取消对应的请求
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+//Close close
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+type sendFunc func(ctx goku_plugin.ContextAccess, cctx context.Context, instance *common.Instance) (*http.Response, error)
+
+// hedge 发送一次可对冲的转发：超过等待时间未响应时向其他实例再发请求，采用最先成功的响应并取消其余请求，
+// 都未成功时采用最后完成的转发；返回采用的转发、按发送顺序的全部实例及最后一次选择的实例下标
+func (app *Application) hedge(ctx goku_plugin.ContextAccess, h *hedgePolicy, send sendFunc, first *common.Instance, lastIndex int, filters []common.Filter) (*attempt, []*common.Instance, int) {
+	results := make(chan *attempt, h.maxHedges+1)
+	launch := func(instance *common.Instance) *attempt {
+		cctx, cancel := context.WithCancel(context.Background())
+		a := &attempt{instance: instance, ctx: &hedgeContext{ContextAccess: ctx}, cancel: cancel}
+		go func() {
+			a.response, a.err = send(a.ctx, cctx, instance)
+			results <- a
+		}()
+		return a
+	}
+
+	attempts := []*attempt{launch(first)}
+	instances := []*common.Instance{first}
+	pending := 1
+	var hedges int64
+	defer func() {
+		app.budget.releaseHedges(hedges)
+	}()
+
+	var timer <-chan time.Time
+	delay := h.wait(ctx.ApiID())
+	if delay > 0 {
+		t := time.NewTimer(delay)
+		defer t.Stop()
+		timer = t.C
+	}
+
+	var last *attempt
+	for pending > 0 {
+		select {
+		case a := <-results:
+			pending--
+			a.ctx.flush()
+			if a.success() {
+				if last != nil {
+					last.discard()
+				}
+				for _, other := range attempts {
+					if other != a {
+						other.cancel()
+					}
+				}
+				go discardAttempts(results, pending)
+				if a != attempts[0] {
+					app.budget.count(monitor.HedgeCounter, ctx, hedgeResultWon)
+				}
+				a.keep()
+				return a, instances, lastIndex
+			}
+			if a.err != nil && app.healthCheckHandler.IsNeedCheck() {
+				app.healthCheckHandler.Check(a.instance)
+			}
+			if last != nil {
+				last.discard()
+			}
+			last = a
+		case <-timer:
+			timer = nil
+			if !app.budget.acquireHedge(h.budget) {
+				app.budget.count(monitor.HedgeCounter, ctx, hedgeResultBudget)
+				continue
+			}
+			instance, index, has := app.service.Select(lastIndex, filters)
+			if !has || containsInstance(instances, instance) {
+				app.budget.releaseHedges(1)
+				continue
+			}
+			hedges++
+			lastIndex = index
+			attempts = append(attempts, launch(instance))
+			instances = append(instances, instance)
+			pending++
+			app.budget.count(monitor.HedgeCounter, ctx, hedgeResultSent)
+			if len(attempts) <= h.maxHedges {
+				t := time.NewTimer(delay)
+				defer t.Stop()
+				timer = t.C
+			}
+		}
+	}
+	last.keep()
+	return last, instances, lastIndex
+}
+
+// discardAttempts 丢弃已被取消的转发的响应
+func discardAttempts(results <-chan *attempt, pending int) {
+	for ; pending > 0; pending-- {
+		a := <-results
+		a.discard()
+	}
+}
+
+func containsInstance(instances []*common.Instance, instance *common.Instance) bool {
+	for _, i := range instances {
+		if i == instance {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package application
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	goku_plugin "github.com/eolinker/goku-plugin"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/diting"
+	goku_labels "github.com/eolinker/goku-api-gateway/goku-labels"
+	node_common "github.com/eolinker/goku-api-gateway/goku-node/common"
+	"github.com/eolinker/goku-api-gateway/goku-service/common"
+	"github.com/eolinker/goku-api-gateway/node/monitor"
+)
+
+func TestHedgePolicy(t *testing.T) {
+	p := NewRetryPolicy(0, nil, &config.HedgeConfig{MinDelay: 30})
+	if p == nil || p.hedge == nil {
+		t.Fatal("hedge without retries should create policy")
+	}
+	if !p.hedging(http.MethodGet) || p.hedging(http.MethodPost) {
+		t.Error("only read requests should be hedged")
+	}
+	if p.hedge.maxHedges != 1 || p.hedge.budget != defaultHedgeBudget {
+		t.Errorf("defaults: maxHedges %d budget %d", p.hedge.maxHedges, p.hedge.budget)
+	}
+	if d := p.hedge.wait(1); d != 30*time.Millisecond {
+		t.Errorf("delay without latency samples: got %s", d)
+	}
+
+	b := &RetryBudget{name: "test"}
+	b.begin()
+	if !b.acquireHedge(10) {
+		t.Error("budget should allow at least one hedge")
+	}
+	if b.acquireHedge(10) {
+		t.Error("hedges over budget should be rejected")
+	}
+}
+
+// statusObserver 记录转发统计中的状态码
+type statusObserver struct {
+	locker   sync.Mutex
+	statuses []string
+}
+
+func (o *statusObserver) Observe(value float64, labels diting.Labels) {
+	o.locker.Lock()
+	o.statuses = append(o.statuses, labels[goku_labels.Status])
+	o.locker.Unlock()
+}
+
+type noCheck struct{}
+
+func (noCheck) Open(conf *config.HealthCheckConfig) {}
+func (noCheck) Check(instance *common.Instance)     {}
+func (noCheck) IsNeedCheck() bool                   { return false }
+func (noCheck) Close() []*common.Instance           { return nil }
+
+func TestHedge(t *testing.T) {
+	observer := new(statusObserver)
+	proxyMonitor := monitor.ProxyMonitor
+	monitor.ProxyMonitor = observer
+	defer func() {
+		monitor.ProxyMonitor = proxyMonitor
+	}()
+
+	slowCancelled := make(chan bool, 1)
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			slowCancelled <- true
+		case <-time.After(2 * time.Second):
+			slowCancelled <- false
+			w.Write([]byte("slow"))
+		}
+	}))
+	defer slow.Close()
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("fast"))
+	}))
+	defer fast.Close()
+
+	factory := common.NewInstanceFactory()
+	instance := func(server *httptest.Server) *common.Instance {
+		host, port, _ := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+		p, _ := strconv.Atoi(port)
+		return factory.General(host, p, 1, nil, nil)
+	}
+	slowInstance, fastInstance := instance(slow), instance(fast)
+	budget := &RetryBudget{name: "test"}
+	app := NewApplication(common.NewService("test", []*common.Instance{slowInstance, fastInstance}), noCheck{})
+	app.SetRetryBudget(budget)
+
+	slowDone := make(chan error, 1)
+	send := func(ctx goku_plugin.ContextAccess, cctx context.Context, instance *common.Instance) (*http.Response, error) {
+		response, err := request(cctx, ctx, nil, http.MethodGet, "http://"+address(instance), nil, nil, nil, 0)
+		if instance == slowInstance {
+			slowDone <- err
+		}
+		return response, err
+	}
+
+	ctx := node_common.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), "1", httptest.NewRecorder())
+	budget.begin()
+	a, instances, _ := app.hedge(ctx, newHedgePolicy(&config.HedgeConfig{Delay: 20}), send, slowInstance, 1, nil)
+	if a.err != nil || a.instance != fastInstance || len(instances) != 2 {
+		t.Fatalf("fast instance should win, got %v %v %d", a.instance, a.err, len(instances))
+	}
+	body, _ := ioutil.ReadAll(a.response.Body)
+	a.response.Body.Close()
+	if string(body) != "fast" {
+		t.Errorf("body: got %s", body)
+	}
+
+	if !<-slowCancelled {
+		t.Error("slow request should be cancelled")
+	}
+	if err := <-slowDone; err == nil {
+		t.Error("slow request should fail after cancel")
+	}
+	if hedges := atomic.LoadInt64(&budget.hedges); hedges != 0 {
+		t.Errorf("hedges should be released, got %d", hedges)
+	}
+	observer.locker.Lock()
+	defer observer.locker.Unlock()
+	for _, status := range observer.statuses {
+		if status != "200" {
+			t.Errorf("cancelled request should not be observed, got status %s", status)
+		}
+	}
+}
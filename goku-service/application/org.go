@@ -1,6 +1,7 @@
 package application
 
 import (
+	"context"
 	"fmt"
 	goku_plugin "github.com/eolinker/goku-plugin"
 	"net/http"
@@ -13,38 +14,41 @@ import (
 //Org org
 type Org struct {
 	server string
+	budget *RetryBudget
 }
 
-//Send 请求发送，忽略重试
-func (app *Org) Send(ctx goku_plugin.ContextAccess,proto string, method string, path string, querys url.Values, header http.Header, body []byte, timeout time.Duration, retry int) (*http.Response, string, []string, error) {
+//Send 请求发送，按重试策略重试同一地址
+func (app *Org) Send(ctx goku_plugin.ContextAccess,proto string, method string, path string, querys url.Values, header http.Header, body []byte, timeout time.Duration, retry *RetryPolicy) (*http.Response, string, []string, error) {
 
 	var response *http.Response
 	var err error
 
 	FinalTargetServer := ""
-	RetryTargetServers := make([]string, 0, retry+1)
+	RetryTargetServers := make([]string, 0, retry.Retries()+1)
 
 	path = utils.TrimPrefixAll(path, "/")
-
-	for doTrice := retry + 1; doTrice > 0; doTrice-- {
+	var retries int64
+	app.budget.begin()
+	defer func() {
+		app.budget.end(retries)
+	}()
+	for try := 1; ; try++ {
 
 		u := fmt.Sprintf("%s://%s/%s", proto, app.server, path)
 		FinalTargetServer = app.server
 		RetryTargetServers = append(RetryTargetServers, FinalTargetServer)
-		response, err = request(ctx,method, u, querys, header, body, timeout)
-		if err != nil {
-			continue
-		} else {
+		response, err = request(context.Background(), ctx, nil, method, u, querys, header, body, retry.timeout(timeout))
+		if !retry.next(ctx, app.budget, try, method, response, err) {
 			return response, FinalTargetServer, RetryTargetServers, err
 		}
+		retries++
 	}
-
-	return response, FinalTargetServer, RetryTargetServers, err
 }
 
 //NewOrg 创建新的IHttpApplication
 func NewOrg(server string) IHttpApplication {
 	return &Org{
 		server: server,
+		budget: GetRetryBudget(server),
 	}
 }
@@ -1,6 +1,7 @@
 package application
 
 import (
+	"context"
 	"fmt"
 	goku_plugin "github.com/eolinker/goku-plugin"
 	"net/http"
@@ -8,7 +9,7 @@ import (
 	"time"
 )
 
-func request(ctx goku_plugin.ContextAccess,method string, backendDomain string, query url.Values, header http.Header, body []byte, timeout time.Duration) (*http.Response, error) {
+func request(cctx context.Context, ctx goku_plugin.ContextAccess, transport http.RoundTripper, method string, backendDomain string, query url.Values, header http.Header, body []byte, timeout time.Duration) (*http.Response, error) {
 
 	if backendDomain == "" {
 		return nil, fmt.Errorf("invaild url")
@@ -24,6 +25,10 @@ func request(ctx goku_plugin.ContextAccess,method string, backendDomain string,
 
 		return nil, err
 	}
+	if transport != nil {
+		req.client.Transport = transport
+	}
+	req.context = cctx
 
 	queryDest := u.Query()
 	if query != nil {
@@ -2,11 +2,13 @@ package application
 
 import (
 	"bytes"
-	"crypto/tls"
+	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
 	"strconv"
 
@@ -14,6 +16,7 @@ import (
 
 	"github.com/eolinker/goku-api-gateway/diting"
 	goku_labels "github.com/eolinker/goku-api-gateway/goku-labels"
+	goku_trace "github.com/eolinker/goku-api-gateway/goku-trace"
 	"github.com/eolinker/goku-api-gateway/node/monitor"
 
 	// "fmt"
@@ -30,6 +33,11 @@ func SetSkipCertificate(skip int) {
 	skipCertificate = skip
 }
 
+// upstreamObserver 记录转发耗时，用于access日志
+type upstreamObserver interface {
+	ObserveUpstream(connect, response time.Duration)
+}
+
 //Request request
 type Request struct {
 	client  *http.Client
@@ -41,6 +49,8 @@ type Request struct {
 	queryParams map[string][]string
 
 	timeout time.Duration
+	//context 为空时使用默认的context，对冲请求以此取消未采用的请求
+	context context.Context
 }
 
 //NewRequest 创建新请求
@@ -67,14 +77,8 @@ func newRequest(method string, URL *url.URL) (*Request, error) {
 		queryParams[key] = values
 	}
 	urlPath = URL.Scheme + "://" + URL.Host + URL.Path
-	tp := http.DefaultTransport
-	if skipCertificate == 1 {
-		tp = &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		}
-	}
 	r := &Request{
-		client:      &http.Client{Transport: tp},
+		client:      &http.Client{Transport: defaultTransport()},
 		method:      method,
 		URL:         urlPath,
 		headers:     make(map[string][]string),
@@ -129,8 +133,27 @@ func (r *Request) Send(ctx goku_plugin.ContextAccess) (*http.Response, error) {
 	}
 	status := 0
 	start := time.Now()
+	var getConn time.Time
+	var connect time.Duration
+	span := goku_trace.StartSpan(goku_trace.FromContext(ctx), fmt.Sprint(req.Method, " ", req.URL.Path), goku_trace.SpanKindClient)
 	defer func() {
+		span.SetAttribute("http.method", req.Method)
+		span.SetAttribute("http.url", req.URL.String())
+		span.SetAttribute("net.peer.name", req.Host)
+		span.SetAttribute("http.status_code", status)
+		if status >= 500 {
+			span.SetStatus(goku_trace.StatusError, strconv.Itoa(status))
+		}
+		span.End()
+
+		// 对冲时被取消的转发不计入转发耗时
+		if status == 499 && r.context != nil && r.context.Err() != nil {
+			return
+		}
 		delay := time.Since(start)
+		if o, ok := ctx.(upstreamObserver); ok {
+			o.ObserveUpstream(connect, delay)
+		}
 		labels := make(diting.Labels)
 
 		labels[goku_labels.Proto] = req.Proto
@@ -144,13 +167,30 @@ func (r *Request) Send(ctx goku_plugin.ContextAccess) (*http.Response, error) {
 	}()
 	req.Header.Set("Accept-Encoding", "gzip")
 	req.Header = parseHeaders(r.headers)
+	goku_trace.InjectHeader(span, req.Header)
 
 	r.client.Timeout = r.timeout
+	base := req.Context()
+	if r.context != nil {
+		base = r.context
+	}
+	req = req.WithContext(httptrace.WithClientTrace(base, &httptrace.ClientTrace{
+		GetConn: func(hostPort string) {
+			getConn = time.Now()
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			if !info.Reused {
+				connect = time.Since(getConn)
+			}
+		},
+	}))
 
 	httpResponse, err := r.client.Do(req)
 
 	if err != nil {
-		if netErr, ok := err.(net.Error); ok {
+		if errors.Is(err, context.Canceled) {
+			status = 499
+		} else if netErr, ok := err.(net.Error); ok {
 			if netErr.Timeout() {
 				status = 504
 			} else {
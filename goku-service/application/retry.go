@@ -0,0 +1,270 @@
+package application
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	goku_plugin "github.com/eolinker/goku-plugin"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/diting"
+	goku_labels "github.com/eolinker/goku-api-gateway/goku-labels"
+	"github.com/eolinker/goku-api-gateway/node/monitor"
+)
+
+const (
+	retryResultStatus = "status"
+	retryResultBudget = "budget"
+	retryErrorOther   = "other"
+)
+
+var (
+	budgetLocker sync.Mutex
+	budgets      = make(map[string]*RetryBudget)
+)
+
+//RetryPolicy 转发的重试及对冲策略，为nil时不重试
+type RetryPolicy struct {
+	retries       int
+	statuses      map[int]bool
+	errors        map[string]bool
+	nonIdempotent bool
+	backoff       time.Duration
+	maxBackoff    time.Duration
+	tryTimeOut    time.Duration
+	budget        int
+	minRetries    int
+	hedge         *hedgePolicy
+}
+
+//NewRetryPolicy 创建重试策略，cfg为空时沿用旧行为：任意请求在转发出错时重试retries次；hedge不为空时启用对冲请求
+func NewRetryPolicy(retries int, cfg *config.RetryConfig, hedge *config.HedgeConfig) *RetryPolicy {
+	if cfg == nil {
+		if retries <= 0 && hedge == nil {
+			return nil
+		}
+		return &RetryPolicy{retries: retries, nonIdempotent: true, hedge: newHedgePolicy(hedge)}
+	}
+	p := &RetryPolicy{
+		retries:       retries,
+		nonIdempotent: cfg.NonIdempotent,
+		backoff:       time.Duration(cfg.Backoff) * time.Millisecond,
+		maxBackoff:    time.Duration(cfg.MaxBackoff) * time.Millisecond,
+		tryTimeOut:    time.Duration(cfg.TryTimeOut) * time.Millisecond,
+		budget:        cfg.Budget,
+		minRetries:    cfg.MinRetries,
+		hedge:         newHedgePolicy(hedge),
+	}
+	if cfg.Retries > 0 {
+		p.retries = cfg.Retries
+	}
+	if len(cfg.Statuses) > 0 {
+		p.statuses = make(map[int]bool)
+		for _, status := range cfg.Statuses {
+			p.statuses[status] = true
+		}
+	}
+	if len(cfg.Errors) > 0 {
+		p.errors = make(map[string]bool)
+		for _, e := range cfg.Errors {
+			p.errors[e] = true
+		}
+	}
+	return p
+}
+
+//Retries 最大重试次数
+func (p *RetryPolicy) Retries() int {
+	if p == nil {
+		return 0
+	}
+	return p.retries
+}
+
+// timeout 单次尝试的超时时间，不超过链路的超时时间
+func (p *RetryPolicy) timeout(timeout time.Duration) time.Duration {
+	if p == nil || p.tryTimeOut <= 0 {
+		return timeout
+	}
+	if timeout > 0 && timeout < p.tryTimeOut {
+		return timeout
+	}
+	return p.tryTimeOut
+}
+
+// retryable 判断本次尝试的结果是否需要重试，返回重试原因
+func (p *RetryPolicy) retryable(method string, response *http.Response, err error) (string, bool) {
+	if !p.nonIdempotent && !idempotent(method) {
+		return "", false
+	}
+	if err != nil {
+		class := errorClass(err)
+		return class, p.errors == nil || p.errors[class]
+	}
+	if response != nil && p.statuses[response.StatusCode] {
+		return retryResultStatus, true
+	}
+	return "", false
+}
+
+// next 判断是否进行第try次重试，需要重试时记录重试、丢弃本次响应并等待退避时间
+func (p *RetryPolicy) next(ctx goku_plugin.ContextAccess, budget *RetryBudget, try int, method string, response *http.Response, err error) bool {
+	if try > p.Retries() {
+		return false
+	}
+	reason, ok := p.retryable(method, response, err)
+	if !ok {
+		return false
+	}
+	if !budget.acquire(p.budget, p.minRetries) {
+		budget.count(monitor.RetryCounter, ctx, retryResultBudget)
+		return false
+	}
+	budget.count(monitor.RetryCounter, ctx, reason)
+	if response != nil {
+		io.Copy(ioutil.Discard, response.Body)
+		response.Body.Close()
+	}
+	if d := p.wait(try); d > 0 {
+		time.Sleep(d)
+	}
+	return true
+}
+
+// wait 第try次重试前的等待时间，指数退避并在[d/2,d]内随机抖动
+func (p *RetryPolicy) wait(try int) time.Duration {
+	if p.backoff <= 0 {
+		return 0
+	}
+	d := p.backoff
+	for i := 1; i < try && (p.maxBackoff <= 0 || d < p.maxBackoff); i++ {
+		d *= 2
+	}
+	if p.maxBackoff > 0 && d > p.maxBackoff {
+		d = p.maxBackoff
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func errorClass(err error) string {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return config.RetryErrorTimeout
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return config.RetryErrorReset
+	}
+	var opErr *net.OpError
+	if errors.Is(err, syscall.ECONNREFUSED) || (errors.As(err, &opErr) && opErr.Op == "dial") {
+		return config.RetryErrorConnect
+	}
+	return retryErrorOther
+}
+
+//RetryBudget 负载的重试及对冲预算，统计负载进行中的请求数、重试数及对冲请求数，由使用该负载的全部接口共享
+type RetryBudget struct {
+	name     string
+	requests int64
+	retries  int64
+	hedges   int64
+}
+
+//GetRetryBudget 获取负载的重试预算
+func GetRetryBudget(name string) *RetryBudget {
+	budgetLocker.Lock()
+	defer budgetLocker.Unlock()
+	b, has := budgets[name]
+	if !has {
+		b = &RetryBudget{name: name}
+		budgets[name] = b
+	}
+	return b
+}
+
+func (b *RetryBudget) begin() {
+	if b != nil {
+		atomic.AddInt64(&b.requests, 1)
+	}
+}
+
+func (b *RetryBudget) end(retries int64) {
+	if b != nil {
+		atomic.AddInt64(&b.requests, -1)
+		atomic.AddInt64(&b.retries, -retries)
+	}
+}
+
+// acquire 申请一次重试，进行中的重试数超过预算时失败，percent为0时不限制
+func (b *RetryBudget) acquire(percent, min int) bool {
+	if b == nil {
+		return true
+	}
+	retries := atomic.AddInt64(&b.retries, 1)
+	if percent <= 0 {
+		return true
+	}
+	limit := atomic.LoadInt64(&b.requests) * int64(percent) / 100
+	if limit < int64(min) {
+		limit = int64(min)
+	}
+	if retries > limit {
+		atomic.AddInt64(&b.retries, -1)
+		return false
+	}
+	return true
+}
+
+// acquireHedge 申请一次对冲请求，进行中的对冲请求超过预算时失败，预算至少允许一个对冲请求
+func (b *RetryBudget) acquireHedge(percent int) bool {
+	if b == nil {
+		return true
+	}
+	hedges := atomic.AddInt64(&b.hedges, 1)
+	limit := atomic.LoadInt64(&b.requests) * int64(percent) / 100
+	if limit < 1 {
+		limit = 1
+	}
+	if hedges > limit {
+		atomic.AddInt64(&b.hedges, -1)
+		return false
+	}
+	return true
+}
+
+func (b *RetryBudget) releaseHedges(hedges int64) {
+	if b != nil {
+		atomic.AddInt64(&b.hedges, -hedges)
+	}
+}
+
+func (b *RetryBudget) count(counter diting.Counter, ctx goku_plugin.ContextAccess, result string) {
+	if counter == nil {
+		return
+	}
+	labels := make(diting.Labels)
+	labels[goku_labels.API] = strconv.Itoa(ctx.ApiID())
+	labels[goku_labels.Strategy] = ctx.StrategyId()
+	labels[goku_labels.Balance] = ""
+	if b != nil {
+		labels[goku_labels.Balance] = b.name
+	}
+	labels[goku_labels.Result] = result
+	counter.Add(1, labels)
+}
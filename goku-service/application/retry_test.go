@@ -0,0 +1,43 @@
+package application
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	p := NewRetryPolicy(1, &config.RetryConfig{Statuses: []int{503}, Errors: []string{config.RetryErrorConnect}}, nil)
+	if p.Retries() != 1 {
+		t.Errorf("retries: got %d", p.Retries())
+	}
+	if _, ok := p.retryable(http.MethodGet, &http.Response{StatusCode: 503}, nil); !ok {
+		t.Error("503 should be retried")
+	}
+	if _, ok := p.retryable(http.MethodPost, &http.Response{StatusCode: 503}, nil); ok {
+		t.Error("non-idempotent request should not be retried by default")
+	}
+	if _, ok := p.retryable(http.MethodGet, &http.Response{StatusCode: 500}, nil); ok {
+		t.Error("500 should not be retried")
+	}
+	if NewRetryPolicy(0, nil, nil) != nil {
+		t.Error("no retry without retries and policy")
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	b := &RetryBudget{name: "test"}
+	for i := 0; i < 10; i++ {
+		b.begin()
+	}
+	if !b.acquire(20, 0) || !b.acquire(20, 0) {
+		t.Error("retries within budget should be allowed")
+	}
+	if b.acquire(20, 0) {
+		t.Error("retries over budget should be rejected")
+	}
+	if !b.acquire(20, 3) {
+		t.Error("retries under minRetries should be allowed")
+	}
+}
@@ -1,6 +1,7 @@
 package application
 
 import (
+	"context"
 	"fmt"
 	goku_plugin "github.com/eolinker/goku-plugin"
 	"net/http"
@@ -16,6 +17,9 @@ import (
 type Application struct {
 	service            *common.Service
 	healthCheckHandler health.CheckHandler
+	subset             *common.Subset
+	transport          http.RoundTripper
+	budget             *RetryBudget
 }
 
 //NewApplication 创建Application
@@ -27,42 +31,79 @@ func NewApplication(service *common.Service, healthCheckHandler health.CheckHand
 
 }
 
+//SetSubset 设置实例子集
+func (app *Application) SetSubset(subset *common.Subset) {
+	app.subset = subset
+}
+
+//SetTransport 设置负载的连接池，为空时使用默认连接池
+func (app *Application) SetTransport(transport http.RoundTripper) {
+	app.transport = transport
+}
+
+//SetRetryBudget 设置负载的重试预算
+func (app *Application) SetRetryBudget(budget *RetryBudget) {
+	app.budget = budget
+}
+
 //Send send
-func (app *Application) Send(ctx goku_plugin.ContextAccess,proto string, method string, path string, querys url.Values, header http.Header, body []byte, timeout time.Duration, retry int) (*http.Response, string, []string, error) {
+func (app *Application) Send(ctx goku_plugin.ContextAccess,proto string, method string, path string, querys url.Values, header http.Header, body []byte, timeout time.Duration, retry *RetryPolicy) (*http.Response, string, []string, error) {
 
 	var response *http.Response
 	var err error
 
 	FinalTargetServer := ""
-	RetryTargetServers := make([]string, 0, retry+1)
+	RetryTargetServers := make([]string, 0, retry.Retries()+1)
 
 	lastIndex := -1
 	path = utils.TrimPrefixAll(path, "/")
-	for doTrice := retry + 1; doTrice > 0; doTrice-- {
-		instance, index, has := app.service.Next(lastIndex)
+	filters := app.subset.Filters(header)
+	send := func(ctx goku_plugin.ContextAccess, cctx context.Context, instance *common.Instance) (*http.Response, error) {
+		u := fmt.Sprintf("%s://%s/%s", proto, address(instance), path)
+		response, err := request(cctx, ctx, app.transport, method, u, querys, header, body, retry.timeout(timeout))
+		if err == nil {
+			instance.ResetFailure()
+		}
+		return response, err
+	}
+	var retries int64
+	app.budget.begin()
+	defer func() {
+		app.budget.end(retries)
+	}()
+	for try := 1; ; try++ {
+		instance, index, has := app.service.Select(lastIndex, filters)
 		lastIndex = index
 		if !has {
 			return nil, FinalTargetServer, RetryTargetServers, fmt.Errorf("not found instance for app:%s", app.service.Name)
 		}
 
-		FinalTargetServer = instance.IP
-		if instance.Port != 0 {
-			FinalTargetServer = fmt.Sprintf("%s:%d", instance.IP, instance.Port)
-		}
-
+		FinalTargetServer = address(instance)
 		RetryTargetServers = append(RetryTargetServers, FinalTargetServer)
-		u := fmt.Sprintf("%s://%s/%s", proto, FinalTargetServer, path)
-		response, err = request(ctx,method, u, querys, header, body, timeout)
-
-		if err != nil {
-			if app.healthCheckHandler.IsNeedCheck() {
-				app.healthCheckHandler.Check(instance)
+		if retry.hedging(method) {
+			a, instances, index := app.hedge(ctx, retry.hedge, send, instance, lastIndex, filters)
+			lastIndex = index
+			for _, i := range instances[1:] {
+				RetryTargetServers = append(RetryTargetServers, address(i))
 			}
+			FinalTargetServer = address(a.instance)
+			response, err = a.response, a.err
 		} else {
+			response, err = send(ctx, context.Background(), instance)
+			if err != nil && app.healthCheckHandler.IsNeedCheck() {
+				app.healthCheckHandler.Check(instance)
+			}
+		}
+		if !retry.next(ctx, app.budget, try, method, response, err) {
 			return response, FinalTargetServer, RetryTargetServers, err
 		}
-
+		retries++
 	}
+}
 
-	return response, FinalTargetServer, RetryTargetServers, err
+func address(instance *common.Instance) string {
+	if instance.Port != 0 {
+		return fmt.Sprintf("%s:%d", instance.IP, instance.Port)
+	}
+	return instance.IP
 }
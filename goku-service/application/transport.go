@@ -0,0 +1,125 @@
+package application
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+)
+
+var (
+	// defaultTransports 未配置连接的负载共用，下标为是否跳过证书校验
+	defaultTransports = [2]*http.Transport{newTransport(nil, false), newTransport(nil, true)}
+
+	transportLocker sync.Mutex
+	transports      = make(map[string]*balanceTransport)
+)
+
+// balanceTransport 负载的连接池，key为生成时的配置，配置不变时复用
+type balanceTransport struct {
+	key       string
+	transport *http.Transport
+}
+
+func defaultTransport() *http.Transport {
+	if skipCertificate == 1 {
+		return defaultTransports[1]
+	}
+	return defaultTransports[0]
+}
+
+func newTransport(cfg *config.TransportConfig, skip bool) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	tp := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		MaxIdleConns:          1000,
+		MaxIdleConnsPerHost:   100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       &tls.Config{InsecureSkipVerify: skip},
+	}
+	if cfg == nil {
+		return tp
+	}
+	if cfg.DialTimeout > 0 {
+		dialer.Timeout = time.Duration(cfg.DialTimeout) * time.Millisecond
+	}
+	if cfg.MaxIdleConns > 0 {
+		tp.MaxIdleConns = cfg.MaxIdleConns
+	}
+	if cfg.MaxIdleConnsPerHost > 0 {
+		tp.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	}
+	tp.MaxConnsPerHost = cfg.MaxConnsPerHost
+	if cfg.IdleConnTimeout > 0 {
+		tp.IdleConnTimeout = time.Duration(cfg.IdleConnTimeout) * time.Millisecond
+	}
+	if cfg.TLSHandshakeTimeout > 0 {
+		tp.TLSHandshakeTimeout = time.Duration(cfg.TLSHandshakeTimeout) * time.Millisecond
+	}
+	tp.ResponseHeaderTimeout = time.Duration(cfg.ResponseHeaderTimeout) * time.Millisecond
+	tp.ForceAttemptHTTP2 = cfg.HTTP2
+	if tlsConfig, err := cfg.TLSConfig(skip); err == nil {
+		tp.TLSClientConfig = tlsConfig
+	} else {
+		log.Warn("transport tls config error:", err)
+	}
+	return tp
+}
+
+//BalanceTransport 获取负载的连接池，配置或全局跳过证书校验变化时重建并关闭原连接池的空闲连接
+func BalanceTransport(name string, cfg *config.TransportConfig) http.RoundTripper {
+	if cfg == nil {
+		removeTransport(name)
+		return defaultTransport()
+	}
+	data, _ := json.Marshal(cfg)
+	key := string(data)
+	skip := skipCertificate == 1
+	if skip {
+		key = "skip:" + key
+	}
+
+	transportLocker.Lock()
+	defer transportLocker.Unlock()
+	if t, has := transports[name]; has {
+		if t.key == key {
+			return t.transport
+		}
+		t.transport.CloseIdleConnections()
+	}
+	t := &balanceTransport{key: key, transport: newTransport(cfg, skip)}
+	transports[name] = t
+	return t.transport
+}
+
+//ResetTransports 关闭已删除负载的连接池
+func ResetTransports(balances map[string]*config.BalanceConfig) {
+	transportLocker.Lock()
+	defer transportLocker.Unlock()
+	for name, t := range transports {
+		if b, has := balances[name]; !has || b.Transport == nil {
+			t.transport.CloseIdleConnections()
+			delete(transports, name)
+		}
+	}
+}
+
+func removeTransport(name string) {
+	transportLocker.Lock()
+	if t, has := transports[name]; has {
+		t.transport.CloseIdleConnections()
+		delete(transports, name)
+	}
+	transportLocker.Unlock()
+}
@@ -0,0 +1,24 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+func TestBalanceTransport(t *testing.T) {
+	cfg := &config.TransportConfig{MaxIdleConnsPerHost: 10, DialTimeout: 500}
+	first := BalanceTransport("demo", cfg)
+	if BalanceTransport("demo", &config.TransportConfig{MaxIdleConnsPerHost: 10, DialTimeout: 500}) != first {
+		t.Error("unchanged config should reuse transport")
+	}
+	if BalanceTransport("demo", &config.TransportConfig{MaxIdleConnsPerHost: 20}) == first {
+		t.Error("changed config should rebuild transport")
+	}
+	if BalanceTransport("demo", nil) != defaultTransport() {
+		t.Error("nil config should use default transport")
+	}
+	if _, has := transports["demo"]; has {
+		t.Error("transport of balance without config should be removed")
+	}
+}
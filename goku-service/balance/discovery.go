@@ -3,12 +3,14 @@ package balance
 import (
 	"github.com/eolinker/goku-api-gateway/config"
 	"github.com/eolinker/goku-api-gateway/goku-service/application"
+	"github.com/eolinker/goku-api-gateway/goku-service/common"
 	"github.com/eolinker/goku-api-gateway/goku-service/discovery"
 )
 
 //ResetBalances 重置负载列表
 func ResetBalances(balances map[string]*config.BalanceConfig) {
 	manager.set(balances)
+	application.ResetTransports(balances)
 }
 
 //GetByName 通过名称获取负载
@@ -23,7 +25,11 @@ func GetByName(name string) (application.IHttpApplication, bool) {
 
 		service, handler, yes := sources.GetApp(b.Config)
 		if yes {
-			return application.NewApplication(service, handler), true
+			app := application.NewApplication(service, handler)
+			app.SetSubset(common.NewSubset(b.Subset))
+			app.SetTransport(application.BalanceTransport(b.Name, b.Transport))
+			app.SetRetryBudget(application.GetRetryBudget(b.Name))
+			return app, true
 		}
 	}
 
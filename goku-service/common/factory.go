@@ -2,6 +2,8 @@ package common
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 )
 
@@ -19,12 +21,12 @@ func NewInstanceFactory() *InstanceFactory {
 	}
 }
 
-//General general
-func (m *InstanceFactory) General(ip string, port int, weight int) *Instance {
+//General general，元数据及标签不同的实例分别缓存
+func (m *InstanceFactory) General(ip string, port int, weight int, metadata map[string]string, tags []string) *Instance {
 	if weight < 1 {
 		weight = 1
 	}
-	key := fmt.Sprintf("%s:%d-%d", ip, port, weight)
+	key := fmt.Sprintf("%s:%d-%d%s", ip, port, weight, metadataKey(metadata, tags))
 	m.locker.RLock()
 	i, h := m.instances[key]
 	m.locker.RUnlock()
@@ -45,6 +47,8 @@ func (m *InstanceFactory) General(ip string, port int, weight int) *Instance {
 		IP:         ip,
 		Port:       port,
 		Weight:     weight,
+		Metadata:   metadata,
+		Tags:       tags,
 		Status:     InstanceRun,
 		locker:     sync.RWMutex{},
 	}
@@ -53,3 +57,17 @@ func (m *InstanceFactory) General(ip string, port int, weight int) *Instance {
 	return i
 
 }
+
+func metadataKey(metadata map[string]string, tags []string) string {
+	if len(metadata) == 0 && len(tags) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(metadata))
+	for k, v := range metadata {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	sorted := append([]string(nil), tags...)
+	sort.Strings(sorted)
+	return "|" + strings.Join(pairs, ",") + "|" + strings.Join(sorted, ",")
+}
@@ -1,6 +1,9 @@
 package common
 
-import "sync"
+import (
+	"sync"
+	"sync/atomic"
+)
 
 //Instance instance
 type Instance struct {
@@ -8,8 +11,14 @@ type Instance struct {
 	IP         string
 	Port       int
 	Weight     int
-	Status     InstanceStatus
-	locker     sync.RWMutex
+	//Metadata 服务发现或静态配置中的元数据，如version、zone
+	Metadata map[string]string
+	Tags     []string
+	//Priority 优先级，数值小的优先，同一服务中只在更高优先级的实例都不可用时才选择，如DNS SRV记录的priority
+	Priority int
+	Status   InstanceStatus
+	locker   sync.RWMutex
+	failures int32
 }
 
 //PInstances PInstances
@@ -58,3 +67,15 @@ func (i *Instance) ChangeStatus(org, dest InstanceStatus) bool {
 	return b
 
 }
+
+//AddFailure 记录一次转发出错，返回连续出错的次数
+func (i *Instance) AddFailure() int {
+	return int(atomic.AddInt32(&i.failures, 1))
+}
+
+//ResetFailure 清除连续出错的次数
+func (i *Instance) ResetFailure() {
+	if atomic.LoadInt32(&i.failures) != 0 {
+		atomic.StoreInt32(&i.failures, 0)
+	}
+}
@@ -41,6 +41,10 @@ func (s *Service) SetInstances(instances []*Instance) {
 
 //Weighting weighting
 func (s *Service) Weighting() (*Instance, int, bool) {
+	return s.weighting(nil)
+}
+
+func (s *Service) weighting(filter Filter) (*Instance, int, bool) {
 	s.locker.RLock()
 	instances := s.instances
 	s.locker.RUnlock()
@@ -48,9 +52,13 @@ func (s *Service) Weighting() (*Instance, int, bool) {
 	if len(instances) == 0 {
 		return nil, 0, false
 	}
+	priority, has := topPriority(instances, filter)
+	if !has {
+		return nil, 0, false
+	}
 	weightSum := 0
 	for _, ins := range instances {
-		if ins.CheckStatus(InstanceRun) {
+		if ins.Priority == priority && ins.CheckStatus(InstanceRun) && filter.match(ins) {
 			weightSum += ins.Weight
 		}
 	}
@@ -59,7 +67,7 @@ func (s *Service) Weighting() (*Instance, int, bool) {
 	}
 	weightValue := rand.Intn(weightSum) + 1
 	for i, ins := range instances {
-		if ins.CheckStatus(InstanceRun) {
+		if ins.Priority == priority && ins.CheckStatus(InstanceRun) && filter.match(ins) {
 			weightValue = weightValue - ins.Weight
 			if weightValue <= 0 {
 				return ins, i, true
@@ -72,8 +80,12 @@ func (s *Service) Weighting() (*Instance, int, bool) {
 
 //Next next
 func (s *Service) Next(lastIndex int) (*Instance, int, bool) {
+	return s.next(lastIndex, nil)
+}
+
+func (s *Service) next(lastIndex int, filter Filter) (*Instance, int, bool) {
 	if lastIndex == -1 {
-		return s.Weighting()
+		return s.weighting(filter)
 	}
 	s.locker.RLock()
 	instances := s.instances
@@ -83,15 +95,46 @@ func (s *Service) Next(lastIndex int) (*Instance, int, bool) {
 	if size == 0 {
 		return nil, 0, false
 	}
+	priority, has := topPriority(instances, filter)
+	if !has {
+		return nil, 0, false
+	}
 
 	for i := 0; i < size; i++ {
 		index := (lastIndex + i) % size
 		instance := instances[index]
 		if instance != nil {
-			if instance.CheckStatus(InstanceRun) {
+			if instance.Priority == priority && instance.CheckStatus(InstanceRun) && filter.match(instance) {
 				return instance, index, true
 			}
 		}
 	}
 	return nil, 0, false
 }
+
+// topPriority 满足条件的可用实例中最高的优先级
+func topPriority(instances []*Instance, filter Filter) (int, bool) {
+	priority, has := 0, false
+	for _, ins := range instances {
+		if ins == nil || !ins.CheckStatus(InstanceRun) || !filter.match(ins) {
+			continue
+		}
+		if !has || ins.Priority < priority {
+			priority, has = ins.Priority, true
+		}
+	}
+	return priority, has
+}
+
+//Select 按优先级依次在满足条件的实例中选择，前一级没有可用实例时才使用下一级
+func (s *Service) Select(lastIndex int, filters []Filter) (*Instance, int, bool) {
+	if len(filters) == 0 {
+		return s.Next(lastIndex)
+	}
+	for _, filter := range filters {
+		if instance, index, has := s.next(lastIndex, filter); has {
+			return instance, index, true
+		}
+	}
+	return nil, 0, false
+}
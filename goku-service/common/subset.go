@@ -0,0 +1,104 @@
+package common
+
+import (
+	"net/http"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+var localZone = ""
+
+//SetLocalZone 设置网关节点所在的可用区，设置后优先转发到同一可用区的实例
+func SetLocalZone(zone string) {
+	localZone = zone
+}
+
+//LocalZone 网关节点所在的可用区
+func LocalZone() string {
+	return localZone
+}
+
+//Filter 实例过滤条件，key为元数据名称，tag匹配实例的任一标签；nil匹配全部实例
+type Filter map[string]string
+
+func (f Filter) match(instance *Instance) bool {
+	for k, v := range f {
+		if k == config.MetadataTag {
+			if !hasTag(instance.Tags, v) {
+				return false
+			}
+			continue
+		}
+		if value, has := instance.Metadata[k]; !has || value != v {
+			return false
+		}
+	}
+	return true
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+//Subset 负载的实例子集
+type Subset struct {
+	selector map[string]string
+	header   string
+	fallback bool
+}
+
+//NewSubset 创建实例子集，未配置时返回nil
+func NewSubset(cfg *config.SubsetConfig) *Subset {
+	if cfg == nil {
+		return nil
+	}
+	return &Subset{
+		selector: cfg.Selector,
+		header:   cfg.Header,
+		fallback: cfg.Fallback,
+	}
+}
+
+//Filters 按优先级返回本次转发的过滤条件：子集内同可用区、子集、（允许回退时）同可用区、全部实例
+func (s *Subset) Filters(header http.Header) []Filter {
+	zone := localZone
+	selector := make(Filter)
+	fallback := true
+	if s != nil {
+		for k, v := range s.selector {
+			selector[k] = v
+		}
+		if s.header != "" {
+			for k, v := range config.ParseSelector(header.Get(s.header)) {
+				selector[k] = v
+			}
+		}
+		fallback = s.fallback || len(selector) == 0
+	}
+	if zone == "" && len(selector) == 0 {
+		return nil
+	}
+	filters := make([]Filter, 0, 4)
+	if len(selector) > 0 {
+		if _, has := selector[config.MetadataZone]; !has && zone != "" {
+			local := Filter{config.MetadataZone: zone}
+			for k, v := range selector {
+				local[k] = v
+			}
+			filters = append(filters, local)
+		}
+		filters = append(filters, selector)
+	}
+	if fallback {
+		if zone != "" {
+			filters = append(filters, Filter{config.MetadataZone: zone})
+		}
+		filters = append(filters, nil)
+	}
+	return filters
+}
@@ -0,0 +1,45 @@
+package common
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+func TestSubsetSelect(t *testing.T) {
+	factory := NewInstanceFactory()
+	v1a := factory.General("10.0.0.1", 80, 1, map[string]string{"version": "v1", "zone": "a"}, nil)
+	v2a := factory.General("10.0.0.2", 80, 1, map[string]string{"version": "v2", "zone": "a"}, []string{"canary"})
+	v2b := factory.General("10.0.0.3", 80, 1, map[string]string{"version": "v2", "zone": "b"}, nil)
+	service := NewService("test", []*Instance{v1a, v2a, v2b})
+
+	SetLocalZone("b")
+	defer SetLocalZone("")
+
+	subset := NewSubset(&config.SubsetConfig{Selector: map[string]string{"version": "v2"}, Header: "X-Subset"})
+	if ins, _, _ := service.Select(-1, subset.Filters(http.Header{})); ins != v2b {
+		t.Errorf("local zone: got %v", ins)
+	}
+
+	v2b.ChangeStatus(InstanceRun, InstanceDown)
+	if ins, _, _ := service.Select(-1, subset.Filters(http.Header{})); ins != v2a {
+		t.Errorf("zone failover: got %v", ins)
+	}
+
+	header := http.Header{}
+	header.Set("X-Subset", "version=v1")
+	if ins, _, _ := service.Select(-1, subset.Filters(header)); ins != v1a {
+		t.Errorf("header selector: got %v", ins)
+	}
+
+	header.Set("X-Subset", "version=v3")
+	if _, _, has := service.Select(-1, subset.Filters(header)); has {
+		t.Error("empty subset without fallback should not select")
+	}
+
+	tagged := NewSubset(&config.SubsetConfig{Selector: map[string]string{config.MetadataTag: "canary"}})
+	if ins, _, _ := service.Select(-1, tagged.Filters(nil)); ins != v2a {
+		t.Errorf("tag selector: got %v", ins)
+	}
+}
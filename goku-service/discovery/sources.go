@@ -4,7 +4,6 @@ import (
 	"errors"
 	"reflect"
 	"sync"
-	"time"
 
 	"github.com/eolinker/goku-api-gateway/config"
 	"github.com/eolinker/goku-api-gateway/goku-service/common"
@@ -62,11 +61,7 @@ func (s *SourceDiscovery) SetHealthConfig(conf *config.HealthCheckConfig) {
 		return
 	}
 
-	s.healthCheckHandler.Open(
-		conf.URL,
-		conf.StatusCode,
-		conf.Second,
-		time.Duration(conf.TimeOutMill)*time.Millisecond)
+	s.healthCheckHandler.Open(conf)
 }
 
 //GetApp getApp
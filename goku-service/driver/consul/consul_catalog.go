@@ -2,6 +2,7 @@ package consul
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	log "github.com/eolinker/goku-api-gateway/goku-log"
@@ -173,7 +174,7 @@ func (d *Discovery) execCallbacks(services map[string][]string, catalogServices
 		hosts := make([]*common.Instance, size)
 		for i, instance := range catalogInstances {
 			//h.hostChangedCallback(appName, newHostInstanceByEureka(appName, &instance))
-			hosts[i] = d.instanceFactory.General(instance.Node.Address, instance.Service.Port, 1)
+			hosts[i] = d.instanceFactory.General(instance.Node.Address, instance.Service.Port, 1, metadata(instance), instance.Service.Tags)
 
 		}
 
@@ -197,3 +198,22 @@ func (d *Discovery) Health() (bool, string) {
 	return ok, desc
 
 }
+
+// metadata 合并节点及服务的元数据，服务优先；key=value格式的标签也作为元数据
+func metadata(instance *api.ServiceEntry) map[string]string {
+	m := make(map[string]string)
+	if instance.Node != nil {
+		for k, v := range instance.Node.Meta {
+			m[k] = v
+		}
+	}
+	for _, tag := range instance.Service.Tags {
+		if kv := strings.SplitN(tag, "=", 2); len(kv) == 2 && kv[0] != "" {
+			m[kv[0]] = kv[1]
+		}
+	}
+	for k, v := range instance.Service.Meta {
+		m[k] = v
+	}
+	return m
+}
@@ -0,0 +1,114 @@
+package dns
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	//TypeA 解析A及AAAA记录，端口取配置
+	TypeA = "a"
+	//TypeSRV 解析SRV记录，端口、优先级及权重取记录
+	TypeSRV = "srv"
+
+	defaultMinRefresh = 5
+	defaultMaxRefresh = 300
+	defaultPort       = 80
+)
+
+//Config dns服务发现配置，刷新间隔单位为秒
+type Config struct {
+	//Nameserver DNS服务器地址，多个以","分隔，为空时使用/etc/resolv.conf中的服务器
+	Nameserver string           `json:"nameserver,omitempty"`
+	Services   []*ServiceConfig `json:"services"`
+	//MinRefresh 按记录TTL刷新时的最短间隔，解析失败时也按此间隔重试
+	MinRefresh int `json:"minRefresh,omitempty"`
+	//MaxRefresh 按记录TTL刷新时的最长间隔
+	MaxRefresh int `json:"maxRefresh,omitempty"`
+}
+
+//ServiceConfig 服务与域名的对应关系
+type ServiceConfig struct {
+	Name string `json:"name"`
+	Host string `json:"host"`
+	//Type 记录类型，为空时以"_"开头的域名按SRV解析，否则按A/AAAA解析
+	Type string `json:"type,omitempty"`
+	//Port A/AAAA记录的实例端口，默认80
+	Port int `json:"port,omitempty"`
+}
+
+//ParseConfig 解析并检查配置
+func ParseConfig(config string) (*Config, error) {
+	c := new(Config)
+	if err := json.Unmarshal([]byte(config), c); err != nil {
+		return nil, err
+	}
+	if c.MinRefresh < 0 || c.MaxRefresh < 0 {
+		return nil, fmt.Errorf("refresh must not be negative")
+	}
+	if c.MinRefresh == 0 {
+		c.MinRefresh = defaultMinRefresh
+	}
+	if c.MaxRefresh == 0 {
+		c.MaxRefresh = defaultMaxRefresh
+	}
+	if c.MaxRefresh < c.MinRefresh {
+		return nil, fmt.Errorf("maxRefresh must not be less than minRefresh")
+	}
+	names := make(map[string]bool)
+	for _, s := range c.Services {
+		if s.Name == "" || s.Host == "" {
+			return nil, fmt.Errorf("service: name and host are required")
+		}
+		if names[s.Name] {
+			return nil, fmt.Errorf("service %s: duplicate", s.Name)
+		}
+		names[s.Name] = true
+		s.Type = strings.ToLower(s.Type)
+		switch s.Type {
+		case "":
+			s.Type = TypeA
+			if strings.HasPrefix(s.Host, "_") {
+				s.Type = TypeSRV
+			}
+		case TypeA, TypeSRV:
+		default:
+			return nil, fmt.Errorf("service %s: invalid type %s", s.Name, s.Type)
+		}
+		if s.Port < 0 || s.Port > 65535 {
+			return nil, fmt.Errorf("service %s: invalid port %d", s.Name, s.Port)
+		}
+		if s.Port == 0 {
+			s.Port = defaultPort
+		}
+	}
+	return c, nil
+}
+
+// refresh 按TTL计算下次刷新的间隔
+func (c *Config) refresh(ttl time.Duration) time.Duration {
+	min := time.Duration(c.MinRefresh) * time.Second
+	max := time.Duration(c.MaxRefresh) * time.Second
+	if ttl < min {
+		return min
+	}
+	if ttl > max {
+		return max
+	}
+	return ttl
+}
+
+func (c *Config) nameservers() []string {
+	if c.Nameserver == "" {
+		return nil
+	}
+	servers := make([]string, 0, 1)
+	for _, s := range strings.Split(c.Nameserver, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			servers = append(servers, s)
+		}
+	}
+	return servers
+}
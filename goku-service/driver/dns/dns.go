@@ -0,0 +1,232 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/goku-service/common"
+)
+
+// lookupTimeout 每个服务单次解析的超时时间
+const lookupTimeout = 10 * time.Second
+
+// srvWeightScale SRV权重的放大倍数
+const srvWeightScale = 100
+
+//Discovery 定时解析域名的A/AAAA或SRV记录作为服务实例，按记录的TTL刷新
+type Discovery struct {
+	locker    sync.RWMutex
+	orgConfig string
+	config    *Config
+	resolver  Resolver
+
+	callback        func([]*common.Service)
+	services        []*common.Service
+	instances       map[string][]*common.Instance
+	instanceFactory *common.InstanceFactory
+	cancel          context.CancelFunc
+}
+
+//NewDNSDiscovery 创建dns服务发现，配置错误时返回nil
+func NewDNSDiscovery(config string) *Discovery {
+	d := &Discovery{
+		instances:       make(map[string][]*common.Instance),
+		instanceFactory: common.NewInstanceFactory(),
+	}
+	if err := d.SetConfig(config); err != nil {
+		log.Error("dns discovery config error:", err)
+		return nil
+	}
+	return d
+}
+
+//SetConfig setConfig
+func (d *Discovery) SetConfig(config string) error {
+	d.locker.RLock()
+	same := d.orgConfig == config && d.config != nil
+	d.locker.RUnlock()
+	if same {
+		return nil
+	}
+	c, err := ParseConfig(config)
+	if err != nil {
+		return err
+	}
+	resolver := NewResolver(c.nameservers())
+	d.locker.Lock()
+	d.orgConfig = config
+	d.config = c
+	d.resolver = resolver
+	d.locker.Unlock()
+	return nil
+}
+
+//SetResolver 替换解析器，配置变化时会按新配置重新创建
+func (d *Discovery) SetResolver(resolver Resolver) {
+	d.locker.Lock()
+	d.resolver = resolver
+	d.locker.Unlock()
+}
+
+//Driver driver
+func (d *Discovery) Driver() string {
+	return DriverName
+}
+
+//SetCallback setCallback
+func (d *Discovery) SetCallback(callback func(services []*common.Service)) {
+	d.callback = callback
+}
+
+//GetServers getServers
+func (d *Discovery) GetServers() ([]*common.Service, error) {
+	d.locker.RLock()
+	defer d.locker.RUnlock()
+	return d.services, nil
+}
+
+//Close close
+func (d *Discovery) Close() error {
+	if d.cancel != nil {
+		d.cancel()
+		d.cancel = nil
+	}
+	return nil
+}
+
+//Open open
+func (d *Discovery) Open() error {
+	d.Close()
+	refresh := d.run()
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	go d.runTask(ctx, refresh)
+	return nil
+}
+
+func (d *Discovery) runTask(ctx context.Context, refresh time.Duration) {
+	timer := time.NewTimer(refresh)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			timer.Reset(d.run())
+		}
+	}
+}
+
+// run 解析全部服务，返回下次刷新的间隔：取记录中最小的TTL，有服务解析失败时按最短间隔重试
+func (d *Discovery) run() time.Duration {
+	d.locker.RLock()
+	c, resolver := d.config, d.resolver
+	d.locker.RUnlock()
+
+	ttl := time.Duration(c.MaxRefresh) * time.Second
+	services := make([]*common.Service, 0, len(c.Services))
+	instances := make(map[string][]*common.Instance, len(c.Services))
+	for _, s := range c.Services {
+		ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+		list, t, err := d.resolve(ctx, resolver, s)
+		cancel()
+		if err != nil {
+			log.Warn("dns discovery resolve ", s.Name, " error:", err)
+			// 解析失败时保留上次的实例
+			d.locker.RLock()
+			list, t = d.instances[s.Name], 0
+			d.locker.RUnlock()
+		}
+		if t < ttl {
+			ttl = t
+		}
+		instances[s.Name] = list
+		if len(list) > 0 {
+			services = append(services, common.NewService(s.Name, list))
+		}
+	}
+
+	d.locker.Lock()
+	d.instances = instances
+	d.services = services
+	d.locker.Unlock()
+	if d.callback != nil {
+		d.callback(services)
+	}
+	return c.refresh(ttl)
+}
+
+// resolve 解析服务的实例，返回其中最小的TTL
+func (d *Discovery) resolve(ctx context.Context, resolver Resolver, s *ServiceConfig) ([]*common.Instance, time.Duration, error) {
+	if s.Type == TypeA {
+		records, err := resolver.LookupHost(ctx, s.Host)
+		if err != nil {
+			return nil, 0, err
+		}
+		instances := make([]*common.Instance, 0, len(records))
+		ttl := minTTL(records)
+		for _, r := range records {
+			instances = append(instances, d.instanceFactory.General(r.Target, s.Port, 1, nil, nil))
+		}
+		return instances, ttl, nil
+	}
+
+	records, err := resolver.LookupSRV(ctx, s.Host)
+	if err != nil {
+		return nil, 0, err
+	}
+	ttl := minTTL(records)
+	instances := make([]*common.Instance, 0, len(records))
+	for _, r := range records {
+		// 目标为"."表示服务不可用
+		if r.Target == "" || r.Target == "." {
+			continue
+		}
+		addrs := []*Record{{Target: r.Target}}
+		if net.ParseIP(r.Target) == nil {
+			addrs, err = resolver.LookupHost(ctx, r.Target)
+			if err != nil {
+				return nil, 0, err
+			}
+			if t := minTTL(addrs); t < ttl {
+				ttl = t
+			}
+		}
+		// 优先级同时写入元数据，优先级变化时生成新的实例
+		metadata := map[string]string{"priority": strconv.Itoa(r.Priority)}
+		for _, addr := range addrs {
+			instance := d.instanceFactory.General(addr.Target, r.Port, srvWeight(r.Weight), metadata, nil)
+			if instance.Priority != r.Priority {
+				instance.Priority = r.Priority
+			}
+			instances = append(instances, instance)
+		}
+	}
+	return instances, ttl, nil
+}
+
+// srvWeight SRV记录的权重，权重为0的记录应以很小的概率被选择（RFC 2782），
+// 因此其余权重放大后再把0映射为1，同一优先级的记录权重均为0时等概率选择
+func srvWeight(weight int) int {
+	if weight <= 0 {
+		return 1
+	}
+	return weight * srvWeightScale
+}
+
+func minTTL(records []*Record) time.Duration {
+	ttl := time.Duration(-1)
+	for _, r := range records {
+		if ttl < 0 || r.TTL < ttl {
+			ttl = r.TTL
+		}
+	}
+	if ttl < 0 {
+		return 0
+	}
+	return ttl
+}
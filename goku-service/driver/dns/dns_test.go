@@ -0,0 +1,145 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/goku-service/common"
+)
+
+type stubResolver struct {
+	hosts map[string][]*Record
+	srvs  map[string][]*Record
+}
+
+func (r *stubResolver) LookupHost(ctx context.Context, host string) ([]*Record, error) {
+	if records, has := r.hosts[host]; has {
+		return records, nil
+	}
+	return nil, errors.New("no such host")
+}
+
+func (r *stubResolver) LookupSRV(ctx context.Context, name string) ([]*Record, error) {
+	if records, has := r.srvs[name]; has {
+		return records, nil
+	}
+	return nil, errors.New("no such host")
+}
+
+func TestDiscoveryRun(t *testing.T) {
+	d := NewDNSDiscovery(`{"services":[{"name":"user","host":"user.example.com","port":8080},{"name":"order","host":"_http._tcp.order.example.com"}],"minRefresh":10,"maxRefresh":60}`)
+	resolver := &stubResolver{
+		hosts: map[string][]*Record{
+			"user.example.com":    {{Target: "10.0.0.1", TTL: 30 * time.Second}, {Target: "10.0.0.2", TTL: 20 * time.Second}},
+			"a.order.example.com": {{Target: "10.0.1.1", TTL: 300 * time.Second}},
+		},
+		srvs: map[string][]*Record{
+			"_http._tcp.order.example.com": {
+				{Target: "a.order.example.com", Port: 9000, Priority: 10, Weight: 5, TTL: 300 * time.Second},
+				{Target: "10.0.1.2", Port: 9001, Priority: 20, Weight: 1, TTL: 300 * time.Second},
+			},
+		},
+	}
+	d.SetResolver(resolver)
+
+	if refresh := d.run(); refresh != 20*time.Second {
+		t.Errorf("refresh: got %s", refresh)
+	}
+	services, _ := d.GetServers()
+	if len(services) != 2 {
+		t.Fatalf("services: got %d", len(services))
+	}
+	order := services[1]
+	primary, _, _ := order.Next(-1)
+	if primary == nil || primary.IP != "10.0.1.1" || primary.Port != 9000 || primary.Weight != 5*srvWeightScale {
+		t.Fatalf("primary: got %+v", primary)
+	}
+	primary.ChangeStatus(common.InstanceRun, common.InstanceDown)
+	if backup, _, _ := order.Next(-1); backup == nil || backup.IP != "10.0.1.2" {
+		t.Errorf("backup: got %+v", backup)
+	}
+
+	// 解析失败时保留上次的实例，并按最短间隔重试
+	delete(resolver.hosts, "user.example.com")
+	if refresh := d.run(); refresh != 10*time.Second {
+		t.Errorf("refresh after failure: got %s", refresh)
+	}
+	if services, _ := d.GetServers(); len(services) != 2 {
+		t.Errorf("services after failure: got %d", len(services))
+	}
+}
+
+func TestDiscoveryZeroWeight(t *testing.T) {
+	d := NewDNSDiscovery(`{"services":[{"name":"order","host":"_http._tcp.order.example.com"}]}`)
+	d.SetResolver(&stubResolver{
+		srvs: map[string][]*Record{
+			"_http._tcp.order.example.com": {
+				{Target: "10.0.1.1", Port: 9000, Priority: 10},
+				{Target: "10.0.1.2", Port: 9000, Priority: 10},
+				{Target: "10.0.1.3", Port: 9000, Priority: 20, Weight: 10},
+			},
+		},
+	})
+	d.run()
+	services, _ := d.GetServers()
+	if len(services) != 1 {
+		t.Fatalf("services: got %d", len(services))
+	}
+	for i := 0; i < 20; i++ {
+		instance, _, has := services[0].Next(-1)
+		if !has {
+			t.Fatal("zero weight instances should be selectable")
+		}
+		if instance.IP == "10.0.1.3" {
+			t.Fatalf("backup selected: %s", instance.IP)
+		}
+	}
+}
+
+func TestClientLookup(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			query := buf[:n]
+			response := append([]byte{}, query...)
+			response[2], response[3] = 0x81, 0x80
+			qtype := query[n-3]
+			switch qtype {
+			case typeA:
+				response[7] = 1
+				// 以压缩指针引用问题中的域名
+				response = append(response, 0xc0, 12, 0, typeA, 0, classIN, 0, 0, 0, 42, 0, 4, 10, 0, 0, 1)
+			case typeSRV:
+				response[7] = 1
+				response = append(response, 0xc0, 12, 0, typeSRV, 0, classIN, 0, 0, 0, 60, 0, 13,
+					0, 10, 0, 5, 0x23, 0x28, 4, 'h', 'o', 's', 't', 0xc0, 12)
+			}
+			conn.WriteTo(response, addr)
+		}
+	}()
+
+	resolver := NewResolver([]string{conn.LocalAddr().String()})
+	records, err := resolver.LookupHost(context.Background(), "user.example.com")
+	if err != nil || len(records) != 1 || records[0].Target != "10.0.0.1" || records[0].TTL != 42*time.Second {
+		t.Fatalf("host: got %v %v", records, err)
+	}
+	records, err = resolver.LookupSRV(context.Background(), "_http._tcp.example.com")
+	if err != nil || len(records) != 1 {
+		t.Fatalf("srv: got %v %v", records, err)
+	}
+	if r := records[0]; r.Target != "host._http._tcp.example.com" || r.Port != 9000 || r.Priority != 10 || r.Weight != 5 || r.TTL != time.Minute {
+		t.Errorf("srv: got %+v", r)
+	}
+}
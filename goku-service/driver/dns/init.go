@@ -0,0 +1,18 @@
+package dns
+
+import (
+	"github.com/eolinker/goku-api-gateway/goku-service/discovery"
+)
+
+//DriverName 驱动名称
+const DriverName = "dns"
+
+//Register 注册
+func Register() {
+	discovery.RegisteredDiscovery(DriverName, discovery.NewDriver(Create))
+}
+
+//Create 创建
+func Create(config string) discovery.Discovery {
+	return NewDNSDiscovery(config)
+}
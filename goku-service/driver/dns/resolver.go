@@ -0,0 +1,326 @@
+package dns
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	typeA    = 1
+	typeAAAA = 28
+	typeSRV  = 33
+	classIN  = 1
+
+	rcodeNameError = 3
+	maxUDPSize     = 4096
+	queryTimeout   = 3 * time.Second
+)
+
+var (
+	errNoRecords = errors.New("no records")
+	errMalformed = errors.New("malformed dns message")
+)
+
+//Record 解析出的记录，A/AAAA记录只有Target
+type Record struct {
+	Target   string
+	Port     int
+	Priority int
+	Weight   int
+	TTL      time.Duration
+}
+
+//Resolver 域名解析，返回的记录带TTL，无法获取TTL时为0
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]*Record, error)
+	LookupSRV(ctx context.Context, name string) ([]*Record, error)
+}
+
+//NewResolver 创建解析器，未指定DNS服务器时使用/etc/resolv.conf中的服务器，仍没有时使用系统解析（无TTL）
+func NewResolver(servers []string) Resolver {
+	if len(servers) == 0 {
+		servers = systemNameservers("/etc/resolv.conf")
+	}
+	if len(servers) == 0 {
+		return systemResolver{}
+	}
+	for i, s := range servers {
+		if _, _, err := net.SplitHostPort(s); err != nil {
+			servers[i] = net.JoinHostPort(s, "53")
+		}
+	}
+	return &client{servers: servers}
+}
+
+func systemNameservers(file string) []string {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	servers := make([]string, 0, 2)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 1 && fields[0] == "nameserver" {
+			servers = append(servers, fields[1])
+		}
+	}
+	return servers
+}
+
+// systemResolver 使用系统解析，无法获取TTL
+type systemResolver struct{}
+
+func (systemResolver) LookupHost(ctx context.Context, host string) ([]*Record, error) {
+	addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]*Record, 0, len(addrs))
+	for _, addr := range addrs {
+		records = append(records, &Record{Target: addr})
+	}
+	return records, nil
+}
+
+func (systemResolver) LookupSRV(ctx context.Context, name string) ([]*Record, error) {
+	_, srvs, err := net.DefaultResolver.LookupSRV(ctx, "", "", name)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]*Record, 0, len(srvs))
+	for _, srv := range srvs {
+		records = append(records, &Record{Target: srv.Target, Port: int(srv.Port), Priority: int(srv.Priority), Weight: int(srv.Weight)})
+	}
+	return records, nil
+}
+
+// client 直接向DNS服务器查询，以取得记录的TTL
+type client struct {
+	servers []string
+}
+
+//LookupHost 查询A及AAAA记录
+func (c *client) LookupHost(ctx context.Context, host string) ([]*Record, error) {
+	records, err := c.query(ctx, host, typeA)
+	if err != nil {
+		return nil, err
+	}
+	records6, err := c.query(ctx, host, typeAAAA)
+	if err != nil {
+		return nil, err
+	}
+	records = append(records, records6...)
+	if len(records) == 0 {
+		return nil, fmt.Errorf("lookup %s: %s", host, errNoRecords)
+	}
+	return records, nil
+}
+
+//LookupSRV 查询SRV记录
+func (c *client) LookupSRV(ctx context.Context, name string) ([]*Record, error) {
+	records, err := c.query(ctx, name, typeSRV)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("lookup %s: %s", name, errNoRecords)
+	}
+	return records, nil
+}
+
+// query 依次向各DNS服务器查询，响应被截断时改用TCP
+func (c *client) query(ctx context.Context, name string, qtype uint16) ([]*Record, error) {
+	id := uint16(rand.Intn(1 << 16))
+	request, err := buildQuery(id, name, qtype)
+	if err != nil {
+		return nil, err
+	}
+	var lastErr error
+	for _, server := range c.servers {
+		response, err := exchange(ctx, "udp", server, request)
+		if err == nil && len(response) > 2 && response[2]&0x02 != 0 {
+			response, err = exchange(ctx, "tcp", server, request)
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		records, err := parseResponse(id, qtype, response)
+		if err != nil {
+			lastErr = fmt.Errorf("lookup %s on %s: %s", name, server, err)
+			continue
+		}
+		return records, nil
+	}
+	return nil, lastErr
+}
+
+func exchange(ctx context.Context, network, server string, request []byte) ([]byte, error) {
+	dialer := net.Dialer{Timeout: queryTimeout}
+	conn, err := dialer.DialContext(ctx, network, server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	deadline := time.Now().Add(queryTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	if network == "udp" {
+		if _, err := conn.Write(request); err != nil {
+			return nil, err
+		}
+		response := make([]byte, maxUDPSize)
+		n, err := conn.Read(response)
+		if err != nil {
+			return nil, err
+		}
+		return response[:n], nil
+	}
+
+	// TCP报文前加两字节长度
+	data := make([]byte, 2+len(request))
+	binary.BigEndian.PutUint16(data, uint16(len(request)))
+	copy(data[2:], request)
+	if _, err := conn.Write(data); err != nil {
+		return nil, err
+	}
+	length := make([]byte, 2)
+	if _, err := io.ReadFull(conn, length); err != nil {
+		return nil, err
+	}
+	response := make([]byte, binary.BigEndian.Uint16(length))
+	if _, err := io.ReadFull(conn, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func buildQuery(id uint16, name string, qtype uint16) ([]byte, error) {
+	// header：期望递归查询，一个问题
+	message := []byte{byte(id >> 8), byte(id), 0x01, 0x00, 0, 1, 0, 0, 0, 0, 0, 0}
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		if len(label) == 0 || len(label) > 63 {
+			return nil, fmt.Errorf("invalid domain name %s", name)
+		}
+		message = append(message, byte(len(label)))
+		message = append(message, label...)
+	}
+	message = append(message, 0, byte(qtype>>8), byte(qtype), 0, classIN)
+	return message, nil
+}
+
+// parseResponse 解析应答中指定类型的记录，忽略CNAME等其他记录
+func parseResponse(id, qtype uint16, message []byte) ([]*Record, error) {
+	if len(message) < 12 || binary.BigEndian.Uint16(message) != id || message[2]&0x80 == 0 {
+		return nil, errMalformed
+	}
+	switch rcode := message[3] & 0x0f; rcode {
+	case 0:
+	case rcodeNameError:
+		return nil, errors.New("no such host")
+	default:
+		return nil, fmt.Errorf("server failure, rcode %d", rcode)
+	}
+	questions := int(binary.BigEndian.Uint16(message[4:]))
+	answers := int(binary.BigEndian.Uint16(message[6:]))
+
+	offset := 12
+	for i := 0; i < questions; i++ {
+		_, next, err := readName(message, offset)
+		if err != nil || next+4 > len(message) {
+			return nil, errMalformed
+		}
+		offset = next + 4
+	}
+
+	records := make([]*Record, 0, answers)
+	for i := 0; i < answers; i++ {
+		_, next, err := readName(message, offset)
+		if err != nil || next+10 > len(message) {
+			return nil, errMalformed
+		}
+		rtype := binary.BigEndian.Uint16(message[next:])
+		ttl := time.Duration(binary.BigEndian.Uint32(message[next+4:])) * time.Second
+		length := int(binary.BigEndian.Uint16(message[next+8:]))
+		start := next + 10
+		offset = start + length
+		if offset > len(message) {
+			return nil, errMalformed
+		}
+		if rtype != qtype {
+			continue
+		}
+		data := message[start:offset]
+		switch rtype {
+		case typeA, typeAAAA:
+			if (rtype == typeA && length != net.IPv4len) || (rtype == typeAAAA && length != net.IPv6len) {
+				return nil, errMalformed
+			}
+			records = append(records, &Record{Target: net.IP(data).String(), TTL: ttl})
+		case typeSRV:
+			if length < 7 {
+				return nil, errMalformed
+			}
+			target, _, err := readName(message, start+6)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, &Record{
+				Target:   target,
+				Priority: int(binary.BigEndian.Uint16(data)),
+				Weight:   int(binary.BigEndian.Uint16(data[2:])),
+				Port:     int(binary.BigEndian.Uint16(data[4:])),
+				TTL:      ttl,
+			})
+		}
+	}
+	return records, nil
+}
+
+// readName 读取域名，支持压缩指针，返回域名及其后的位置
+func readName(message []byte, offset int) (string, int, error) {
+	labels := make([]string, 0, 4)
+	next := -1
+	for jumps := 0; ; {
+		if offset >= len(message) {
+			return "", 0, errMalformed
+		}
+		length := int(message[offset])
+		switch {
+		case length == 0:
+			if next < 0 {
+				next = offset + 1
+			}
+			return strings.Join(labels, "."), next, nil
+		case length&0xc0 == 0xc0:
+			if offset+1 >= len(message) || jumps > 16 {
+				return "", 0, errMalformed
+			}
+			if next < 0 {
+				next = offset + 2
+			}
+			offset = int(binary.BigEndian.Uint16(message[offset:]) & 0x3fff)
+			jumps++
+		default:
+			if offset+1+length > len(message) {
+				return "", 0, errMalformed
+			}
+			labels = append(labels, string(message[offset+1:offset+1+length]))
+			offset += 1 + length
+		}
+	}
+}
@@ -111,9 +111,16 @@ func (d *Eureka) execCallbacks(apps *Applications) {
 				continue
 			}
 			weight := 0
-			if w, has := ins.Metadata.Map[d.weightKey]; has {
-				weight, _ = strconv.Atoi(w)
-
+			var metadata map[string]string
+			if ins.Metadata != nil {
+				metadata = make(map[string]string, len(ins.Metadata.Map))
+				for k, v := range ins.Metadata.Map {
+					if k == d.weightKey {
+						weight, _ = strconv.Atoi(v)
+						continue
+					}
+					metadata[k] = v
+				}
 			}
 			if weight == 0 {
 				weight = 1
@@ -124,7 +131,7 @@ func (d *Eureka) execCallbacks(apps *Applications) {
 			} else if ins.SecurePort.Enabled {
 				port = ins.SecurePort.Port
 			}
-			inses = append(inses, d.instanceFactory.General(ins.IPAddr, port, weight))
+			inses = append(inses, d.instanceFactory.General(ins.IPAddr, port, weight, metadata, nil))
 		}
 		server := common.NewService(app.Name, inses)
 		services = append(services, server)
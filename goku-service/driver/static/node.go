@@ -5,4 +5,7 @@ type Node struct {
 	IP     string
 	Port   int
 	Weight int
+	//Metadata 实例元数据，如 10.0.0.1:8080 10 version=v2 zone=a tags=canary
+	Metadata map[string]string
+	Tags     []string
 }
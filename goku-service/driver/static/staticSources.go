@@ -3,7 +3,6 @@ package static
 import (
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/eolinker/goku-api-gateway/config"
 	"github.com/eolinker/goku-api-gateway/goku-service/health"
@@ -34,11 +33,7 @@ func (s *Sources) SetHealthConfig(conf *config.HealthCheckConfig) {
 		return
 	}
 
-	s.healthCheckHandler.Open(
-		conf.URL,
-		conf.StatusCode,
-		conf.Second,
-		time.Duration(conf.TimeOutMill)*time.Millisecond)
+	s.healthCheckHandler.Open(conf)
 }
 
 //Close close
@@ -111,10 +106,24 @@ func (s *Sources) decode(config string) (*common.Service, error) {
 		if word[l-1] == ';' {
 			value = word[:l-1]
 		}
+		// ip:port之后key=value格式的为实例元数据，tags的值为逗号分隔的标签
+		if index > 0 && strings.Contains(value, "=") {
+			kv := strings.SplitN(value, "=", 2)
+			if kv[0] == "tags" {
+				node.Tags = append(node.Tags, strings.Split(kv[1], ",")...)
+			} else if kv[0] != "" {
+				node.Metadata[kv[0]] = kv[1]
+			}
+			if word[l-1] == ';' {
+				index = 0
+				node = nil
+			}
+			continue
+		}
 		switch index {
 		case 0:
 			{
-				node = new(Node)
+				node = &Node{Metadata: make(map[string]string)}
 				vs := strings.Split(value, ":")
 				if len(vs) > 2 {
 					return nil, fmt.Errorf("decode ip:port failt for[%s]", value)
@@ -152,7 +161,7 @@ func (s *Sources) decode(config string) (*common.Service, error) {
 
 	if len(nodes) > 0 {
 		for _, n := range nodes {
-			instance := s.instanceFactory.General(n.IP, n.Port, n.Weight)
+			instance := s.instanceFactory.General(n.IP, n.Port, n.Weight, n.Metadata, n.Tags)
 			instances = append(instances, instance)
 		}
 		s := common.NewService("static_upstream", instances)
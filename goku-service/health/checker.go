@@ -3,18 +3,37 @@ package health
 import (
 	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
 	"net/http"
+	"regexp"
+	"strings"
 	"time"
 
+	"github.com/eolinker/goku-api-gateway/config"
 	"github.com/eolinker/goku-api-gateway/goku-service/common"
 )
 
+// maxCheckBody 检查响应体时最多读取的长度
+const maxCheckBody = 64 * 1024
+
 //Checker checker
 type Checker struct {
 	path    string
 	second  int
 	timeout time.Duration
 
+	protocol         string
+	method           string
+	host             string
+	headers          map[string]string
+	body             string
+	bodyRegexp       *regexp.Regexp
+	grpcService      string
+	healthyThreshold int
+	client           *http.Client
+
 	instances  map[string][]*common.Instance
 	sum        int
 	cancelFunc context.CancelFunc
@@ -40,26 +59,71 @@ func (c *Checker) Open() {
 	go c.doloop(ctx, c.closeDone)
 }
 func (c *Checker) check(instance *common.Instance) bool {
-	// todo 这里有个问题，没有指定协议，只能通过端口进行简单判定，如果没有设置端口或者设置自定义的https的端口，会导致无法识别到https
 	server := instance.IP
 	if instance.Port != 0 {
 		server = fmt.Sprintf("%s:%d", instance.IP, instance.Port)
 	}
-	protocol := "http"
-	if instance.Port == 443 {
-		protocol = "https"
+	switch c.protocol {
+	case config.HealthCheckTCP:
+		conn, err := net.DialTimeout("tcp", server, c.timeout)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+		return true
+	case config.HealthCheckGRPC:
+		authority := c.host
+		if authority == "" {
+			authority = server
+		}
+		return checkGRPC(server, authority, c.grpcService, c.timeout)
+	}
+	return c.checkHTTP(instance, server)
+}
+
+func (c *Checker) checkHTTP(instance *common.Instance, server string) bool {
+	// 未指定协议时只能通过端口进行简单判定
+	protocol := c.protocol
+	if protocol == "" {
+		protocol = config.HealthCheckHTTP
+		if instance.Port == 443 {
+			protocol = config.HealthCheckHTTPS
+		}
 	}
 
 	url := fmt.Sprintf("%s://%s/%s", protocol, server, c.path)
-	response, err := http.Get(url)
+	request, err := http.NewRequest(c.method, url, nil)
 	if err != nil {
 		return false
 	}
+	for name, value := range c.headers {
+		request.Header.Set(name, value)
+	}
+	if c.host != "" {
+		request.Host = c.host
+	}
+	response, err := c.client.Do(request)
+	if err != nil {
+		return false
+	}
+	defer response.Body.Close()
 
-	if c.statusCodes[response.StatusCode] {
+	if !c.statusCodes[response.StatusCode] {
+		io.Copy(ioutil.Discard, response.Body)
+		return false
+	}
+	if c.body == "" && c.bodyRegexp == nil {
+		io.Copy(ioutil.Discard, response.Body)
 		return true
 	}
-	return false
+	body, err := ioutil.ReadAll(io.LimitReader(response.Body, maxCheckBody))
+	if err != nil {
+		return false
+	}
+	if c.body != "" && !strings.Contains(string(body), c.body) {
+		return false
+	}
+	return c.bodyRegexp == nil || c.bodyRegexp.Match(body)
 }
 func (c *Checker) doloop(ctx context.Context, closeDone chan int) {
 	defer close(closeDone)
@@ -72,6 +136,8 @@ func (c *Checker) doloop(ctx context.Context, closeDone chan int) {
 	if instances == nil {
 		instances = make(map[string][]*common.Instance)
 	}
+	// passes 实例连续检查成功的次数
+	passes := make(map[string]int)
 
 	for {
 		select {
@@ -87,6 +153,7 @@ func (c *Checker) doloop(ctx context.Context, closeDone chan int) {
 					// 处理空列表
 					if len(ins) == 0 {
 						delete(instances, instanceID)
+						delete(passes, instanceID)
 						continue
 					}
 
@@ -101,13 +168,21 @@ func (c *Checker) doloop(ctx context.Context, closeDone chan int) {
 					// 移除没有需要待检查的实例id
 					if len(insNew) == 0 {
 						delete(instances, instanceID)
+						delete(passes, instanceID)
 						continue
 					}
 					instance := insNew[0]
 
 					if c.check(instance) {
+						passes[instanceID]++
+					} else {
+						delete(passes, instanceID)
+					}
+					if passes[instanceID] >= c.healthyThreshold {
 						delete(instances, instanceID)
+						delete(passes, instanceID)
 						for _, in := range insNew {
+							in.ResetFailure()
 							in.ChangeStatus(common.InstanceChecking, common.InstanceRun)
 						}
 					} else {
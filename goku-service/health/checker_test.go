@@ -0,0 +1,64 @@
+package health
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/goku-service/common"
+)
+
+func TestCheckerCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead && r.Header.Get("X-Check") == "1" {
+			w.Write([]byte(`{"status":"UP"}`))
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	host, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	p, _ := strconv.Atoi(port)
+	instance := common.NewInstanceFactory().General(host, p, 1, nil, nil)
+
+	box := &CheckBox{}
+	box.Open(&config.HealthCheckConfig{
+		IsHealthCheck: true,
+		URL:           "health",
+		StatusCode:    "200, 204",
+		HealthCheckOptions: config.HealthCheckOptions{
+			Headers:    map[string]string{"X-Check": "1"},
+			BodyRegexp: `"status":\s*"UP"`,
+		},
+	})
+	defer box.Close()
+	if !box.checker.check(instance) {
+		t.Error("http check should pass")
+	}
+
+	box.checker.headers = nil
+	if box.checker.check(instance) {
+		t.Error("http check without header should fail")
+	}
+
+	box.checker.protocol = config.HealthCheckTCP
+	if !box.checker.check(instance) {
+		t.Error("tcp check should pass")
+	}
+}
+
+func TestServingStatus(t *testing.T) {
+	if status, ok := servingStatus(grpcMessage([]byte{0x08, grpcServing})); !ok || status != grpcServing {
+		t.Errorf("got %d %v", status, ok)
+	}
+	if status, ok := servingStatus(grpcMessage(nil)); !ok || status != 0 {
+		t.Errorf("empty response: got %d %v", status, ok)
+	}
+	if _, ok := servingStatus([]byte{0, 0, 0, 0, 9, 0x08}); ok {
+		t.Error("truncated response should fail")
+	}
+}
@@ -0,0 +1,220 @@
+package health
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"time"
+)
+
+// gRPC健康检查只需要一次明文HTTP/2的unary调用，这里直接读写帧，不引入完整的HTTP/2及gRPC实现
+const (
+	http2Preface = "PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n"
+
+	frameData      = 0x0
+	frameHeaders   = 0x1
+	frameRSTStream = 0x3
+	frameSettings  = 0x4
+	framePing      = 0x6
+	frameGoAway    = 0x7
+
+	flagEndStream  = 0x1
+	flagAck        = 0x1
+	flagEndHeaders = 0x4
+	flagPadded     = 0x8
+
+	// grpcServing grpc.health.v1.HealthCheckResponse.ServingStatus.SERVING
+	grpcServing = 1
+	// maxFrameSize 允许读取的最大帧长度
+	maxFrameSize = 1 << 20
+)
+
+// checkGRPC 调用grpc.health.v1.Health/Check，返回状态为SERVING时健康
+func checkGRPC(server, authority, service string, timeout time.Duration) bool {
+	conn, err := net.DialTimeout("tcp", server, timeout)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	request := new(bytes.Buffer)
+	request.WriteString(http2Preface)
+	writeFrame(request, frameSettings, 0, 0, nil)
+	writeFrame(request, frameHeaders, flagEndHeaders, 1, hpackHeaders(
+		":method", "POST",
+		":scheme", "http",
+		":path", "/grpc.health.v1.Health/Check",
+		":authority", authority,
+		"content-type", "application/grpc",
+		"te", "trailers",
+	))
+	writeFrame(request, frameData, flagEndStream, 1, grpcMessage(healthCheckRequest(service)))
+	if _, err := conn.Write(request.Bytes()); err != nil {
+		return false
+	}
+
+	data, ok := readGRPCResponse(conn)
+	if !ok {
+		return false
+	}
+	status, ok := servingStatus(data)
+	return ok && status == grpcServing
+}
+
+// readGRPCResponse 读取stream 1的响应数据，直到stream结束
+func readGRPCResponse(conn net.Conn) ([]byte, bool) {
+	data := new(bytes.Buffer)
+	header := make([]byte, 9)
+	for {
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return nil, false
+		}
+		length := int(header[0])<<16 | int(header[1])<<8 | int(header[2])
+		frameType, flags := header[3], header[4]
+		streamID := binary.BigEndian.Uint32(header[5:]) & 0x7fffffff
+		if length > maxFrameSize {
+			return nil, false
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(conn, payload); err != nil {
+			return nil, false
+		}
+
+		switch frameType {
+		case frameSettings:
+			if flags&flagAck == 0 {
+				ack := new(bytes.Buffer)
+				writeFrame(ack, frameSettings, flagAck, 0, nil)
+				conn.Write(ack.Bytes())
+			}
+		case framePing:
+			if flags&flagAck == 0 {
+				pong := new(bytes.Buffer)
+				writeFrame(pong, framePing, flagAck, 0, payload)
+				conn.Write(pong.Bytes())
+			}
+		case frameRSTStream, frameGoAway:
+			return nil, false
+		case frameData:
+			if streamID != 1 {
+				continue
+			}
+			if flags&flagPadded != 0 {
+				if len(payload) == 0 || int(payload[0]) >= len(payload) {
+					return nil, false
+				}
+				payload = payload[1 : len(payload)-int(payload[0])]
+			}
+			data.Write(payload)
+			if flags&flagEndStream != 0 {
+				return data.Bytes(), true
+			}
+		case frameHeaders:
+			if streamID == 1 && flags&flagEndStream != 0 {
+				return data.Bytes(), true
+			}
+		}
+	}
+}
+
+func writeFrame(w *bytes.Buffer, frameType, flags byte, streamID uint32, payload []byte) {
+	length := len(payload)
+	w.Write([]byte{byte(length >> 16), byte(length >> 8), byte(length), frameType, flags})
+	binary.Write(w, binary.BigEndian, streamID&0x7fffffff)
+	w.Write(payload)
+}
+
+// hpackHeaders 以不索引的字面量编码请求头，不使用霍夫曼编码
+func hpackHeaders(pairs ...string) []byte {
+	block := new(bytes.Buffer)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		block.WriteByte(0)
+		hpackString(block, pairs[i])
+		hpackString(block, pairs[i+1])
+	}
+	return block.Bytes()
+}
+
+func hpackString(w *bytes.Buffer, s string) {
+	// 7位前缀的整数编码
+	n := len(s)
+	if n < 127 {
+		w.WriteByte(byte(n))
+	} else {
+		w.WriteByte(127)
+		n -= 127
+		for n >= 128 {
+			w.WriteByte(byte(n%128 + 128))
+			n /= 128
+		}
+		w.WriteByte(byte(n))
+	}
+	w.WriteString(s)
+}
+
+// healthCheckRequest 编码grpc.health.v1.HealthCheckRequest
+func healthCheckRequest(service string) []byte {
+	if service == "" {
+		return nil
+	}
+	message := []byte{0x0a}
+	message = appendVarint(message, uint64(len(service)))
+	return append(message, service...)
+}
+
+// grpcMessage 加上gRPC的消息头：是否压缩及消息长度
+func grpcMessage(message []byte) []byte {
+	data := make([]byte, 5, 5+len(message))
+	binary.BigEndian.PutUint32(data[1:], uint32(len(message)))
+	return append(data, message...)
+}
+
+// servingStatus 解析grpc.health.v1.HealthCheckResponse的status字段
+func servingStatus(data []byte) (uint64, bool) {
+	if len(data) < 5 || data[0] != 0 {
+		return 0, false
+	}
+	length := binary.BigEndian.Uint32(data[1:5])
+	if uint32(len(data)-5) < length {
+		return 0, false
+	}
+	message := data[5 : 5+length]
+	status := uint64(0)
+	for len(message) > 0 {
+		key, n := binary.Uvarint(message)
+		if n <= 0 {
+			return 0, false
+		}
+		message = message[n:]
+		switch key & 0x7 {
+		case 0:
+			v, n := binary.Uvarint(message)
+			if n <= 0 {
+				return 0, false
+			}
+			message = message[n:]
+			if key>>3 == 1 {
+				status = v
+			}
+		case 2:
+			l, n := binary.Uvarint(message)
+			if n <= 0 || uint64(len(message)-n) < l {
+				return 0, false
+			}
+			message = message[n+int(l):]
+		default:
+			return 0, false
+		}
+	}
+	return status, true
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
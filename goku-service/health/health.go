@@ -1,16 +1,19 @@
 package health
 
 import (
+	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/eolinker/goku-api-gateway/config"
 	"github.com/eolinker/goku-api-gateway/goku-service/common"
 )
 
 //CheckHandler checkHandler
 type CheckHandler interface {
-	Open(path string, statusCodes string, second int, timeout time.Duration)
+	Open(conf *config.HealthCheckConfig)
 	Check(instance *common.Instance)
 	IsNeedCheck() bool
 	Close() []*common.Instance
@@ -18,23 +21,24 @@ type CheckHandler interface {
 
 //CheckBox checkBox
 type CheckBox struct {
-	isNeedCheck bool
-	statusCodes map[string]bool
-	checker     *Checker
+	isNeedCheck        bool
+	statusCodes        map[string]bool
+	unhealthyThreshold int
+	checker            *Checker
 }
 
 //Open open
-func (c *CheckBox) Open(path string, statusCodes string, second int, timeout time.Duration) {
+func (c *CheckBox) Open(conf *config.HealthCheckConfig) {
 
 	old := c.checker
 
 	checker := new(Checker)
 
-	checker.path = strings.TrimPrefix(path, "/")
+	checker.path = strings.TrimPrefix(conf.URL, "/")
 	status := make(map[int]bool)
-	for _, s := range strings.Split(statusCodes, ",") {
-		code, e := strconv.Atoi(s)
-		if e != nil {
+	for _, s := range strings.Split(conf.StatusCode, ",") {
+		code, e := strconv.Atoi(strings.TrimSpace(s))
+		if e == nil {
 			status[code] = true
 		}
 
@@ -43,15 +47,33 @@ func (c *CheckBox) Open(path string, statusCodes string, second int, timeout tim
 		status[200] = true
 	}
 	checker.statusCodes = status
-	checker.second = second
+	checker.second = conf.Second
 	if checker.second < 5 {
 		checker.second = 5
 	}
-	checker.timeout = timeout
+	checker.timeout = time.Duration(conf.TimeOutMill) * time.Millisecond
 
 	if checker.timeout < time.Millisecond*100 {
 		checker.timeout = time.Millisecond * 100
 	}
+	checker.protocol = conf.Protocol
+	checker.method = strings.ToUpper(conf.Method)
+	if checker.method == "" {
+		checker.method = http.MethodGet
+	}
+	checker.host = conf.Host
+	checker.headers = conf.Headers
+	checker.body = conf.Body
+	if conf.BodyRegexp != "" {
+		checker.bodyRegexp, _ = regexp.Compile(conf.BodyRegexp)
+	}
+	checker.grpcService = conf.GRPCService
+	checker.healthyThreshold = conf.HealthyThreshold
+	if checker.healthyThreshold < 1 {
+		checker.healthyThreshold = 1
+	}
+	checker.client = &http.Client{Timeout: checker.timeout}
+	c.unhealthyThreshold = conf.UnhealthyThreshold
 
 	if old != nil {
 		sources, _ := old.Close()
@@ -63,11 +85,14 @@ func (c *CheckBox) Open(path string, statusCodes string, second int, timeout tim
 	c.isNeedCheck = true
 }
 
-//Check check
+//Check 记录实例转发出错，连续出错达到阈值时摘除实例并开始检查
 func (c *CheckBox) Check(instance *common.Instance) {
 	if !c.isNeedCheck {
 		return
 	}
+	if instance.AddFailure() < c.unhealthyThreshold {
+		return
+	}
 	if c.checker != nil {
 		c.checker.Check(instance)
 	}
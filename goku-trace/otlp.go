@@ -0,0 +1,223 @@
+package goku_trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+)
+
+const (
+	defaultQueueSize     = 4096
+	defaultBatchSize     = 512
+	defaultFlushInterval = time.Second * 5
+)
+
+//OTLPExporter 通过OTLP/HTTP(JSON)批量上报span
+type OTLPExporter struct {
+	endpoint  string
+	client    *http.Client
+	resource  map[string]interface{}
+	queue     chan *Span
+	batchSize int
+	interval  time.Duration
+	dropped   uint64
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+//NewOTLPExporter 创建OTLP exporter，endpoint为collector的traces地址，如 http://127.0.0.1:4318/v1/traces
+func NewOTLPExporter(endpoint string, serviceName string) *OTLPExporter {
+	ctx, cancel := context.WithCancel(context.Background())
+	e := &OTLPExporter{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: time.Second * 10},
+		resource: map[string]interface{}{
+			"service.name": serviceName,
+		},
+		queue:     make(chan *Span, defaultQueueSize),
+		batchSize: defaultBatchSize,
+		interval:  defaultFlushInterval,
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	for k, v := range resourceAttributes() {
+		e.resource[k] = v
+	}
+	e.wg.Add(1)
+	go e.loop()
+	return e
+}
+
+//Export 队列满时丢弃，不阻塞请求
+func (e *OTLPExporter) Export(span *Span) {
+	select {
+	case e.queue <- span:
+	default:
+		atomic.AddUint64(&e.dropped, 1)
+	}
+}
+
+//Dropped 因队列已满丢弃的span数
+func (e *OTLPExporter) Dropped() uint64 {
+	return atomic.LoadUint64(&e.dropped)
+}
+
+//Close 停止并上报剩余span
+func (e *OTLPExporter) Close() {
+	e.cancel()
+	e.wg.Wait()
+}
+
+func (e *OTLPExporter) loop() {
+	defer e.wg.Done()
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	batch := make([]*Span, 0, e.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := e.send(batch); err != nil {
+			log.Warn("export spans:", err)
+		}
+		batch = make([]*Span, 0, e.batchSize)
+	}
+	for {
+		select {
+		case <-e.ctx.Done():
+			for {
+				select {
+				case span := <-e.queue:
+					batch = append(batch, span)
+				default:
+					flush()
+					return
+				}
+			}
+		case span := <-e.queue:
+			batch = append(batch, span)
+			if len(batch) >= e.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func (e *OTLPExporter) send(spans []*Span) error {
+	body, err := json.Marshal(e.encode(spans))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("collector response status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+type otlpValue map[string]interface{}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	TraceState        string          `json:"traceState,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+func (e *OTLPExporter) encode(spans []*Span) map[string]interface{} {
+	list := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		s.locker.Lock()
+		os := otlpSpan{
+			TraceID:           s.context.TraceID.String(),
+			SpanID:            s.context.SpanID.String(),
+			TraceState:        s.context.TraceState,
+			Name:              s.name,
+			Kind:              int(s.kind),
+			StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+			Attributes:        toAttributes(s.attributes),
+			Status:            otlpStatus{Code: int(s.status), Message: s.message},
+		}
+		s.locker.Unlock()
+		if s.parent.IsValid() {
+			os.ParentSpanID = s.parent.String()
+		}
+		list = append(list, os)
+	}
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": toAttributes(e.resource),
+				},
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]interface{}{"name": "goku-api-gateway"},
+						"spans": list,
+					},
+				},
+			},
+		},
+	}
+}
+
+func toAttributes(m map[string]interface{}) []otlpAttribute {
+	attrs := make([]otlpAttribute, 0, len(m))
+	for k, v := range m {
+		var value otlpValue
+		switch t := v.(type) {
+		case string:
+			value = otlpValue{"stringValue": t}
+		case bool:
+			value = otlpValue{"boolValue": t}
+		case int:
+			value = otlpValue{"intValue": strconv.Itoa(t)}
+		case int64:
+			value = otlpValue{"intValue": strconv.FormatInt(t, 10)}
+		case float64:
+			value = otlpValue{"doubleValue": t}
+		default:
+			value = otlpValue{"stringValue": fmt.Sprint(t)}
+		}
+		attrs = append(attrs, otlpAttribute{Key: k, Value: value})
+	}
+	return attrs
+}
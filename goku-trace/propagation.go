@@ -0,0 +1,134 @@
+package goku_trace
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	//HeaderTraceParent W3C traceparent
+	HeaderTraceParent = "traceparent"
+	//HeaderTraceState W3C tracestate
+	HeaderTraceState = "tracestate"
+	//HeaderB3 B3 single header
+	HeaderB3 = "b3"
+	//HeaderB3TraceID B3 multi header
+	HeaderB3TraceID = "X-B3-TraceId"
+	//HeaderB3SpanID B3 multi header
+	HeaderB3SpanID = "X-B3-SpanId"
+	//HeaderB3ParentSpanID B3 multi header
+	HeaderB3ParentSpanID = "X-B3-ParentSpanId"
+	//HeaderB3Sampled B3 multi header
+	HeaderB3Sampled = "X-B3-Sampled"
+)
+
+//ParseTraceParent 解析W3C traceparent: version-traceid-spanid-flags
+func ParseTraceParent(value string) (SpanContext, bool) {
+	var sc SpanContext
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return sc, false
+	}
+	version := parts[0]
+	if len(version) != 2 || version == "ff" || (version == "00" && len(parts) != 4) {
+		return sc, false
+	}
+	if !decodeHex(parts[1], sc.TraceID[:]) || !decodeHex(parts[2], sc.SpanID[:]) {
+		return sc, false
+	}
+	var flags [1]byte
+	if !decodeHex(parts[3], flags[:]) {
+		return sc, false
+	}
+	sc.Sampled = flags[0]&0x01 == 0x01
+	return sc, sc.IsValid()
+}
+
+//FormatTraceParent 生成W3C traceparent
+func FormatTraceParent(sc SpanContext) string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", sc.TraceID, sc.SpanID, flags)
+}
+
+//ParseB3 解析B3 single header: traceid-spanid[-sampled[-parentspanid]]
+func ParseB3(value string) (SpanContext, bool) {
+	var sc SpanContext
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 2 {
+		return sc, false
+	}
+	if !decodeB3TraceID(parts[0], &sc.TraceID) || !decodeHex(parts[1], sc.SpanID[:]) {
+		return sc, false
+	}
+	if len(parts) > 2 {
+		sc.Sampled = parts[2] == "1" || parts[2] == "d"
+	}
+	return sc, sc.IsValid()
+}
+
+//Extract 从请求头中读取上游的SpanContext，W3C优先
+func Extract(header http.Header, b3 bool) (SpanContext, bool) {
+	if tp := header.Get(HeaderTraceParent); tp != "" {
+		if sc, ok := ParseTraceParent(tp); ok {
+			sc.TraceState = header.Get(HeaderTraceState)
+			return sc, true
+		}
+	}
+	if !b3 {
+		return SpanContext{}, false
+	}
+	if v := header.Get(HeaderB3); v != "" {
+		return ParseB3(v)
+	}
+	var sc SpanContext
+	if !decodeB3TraceID(header.Get(HeaderB3TraceID), &sc.TraceID) || !decodeHex(header.Get(HeaderB3SpanID), sc.SpanID[:]) {
+		return SpanContext{}, false
+	}
+	sc.Sampled = header.Get(HeaderB3Sampled) == "1"
+	return sc, sc.IsValid()
+}
+
+//Inject 将SpanContext写入转发请求头
+func Inject(header http.Header, sc SpanContext, b3 bool) {
+	if !sc.IsValid() {
+		return
+	}
+	header.Set(HeaderTraceParent, FormatTraceParent(sc))
+	if sc.TraceState != "" {
+		header.Set(HeaderTraceState, sc.TraceState)
+	} else {
+		header.Del(HeaderTraceState)
+	}
+	if b3 {
+		sampled := "0"
+		if sc.Sampled {
+			sampled = "1"
+		}
+		header.Set(HeaderB3, fmt.Sprintf("%s-%s-%s", sc.TraceID, sc.SpanID, sampled))
+		header.Del(HeaderB3TraceID)
+		header.Del(HeaderB3SpanID)
+		header.Del(HeaderB3ParentSpanID)
+		header.Del(HeaderB3Sampled)
+	}
+}
+
+func decodeHex(s string, dst []byte) bool {
+	if len(s) != len(dst)*2 || strings.ToLower(s) != s {
+		return false
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err == nil
+}
+
+// decodeB3TraceID B3允许64位traceID，左侧补0
+func decodeB3TraceID(s string, t *TraceID) bool {
+	if len(s) == 16 {
+		s = strings.Repeat("0", 16) + s
+	}
+	return decodeHex(s, t[:])
+}
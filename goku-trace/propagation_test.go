@@ -0,0 +1,82 @@
+package goku_trace
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestTraceParent(t *testing.T) {
+	value := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	sc, ok := ParseTraceParent(value)
+	if !ok {
+		t.Fatal("parse traceparent failed")
+	}
+	if sc.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || sc.SpanID.String() != "00f067aa0ba902b7" || !sc.Sampled {
+		t.Fatalf("unexpected span context: %+v", sc)
+	}
+	if FormatTraceParent(sc) != value {
+		t.Fatalf("format traceparent: %s", FormatTraceParent(sc))
+	}
+
+	for _, invalid := range []string{
+		"",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+	} {
+		if _, ok := ParseTraceParent(invalid); ok {
+			t.Errorf("%q should be invalid", invalid)
+		}
+	}
+}
+
+func TestExtractInject(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-B3-TraceId", "a3ce929d0e0e4736")
+	header.Set("X-B3-SpanId", "00f067aa0ba902b7")
+	header.Set("X-B3-Sampled", "1")
+	if _, ok := Extract(header, false); ok {
+		t.Fatal("b3 should be ignored when disabled")
+	}
+	sc, ok := Extract(header, true)
+	if !ok || sc.TraceID.String() != "0000000000000000a3ce929d0e0e4736" || !sc.Sampled {
+		t.Fatalf("extract b3: %+v %v", sc, ok)
+	}
+
+	tracer := NewTracer(1, true, nil)
+	parent := tracer.start("server", SpanKindServer, sc, true)
+	child := StartSpan(parent, "client", SpanKindClient)
+
+	out := http.Header{}
+	InjectHeader(child, out)
+	got, ok := Extract(out, false)
+	if !ok {
+		t.Fatal("extract injected traceparent failed")
+	}
+	if got.TraceID != sc.TraceID || got.SpanID != child.Context().SpanID || !got.Sampled {
+		t.Fatalf("unexpected injected context: %+v", got)
+	}
+	if out.Get(HeaderB3) == "" || out.Get(HeaderB3TraceID) != "" {
+		t.Fatalf("b3 headers not rewritten: %v", out)
+	}
+	if child.parent != parent.Context().SpanID {
+		t.Fatal("child span should reference parent span")
+	}
+}
+
+func TestSampler(t *testing.T) {
+	if s := NewTracer(0, false, nil).start("s", SpanKindServer, SpanContext{}, false); s.Context().Sampled {
+		t.Fatal("rate 0 should not sample")
+	}
+	if s := NewTracer(1, false, nil).start("s", SpanKindServer, SpanContext{}, false); !s.Context().Sampled {
+		t.Fatal("rate 1 should sample")
+	}
+	var nilSpan *Span
+	nilSpan.SetAttribute("k", "v")
+	nilSpan.End()
+	if StartSpan(nilSpan, "child", SpanKindInternal) != nil {
+		t.Fatal("child of nil span should be nil")
+	}
+}
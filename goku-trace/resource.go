@@ -0,0 +1,25 @@
+package goku_trace
+
+import "sync"
+
+var (
+	resourceLocker sync.RWMutex
+	resource       = make(map[string]interface{})
+)
+
+//SetResource 设置上报span时附带的节点信息，如集群、实例
+func SetResource(key string, value string) {
+	resourceLocker.Lock()
+	resource[key] = value
+	resourceLocker.Unlock()
+}
+
+func resourceAttributes() map[string]interface{} {
+	resourceLocker.RLock()
+	defer resourceLocker.RUnlock()
+	m := make(map[string]interface{}, len(resource))
+	for k, v := range resource {
+		m[k] = v
+	}
+	return m
+}
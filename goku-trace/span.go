@@ -0,0 +1,158 @@
+package goku_trace
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+//SpanKind span类型
+type SpanKind int
+
+const (
+	//SpanKindInternal 内部调用
+	SpanKindInternal SpanKind = 1
+	//SpanKindServer 服务端
+	SpanKindServer SpanKind = 2
+	//SpanKindClient 客户端
+	SpanKindClient SpanKind = 3
+)
+
+//StatusCode span状态
+type StatusCode int
+
+const (
+	//StatusUnset 未设置
+	StatusUnset StatusCode = 0
+	//StatusOK 成功
+	StatusOK StatusCode = 1
+	//StatusError 失败
+	StatusError StatusCode = 2
+)
+
+//TraceID traceID
+type TraceID [16]byte
+
+//SpanID spanID
+type SpanID [8]byte
+
+//String hex
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+//IsValid 全0为非法
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+//String hex
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+//IsValid 全0为非法
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+//SpanContext 跨进程传递的span信息
+type SpanContext struct {
+	TraceID    TraceID
+	SpanID     SpanID
+	Sampled    bool
+	TraceState string
+}
+
+//IsValid isValid
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+//Span span
+type Span struct {
+	tracer     *Tracer
+	name       string
+	kind       SpanKind
+	context    SpanContext
+	parent     SpanID
+	start      time.Time
+	end        time.Time
+	status     StatusCode
+	message    string
+	attributes map[string]interface{}
+	locker     sync.Mutex
+	once       sync.Once
+}
+
+//Context 返回span的SpanContext，nil span返回空值
+func (s *Span) Context() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.context
+}
+
+//TraceID 当前traceID，nil span返回空字符串
+func (s *Span) TraceID() string {
+	if s == nil {
+		return ""
+	}
+	return s.context.TraceID.String()
+}
+
+//SetAttribute 设置属性，value支持string、bool、int、int64、float64
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil || !s.context.Sampled {
+		return
+	}
+	s.locker.Lock()
+	s.attributes[key] = value
+	s.locker.Unlock()
+}
+
+//SetStatus 设置状态
+func (s *Span) SetStatus(code StatusCode, message string) {
+	if s == nil {
+		return
+	}
+	s.locker.Lock()
+	s.status = code
+	s.message = message
+	s.locker.Unlock()
+}
+
+//End 结束span，采样的span会交给exporter
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.once.Do(func() {
+		s.end = time.Now()
+		if s.context.Sampled {
+			s.tracer.export(s)
+		}
+	})
+}
+
+func newTraceID() TraceID {
+	var t TraceID
+	_, _ = rand.Read(t[:])
+	return t
+}
+
+func newSpanID() SpanID {
+	var s SpanID
+	for !s.IsValid() {
+		_, _ = rand.Read(s[:])
+	}
+	return s
+}
+
+// traceIDRatio 取traceID低8字节作为采样依据，保证同一trace在各节点采样结果一致
+func traceIDRatio(t TraceID) float64 {
+	v := binary.BigEndian.Uint64(t[8:]) >> 11
+	return float64(v) / float64(uint64(1)<<53)
+}
@@ -0,0 +1,137 @@
+package goku_trace
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+//Exporter span导出
+type Exporter interface {
+	Export(span *Span)
+	Close()
+}
+
+//Tracer tracer
+type Tracer struct {
+	sampleRate float64
+	b3         bool
+	exporter   Exporter
+}
+
+var (
+	current atomic.Value
+)
+
+type tracerHolder struct {
+	tracer *Tracer
+}
+
+//NewTracer 创建tracer，sampleRate取值[0,1]，b3为true时同时读写B3头
+func NewTracer(sampleRate float64, b3 bool, exporter Exporter) *Tracer {
+	if sampleRate < 0 {
+		sampleRate = 0
+	}
+	if sampleRate > 1 {
+		sampleRate = 1
+	}
+	return &Tracer{
+		sampleRate: sampleRate,
+		b3:         b3,
+		exporter:   exporter,
+	}
+}
+
+//SetTracer 设置全局tracer，nil表示关闭链路追踪，旧的exporter会被关闭
+func SetTracer(t *Tracer) {
+	old := GetTracer()
+	current.Store(tracerHolder{tracer: t})
+	if old != nil && old != t && old.exporter != nil {
+		old.exporter.Close()
+	}
+}
+
+//GetTracer 获取全局tracer，未开启时返回nil
+func GetTracer() *Tracer {
+	v := current.Load()
+	if v == nil {
+		return nil
+	}
+	return v.(tracerHolder).tracer
+}
+
+//StartServerSpan 为进入网关的请求创建span，会沿用上游传递的trace信息；未开启时返回nil
+func StartServerSpan(name string, header http.Header) *Span {
+	t := GetTracer()
+	if t == nil {
+		return nil
+	}
+	parent, has := Extract(header, t.b3)
+	if !has {
+		return t.start(name, SpanKindServer, SpanContext{}, false)
+	}
+	return t.start(name, SpanKindServer, parent, true)
+}
+
+//StartSpan 创建子span，parent为nil时返回nil
+func StartSpan(parent *Span, name string, kind SpanKind) *Span {
+	if parent == nil {
+		return nil
+	}
+	return parent.tracer.start(name, kind, parent.context, true)
+}
+
+//InjectHeader 将span信息写入转发请求头
+func InjectHeader(span *Span, header http.Header) {
+	if span == nil {
+		return
+	}
+	Inject(header, span.context, span.tracer.b3)
+}
+
+func (t *Tracer) start(name string, kind SpanKind, parent SpanContext, hasParent bool) *Span {
+	span := &Span{
+		tracer:     t,
+		name:       name,
+		kind:       kind,
+		start:      time.Now(),
+		attributes: make(map[string]interface{}),
+	}
+	if hasParent {
+		span.parent = parent.SpanID
+		span.context = SpanContext{
+			TraceID:    parent.TraceID,
+			SpanID:     newSpanID(),
+			Sampled:    parent.Sampled,
+			TraceState: parent.TraceState,
+		}
+		return span
+	}
+	traceID := newTraceID()
+	span.context = SpanContext{
+		TraceID: traceID,
+		SpanID:  newSpanID(),
+		Sampled: t.sampleRate > 0 && traceIDRatio(traceID) < t.sampleRate,
+	}
+	return span
+}
+
+func (t *Tracer) export(span *Span) {
+	if t == nil || t.exporter == nil {
+		return
+	}
+	t.exporter.Export(span)
+}
+
+//Carrier 持有当前span的上下文，如goku-node/common.Context
+type Carrier interface {
+	Span() *Span
+}
+
+//FromContext 从上下文中取出span，上下文不支持时返回nil
+func FromContext(ctx interface{}) *Span {
+	if c, ok := ctx.(Carrier); ok {
+		return c.Span()
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/ksitigarbha"
+)
+
+//ModuleNameSpace 模块空间名称
+const ModuleNameSpace = "diting.tracing"
+const moduleName = "Tracing"
+const desc = "链路追踪(W3C Trace Context，通过OTLP/HTTP上报)"
+const content = `[
+        {
+            "type": "line",
+            "label":"Collector地址",
+            "descript":"(OTLP/HTTP，如 http://127.0.0.1:4318/v1/traces)",
+            "items":[
+                {
+                    "type":"text",
+                    "name":"endpoint",
+                    "placeholder":"http://127.0.0.1:4318/v1/traces",
+                    "required":true,
+                    "pattern":""
+                }
+            ]
+        },
+        {
+            "type": "line",
+            "label":"采样率",
+            "descript":"(0~1，上游已带trace信息时沿用上游的采样结果)",
+            "items":[
+                {
+                    "type":"text",
+                    "name":"sampleRate",
+                    "placeholder":"1",
+                    "required":true,
+                    "pattern":"^(0(\\.\\d+)?|1(\\.0+)?)$"
+                }
+            ]
+        },
+        {
+            "type": "line",
+            "label":"传播协议",
+            "descript":"(w3c 或 w3c,b3)",
+            "items":[
+                {
+                    "type":"text",
+                    "name":"propagation",
+                    "placeholder":"w3c",
+                    "required":false,
+                    "pattern":""
+                }
+            ]
+        },
+        {
+            "type": "line",
+            "label":"服务名",
+            "descript":"",
+            "items":[
+                {
+                    "type":"text",
+                    "name":"serviceName",
+                    "placeholder":"goku-node",
+                    "required":false,
+                    "pattern":""
+                }
+            ]
+        }
+    ]`
+
+//DefaultServiceName 默认服务名
+const DefaultServiceName = "goku-node"
+
+var (
+	mode []ksitigarbha.Model
+)
+
+func init() {
+	err := json.Unmarshal([]byte(content), &mode)
+	if err != nil {
+		panic("init tracing config error:" + err.Error())
+	}
+}
+
+//TracingModule 配置
+type TracingModule struct {
+}
+
+//TracingConfig tracingConfig
+type TracingConfig struct {
+	Endpoint    string `json:"endpoint"`
+	SampleRate  string `json:"sampleRate"`
+	Propagation string `json:"propagation"`
+	ServiceName string `json:"serviceName"`
+}
+
+//Rate 采样率
+func (c *TracingConfig) Rate() float64 {
+	rate, _ := strconv.ParseFloat(c.SampleRate, 64)
+	return rate
+}
+
+//B3 是否同时使用B3传播
+func (c *TracingConfig) B3() bool {
+	for _, p := range strings.Split(c.Propagation, ",") {
+		if strings.EqualFold(strings.TrimSpace(p), "b3") {
+			return true
+		}
+	}
+	return false
+}
+
+//GetModel getModel
+func (c *TracingModule) GetModel() []ksitigarbha.Model {
+	return mode
+}
+
+//GetDesc getDesc
+func (c *TracingModule) GetDesc() string {
+	return desc
+}
+
+//GetName getName
+func (c *TracingModule) GetName() string {
+	return moduleName
+}
+
+//GetNameSpace getNameSpace
+func (c *TracingModule) GetNameSpace() string {
+	return ModuleNameSpace
+}
+
+//GetDefaultConfig getDefaultConfig
+func (c *TracingModule) GetDefaultConfig() interface{} {
+	return &TracingConfig{
+		Endpoint:    "",
+		SampleRate:  "1",
+		Propagation: "w3c",
+		ServiceName: DefaultServiceName,
+	}
+}
+
+//Encode encode
+func (c *TracingModule) Encode(v interface{}) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	if vm, ok := v.(*TracingConfig); ok {
+		d, _ := json.Marshal(vm)
+		return string(d), nil
+	}
+
+	return "", errors.New("illegal config")
+}
+
+//Decode decode
+func Decode(config string) (*TracingConfig, error) {
+	mc := new(TracingConfig)
+	err := json.Unmarshal([]byte(config), &mc)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(mc.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.New("invalid endpoint")
+	}
+	if mc.SampleRate == "" {
+		mc.SampleRate = "1"
+	}
+	rate, err := strconv.ParseFloat(mc.SampleRate, 64)
+	if err != nil || rate < 0 || rate > 1 {
+		return nil, errors.New("invalid sampleRate")
+	}
+	for _, p := range strings.Split(mc.Propagation, ",") {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p != "" && p != "w3c" && p != "b3" {
+			return nil, errors.New("invalid propagation")
+		}
+	}
+	if mc.ServiceName == "" {
+		mc.ServiceName = DefaultServiceName
+	}
+	return mc, nil
+}
+
+//Decode decode
+func (c *TracingModule) Decode(config string) (interface{}, error) {
+	return Decode(config)
+}
+
+//Register 模板注册
+func Register() {
+	ksitigarbha.Register(moduleName, new(TracingModule))
+}
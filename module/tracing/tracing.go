@@ -0,0 +1,76 @@
+package tracing
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/eolinker/goku-api-gateway/diting"
+	goku_trace "github.com/eolinker/goku-api-gateway/goku-trace"
+	"github.com/eolinker/goku-api-gateway/module"
+	"github.com/eolinker/goku-api-gateway/module/tracing/config"
+)
+
+var (
+	errNotMetrics = errors.New("tracing module does not provide metrics")
+)
+
+//Constructor constructor
+type Constructor struct {
+	locker sync.Mutex
+	conf   config.TracingConfig
+}
+
+//Register register
+func Register() {
+	config.Register()
+	module.Register(config.ModuleNameSpace, false)
+	diting.Register(config.ModuleNameSpace, new(Constructor))
+}
+
+//Namespace nameSpace
+func (c *Constructor) Namespace() string {
+	return config.ModuleNameSpace
+}
+
+//Create 根据配置开启链路追踪，配置未变化时沿用原exporter
+func (c *Constructor) Create(conf string) (diting.Factory, error) {
+	confV, err := config.Decode(conf)
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	c.locker.Lock()
+	defer c.locker.Unlock()
+	if c.conf != *confV || goku_trace.GetTracer() == nil {
+		exporter := goku_trace.NewOTLPExporter(confV.Endpoint, confV.ServiceName)
+		goku_trace.SetTracer(goku_trace.NewTracer(confV.Rate(), confV.B3(), exporter))
+		c.conf = *confV
+	}
+	module.Open(config.ModuleNameSpace)
+	return c, nil
+}
+
+//Close 关闭链路追踪
+func (c *Constructor) Close() {
+	c.locker.Lock()
+	module.Close(config.ModuleNameSpace)
+	c.conf = config.TracingConfig{}
+	goku_trace.SetTracer(nil)
+	c.locker.Unlock()
+}
+
+//NewCounter 链路追踪模块不提供指标
+func (c *Constructor) NewCounter(opt *diting.CounterOpts) (diting.Counter, error) {
+	return nil, errNotMetrics
+}
+
+//NewHistogram 链路追踪模块不提供指标
+func (c *Constructor) NewHistogram(opt *diting.HistogramOpts) (diting.Histogram, error) {
+	return nil, errNotMetrics
+}
+
+//NewGauge 链路追踪模块不提供指标
+func (c *Constructor) NewGauge(opt *diting.GaugeOpts) (diting.Gauge, error) {
+	return nil, errNotMetrics
+}
@@ -0,0 +1,116 @@
+package gateway
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	access_log "github.com/eolinker/goku-api-gateway/goku-node/access-log"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	fields "github.com/eolinker/goku-api-gateway/server/access-field"
+)
+
+// setAccessFields 填充转发耗时、请求长度、调用方等域，以及配置中的动态域
+func setAccessFields(ctx *common.Context, req *http.Request, w http.ResponseWriter) {
+	ctx.LogFields[fields.RequestLength] = requestLength(ctx, req)
+	if traceID := ctx.Span().TraceID(); traceID != "" {
+		ctx.LogFields[fields.TraceID] = traceID
+	}
+	if consumer, has := ctx.GetCache(common.CacheConsumer); has {
+		ctx.LogFields[fields.Consumer] = fmt.Sprint(consumer)
+	}
+
+	if upstreams := ctx.UpstreamTimes(); len(upstreams) > 0 {
+		connect := make([]string, 0, len(upstreams))
+		response := make([]string, 0, len(upstreams))
+		for _, u := range upstreams {
+			connect = append(connect, formatMillisecond(u.Connect))
+			response = append(response, formatMillisecond(u.Response))
+		}
+		ctx.LogFields[fields.UpstreamConnectTime] = strings.Join(connect, ",")
+		ctx.LogFields[fields.UpstreamResponseTime] = strings.Join(response, ",")
+	}
+
+	plugins := ctx.PluginTimes()
+	if len(plugins) > 0 {
+		times := make([]string, 0, len(plugins))
+		for _, p := range plugins {
+			times = append(times, p.Name+":"+formatMillisecond(p.Duration))
+		}
+		ctx.LogFields[fields.PluginTime] = strings.Join(times, ",")
+	}
+
+	for _, key := range access_log.DynamicFields() {
+		prefix, name, _ := key.Dynamic()
+		var value string
+		has := false
+		switch prefix {
+		case fields.HTTPHeaderPrefix:
+			value, has = headerValue(req.Header, name)
+		case fields.SentHTTPHeaderPrefix:
+			value, has = headerValue(w.Header(), name)
+		case fields.CookiePrefix:
+			if cookie, err := req.Cookie(name); err == nil {
+				value, has = cookie.Value, true
+			}
+		case fields.ArgPrefix:
+			if values, ok := req.URL.Query()[name]; ok && len(values) > 0 {
+				value, has = values[0], true
+			}
+		case fields.PluginTimePrefix:
+			var d time.Duration
+			for _, p := range plugins {
+				if p.Name == name {
+					d += p.Duration
+					has = true
+				}
+			}
+			value = formatMillisecond(d)
+		}
+		if has {
+			ctx.LogFields[key.Key()] = value
+		}
+	}
+}
+
+// headerValue 域名称中的"_"对应header中的"-"
+func headerValue(header http.Header, name string) (string, bool) {
+	values, has := header[http.CanonicalHeaderKey(strings.ReplaceAll(name, "_", "-"))]
+	if !has || len(values) == 0 {
+		return "", false
+	}
+	return strings.Join(values, ","), true
+}
+
+// requestLength 请求行、请求头及请求体的长度
+func requestLength(ctx *common.Context, req *http.Request) int {
+	length := len(req.Method) + len(req.RequestURI) + len(req.Proto) + 4
+	for key, values := range req.Header {
+		for _, value := range values {
+			length += len(key) + len(value) + 4
+		}
+	}
+	length += 2
+	if body, err := ctx.RequestOrg.RawBody(); err == nil {
+		length += len(body)
+	}
+	return length
+}
+
+func formatMillisecond(d time.Duration) string {
+	return fmt.Sprintf("%.3f", float64(d)/float64(time.Millisecond))
+}
+
+// upstreamStatus 返回本次转发的负载，以及负载是否不可用：未取得响应或响应为502~504
+func upstreamStatus(ctx *common.Context) (string, bool) {
+	balance, _ := ctx.LogFields[fields.Balance].(string)
+	if balance == "" {
+		return "", false
+	}
+	status, has := ctx.LogFields[fields.ProxyStatusCode].(int)
+	if !has {
+		return balance, true
+	}
+	return balance, status >= 502 && status <= 504
+}
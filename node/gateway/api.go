@@ -51,10 +51,7 @@ func (h *API) Router(ctx *common.Context) {
 func (h *API) accessFlow(ctx *common.Context) bool {
 	for _, handler := range h.pluginAccess {
 		flag, err := handler.Execute(ctx)
-		if err != nil {
-			fmt.Println(err)
-		}
-		if flag == false && handler.IsStop() {
+		if handler.IsBreak(flag, err) {
 
 			return false
 		}
@@ -63,7 +60,7 @@ func (h *API) accessFlow(ctx *common.Context) bool {
 }
 
 func (h *API) accessGlobalFlow(ctx *common.Context) {
-	// 全局插件不中断
+	// 全局插件不中断，错误由执行器记录
 	for _, handler := range h.pluginAccessGlobal {
 		_, _ = handler.Execute(ctx)
 	}
@@ -71,9 +68,8 @@ func (h *API) accessGlobalFlow(ctx *common.Context) {
 
 func (h *API) proxyFlow(ctx *common.Context) bool {
 	for _, handler := range h.pluginProxies {
-		flag, _ := handler.Execute(ctx)
-
-		if flag == false && handler.IsStop() {
+		flag, err := handler.Execute(ctx)
+		if handler.IsBreak(flag, err) {
 
 			return false
 		}
@@ -82,9 +78,8 @@ func (h *API) proxyFlow(ctx *common.Context) bool {
 }
 
 func (h *API) proxyGlobalFlow(ctx *common.Context) {
-	// 全局插件不中断
+	// 全局插件不中断，错误由执行器记录
 	for _, handler := range h.pluginProxiesGlobal {
 		_, _ = handler.Execute(ctx)
-
 	}
 }
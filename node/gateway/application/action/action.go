@@ -3,6 +3,7 @@ package action
 import (
 	"strings"
 
+	"github.com/eolinker/goku-api-gateway/common/jmespath"
 	"github.com/eolinker/goku-api-gateway/config"
 	"github.com/eolinker/goku-api-gateway/node/gateway/response"
 )
@@ -18,6 +19,8 @@ const (
 	Black = "black"
 	//White white
 	White = "white"
+	//Set 以JMESPath表达式的结果设置字段
+	Set = "set"
 )
 
 //Filter 过滤器
@@ -52,6 +55,15 @@ func GenByconfig(ac *config.ActionConfig) Filter {
 			target: ac.Target,
 			source: ac.Original,
 		}
+	case Set:
+		expr, err := jmespath.Compile(ac.Original)
+		if err != nil {
+			return nil
+		}
+		return &SetFilter{
+			expr:   expr,
+			target: ac.Target,
+		}
 	}
 	return nil
 }
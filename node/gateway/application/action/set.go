@@ -0,0 +1,21 @@
+package action
+
+import (
+	"github.com/eolinker/goku-api-gateway/common/jmespath"
+	"github.com/eolinker/goku-api-gateway/node/gateway/response"
+)
+
+//SetFilter 将表达式在当前数据上的结果设置到目标字段
+type SetFilter struct {
+	expr   *jmespath.Expression
+	target string
+}
+
+//Do do
+func (f *SetFilter) Do(value *response.Response) {
+	v, err := f.expr.Search(value.Data)
+	if err != nil {
+		return
+	}
+	value.SetValue(f.target, v)
+}
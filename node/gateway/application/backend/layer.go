@@ -3,17 +3,24 @@ package backend
 import (
 	"compress/gzip"
 	"context"
+	"encoding/json"
 	"io/ioutil"
+	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
+	"github.com/eolinker/goku-api-gateway/common/jmespath"
 	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
 	"github.com/eolinker/goku-api-gateway/goku-node/common"
 	"github.com/eolinker/goku-api-gateway/goku-service/application"
 	"github.com/eolinker/goku-api-gateway/goku-service/balance"
 	"github.com/eolinker/goku-api-gateway/node/gateway/application/action"
 	"github.com/eolinker/goku-api-gateway/node/gateway/application/interpreter"
+	"github.com/eolinker/goku-api-gateway/node/gateway/application/mirror"
 	"github.com/eolinker/goku-api-gateway/node/gateway/response"
+	"github.com/eolinker/goku-api-gateway/node/gateway/soap"
 )
 
 //Layer layer
@@ -28,12 +35,22 @@ type Layer struct {
 	Path   interpreter.Interpreter
 	Decode response.DecodeHandle
 
-	Body    interpreter.Interpreter
+	Body interpreter.Interpreter
+	//Headers 设置到请求的头部，值支持模版变量
+	Headers map[string]interpreter.Interpreter
 	Encode  string
 	Target  string
-	Group   []string
-	Retry   int
-	TimeOut time.Duration
+	//TargetExpr Target为JMESPath表达式时，以表达式结果作为返回数据
+	TargetExpr *jmespath.Expression
+	Group      []string
+	//GroupExpr Group为JMESPath表达式时，以表达式结果作为分组后的返回数据
+	GroupExpr *jmespath.Expression
+	Retry     *application.RetryPolicy
+	TimeOut   time.Duration
+	//SOAP 不为空时以SOAP协议调用，请求体为JSON参数
+	SOAP *soap.Client
+	//Mirror 不为空时按比例将请求镜像到影子负载
+	Mirror *mirror.Mirror
 }
 
 //Send send
@@ -45,16 +62,31 @@ func (b *Layer) Send(deadline context.Context, ctx *common.Context, variables *i
 		method = ctx.ProxyRequest.Method
 	}
 	header:= ctx.ProxyRequest.Headers()
-	if b.Encode == "json"{
-		header.Set("content-type","application/json; charset=utf-8")
-	} else if b.Encode == "xml" {
-		header.Set("content-type","application/xml; charset=utf-8")
+	if b.SOAP != nil {
+		if strings.TrimSpace(body) == "" {
+			body = soapParams(variables)
+		}
+		envelope, err := b.SOAP.Envelope([]byte(body))
+		if err != nil {
+			return nil, err
+		}
+		method, body = http.MethodPost, string(envelope)
+		b.SOAP.SetHeader(header)
+	} else if response.HasEncoder(b.Encode) {
+		encoder := response.GetEncoder(b.Encode)
+		header.Set("content-type", encoder.ContentType())
+		body = encodeBody(encoder, b.Encode, body)
+	}
+	for name, value := range b.Headers {
+		header.Set(name, value.Execution(variables))
 	}
 
+	shadow := b.Mirror.Prepare(ctx, b.Protocol, method, path, nil, header, []byte(body), b.TimeOut)
 	r, finalTargetServer, retryTargetServers, err := b.Balance.Send(ctx, b.Protocol, method, path, nil, header, []byte(body), b.TimeOut, b.Retry)
 
 
 	if err != nil {
+		shadow.Send(ctx, 0, nil)
 		return nil, err
 	}
 	backendResponse := &BackendResponse{
@@ -76,30 +108,37 @@ func (b *Layer) Send(deadline context.Context, ctx *common.Context, variables *i
 
 	backendResponse.BodyOrg, err = ioutil.ReadAll(bd)
 	if err != nil {
+		shadow.Send(ctx, 0, nil)
 		return backendResponse, nil
 	}
+	shadow.Send(ctx, r.StatusCode, backendResponse.BodyOrg)
 
-	rp, e := response.Decode(backendResponse.BodyOrg, b.Decode)
+	var rp *response.Response
+	var e error
+	if b.SOAP != nil {
+		var isFault bool
+		rp = new(response.Response)
+		rp.Data, isFault, e = soap.Decode(backendResponse.BodyOrg)
+		if e == nil && isFault {
+			return nil, &soap.FaultError{StatusCode: r.StatusCode, Fault: rp.Data}
+		}
+	} else {
+		rp, e = response.Decode(backendResponse.BodyOrg, b.Decode)
+	}
 	if e != nil {
 		backendResponse.Body = nil
 		return nil, e
 	}
 
 	b.Filter.Do(rp)
-
-	if b.Target != "" {
-		rp.ReTarget(b.Target)
-	}
-	if len(b.Group) > 0 {
-		rp.Group(b.Group)
-	}
+	b.shape(rp)
 
 	backendResponse.Body = rp.Data
 	return backendResponse, nil
 }
 
-//NewLayer newLayer
-func NewLayer(step *config.APIStepConfig) *Layer {
+//NewLayer newLayer，retry、hedge为接口的重试策略及对冲请求
+func NewLayer(step *config.APIStepConfig, retry *config.RetryConfig, hedge *config.HedgeConfig) *Layer {
 	var b = &Layer{
 		BalanceName: step.Balance,
 		Balance:     nil,
@@ -114,13 +153,122 @@ func NewLayer(step *config.APIStepConfig) *Layer {
 		Group:       nil,
 		TimeOut:     time.Duration(step.TimeOut) * time.Millisecond,
 		Body:        interpreter.Gen(step.Body, step.Encode),
-		Retry:       step.Retry,
+		Retry:       application.NewRetryPolicy(step.Retry, retry, hedge),
+		SOAP:        soap.New(step.SOAP),
 	}
-	if step.Group != "" {
+	if jmespath.IsExpression(step.Group) {
+		b.GroupExpr = compile("group", step.Group)
+	} else if step.Group != "" {
 		b.Group = strings.Split(step.Group, ".")
 	}
+	if jmespath.IsExpression(step.Target) {
+		b.TargetExpr = compile("target", step.Target)
+	}
+	b.Headers = genHeaders(step.Headers)
 
 	b.Balance, b.HasBalance = balance.GetByName(b.BalanceName)
 
 	return b
 }
+
+// shape 按Target取出返回数据，再按Group分组
+func (b *Layer) shape(rp *response.Response) {
+	if b.TargetExpr != nil {
+		rp.Data = search(b.TargetExpr, rp.Data)
+	} else if b.Target != "" {
+		rp.ReTarget(b.Target)
+	}
+	if b.GroupExpr != nil {
+		rp.Data = search(b.GroupExpr, rp.Data)
+	} else if len(b.Group) > 0 {
+		rp.Group(b.Group)
+	}
+}
+
+// search 执行表达式，出错或结果为null时返回空对象
+func search(expr *jmespath.Expression, data interface{}) interface{} {
+	v, err := expr.Search(data)
+	if err != nil || v == nil {
+		return make(map[string]interface{})
+	}
+	return v
+}
+
+// compile 编译步骤中的表达式，出错时记录日志并忽略该表达式
+func compile(field, expr string) *jmespath.Expression {
+	e, err := jmespath.Compile(expr)
+	if err != nil {
+		log.Warn("invalid ", field, " ", expr, ":", err)
+		return nil
+	}
+	return e
+}
+
+// encodeBody 模版生成的JSON请求体转换为目标编码，无法解析为JSON时原样发送
+func encodeBody(encoder response.Encoder, encode string, body string) string {
+	if encode = strings.ToLower(encode); encode == response.JSON || encode == response.String {
+		return body
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(body), &v); err != nil {
+		return body
+	}
+	data, err := encoder.Encode(v, []byte(body))
+	if err != nil {
+		log.Warn("encode body error:", err)
+		return body
+	}
+	return string(data)
+}
+
+// soapParams 未配置请求体模版时，以restful参数、query参数及请求体中的字段作为SOAP参数
+func soapParams(variables *interpreter.Variables) string {
+	params := make(map[string]interface{})
+	for k, v := range variables.Restful {
+		params[k] = v
+	}
+	for k, v := range variables.Query {
+		if len(v) == 1 {
+			params[k] = v[0]
+		} else {
+			params[k] = v
+		}
+	}
+	if len(variables.Bodes) > 0 {
+		switch body := variables.Bodes[0].(type) {
+		case map[string]interface{}:
+			for k, v := range body {
+				params[k] = v
+			}
+		case url.Values:
+			for k := range body {
+				params[k] = body.Get(k)
+			}
+		}
+	}
+	data, _ := json.Marshal(params)
+	return string(data)
+}
+
+// genHeaders 解析"名称: 值"格式的头部配置
+func genHeaders(headers []string) map[string]interpreter.Interpreter {
+	if len(headers) == 0 {
+		return nil
+	}
+	m := make(map[string]interpreter.Interpreter, len(headers))
+	for _, h := range headers {
+		i := strings.Index(h, ":")
+		if i < 1 {
+			continue
+		}
+		name := strings.TrimSpace(h[:i])
+		value := strings.TrimSpace(h[i+1:])
+		exe, err := interpreter.Parse(value)
+		if err != nil {
+			log.Warn("invalid header ", name, ":", err)
+			continue
+		}
+		m[name] = exe
+	}
+	return m
+}
@@ -0,0 +1,79 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/diting"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	"github.com/eolinker/goku-api-gateway/node/gateway/application/interpreter"
+	"github.com/eolinker/goku-api-gateway/node/gateway/response"
+	"github.com/eolinker/goku-api-gateway/node/gateway/soap"
+	"github.com/eolinker/goku-api-gateway/node/monitor"
+)
+
+type discardObserver struct{}
+
+func (discardObserver) Observe(value float64, labels diting.Labels) {}
+
+func TestLayerSOAPFault(t *testing.T) {
+	proxyMonitor := monitor.ProxyMonitor
+	monitor.ProxyMonitor = discardObserver{}
+	defer func() {
+		monitor.ProxyMonitor = proxyMonitor
+	}()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><soap:Fault><faultcode>soap:Server</faultcode><faultstring>user not found</faultstring></soap:Fault></soap:Body></soap:Envelope>`))
+	}))
+	defer server.Close()
+
+	layer := NewLayer(&config.APIStepConfig{
+		Proto:   "http",
+		Balance: strings.TrimPrefix(server.URL, "http://"),
+		Method:  "POST",
+		Path:    "/user",
+		SOAP:    &config.SOAPConfig{Version: "1.1", Action: "urn:GetUser", Element: "GetUser", Namespace: "urn:user"},
+	}, nil, nil)
+
+	ctx := common.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), "1", httptest.NewRecorder())
+	variables := interpreter.NewVariables(nil, nil, make(http.Header), nil, nil, nil, 1)
+	r, err := layer.Send(context.Background(), ctx, variables)
+	fault, ok := err.(*soap.FaultError)
+	if !ok || r != nil {
+		t.Fatalf("expected soap fault, got %v %v", r, err)
+	}
+	if fault.StatusCode != http.StatusInternalServerError || !strings.Contains(fault.Error(), "user not found") {
+		t.Errorf("fault: got %d %s", fault.StatusCode, fault.Error())
+	}
+}
+
+func TestLayerShape(t *testing.T) {
+	data := func() *response.Response {
+		return &response.Response{Data: map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{"id": 1.0, "status": "ok"},
+				map[string]interface{}{"id": 2.0, "status": "failed"},
+			},
+		}}
+	}
+
+	rp := data()
+	NewLayer(&config.APIStepConfig{Target: "items[?status=='ok']", Group: "{ids: [*].id, total: length(@)}"}, nil, nil).shape(rp)
+	if got, _ := json.Marshal(rp.Data); string(got) != `{"ids":[1],"total":1}` {
+		t.Errorf("group expression: got %s", got)
+	}
+
+	rp = data()
+	NewLayer(&config.APIStepConfig{Target: "items", Group: "a.b"}, nil, nil).shape(rp)
+	if got, _ := json.Marshal(rp.Data); !strings.HasPrefix(string(got), `{"a":{"b":[`) {
+		t.Errorf("group path: got %s", got)
+	}
+}
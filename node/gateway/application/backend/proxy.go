@@ -4,6 +4,7 @@ import (
 	"compress/gzip"
 	"fmt"
 	"io/ioutil"
+	"strconv"
 	"strings"
 
 	"github.com/eolinker/goku-api-gateway/config"
@@ -14,6 +15,7 @@ import (
 	"time"
 
 	"github.com/eolinker/goku-api-gateway/node/gateway/application/interpreter"
+	"github.com/eolinker/goku-api-gateway/node/gateway/application/mirror"
 	"github.com/eolinker/goku-api-gateway/node/gateway/response"
 )
 
@@ -32,12 +34,14 @@ type Proxy struct {
 
 	RequestPath string
 
-	Retry   int
+	Retry   *application.RetryPolicy
 	TimeOut time.Duration
+	//Mirror 不为空时按比例将请求镜像到影子负载
+	Mirror *mirror.Mirror
 }
 
-//NewProxyBackendTarget 创建新的转发后端目标
-func NewProxyBackendTarget(step *config.APIStepConfig, requestPath string, balanceTarget string) *Proxy {
+//NewProxyBackendTarget 创建新的转发后端目标，retry、hedge为接口的重试策略及对冲请求
+func NewProxyBackendTarget(step *config.APIStepConfig, requestPath string, balanceTarget string, retry *config.RetryConfig, hedge *config.HedgeConfig) *Proxy {
 	b := &Proxy{
 		BalanceName: balanceTarget,
 		Protocol:    step.Proto,
@@ -48,7 +52,7 @@ func NewProxyBackendTarget(step *config.APIStepConfig, requestPath string, balan
 		Decode:      response.GetDecoder(step.Decode),
 
 		TimeOut: time.Duration(step.TimeOut) * time.Millisecond,
-		Retry:   step.Retry,
+		Retry:   application.NewRetryPolicy(step.Retry, retry, hedge),
 	}
 
 	b.Balance, b.HasBalance = balance.GetByName(balanceTarget)
@@ -81,6 +85,7 @@ func (b *Proxy) Send(ctx *common.Context, variables *interpreter.Variables) (*Ba
 	if method == "FOLLOW" {
 		method = ctx.ProxyRequest.Method
 	}
+	shadow := b.Mirror.Prepare(ctx, b.Protocol, method, path, ctx.ProxyRequest.Querys(), ctx.ProxyRequest.Headers(), variables.Org, b.TimeOut)
 	r, finalTargetServer, retryTargetServers, err := b.Balance.Send(ctx, b.Protocol, method, path, ctx.ProxyRequest.Querys(), ctx.ProxyRequest.Headers(), variables.Org, b.TimeOut, b.Retry)
 
 	backendResponse := &BackendResponse{
@@ -97,9 +102,11 @@ func (b *Proxy) Send(ctx *common.Context, variables *interpreter.Variables) (*Ba
 	}
 	if err != nil {
 		backendResponse.StatusCode, backendResponse.Status = 503, "503"
+		shadow.Send(ctx, 0, nil)
 		return backendResponse, err
 	}
 	backendResponse.Header = r.Header
+	backendResponse.StatusCode, backendResponse.Status = r.StatusCode, strconv.Itoa(r.StatusCode)
 	defer r.Body.Close()
 	bd := r.Body
 	if r.Header.Get("Content-Encoding") == "gzip" {
@@ -108,8 +115,10 @@ func (b *Proxy) Send(ctx *common.Context, variables *interpreter.Variables) (*Ba
 	}
 	backendResponse.BodyOrg, err = ioutil.ReadAll(bd)
 	if err != nil {
+		shadow.Send(ctx, 0, nil)
 		return backendResponse, nil
 	}
+	shadow.Send(ctx, backendResponse.StatusCode, backendResponse.BodyOrg)
 
 	if b.Decode != nil {
 		rp, e := response.Decode(backendResponse.BodyOrg, b.Decode)
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"strconv"
 	"strings"
+
+	"github.com/eolinker/goku-api-gateway/common/jmespath"
 )
 
 //ReaderCreateFunc readerCreateFunc
@@ -50,6 +52,17 @@ func init() {
 			index = i
 		}
 
+		if jmespath.IsExpression(string(body)) {
+			expr, err := jmespath.Compile(string(body))
+			if err != nil {
+				return nil, err
+			}
+			return &_ExpressionReader{
+				Index: index,
+				Expr:  expr,
+			}, nil
+		}
+
 		pathData := bytes.Split(body, pathSplitSeq)
 
 		path := make([]string, 0, len(pathData))
@@ -120,7 +133,7 @@ func genReader(line []byte) (Reader, error) {
 
 	kindex := bytes.IndexAny(line, ".")
 
-	if kindex == -1 {
+	if kindex < 4 {
 		return nil, GrammarError(line)
 	}
 
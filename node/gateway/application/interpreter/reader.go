@@ -5,6 +5,8 @@ import (
 	"net/url"
 	"reflect"
 	"strconv"
+
+	"github.com/eolinker/goku-api-gateway/common/jmespath"
 )
 
 //Reader reder
@@ -89,6 +91,31 @@ func find(node *reflect.Value, path []string) string {
 	return ""
 }
 
+//_ExpressionReader 以JMESPath表达式读取body，如{{body1.items[?status=='ok'].id}}
+type _ExpressionReader struct {
+	Index int
+	Expr  *jmespath.Expression
+}
+
+func (r *_ExpressionReader) Read(variables *Variables) string {
+	if len(variables.Bodes) <= r.Index {
+		return ""
+	}
+	data := variables.Bodes[r.Index]
+	if form, ok := data.(url.Values); ok {
+		m := make(map[string]interface{}, len(form))
+		for k := range form {
+			m[k] = form.Get(k)
+		}
+		data = m
+	}
+	v, err := r.Expr.Search(data)
+	if err != nil {
+		return ""
+	}
+	return jmespath.ToString(v)
+}
+
 type _HeaderReader struct {
 	Index int
 	Key   string
@@ -3,6 +3,8 @@ package application
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/eolinker/goku-api-gateway/config"
@@ -10,7 +12,12 @@ import (
 	"github.com/eolinker/goku-api-gateway/goku-node/common"
 	"github.com/eolinker/goku-api-gateway/node/gateway/application/backend"
 	"github.com/eolinker/goku-api-gateway/node/gateway/application/interpreter"
+	"github.com/eolinker/goku-api-gateway/node/gateway/application/mirror"
+	"github.com/eolinker/goku-api-gateway/node/gateway/application/traffic"
+	"github.com/eolinker/goku-api-gateway/node/gateway/application/transform"
 	"github.com/eolinker/goku-api-gateway/node/gateway/response"
+	"github.com/eolinker/goku-api-gateway/node/gateway/soap"
+	access_field "github.com/eolinker/goku-api-gateway/server/access-field"
 )
 
 //LayerApplication layer application
@@ -18,6 +25,10 @@ type LayerApplication struct {
 	output    response.Encoder
 	backsides []*backend.Layer
 	static    *staticeResponse
+	transform *transform.Transformer
+	traffic   *traffic.Policy
+	//splits 各拆分标签对应的链路，参与拆分的步骤替换为拆分项的负载
+	splits map[string][]*backend.Layer
 
 	timeOut time.Duration
 }
@@ -25,11 +36,21 @@ type LayerApplication struct {
 //Execute execute
 func (app *LayerApplication) Execute(ctx *common.Context) {
 
+	backsides := app.backsides
+	if app.traffic != nil {
+		ctx.LogFields[access_field.TrafficSplit] = config.TrafficDefaultLabel
+		if split := app.traffic.Select(ctx); split != nil {
+			backsides = app.splits[split.Label]
+			ctx.LogFields[access_field.TrafficSplit] = split.Label
+		}
+	}
+
+	app.transform.Request(ctx)
 	orgBody, _ := ctx.ProxyRequest.RawBody()
 
 	bodyObj, _ := ctx.ProxyRequest.BodyInterface()
 
-	variables := interpreter.NewVariables(orgBody, bodyObj, ctx.ProxyRequest.Headers(), ctx.ProxyRequest.Cookies(), ctx.RestfulParam, ctx.ProxyRequest.Querys(), len(app.backsides))
+	variables := interpreter.NewVariables(orgBody, bodyObj, ctx.ProxyRequest.Headers(), ctx.ProxyRequest.Cookies(), ctx.RestfulParam, ctx.ProxyRequest.Querys(), len(backsides))
 
 	deadline := context.Background()
 	cancelFunc := context.CancelFunc(nil)
@@ -42,7 +63,7 @@ func (app *LayerApplication) Execute(ctx *common.Context) {
 
 	resC := make(chan int, 1)
 	errC := make(chan error, 1)
-	go app.do(deadline, variables, ctx, resC, errC)
+	go app.do(deadline, backsides, variables, ctx, resC, errC)
 
 	defer func() {
 		close(resC)
@@ -56,8 +77,12 @@ func (app *LayerApplication) Execute(ctx *common.Context) {
 		// 超时
 		return
 	case e := <-errC:
-		fmt.Println(e)
 		cancelFunc()
+		if fault, ok := e.(*soap.FaultError); ok {
+			app.writeFault(ctx, fault)
+			return
+		}
+		fmt.Println(e)
 		ctx.SetStatus(504, "504")
 		ctx.SetBody([]byte("[ERROR]Fail to get response after proxy!"))
 		//error
@@ -75,6 +100,9 @@ func (app *LayerApplication) Execute(ctx *common.Context) {
 		log.Warn("encode response error:", e)
 		return
 	}
+	if contentType := app.output.ContentType(); contentType != "" {
+		headers.Set("Content-Type", contentType)
+	}
 	//if headers.Get("Content-Encoding") == "gzip" {
 	//	var b bytes.Buffer
 	//	wb := gzip.NewWriter(&b)
@@ -82,13 +110,32 @@ func (app *LayerApplication) Execute(ctx *common.Context) {
 	//	wb.Flush()
 	//	body, _ = ioutil.ReadAll(&b)
 	//}
-	ctx.SetProxyResponseHandler(common.NewResponseReader(headers, 200, "200", body))
+	statusCode := app.transform.Response(variables, headers, 200)
+	ctx.SetProxyResponseHandler(common.NewResponseReader(headers, statusCode, strconv.Itoa(statusCode), body))
 
 }
-func (app *LayerApplication) do(ctxDeadline context.Context, variables *interpreter.Variables, ctx *common.Context, resC chan<- int, errC chan<- error) {
+// writeFault 步骤返回SOAP Fault时中断编排，以Fault作为响应，状态码不低于500
+func (app *LayerApplication) writeFault(ctx *common.Context, fault *soap.FaultError) {
+	body, e := app.output.Encode(fault.Fault, nil)
+	if e != nil {
+		log.Warn("encode soap fault error:", e)
+		return
+	}
+	headers := make(http.Header)
+	if contentType := app.output.ContentType(); contentType != "" {
+		headers.Set("Content-Type", contentType)
+	}
+	statusCode := fault.StatusCode
+	if statusCode < 500 {
+		statusCode = 500
+	}
+	ctx.SetProxyResponseHandler(common.NewResponseReader(headers, statusCode, strconv.Itoa(statusCode), body))
+}
+
+func (app *LayerApplication) do(ctxDeadline context.Context, backsides []*backend.Layer, variables *interpreter.Variables, ctx *common.Context, resC chan<- int, errC chan<- error) {
 
-	l := len(app.backsides)
-	for i, b := range app.backsides {
+	l := len(backsides)
+	for i, b := range backsides {
 
 		if deadline, ok := ctxDeadline.Deadline(); ok {
 			if time.Now().After(deadline) {
@@ -130,12 +177,15 @@ func NewLayerApplication(apiContent *config.APIContent) *LayerApplication {
 		output:    response.GetEncoder(apiContent.OutPutEncoder),
 		backsides: make([]*backend.Layer, 0, len(apiContent.Steps)),
 		static:    nil,
+		transform: transform.New(apiContent.Transform),
 		timeOut:   time.Duration(apiContent.TimeOutTotal) * time.Millisecond,
 	}
 
 	for _, step := range apiContent.Steps {
-		app.backsides = append(app.backsides, backend.NewLayer(step))
+		app.backsides = append(app.backsides, backend.NewLayer(step, apiContent.RetryPolicy, apiContent.Hedge))
 	}
+	app.genSplits(apiContent)
+	app.setMirror(mirror.New(apiContent.Mirror))
 
 	if apiContent.StaticResponse != "" {
 		staticResponseStrategy := config.Parse(apiContent.StaticResponseStrategy)
@@ -143,3 +193,47 @@ func NewLayerApplication(apiContent *config.APIContent) *LayerApplication {
 	}
 	return app
 }
+
+// genSplits 为每个拆分项生成链路，没有步骤参与拆分时不启用流量拆分
+func (app *LayerApplication) genSplits(apiContent *config.APIContent) {
+	policy := traffic.New(apiContent.Traffic)
+	if policy == nil {
+		return
+	}
+	applied := false
+	splits := make(map[string][]*backend.Layer)
+	for _, split := range policy.Splits() {
+		backsides := make([]*backend.Layer, 0, len(app.backsides))
+		for i, step := range apiContent.Steps {
+			if !policy.Applies(step.Balance) {
+				backsides = append(backsides, app.backsides[i])
+				continue
+			}
+			applied = true
+			splitStep := *step
+			splitStep.Balance = split.Balance
+			backsides = append(backsides, backend.NewLayer(&splitStep, apiContent.RetryPolicy, apiContent.Hedge))
+		}
+		splits[split.Label] = backsides
+	}
+	if applied {
+		app.traffic = policy
+		app.splits = splits
+	}
+}
+
+// setMirror 为需要镜像的步骤设置流量镜像，包括各拆分项的链路
+func (app *LayerApplication) setMirror(m *mirror.Mirror) {
+	if m == nil {
+		return
+	}
+	layers := append([]*backend.Layer{}, app.backsides...)
+	for _, backsides := range app.splits {
+		layers = append(layers, backsides...)
+	}
+	for _, layer := range layers {
+		if m.Applies(layer.BalanceName) {
+			layer.Mirror = m
+		}
+	}
+}
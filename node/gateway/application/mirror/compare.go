@@ -0,0 +1,86 @@
+package mirror
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//Compare 比较原响应与影子响应，返回不一致的字段，状态码不一致时包含status
+// 两者均为JSON时逐字段比较，字段路径以"."分隔，数组下标作为路径的一级；否则整体比较响应体
+func Compare(statusCode int, primary []byte, shadowStatusCode int, shadow []byte, ignore []string) []string {
+	diff := make([]string, 0)
+	if statusCode != shadowStatusCode {
+		diff = append(diff, "status")
+	}
+	var a, b interface{}
+	if json.Unmarshal(primary, &a) != nil || json.Unmarshal(shadow, &b) != nil {
+		if !bytes.Equal(primary, shadow) {
+			diff = append(diff, "body")
+		}
+		return diff
+	}
+	left := make(map[string]interface{})
+	right := make(map[string]interface{})
+	flatten("", a, left)
+	flatten("", b, right)
+	fields := make([]string, 0)
+	for path, v := range left {
+		if w, has := right[path]; !has || !reflect.DeepEqual(v, w) {
+			fields = append(fields, path)
+		}
+	}
+	for path := range right {
+		if _, has := left[path]; !has {
+			fields = append(fields, path)
+		}
+	}
+	sort.Strings(fields)
+	for _, path := range fields {
+		if !ignored(path, ignore) {
+			diff = append(diff, path)
+		}
+	}
+	return diff
+}
+
+// flatten 将JSON展开为叶子路径，空对象及空数组作为叶子保留
+func flatten(prefix string, v interface{}, result map[string]interface{}) {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		if len(value) == 0 {
+			result[prefix] = value
+		}
+		for k, child := range value {
+			flatten(join(prefix, k), child, result)
+		}
+	case []interface{}:
+		if len(value) == 0 {
+			result[prefix] = value
+		}
+		for i, child := range value {
+			flatten(join(prefix, strconv.Itoa(i)), child, result)
+		}
+	default:
+		result[prefix] = value
+	}
+}
+
+func join(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func ignored(path string, ignore []string) bool {
+	for _, field := range ignore {
+		if path == field || strings.HasPrefix(path, field+".") {
+			return true
+		}
+	}
+	return false
+}
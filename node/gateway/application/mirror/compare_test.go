@@ -0,0 +1,28 @@
+package mirror
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	cases := []struct {
+		status, shadowStatus int
+		primary, shadow      string
+		ignore               []string
+		want                 []string
+	}{
+		{200, 200, `{"a":1,"b":{"c":[1,2]}}`, `{"b":{"c":[1,2]},"a":1}`, nil, []string{}},
+		{200, 500, `{"a":1}`, `{"a":1}`, nil, []string{"status"}},
+		{200, 200, `{"a":1,"b":{"c":[1,2]},"t":"x"}`, `{"a":2,"b":{"c":[1]},"t":"y","d":true}`, []string{"t"}, []string{"a", "b.c.1", "d"}},
+		{200, 200, `{"a":{"ts":1,"v":1}}`, `{"a":{"ts":2,"v":1}}`, []string{"a.ts"}, []string{}},
+		{200, 200, `plain`, `plain`, nil, []string{}},
+		{200, 200, `plain`, `{"a":1}`, nil, []string{"body"}},
+	}
+	for i, c := range cases {
+		got := Compare(c.status, []byte(c.primary), c.shadowStatus, []byte(c.shadow), c.ignore)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("case %d: got %v, want %v", i, got, c.want)
+		}
+	}
+}
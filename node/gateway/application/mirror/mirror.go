@@ -0,0 +1,220 @@
+package mirror
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	goku_plugin "github.com/eolinker/goku-plugin"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/diting"
+	goku_labels "github.com/eolinker/goku-api-gateway/goku-labels"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/goku-service/application"
+	"github.com/eolinker/goku-api-gateway/goku-service/balance"
+	"github.com/eolinker/goku-api-gateway/node/monitor"
+)
+
+const (
+	//HeaderMirror 影子请求携带的请求头，便于影子服务识别镜像流量
+	HeaderMirror = "X-Goku-Mirror"
+
+	resultSent    = "sent"
+	resultFailed  = "failed"
+	resultDropped = "dropped"
+	resultMatch   = "match"
+	resultDiff    = "diff"
+)
+
+// maxShadows 同时进行中的影子请求上限，超出时丢弃，避免影子负载变慢时堆积
+var maxShadows = make(chan struct{}, 1024)
+
+//Mirror 流量镜像
+type Mirror struct {
+	source      string
+	balanceName string
+	balance     application.IHttpApplication
+	percent     int
+	compare     bool
+	ignore      []string
+	timeOut     time.Duration
+}
+
+//New 创建流量镜像，未配置或影子负载不存在时返回nil
+func New(cfg *config.MirrorConfig) *Mirror {
+	if cfg == nil || cfg.Balance == "" || cfg.Percent <= 0 {
+		return nil
+	}
+	m := &Mirror{
+		source:      cfg.Source,
+		balanceName: cfg.Balance,
+		percent:     cfg.Percent,
+		compare:     cfg.Compare,
+		timeOut:     time.Duration(cfg.TimeOut) * time.Millisecond,
+	}
+	for _, field := range cfg.Ignore {
+		m.ignore = append(m.ignore, strings.Trim(field, "."))
+	}
+	app, has := balance.GetByName(cfg.Balance)
+	if !has {
+		log.Warn("mirror balance not found:", cfg.Balance)
+		return nil
+	}
+	m.balance = app
+	return m
+}
+
+//Applies 判断转发到该负载的请求是否需要镜像
+func (m *Mirror) Applies(balance string) bool {
+	return m != nil && (m.source == "" || m.source == balance) && m.balanceName != balance
+}
+
+//Shadow 一次采样命中的影子请求，保存转发前的请求快照
+type Shadow struct {
+	mirror     *Mirror
+	apiID      int
+	strategyID string
+
+	protocol string
+	method   string
+	path     string
+	querys   url.Values
+	header   http.Header
+	body     []byte
+	timeOut  time.Duration
+}
+
+//Prepare 按采样比例决定是否镜像本次转发，命中时复制请求，未命中返回nil
+func (m *Mirror) Prepare(ctx goku_plugin.ContextApiInfo, protocol, method, path string, querys url.Values, header http.Header, body []byte, timeOut time.Duration) *Shadow {
+	if m == nil || m.percent < 100 && rand.Intn(100) >= m.percent {
+		return nil
+	}
+	s := &Shadow{
+		mirror:     m,
+		apiID:      ctx.ApiID(),
+		strategyID: ctx.StrategyId(),
+		protocol:   protocol,
+		method:     method,
+		path:       path,
+		querys:     make(url.Values, len(querys)),
+		header:     header.Clone(),
+		body:       append([]byte(nil), body...),
+		timeOut:    timeOut,
+	}
+	for k, v := range querys {
+		s.querys[k] = append([]string(nil), v...)
+	}
+	if s.header == nil {
+		s.header = make(http.Header)
+	}
+	s.header.Set(HeaderMirror, "1")
+	if m.timeOut > 0 {
+		s.timeOut = m.timeOut
+	}
+	return s
+}
+
+//Send 异步发送影子请求，statusCode及body为原请求的响应，原请求失败时statusCode为0
+func (s *Shadow) Send(ctx goku_plugin.ContextAccess, statusCode int, body []byte) {
+	if s == nil {
+		return
+	}
+	select {
+	case maxShadows <- struct{}{}:
+	default:
+		s.count(monitor.MirrorCounter, resultDropped)
+		return
+	}
+	if s.mirror.compare && statusCode != 0 {
+		body = append([]byte(nil), body...)
+	}
+	go func() {
+		defer func() {
+			<-maxShadows
+			if e := recover(); e != nil {
+				log.Warn("mirror panic:", e)
+			}
+		}()
+		s.do(&shadowContext{ContextAccess: ctx, apiID: s.apiID, strategyID: s.strategyID}, statusCode, body)
+	}()
+}
+
+func (s *Shadow) do(ctx goku_plugin.ContextAccess, statusCode int, primary []byte) {
+	start := time.Now()
+	r, _, _, err := s.mirror.balance.Send(ctx, s.protocol, s.method, s.path, s.querys, s.header, s.body, s.timeOut, nil)
+	if err != nil {
+		s.observe(start, 503)
+		s.count(monitor.MirrorCounter, resultFailed)
+		log.Debug("mirror to ", s.mirror.balanceName, " error:", err)
+		return
+	}
+	defer r.Body.Close()
+	var reader io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		if gz, e := gzip.NewReader(r.Body); e == nil {
+			reader = gz
+		}
+	}
+	shadow, err := ioutil.ReadAll(reader)
+	s.observe(start, r.StatusCode)
+	s.count(monitor.MirrorCounter, resultSent)
+	if err != nil || !s.mirror.compare || statusCode == 0 {
+		return
+	}
+
+	diff := Compare(statusCode, primary, r.StatusCode, shadow, s.mirror.ignore)
+	if len(diff) == 0 {
+		s.count(monitor.MirrorDiffCounter, resultMatch)
+		return
+	}
+	s.count(monitor.MirrorDiffCounter, resultDiff)
+	log.Debug("mirror diff api:", s.apiID, " path:", s.path, " fields:", strings.Join(diff, ","))
+}
+
+func (s *Shadow) labels() diting.Labels {
+	labels := make(diting.Labels)
+	labels[goku_labels.API] = strconv.Itoa(s.apiID)
+	labels[goku_labels.Strategy] = s.strategyID
+	labels[goku_labels.Balance] = s.mirror.balanceName
+	return labels
+}
+
+func (s *Shadow) observe(start time.Time, status int) {
+	if monitor.MirrorMonitor == nil {
+		return
+	}
+	labels := s.labels()
+	labels[goku_labels.Status] = strconv.Itoa(status)
+	monitor.MirrorMonitor.Observe(float64(time.Since(start)/time.Millisecond), labels)
+}
+
+func (s *Shadow) count(counter diting.Counter, result string) {
+	if counter == nil {
+		return
+	}
+	labels := s.labels()
+	labels[goku_labels.Result] = result
+	counter.Add(1, labels)
+}
+
+// shadowContext 影子请求使用的上下文，不记录转发耗时，也不参与原请求的链路追踪
+type shadowContext struct {
+	goku_plugin.ContextAccess
+	apiID      int
+	strategyID string
+}
+
+func (c *shadowContext) ApiID() int {
+	return c.apiID
+}
+
+func (c *shadowContext) StrategyId() string {
+	return c.strategyID
+}
@@ -0,0 +1,22 @@
+package mirror
+
+import (
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/goku-service/balance"
+)
+
+func TestNewMissingBalance(t *testing.T) {
+	balance.ResetBalances(map[string]*config.BalanceConfig{
+		"shadow": {Name: "shadow", DiscoverName: "not-exist"},
+	})
+	defer balance.ResetBalances(nil)
+
+	if m := New(&config.MirrorConfig{Balance: "shadow", Percent: 100}); m != nil {
+		t.Error("mirror to missing balance should be disabled")
+	}
+	if m := New(&config.MirrorConfig{Balance: "127.0.0.1:8080", Percent: 100}); m == nil || m.balance == nil {
+		t.Error("mirror to address should be created")
+	}
+}
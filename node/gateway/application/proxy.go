@@ -2,6 +2,7 @@ package application
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/eolinker/goku-api-gateway/goku-service/application"
@@ -11,6 +12,9 @@ import (
 	"github.com/eolinker/goku-api-gateway/goku-node/common"
 	"github.com/eolinker/goku-api-gateway/node/gateway/application/backend"
 	"github.com/eolinker/goku-api-gateway/node/gateway/application/interpreter"
+	"github.com/eolinker/goku-api-gateway/node/gateway/application/mirror"
+	"github.com/eolinker/goku-api-gateway/node/gateway/application/traffic"
+	"github.com/eolinker/goku-api-gateway/node/gateway/application/transform"
 
 	"github.com/eolinker/goku-api-gateway/node/gateway/response"
 	access_field "github.com/eolinker/goku-api-gateway/server/access-field"
@@ -21,7 +25,11 @@ type DefaultApplication struct {
 	output        response.Encoder
 	backend       *backend.Proxy
 	static        *staticeResponse
+	transform     *transform.Transformer
 	balanceTarget string
+	traffic       *traffic.Policy
+	//splits 各拆分标签对应的转发
+	splits map[string]*backend.Proxy
 }
 
 //NewDefaultApplication create new default application
@@ -32,10 +40,29 @@ func NewDefaultApplication(apiContent *config.APIContent, target string) *Defaul
 		static:        nil,
 		balanceTarget: target,
 		output:        response.GetEncoder(apiContent.OutPutEncoder),
+		transform:     transform.New(apiContent.Transform),
 	}
 	if len(apiContent.Steps) == 1 {
 		step := apiContent.Steps[0]
-		app.backend = backend.NewProxyBackendTarget(step, apiContent.RequestURL, target)
+		app.backend = backend.NewProxyBackendTarget(step, apiContent.RequestURL, target, apiContent.RetryPolicy, apiContent.Hedge)
+		if policy := traffic.New(apiContent.Traffic); policy.Applies(target) {
+			app.traffic = policy
+			app.splits = make(map[string]*backend.Proxy)
+			for _, split := range policy.Splits() {
+				app.splits[split.Label] = backend.NewProxyBackendTarget(step, apiContent.RequestURL, split.Balance, apiContent.RetryPolicy, apiContent.Hedge)
+			}
+		}
+		if m := mirror.New(apiContent.Mirror); m != nil {
+			proxies := []*backend.Proxy{app.backend}
+			for _, proxy := range app.splits {
+				proxies = append(proxies, proxy)
+			}
+			for _, proxy := range proxies {
+				if m.Applies(proxy.BalanceName) {
+					proxy.Mirror = m
+				}
+			}
+		}
 	}
 	if apiContent.StaticResponse != "" {
 		staticResponseStrategy := config.Parse(apiContent.StaticResponseStrategy)
@@ -48,14 +75,23 @@ func NewDefaultApplication(apiContent *config.APIContent, target string) *Defaul
 //Execute execute
 func (app *DefaultApplication) Execute(ctx *common.Context) {
 
-	ctx.LogFields[access_field.Balance] = app.balanceTarget
+	proxy, balance := app.backend, app.balanceTarget
+	if app.traffic != nil {
+		ctx.LogFields[access_field.TrafficSplit] = config.TrafficDefaultLabel
+		if split := app.traffic.Select(ctx); split != nil {
+			proxy, balance = app.splits[split.Label], split.Balance
+			ctx.LogFields[access_field.TrafficSplit] = split.Label
+		}
+	}
+	ctx.LogFields[access_field.Balance] = balance
 
-	if app.backend != nil {
+	if proxy != nil {
+		app.transform.Request(ctx)
 		orgBody, _ := ctx.ProxyRequest.RawBody()
 
 		variables := interpreter.NewVariables(orgBody, nil, ctx.ProxyRequest.Headers(), ctx.ProxyRequest.Cookies(), ctx.RestfulParam, ctx.ProxyRequest.Querys(), 1)
 
-		r, err := app.backend.Send(ctx, variables)
+		r, err := proxy.Send(ctx, variables)
 		if r != nil {
 
 			ctx.ProxyRequest.Method = r.Method
@@ -78,11 +114,24 @@ func (app *DefaultApplication) Execute(ctx *common.Context) {
 
 		ctx.LogFields[access_field.ProxyStatusCode] = r.StatusCode
 
-		body, err := app.output.Encode(r.Body, r.BodyOrg)
-		if err != nil {
-			body = r.BodyOrg
+		statusCode, status := r.StatusCode, r.Status
+		if app.transform != nil {
+			variables.AppendResponse(r.Header, r.Body)
+			if code := app.transform.Response(variables, r.Header, r.StatusCode); code != statusCode {
+				statusCode, status = code, strconv.Itoa(code)
+			}
+		}
+
+		body := r.BodyOrg
+		if r.Body != nil {
+			if data, err := app.output.Encode(r.Body, r.BodyOrg); err == nil {
+				body = data
+				if contentType := app.output.ContentType(); contentType != "" {
+					r.Header.Set("Content-Type", contentType)
+				}
+			}
 		}
-		ctx.SetProxyResponseHandler(common.NewResponseReader(r.Header, r.StatusCode, r.Status, body))
+		ctx.SetProxyResponseHandler(common.NewResponseReader(r.Header, statusCode, status, body))
 
 		return
 
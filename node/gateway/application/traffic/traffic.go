@@ -0,0 +1,222 @@
+package traffic
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"net/http"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+)
+
+//Split 命中的拆分项
+type Split struct {
+	Label   string
+	Balance string
+}
+
+//Policy 接口的流量拆分策略
+type Policy struct {
+	balance string
+	matches []*matchSplit
+	weights []*weightSplit
+	sticky  *config.TrafficSticky
+}
+
+type matchSplit struct {
+	split *Split
+	match *config.TrafficMatch
+}
+
+type weightSplit struct {
+	split *Split
+	//limit 累计权重，桶号小于该值时命中
+	limit uint32
+}
+
+//New 创建流量拆分策略，未配置拆分项时返回nil
+func New(cfg *config.TrafficConfig) *Policy {
+	if cfg == nil || len(cfg.Splits) == 0 {
+		return nil
+	}
+	p := &Policy{
+		balance: cfg.Balance,
+		sticky:  cfg.Sticky,
+	}
+	var total uint32
+	for _, s := range cfg.Splits {
+		split := &Split{Label: s.Label, Balance: s.Balance}
+		if s.Match != nil {
+			p.matches = append(p.matches, &matchSplit{split: split, match: s.Match})
+		}
+		if s.Weight > 0 {
+			total += uint32(s.Weight)
+			p.weights = append(p.weights, &weightSplit{split: split, limit: total})
+		}
+	}
+	return p
+}
+
+//Splits 策略中的全部拆分项
+func (p *Policy) Splits() []*Split {
+	if p == nil {
+		return nil
+	}
+	splits := make([]*Split, 0, len(p.matches)+len(p.weights))
+	has := make(map[string]bool)
+	for _, m := range p.matches {
+		splits = append(splits, m.split)
+		has[m.split.Label] = true
+	}
+	for _, w := range p.weights {
+		if !has[w.split.Label] {
+			splits = append(splits, w.split)
+		}
+	}
+	return splits
+}
+
+//Applies 判断转发到该负载的请求是否参与拆分
+func (p *Policy) Applies(balance string) bool {
+	return p != nil && (p.balance == "" || p.balance == balance)
+}
+
+//Select 为请求选择拆分项，先按顺序检查匹配条件，再按权重拆分，未命中时返回nil
+func (p *Policy) Select(ctx *common.Context) *Split {
+	if p == nil {
+		return nil
+	}
+	return p.selectBy(&contextSource{ctx: ctx})
+}
+
+func (p *Policy) selectBy(s source) *Split {
+	for _, m := range p.matches {
+		if matches(m.match, s) {
+			return m.split
+		}
+	}
+	if len(p.weights) == 0 {
+		return nil
+	}
+	b := p.bucket(s)
+	for _, w := range p.weights {
+		if b < w.limit {
+			return w.split
+		}
+	}
+	return nil
+}
+
+// bucket 将请求映射到0~99的桶，取不到粘性键时随机分配
+func (p *Policy) bucket(s source) uint32 {
+	if p.sticky != nil {
+		if key, has := stickyKey(p.sticky, s); has && key != "" {
+			h := fnv.New32a()
+			_, _ = h.Write([]byte(key))
+			return h.Sum32() % 100
+		}
+	}
+	return uint32(rand.Intn(100))
+}
+
+func stickyKey(sticky *config.TrafficSticky, s source) (string, bool) {
+	switch sticky.Source {
+	case config.StickyHeader:
+		return s.header(sticky.Key)
+	case config.StickyCookie:
+		return s.cookie(sticky.Key)
+	case config.StickyQuery:
+		return s.query(sticky.Key)
+	case config.StickyConsumer:
+		consumers := s.consumers()
+		if len(consumers) == 0 {
+			return "", false
+		}
+		return consumers[0], true
+	case config.StickyIP:
+		ip := s.ip()
+		return ip, ip != ""
+	}
+	return "", false
+}
+
+func matches(m *config.TrafficMatch, s source) bool {
+	if !matchValues(m.Headers, s.header) || !matchValues(m.Cookies, s.cookie) || !matchValues(m.Query, s.query) {
+		return false
+	}
+	if len(m.Consumers) == 0 {
+		return true
+	}
+	for _, c := range s.consumers() {
+		for _, want := range m.Consumers {
+			if c == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchValues(want map[string]string, get func(name string) (string, bool)) bool {
+	for name, value := range want {
+		v, has := get(name)
+		if !has || (value != "" && v != value) {
+			return false
+		}
+	}
+	return true
+}
+
+// source 拆分时读取的请求信息
+type source interface {
+	header(name string) (string, bool)
+	cookie(name string) (string, bool)
+	query(name string) (string, bool)
+	//consumers 调用方，依次为应用ID及策略ID
+	consumers() []string
+	ip() string
+}
+
+type contextSource struct {
+	ctx *common.Context
+}
+
+func (c *contextSource) header(name string) (string, bool) {
+	values, has := c.ctx.RequestOrg.Headers()[http.CanonicalHeaderKey(name)]
+	if !has || len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
+
+func (c *contextSource) cookie(name string) (string, bool) {
+	cookie, err := c.ctx.RequestOrg.Cookie(name)
+	if err != nil {
+		return "", false
+	}
+	return cookie.Value, true
+}
+
+func (c *contextSource) query(name string) (string, bool) {
+	values, has := c.ctx.RequestOrg.URL().Query()[name]
+	if !has || len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
+
+func (c *contextSource) consumers() []string {
+	consumers := make([]string, 0, 2)
+	if consumer, has := c.ctx.GetCache(common.CacheConsumer); has {
+		consumers = append(consumers, fmt.Sprint(consumer))
+	}
+	if strategyID := c.ctx.StrategyId(); strategyID != "" {
+		consumers = append(consumers, strategyID)
+	}
+	return consumers
+}
+
+func (c *contextSource) ip() string {
+	return c.ctx.ProxyRequest.GetHeader("X-Real-Ip")
+}
@@ -0,0 +1,78 @@
+package traffic
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+type testSource struct {
+	headers  map[string]string
+	consumer []string
+	remoteIP string
+}
+
+func (t *testSource) header(name string) (string, bool) {
+	v, has := t.headers[name]
+	return v, has
+}
+
+func (t *testSource) cookie(name string) (string, bool) { return "", false }
+
+func (t *testSource) query(name string) (string, bool) { return "", false }
+
+func (t *testSource) consumers() []string { return t.consumer }
+
+func (t *testSource) ip() string { return t.remoteIP }
+
+func TestSelect(t *testing.T) {
+	p := New(&config.TrafficConfig{
+		Balance: "stable",
+		Splits: []*config.TrafficSplit{
+			{Label: "beta", Balance: "beta", Match: &config.TrafficMatch{Headers: map[string]string{"X-Beta": ""}}},
+			{Label: "partner", Balance: "partner", Match: &config.TrafficMatch{Consumers: []string{"app-1"}}},
+			{Label: "canary", Balance: "canary", Weight: 30},
+		},
+		Sticky: &config.TrafficSticky{Source: config.StickyIP},
+	})
+	if !p.Applies("stable") || p.Applies("other") {
+		t.Fatal("applies mismatch")
+	}
+	if s := p.selectBy(&testSource{headers: map[string]string{"X-Beta": "1"}}); s == nil || s.Label != "beta" {
+		t.Errorf("header match: got %v", s)
+	}
+	if s := p.selectBy(&testSource{consumer: []string{"app-1"}}); s == nil || s.Label != "partner" {
+		t.Errorf("consumer match: got %v", s)
+	}
+
+	canary := 0
+	for i := 0; i < 1000; i++ {
+		src := &testSource{remoteIP: fmt.Sprintf("10.0.%d.%d", i/250, i%250)}
+		first := p.selectBy(src)
+		if first != p.selectBy(src) {
+			t.Fatalf("sticky selection changed for %s", src.remoteIP)
+		}
+		if first != nil {
+			canary++
+		}
+	}
+	if canary < 200 || canary > 400 {
+		t.Errorf("weight split: got %d of 1000 for 30%%", canary)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	cases := []*config.TrafficConfig{
+		{Splits: []*config.TrafficSplit{{Label: "a", Balance: "b"}}},
+		{Splits: []*config.TrafficSplit{{Label: "a", Balance: "b", Weight: 60}, {Label: "a", Balance: "c", Weight: 10}}},
+		{Splits: []*config.TrafficSplit{{Label: "a", Balance: "b", Weight: 60}, {Label: "c", Balance: "c", Weight: 50}}},
+		{Splits: []*config.TrafficSplit{{Label: "default", Balance: "b", Weight: 10}}},
+		{Splits: []*config.TrafficSplit{{Label: "a", Balance: "b", Weight: 10}}, Sticky: &config.TrafficSticky{Source: "header"}},
+	}
+	for i, c := range cases {
+		if err := c.Check(); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	"github.com/eolinker/goku-api-gateway/node/gateway/application/interpreter"
+)
+
+//Transformer 接口的请求及响应转换
+type Transformer struct {
+	requestHeaders  *fieldTransform
+	requestQuery    *fieldTransform
+	requestCookies  *fieldTransform
+	requestBody     []*bodyField
+	responseHeaders *fieldTransform
+	responseCookies *fieldTransform
+	statusCodes     map[int]int
+}
+
+type entry struct {
+	name  string
+	value interpreter.Interpreter
+}
+
+type fieldTransform struct {
+	remove []string
+	rename [][2]string
+	set    []*entry
+	add    []*entry
+}
+
+type bodyField struct {
+	path  []string
+	kind  string
+	value interpreter.Interpreter
+}
+
+//Parse 编译模版，模版不合法时返回错误
+func Parse(tpl string) (interpreter.Interpreter, error) {
+	i, err := interpreter.Parse(tpl)
+	if err != nil {
+		return nil, fmt.Errorf("template %s:%s", tpl, err.Error())
+	}
+	return i, nil
+}
+
+// compile 编译模版，不合法时去掉模版标记按原文输出
+func compile(tpl string) interpreter.Interpreter {
+	i, err := Parse(tpl)
+	if err != nil {
+		log.Warn("transform:", err)
+		i, _ = interpreter.Parse(strings.NewReplacer("{{", "", "}}", "").Replace(tpl))
+	}
+	return i
+}
+
+func sortedEntries(m map[string]string) []*entry {
+	entries := make([]*entry, 0, len(m))
+	for name, tpl := range m {
+		entries = append(entries, &entry{name: name, value: compile(tpl)})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].name < entries[j].name
+	})
+	return entries
+}
+
+func newFieldTransform(cfg *config.FieldTransform) *fieldTransform {
+	if cfg == nil {
+		return nil
+	}
+	f := &fieldTransform{
+		remove: cfg.Remove,
+		rename: make([][2]string, 0, len(cfg.Rename)),
+		set:    sortedEntries(cfg.Set),
+		add:    sortedEntries(cfg.Add),
+	}
+	for from, to := range cfg.Rename {
+		f.rename = append(f.rename, [2]string{from, to})
+	}
+	sort.Slice(f.rename, func(i, j int) bool {
+		return f.rename[i][0] < f.rename[j][0]
+	})
+	return f
+}
+
+//New 根据配置创建转换，没有配置时返回nil
+func New(cfg *config.TransformConfig) *Transformer {
+	if cfg == nil || (cfg.Request == nil && cfg.Response == nil) {
+		return nil
+	}
+	t := new(Transformer)
+	if r := cfg.Request; r != nil {
+		t.requestHeaders = newFieldTransform(r.Headers)
+		t.requestQuery = newFieldTransform(r.Query)
+		t.requestCookies = newFieldTransform(r.Cookies)
+		for _, b := range r.Body {
+			t.requestBody = append(t.requestBody, &bodyField{
+				path:  strings.Split(strings.Trim(b.Path, "."), "."),
+				kind:  b.Type,
+				value: compile(b.Value),
+			})
+		}
+	}
+	if r := cfg.Response; r != nil {
+		t.responseHeaders = newFieldTransform(r.Headers)
+		t.responseCookies = newFieldTransform(r.Cookies)
+		t.statusCodes = r.StatusCodes
+	}
+	return t
+}
+
+// apply 先计算全部模版再按remove、rename、set、add的顺序修改，模版读取的是修改前的值
+func (f *fieldTransform) apply(vs values, variables *interpreter.Variables) {
+	if f == nil {
+		return
+	}
+	set := make([]string, len(f.set))
+	for i, e := range f.set {
+		set[i] = e.value.Execution(variables)
+	}
+	add := make([]string, len(f.add))
+	for i, e := range f.add {
+		add[i] = e.value.Execution(variables)
+	}
+
+	for _, name := range f.remove {
+		vs.del(name)
+	}
+	for _, r := range f.rename {
+		vs.rename(r[0], r[1])
+	}
+	for i, e := range f.set {
+		vs.set(e.name, []string{set[i]})
+	}
+	for i, e := range f.add {
+		vs.set(e.name, append(append([]string{}, vs.get(e.name)...), add[i]))
+	}
+}
+
+//Request 转发前转换请求的头部、query、cookie及JSON请求体
+func (t *Transformer) Request(ctx *common.Context) {
+	if t == nil {
+		return
+	}
+	req := ctx.ProxyRequest
+	orgBody, _ := req.RawBody()
+	bodyObj, _ := req.BodyInterface()
+	query := make(url.Values, len(req.Querys()))
+	for k, v := range req.Querys() {
+		query[k] = v
+	}
+	variables := interpreter.NewVariables(orgBody, bodyObj, req.Headers(), req.Cookies(), ctx.RestfulParam, query, 0)
+
+	if t.requestCookies != nil {
+		cookies := &cookieValues{cookies: req.Cookies()}
+		t.requestCookies.apply(cookies, variables)
+		req.DelHeader("Cookie")
+		for _, c := range cookies.cookies {
+			req.AddCookie(c)
+		}
+	}
+	if t.requestHeaders != nil {
+		header := req.Headers()
+		t.requestHeaders.apply(headerValues(header), variables)
+		for name := range req.Headers() {
+			if _, has := header[name]; !has {
+				req.DelHeader(name)
+			}
+		}
+		for name, vs := range header {
+			req.DelHeader(name)
+			for _, v := range vs {
+				req.AddHeader(name, v)
+			}
+		}
+	}
+	t.requestQuery.apply(queryValues(req.Querys()), variables)
+	if len(t.requestBody) > 0 {
+		t.injectBody(ctx, orgBody, variables)
+	}
+}
+
+func (t *Transformer) injectBody(ctx *common.Context, orgBody []byte, variables *interpreter.Variables) {
+	req := ctx.ProxyRequest
+	contentType := req.ContentType()
+	if mediaType, _, _ := mime.ParseMediaType(contentType); contentType != "" && !strings.Contains(mediaType, "json") {
+		return
+	}
+	body := make(map[string]interface{})
+	if len(strings.TrimSpace(string(orgBody))) > 0 {
+		if err := json.Unmarshal(orgBody, &body); err != nil {
+			log.Debug("transform: request body is not a json object:", err)
+			return
+		}
+	}
+	for _, f := range t.requestBody {
+		value, err := f.convert(f.value.Execution(variables))
+		if err != nil {
+			log.Debug("transform: body field ", strings.Join(f.path, "."), ":", err)
+			continue
+		}
+		setPath(body, f.path, value)
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		log.Warn("transform: encode request body:", err)
+		return
+	}
+	if contentType == "" {
+		contentType = "application/json; charset=utf-8"
+		req.SetHeader("Content-Type", contentType)
+	}
+	req.SetRaw(contentType, data)
+}
+
+func (f *bodyField) convert(v string) (interface{}, error) {
+	switch f.kind {
+	case config.BodyFieldNumber:
+		return strconv.ParseFloat(v, 64)
+	case config.BodyFieldBoolean:
+		return strconv.ParseBool(v)
+	case config.BodyFieldJSON:
+		var value interface{}
+		err := json.Unmarshal([]byte(v), &value)
+		return value, err
+	}
+	return v, nil
+}
+
+// setPath 设置多级字段，中间字段不存在或不是对象时替换为对象
+func setPath(node map[string]interface{}, path []string, value interface{}) {
+	for _, name := range path[:len(path)-1] {
+		next, ok := node[name].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			node[name] = next
+		}
+		node = next
+	}
+	node[path[len(path)-1]] = value
+}
+
+//Response 返回前转换响应的头部及Set-Cookie，返回映射后的状态码
+func (t *Transformer) Response(variables *interpreter.Variables, header http.Header, statusCode int) int {
+	if t == nil {
+		return statusCode
+	}
+	t.responseHeaders.apply(headerValues(header), variables)
+	if t.responseCookies != nil {
+		cookies := &cookieValues{cookies: (&http.Response{Header: header}).Cookies()}
+		t.responseCookies.apply(cookies, variables)
+		header.Del("Set-Cookie")
+		for _, c := range cookies.cookies {
+			if v := c.String(); v != "" {
+				header.Add("Set-Cookie", v)
+			}
+		}
+	}
+	if code, has := t.statusCodes[statusCode]; has {
+		return code
+	}
+	return statusCode
+}
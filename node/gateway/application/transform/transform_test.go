@@ -0,0 +1,67 @@
+package transform
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/node/gateway/application/interpreter"
+)
+
+func TestResponse(t *testing.T) {
+	tr := New(&config.TransformConfig{
+		Response: &config.ResponseTransform{
+			Headers: &config.FieldTransform{
+				Remove: []string{"Server"},
+				Rename: map[string]string{"X-Old": "X-New"},
+				Set:    map[string]string{"X-Request-Id": "{{header.X-Request-Id}}"},
+				Add:    map[string]string{"X-Tag": "{{query.tag}}"},
+			},
+			Cookies: &config.FieldTransform{
+				Rename: map[string]string{"sid": "session"},
+				Remove: []string{"debug"},
+			},
+			StatusCodes: map[int]int{404: 200},
+		},
+	})
+
+	request := http.Header{"X-Request-Id": {"abc"}}
+	variables := interpreter.NewVariables(nil, nil, request, nil, nil, url.Values{"tag": {"v2"}}, 1)
+	header := http.Header{
+		"Server":     {"nginx"},
+		"X-Old":      {"1"},
+		"X-Tag":      {"v1"},
+		"Set-Cookie": {"sid=1; Path=/; HttpOnly", "debug=1"},
+	}
+	variables.AppendResponse(header, nil)
+
+	if code := tr.Response(variables, header, 404); code != 200 {
+		t.Fatalf("status:want 200,got %d", code)
+	}
+	if header.Get("Server") != "" || header.Get("X-Old") != "" || header.Get("X-New") != "1" {
+		t.Fatalf("remove or rename failed:%v", header)
+	}
+	if header.Get("X-Request-Id") != "abc" {
+		t.Fatalf("set failed:%v", header)
+	}
+	if tags := header["X-Tag"]; len(tags) != 2 || tags[1] != "v2" {
+		t.Fatalf("add failed:%v", tags)
+	}
+	if cookies := header["Set-Cookie"]; len(cookies) != 1 || cookies[0] != "session=1; Path=/; HttpOnly" {
+		t.Fatalf("cookies failed:%v", cookies)
+	}
+	if code := tr.Response(variables, header, 500); code != 500 {
+		t.Fatalf("status:want 500,got %d", code)
+	}
+}
+
+func TestSetPath(t *testing.T) {
+	body := map[string]interface{}{"a": "x"}
+	setPath(body, []string{"a", "b"}, 1)
+	setPath(body, []string{"c"}, true)
+	a, ok := body["a"].(map[string]interface{})
+	if !ok || a["b"] != 1 || body["c"] != true {
+		t.Fatalf("unexpected body:%v", body)
+	}
+}
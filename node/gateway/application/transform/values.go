@@ -0,0 +1,109 @@
+package transform
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// values 可转换的键值集合
+type values interface {
+	get(name string) []string
+	set(name string, vs []string)
+	del(name string)
+	rename(from, to string)
+}
+
+type headerValues http.Header
+
+func (h headerValues) get(name string) []string {
+	return http.Header(h)[http.CanonicalHeaderKey(name)]
+}
+
+func (h headerValues) set(name string, vs []string) {
+	http.Header(h)[http.CanonicalHeaderKey(name)] = vs
+}
+
+func (h headerValues) del(name string) {
+	http.Header(h).Del(name)
+}
+
+func (h headerValues) rename(from, to string) {
+	if vs := h.get(from); len(vs) > 0 {
+		h.del(from)
+		h.set(to, vs)
+	}
+}
+
+type queryValues url.Values
+
+func (q queryValues) get(name string) []string {
+	return q[name]
+}
+
+func (q queryValues) set(name string, vs []string) {
+	q[name] = vs
+}
+
+func (q queryValues) del(name string) {
+	delete(q, name)
+}
+
+func (q queryValues) rename(from, to string) {
+	if vs, has := q[from]; has {
+		delete(q, from)
+		q[to] = vs
+	}
+}
+
+// cookieValues cookie列表，修改时保留cookie的其它属性
+type cookieValues struct {
+	cookies []*http.Cookie
+}
+
+func (c *cookieValues) get(name string) []string {
+	vs := make([]string, 0, 1)
+	for _, cookie := range c.cookies {
+		if cookie.Name == name {
+			vs = append(vs, cookie.Value)
+		}
+	}
+	return vs
+}
+
+func (c *cookieValues) set(name string, vs []string) {
+	var tpl *http.Cookie
+	cookies := make([]*http.Cookie, 0, len(c.cookies)+len(vs))
+	for _, cookie := range c.cookies {
+		if cookie.Name != name {
+			cookies = append(cookies, cookie)
+		} else if tpl == nil {
+			tpl = cookie
+		}
+	}
+	for _, v := range vs {
+		cookie := &http.Cookie{Name: name}
+		if tpl != nil {
+			copied := *tpl
+			cookie = &copied
+		}
+		cookie.Value = v
+		cookies = append(cookies, cookie)
+	}
+	c.cookies = cookies
+}
+
+func (c *cookieValues) del(name string) {
+	c.set(name, nil)
+}
+
+func (c *cookieValues) rename(from, to string) {
+	if len(c.get(from)) == 0 {
+		return
+	}
+	c.del(to)
+	for _, cookie := range c.cookies {
+		if cookie.Name == from {
+			cookie.Name = to
+		}
+	}
+}
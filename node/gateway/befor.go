@@ -46,12 +46,9 @@ func (r *Before) BeforeMatch(ctx *common.Context) bool {
 	log.Debug(requestID, " before plugin : begin")
 	for _, handler := range r.pluginBefor {
 
-		flag, _ := handler.Execute(ctx)
-
-		if flag == false {
-			if handler.IsStop() == true {
-				return false
-			}
+		flag, err := handler.Execute(ctx)
+		if handler.IsBreak(flag, err) {
+			return false
 		}
 	}
 	log.Debug(requestID, " before plugin : end")
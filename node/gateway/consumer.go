@@ -0,0 +1,64 @@
+package gateway
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+)
+
+// consumerOf 从鉴权凭证中识别调用方，鉴权插件可能隐藏凭证，因此在鉴权前读取
+// Basic取用户名，Jwt取iss（没有时取sub），Apikey取其sha256的前16位，避免密钥写入日志；Oauth2的令牌会变化，不识别
+func consumerOf(authType string, ctx *common.Context) string {
+	request := ctx.Request()
+	switch authType {
+	case "Basic":
+		credential := strings.TrimSpace(request.GetHeader("Authorization"))
+		if len(credential) < 6 || !strings.EqualFold(credential[:6], "Basic ") {
+			return ""
+		}
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(credential[6:]))
+		if err != nil {
+			return ""
+		}
+		return strings.SplitN(string(decoded), ":", 2)[0]
+	case "Jwt":
+		token := strings.TrimSpace(request.GetHeader("Authorization"))
+		if len(token) > 7 && strings.EqualFold(token[:7], "Bearer ") {
+			token = strings.TrimSpace(token[7:])
+		}
+		parts := strings.Split(token, ".")
+		if len(parts) != 3 {
+			return ""
+		}
+		payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+		if err != nil {
+			return ""
+		}
+		claims := struct {
+			Issuer  string `json:"iss"`
+			Subject string `json:"sub"`
+		}{}
+		if json.Unmarshal(payload, &claims) != nil {
+			return ""
+		}
+		if claims.Issuer != "" {
+			return claims.Issuer
+		}
+		return claims.Subject
+	case "Apikey":
+		key := request.GetHeader("Apikey")
+		if key == "" {
+			key = request.URL().Query().Get("Apikey")
+		}
+		if key == "" {
+			return ""
+		}
+		sum := sha256.Sum256([]byte(key))
+		return "apikey-" + hex.EncodeToString(sum[:8])
+	}
+	return ""
+}
@@ -0,0 +1,53 @@
+package gateway
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	plugin_executor "github.com/eolinker/goku-api-gateway/node/gateway/plugin-executor"
+)
+
+// hideCredential 模拟鉴权通过后隐藏凭证的鉴权插件
+type hideCredential struct {
+	plugin_executor.Executor
+}
+
+func (h *hideCredential) Execute(ctx *common.Context) (bool, error) {
+	ctx.RequestOrg.Headers().Del("Authorization")
+	return true, nil
+}
+
+func TestAuthConsumer(t *testing.T) {
+	tests := []struct {
+		authType      string
+		authorization string
+		consumer      string
+	}{
+		// alice:secret
+		{"Basic", "Basic YWxpY2U6c2VjcmV0", "alice"},
+		// {"iss":"app1","sub":"u1"}
+		{"Jwt", "Bearer eyJhbGciOiJIUzI1NiJ9.eyJpc3MiOiJhcHAxIiwic3ViIjoidTEifQ.sig", "app1"},
+		{"Oauth2", "Bearer token", ""},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization-Type", test.authType)
+		req.Header.Set("Authorization", test.authorization)
+		ctx := common.NewContext(req, "1", httptest.NewRecorder())
+		s := &Strategy{authPlugin: map[string]plugin_executor.Executor{test.authType: &hideCredential{}}}
+		if ok, err := s.auth(ctx); !ok || err != nil {
+			t.Fatalf("%s: auth got %v %v", test.authType, ok, err)
+		}
+		consumer, _ := ctx.GetCache(common.CacheConsumer)
+		if test.consumer == "" && consumer != nil || test.consumer != "" && consumer != test.consumer {
+			t.Errorf("%s: consumer got %v", test.authType, consumer)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/?Apikey=secret", nil)
+	ctx := common.NewContext(req, "1", httptest.NewRecorder())
+	if consumer := consumerOf("Apikey", ctx); consumer == "" || consumer == "secret" {
+		t.Errorf("apikey: consumer got %s", consumer)
+	}
+}
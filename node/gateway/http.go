@@ -8,6 +8,7 @@ import (
 
 	"github.com/eolinker/goku-api-gateway/diting"
 	goku_labels "github.com/eolinker/goku-api-gateway/goku-labels"
+	goku_trace "github.com/eolinker/goku-api-gateway/goku-trace"
 	"github.com/eolinker/goku-api-gateway/node/monitor"
 
 	log "github.com/eolinker/goku-api-gateway/goku-log"
@@ -31,6 +32,10 @@ func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 
 	ctx := common.NewContext(req, requestID, w)
 
+	span := goku_trace.StartServerSpan(fmt.Sprint(req.Method, " ", req.URL.Path), req.Header)
+	defer span.End()
+	ctx.SetSpan(span)
+
 	log.Debug(requestID, " url: ", ctx.Request().URL().String())
 	log.Debug(requestID, " header: ", ctx.RequestOrg.Header.String())
 
@@ -56,6 +61,17 @@ func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 
 	delay := time.Since(timeStart)
 
+	span.SetAttribute("http.method", req.Method)
+	span.SetAttribute("http.target", req.URL.RequestURI())
+	span.SetAttribute("http.host", req.Host)
+	span.SetAttribute("http.status_code", status)
+	span.SetAttribute("goku.request_id", requestID)
+	span.SetAttribute("goku.api_id", ctx.ApiID())
+	span.SetAttribute("goku.strategy_id", ctx.StrategyId())
+	if status >= 500 {
+		span.SetStatus(goku_trace.StatusError, strconv.Itoa(status))
+	}
+
 	ctx.LogFields[fields.RequestID] = requestID
 	ctx.LogFields[fields.StatusCode] = status
 	ctx.LogFields[fields.HTTPUserAgent] = fmt.Sprint("\"", req.UserAgent(), "\"")
@@ -64,6 +80,7 @@ func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	ctx.LogFields[fields.Request] = fmt.Sprint("\"", req.Method, " ", req.URL.String(), " ", req.Proto, "\"")
 	ctx.LogFields[fields.BodyBytesSent] = n
 	ctx.LogFields[fields.Host] = req.Host
+	setAccessFields(ctx, req, w)
 	access_log.Log(ctx.LogFields)
 	log.WithFields(ctx.LogFields).Info()
 
@@ -79,4 +96,16 @@ func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	labels[goku_labels.Status] = strconv.Itoa(status)
 	monitor.APIMonitor.Observe(float64(delay/time.Millisecond), labels)
 
+	if split, has := ctx.LogFields[fields.TrafficSplit].(string); has {
+		splitLabels := make(diting.Labels)
+		splitLabels[goku_labels.API] = labels[goku_labels.API]
+		splitLabels[goku_labels.Strategy] = labels[goku_labels.Strategy]
+		splitLabels[goku_labels.Split] = split
+		splitLabels[goku_labels.Status] = labels[goku_labels.Status]
+		monitor.TrafficSplitCounter.Add(1, splitLabels)
+	}
+
+	balance, upstreamFailed := upstreamStatus(ctx)
+	monitor.Report(ctx.ApiID(), ctx.StrategyId(), balance, status, upstreamFailed, float64(delay/time.Millisecond))
+
 }
@@ -0,0 +1,51 @@
+package plugin_executor
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/diting"
+	goku_labels "github.com/eolinker/goku-api-gateway/goku-labels"
+	"github.com/eolinker/goku-api-gateway/node/monitor"
+)
+
+//ErrTimeout 插件执行超时
+var ErrTimeout = errors.New("plugin execute timeout")
+
+//PanicError 插件执行崩溃
+type PanicError struct {
+	Value interface{}
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprint("plugin panic: ", e.Value)
+}
+
+func errorResult(err error) string {
+	switch err.(type) {
+	case nil:
+		return "ok"
+	case *PanicError:
+		return "panic"
+	}
+	if err == ErrTimeout {
+		return "timeout"
+	}
+	return "error"
+}
+
+// observe 上报插件执行耗时及出错计数
+func observe(name, phase string, duration time.Duration, err error) {
+	if monitor.PluginMonitor == nil {
+		return
+	}
+	labels := make(diting.Labels)
+	labels[goku_labels.Plugin] = name
+	labels[goku_labels.Phase] = phase
+	labels[goku_labels.Result] = errorResult(err)
+	monitor.PluginMonitor.Observe(float64(duration)/float64(time.Millisecond), labels)
+	if err != nil {
+		monitor.PluginErrorCounter.Add(1, labels)
+	}
+}
@@ -1,11 +1,17 @@
 package plugin_executor
 
 import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/eolinker/goku-api-gateway/config"
 	log "github.com/eolinker/goku-api-gateway/goku-log"
 	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	goku_trace "github.com/eolinker/goku-api-gateway/goku-trace"
 	goku_plugin "github.com/eolinker/goku-plugin"
 )
 
@@ -14,11 +20,17 @@ type Executor interface {
 	Execute(ctx *common.Context) (isContinue bool, e error)
 	IsStop() bool
 	IsAuth() bool
+	IsBreak(isContinue bool, err error) bool
 }
 type executorInfo struct {
 	Name   string
 	isStop bool
 	isAuth bool
+
+	timeout     time.Duration
+	onError     string
+	errorStatus int
+	errorBody   string
 }
 
 func (ex *executorInfo) IsStop() bool {
@@ -29,12 +41,144 @@ func (ex *executorInfo) IsAuth() bool {
 	return ex.isAuth
 }
 
+//IsBreak 根据插件执行结果判断是否中断后续流程
+func (ex *executorInfo) IsBreak(isContinue bool, err error) bool {
+	if err != nil {
+		return !ex.failOpen()
+	}
+	return !isContinue && ex.isStop
+}
+
+// failOpen 出错时是否忽略错误继续执行，未设置出错策略时非中断插件忽略错误
+func (ex *executorInfo) failOpen() bool {
+	switch ex.onError {
+	case config.PluginOnErrorContinue:
+		return true
+	case config.PluginOnErrorStop:
+		return false
+	}
+	return !ex.isStop
+}
+
 func genExecutor(cfg *config.PluginConfig) executorInfo {
-	return executorInfo{
+	info := executorInfo{
 		Name:   cfg.Name,
 		isStop: cfg.IsStop,
 		isAuth: cfg.IsAuth,
 	}
+	if cfg.Policy != nil {
+		info.timeout = time.Duration(cfg.Policy.Timeout) * time.Millisecond
+		info.onError = cfg.Policy.OnError
+		info.errorStatus = cfg.Policy.ErrorStatus
+		info.errorBody = cfg.Policy.ErrorBody
+	}
+	return info
+}
+
+// startSpan 为插件的执行创建子span
+func startSpan(ctx *common.Context, phase string, name string) *goku_trace.Span {
+	span := goku_trace.StartSpan(ctx.Span(), fmt.Sprint("plugin ", phase, " ", name), goku_trace.SpanKindInternal)
+	span.SetAttribute("goku.plugin.phase", phase)
+	span.SetAttribute("goku.plugin.name", name)
+	return span
+}
+
+func endSpan(span *goku_trace.Span, isContinue bool, err error) {
+	span.SetAttribute("goku.plugin.continue", isContinue)
+	if err != nil {
+		span.SetStatus(goku_trace.StatusError, err.Error())
+	}
+	span.End()
+}
+
+// run 执行插件，统一处理超时、崩溃恢复、耗时统计及出错策略
+func (ex *executorInfo) run(ctx *common.Context, phase string, fn func(ctx pluginContext) (bool, error)) (bool, error) {
+	requestID := ctx.RequestId()
+	ctx.SetPlugin(ex.Name)
+	log.Debug(requestID, " ", phase, " plugin :", ex.Name, " start")
+	now := time.Now()
+	span := startSpan(ctx, phase, ex.Name)
+	isContinue, err := ex.call(ctx, fn)
+	endSpan(span, isContinue, err)
+	duration := time.Since(now)
+	ctx.AddPluginTime(ex.Name, duration)
+	observe(ex.Name, phase, duration, err)
+	log.Debug(requestID, " ", phase, " plugin :", ex.Name, " Duration:", duration)
+	log.Debug(requestID, " ", phase, " plugin :", ex.Name, " end")
+	if err == nil {
+		return isContinue, nil
+	}
+
+	log.Warn(requestID, " ", phase, " plugin:", ex.Name, " error:", err)
+	// 不中断请求时不改写响应
+	if ex.failOpen() {
+		return true, nil
+	}
+	ex.writeError(ctx, phase, err)
+	return false, err
+}
+
+// call 调用插件，超时或崩溃时返回对应错误；
+// 超时后插件仍在后台执行直至返回，因此设置了超时的插件使用受保护的上下文，返回前关闭，之后插件的读写均被丢弃
+func (ex *executorInfo) call(ctx *common.Context, fn func(ctx pluginContext) (bool, error)) (bool, error) {
+	if ex.timeout <= 0 {
+		return safeCall(ctx, fn)
+	}
+	type result struct {
+		isContinue bool
+		err        error
+	}
+	gctx := newGuardContext(ctx)
+	defer gctx.close()
+	done := make(chan result, 1)
+	go func() {
+		isContinue, err := safeCall(gctx, fn)
+		done <- result{isContinue: isContinue, err: err}
+	}()
+	timer := time.NewTimer(ex.timeout)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return r.isContinue, r.err
+	case <-timer.C:
+		return false, ErrTimeout
+	}
+}
+
+func safeCall(ctx pluginContext, fn func(ctx pluginContext) (bool, error)) (isContinue bool, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			log.Error("plugin panic:", v, "\n", string(debug.Stack()))
+			isContinue, err = false, &PanicError{Value: v}
+		}
+	}()
+	return fn(ctx)
+}
+
+// writeError 按配置的模板写入错误响应，未配置时仅在超时或崩溃时覆盖插件的响应
+func (ex *executorInfo) writeError(ctx *common.Context, phase string, err error) {
+	status := ex.errorStatus
+	if status == 0 {
+		switch err.(type) {
+		case *PanicError:
+			status = http.StatusInternalServerError
+		default:
+			if err != ErrTimeout {
+				return
+			}
+			status = http.StatusGatewayTimeout
+		}
+	}
+	ctx.SetStatus(status, strconv.Itoa(status))
+	body := ex.errorBody
+	if body == "" {
+		body = "[ERROR]plugin $plugin $phase error: $error"
+	}
+	ctx.SetBody([]byte(strings.NewReplacer(
+		"$plugin", ex.Name,
+		"$phase", phase,
+		"$error", err.Error(),
+	).Replace(body)))
 }
 
 type beforeExecutor struct {
@@ -44,17 +188,9 @@ type beforeExecutor struct {
 
 //Execute execute
 func (ex *beforeExecutor) Execute(ctx *common.Context) (isContinue bool, e error) {
-	requestID := ctx.RequestId()
-	ctx.SetPlugin(ex.Name)
-	log.Debug(requestID, " before plugin :", ex.Name, " start")
-	now := time.Now()
-	isContinue, err := ex.plugin.BeforeMatch(ctx)
-	log.Debug(requestID, " before plugin :", ex.Name, " Duration:", time.Since(now))
-	log.Debug(requestID, " before plugin :", ex.Name, " end")
-	if err != nil {
-		log.Warn(requestID, " before plugin:", ex.Name, " error:", err)
-	}
-	return isContinue, err
+	return ex.run(ctx, "before", func(ctx pluginContext) (bool, error) {
+		return ex.plugin.BeforeMatch(ctx)
+	})
 }
 
 //NewBeforeExecutor 创建before阶段执行器
@@ -72,18 +208,9 @@ type accessExecutor struct {
 }
 
 func (ex *accessExecutor) Execute(ctx *common.Context) (isContinue bool, e error) {
-	requestID := ctx.RequestId()
-	ctx.SetPlugin(ex.Name)
-
-	log.Debug(requestID, " access plugin :", ex.Name, " start")
-	now := time.Now()
-	isContinue, err := ex.plugin.Access(ctx)
-	log.Debug(requestID, " access plugin :", ex.Name, " Duration:", time.Since(now))
-	log.Debug(requestID, " access plugin :", ex.Name, " end")
-	if err != nil {
-		log.Warn(requestID, " access plugin:", ex.Name, " error:", err)
-	}
-	return isContinue, err
+	return ex.run(ctx, "access", func(ctx pluginContext) (bool, error) {
+		return ex.plugin.Access(ctx)
+	})
 }
 
 //NewAccessExecutor 创建access阶段执行器
@@ -101,18 +228,9 @@ type proxyExecutor struct {
 
 //Execute execute
 func (ex *proxyExecutor) Execute(ctx *common.Context) (isContinue bool, e error) {
-	requestID := ctx.RequestId()
-	ctx.SetPlugin(ex.Name)
-
-	log.Debug(requestID, " proxy plugin :", ex.Name, " start")
-	now := time.Now()
-	isContinue, err := ex.plugin.Proxy(ctx)
-	log.Debug(requestID, " proxy plugin :", ex.Name, " Duration:", time.Since(now))
-	log.Debug(requestID, " proxy plugin :", ex.Name, " end")
-	if err != nil {
-		log.Warn(requestID, " proxy plugin:", ex.Name, " error:", err)
-	}
-	return isContinue, err
+	return ex.run(ctx, "proxy", func(ctx pluginContext) (bool, error) {
+		return ex.plugin.Proxy(ctx)
+	})
 }
 
 //NewProxyExecutor 创建proxy阶段执行器
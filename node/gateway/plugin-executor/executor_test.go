@@ -0,0 +1,134 @@
+package plugin_executor
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	goku_plugin "github.com/eolinker/goku-plugin"
+)
+
+type testAccess func(ctx goku_plugin.ContextAccess) (bool, error)
+
+func (f testAccess) Access(ctx goku_plugin.ContextAccess) (bool, error) {
+	return f(ctx)
+}
+
+func newTestContext() *common.Context {
+	req := httptest.NewRequest("GET", "/", nil)
+	return common.NewContext(req, "test", httptest.NewRecorder())
+}
+
+func TestPanic(t *testing.T) {
+	ex := NewAccessExecutor(&config.PluginConfig{Name: "p", IsStop: true}, testAccess(func(ctx goku_plugin.ContextAccess) (bool, error) {
+		panic("boom")
+	}))
+	ctx := newTestContext()
+	isContinue, err := ex.Execute(ctx)
+	if _, ok := err.(*PanicError); !ok || isContinue || !ex.IsBreak(isContinue, err) {
+		t.Fatalf("got continue=%v err=%v", isContinue, err)
+	}
+	if ctx.StatusCode() != 500 {
+		t.Errorf("status %d", ctx.StatusCode())
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	ex := NewAccessExecutor(&config.PluginConfig{
+		Name:   "p",
+		Policy: &config.PluginPolicy{Timeout: 20, OnError: config.PluginOnErrorStop, ErrorStatus: 503, ErrorBody: "$plugin $error"},
+	}, testAccess(func(ctx goku_plugin.ContextAccess) (bool, error) {
+		time.Sleep(time.Second)
+		return true, nil
+	}))
+	ctx := newTestContext()
+	isContinue, err := ex.Execute(ctx)
+	if err != ErrTimeout || !ex.IsBreak(isContinue, err) {
+		t.Fatalf("got continue=%v err=%v", isContinue, err)
+	}
+	if ctx.StatusCode() != 503 || string(ctx.GetBody()) != "p "+ErrTimeout.Error() {
+		t.Errorf("got %d %s", ctx.StatusCode(), ctx.GetBody())
+	}
+}
+
+func TestTimeoutLateWrite(t *testing.T) {
+	finished := make(chan struct{})
+	ex := NewAccessExecutor(&config.PluginConfig{
+		Name:   "p",
+		Policy: &config.PluginPolicy{Timeout: 20, OnError: config.PluginOnErrorStop},
+	}, testAccess(func(ctx goku_plugin.ContextAccess) (bool, error) {
+		defer close(finished)
+		time.Sleep(50 * time.Millisecond)
+		ctx.SetStatus(200, "200")
+		ctx.SetBody([]byte("late"))
+		ctx.SetHeader("X-Late", "1")
+		ctx.Proxy().SetHeader("X-Late", "1")
+		ctx.SetCache("late", true)
+		return true, nil
+	}))
+	ctx := newTestContext()
+	if _, err := ex.Execute(ctx); err != ErrTimeout {
+		t.Fatalf("got err=%v", err)
+	}
+	// 网关在插件仍在执行时继续处理上下文
+	for i := 0; i < 10; i++ {
+		ctx.SetHeader("X-Gateway", "1")
+		ctx.Proxy().SetHeader("X-Gateway", "1")
+		time.Sleep(10 * time.Millisecond)
+	}
+	<-finished
+	if ctx.StatusCode() != 504 || string(ctx.GetBody()) == "late" {
+		t.Errorf("timeout response overwritten: %d %s", ctx.StatusCode(), ctx.GetBody())
+	}
+	if ctx.GetHeader("X-Late") != "" || ctx.Proxy().GetHeader("X-Late") != "" {
+		t.Error("late header written")
+	}
+	if _, has := ctx.GetCache("late"); has {
+		t.Error("late cache written")
+	}
+}
+
+func TestFailOpen(t *testing.T) {
+	ex := NewAccessExecutor(&config.PluginConfig{
+		Name:   "p",
+		IsStop: true,
+		Policy: &config.PluginPolicy{OnError: config.PluginOnErrorContinue},
+	}, testAccess(func(ctx goku_plugin.ContextAccess) (bool, error) {
+		return false, errors.New("redis down")
+	}))
+	isContinue, err := ex.Execute(newTestContext())
+	if err != nil || !isContinue || ex.IsBreak(isContinue, err) {
+		t.Errorf("got continue=%v err=%v", isContinue, err)
+	}
+}
+
+func TestNonStopError(t *testing.T) {
+	panicPlugin := testAccess(func(ctx goku_plugin.ContextAccess) (bool, error) {
+		panic("boom")
+	})
+
+	// 未设置出错策略的非中断插件忽略错误，不改写响应
+	ex := NewAccessExecutor(&config.PluginConfig{Name: "p", Policy: &config.PluginPolicy{Timeout: 1000}}, panicPlugin)
+	ctx := newTestContext()
+	isContinue, err := ex.Execute(ctx)
+	if err != nil || !isContinue || ex.IsBreak(isContinue, err) {
+		t.Errorf("default: got continue=%v err=%v", isContinue, err)
+	}
+	if ctx.StatusCode() != 0 || len(ctx.GetBody()) != 0 {
+		t.Errorf("default: response written %d %s", ctx.StatusCode(), ctx.GetBody())
+	}
+
+	// stop策略的非中断插件出错时中断并返回错误响应
+	ex = NewAccessExecutor(&config.PluginConfig{Name: "p", Policy: &config.PluginPolicy{OnError: config.PluginOnErrorStop}}, panicPlugin)
+	ctx = newTestContext()
+	isContinue, err = ex.Execute(ctx)
+	if _, ok := err.(*PanicError); !ok || !ex.IsBreak(isContinue, err) {
+		t.Errorf("stop: got continue=%v err=%v", isContinue, err)
+	}
+	if ctx.StatusCode() != 500 {
+		t.Errorf("stop: status %d", ctx.StatusCode())
+	}
+}
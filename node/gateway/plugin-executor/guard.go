@@ -0,0 +1,430 @@
+package plugin_executor
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	goku_trace "github.com/eolinker/goku-api-gateway/goku-trace"
+	goku_plugin "github.com/eolinker/goku-plugin"
+)
+
+// pluginContext 各阶段插件可用的上下文
+type pluginContext interface {
+	goku_plugin.ContextAccess
+	ProxyResponse() goku_plugin.ResponseReader
+}
+
+// guard 超时后插件可能仍在后台执行，关闭后丢弃插件对上下文的读写，避免与网关后续处理并发
+type guard struct {
+	locker sync.Mutex
+	closed bool
+}
+
+func (g *guard) do(f func()) {
+	g.locker.Lock()
+	if !g.closed {
+		f()
+	}
+	g.locker.Unlock()
+}
+
+// close 关闭后返回，此时插件正在进行的读写已完成
+func (g *guard) close() {
+	g.locker.Lock()
+	g.closed = true
+	g.locker.Unlock()
+}
+
+type guardCookieReader struct {
+	g *guard
+	r goku_plugin.CookieReader
+}
+
+func (c *guardCookieReader) Cookie(name string) (cookie *http.Cookie, err error) {
+	err = ErrTimeout
+	c.g.do(func() { cookie, err = c.r.Cookie(name) })
+	return
+}
+
+func (c *guardCookieReader) Cookies() (cookies []*http.Cookie) {
+	c.g.do(func() { cookies = c.r.Cookies() })
+	return
+}
+
+type guardHeaderReader struct {
+	g *guard
+	r goku_plugin.HeaderReader
+}
+
+func (h *guardHeaderReader) GetHeader(name string) (value string) {
+	h.g.do(func() { value = h.r.GetHeader(name) })
+	return
+}
+
+func (h *guardHeaderReader) Headers() (header http.Header) {
+	h.g.do(func() { header = h.r.Headers() })
+	return
+}
+
+type guardHeaderWriter struct {
+	g *guard
+	w goku_plugin.HeaderWriter
+}
+
+func (h *guardHeaderWriter) SetHeader(key, value string) {
+	h.g.do(func() { h.w.SetHeader(key, value) })
+}
+
+func (h *guardHeaderWriter) AddHeader(key, value string) {
+	h.g.do(func() { h.w.AddHeader(key, value) })
+}
+
+func (h *guardHeaderWriter) DelHeader(key string) {
+	h.g.do(func() { h.w.DelHeader(key) })
+}
+
+type guardHeader struct {
+	guardHeaderReader
+	guardHeaderWriter
+}
+
+func newGuardHeader(g *guard, h goku_plugin.Header) *guardHeader {
+	return &guardHeader{
+		guardHeaderReader: guardHeaderReader{g: g, r: h},
+		guardHeaderWriter: guardHeaderWriter{g: g, w: h},
+	}
+}
+
+type guardBodyReader struct {
+	g *guard
+	r goku_plugin.BodyDataReader
+}
+
+func (b *guardBodyReader) ContentType() (contentType string) {
+	b.g.do(func() { contentType = b.r.ContentType() })
+	return
+}
+
+func (b *guardBodyReader) BodyForm() (form url.Values, err error) {
+	err = ErrTimeout
+	b.g.do(func() { form, err = b.r.BodyForm() })
+	return
+}
+
+func (b *guardBodyReader) Files() (files map[string]*goku_plugin.FileHeader, err error) {
+	err = ErrTimeout
+	b.g.do(func() { files, err = b.r.Files() })
+	return
+}
+
+func (b *guardBodyReader) GetForm(key string) (value string) {
+	b.g.do(func() { value = b.r.GetForm(key) })
+	return
+}
+
+func (b *guardBodyReader) GetFile(key string) (file *goku_plugin.FileHeader, has bool) {
+	b.g.do(func() { file, has = b.r.GetFile(key) })
+	return
+}
+
+func (b *guardBodyReader) RawBody() (body []byte, err error) {
+	err = ErrTimeout
+	b.g.do(func() { body, err = b.r.RawBody() })
+	return
+}
+
+type guardBodyWriter struct {
+	g *guard
+	w goku_plugin.BodyDatawriter
+}
+
+func (b *guardBodyWriter) SetForm(values url.Values) (err error) {
+	err = ErrTimeout
+	b.g.do(func() { err = b.w.SetForm(values) })
+	return
+}
+
+func (b *guardBodyWriter) SetToForm(key, value string) (err error) {
+	err = ErrTimeout
+	b.g.do(func() { err = b.w.SetToForm(key, value) })
+	return
+}
+
+func (b *guardBodyWriter) AddForm(key, value string) (err error) {
+	err = ErrTimeout
+	b.g.do(func() { err = b.w.AddForm(key, value) })
+	return
+}
+
+func (b *guardBodyWriter) AddFile(key string, file *goku_plugin.FileHeader) (err error) {
+	err = ErrTimeout
+	b.g.do(func() { err = b.w.AddFile(key, file) })
+	return
+}
+
+func (b *guardBodyWriter) SetRaw(contentType string, body []byte) {
+	b.g.do(func() { b.w.SetRaw(contentType, body) })
+}
+
+// guardRequestReader 原始请求
+type guardRequestReader struct {
+	guardCookieReader
+	guardHeaderReader
+	guardBodyReader
+	g *guard
+	r goku_plugin.RequestReader
+}
+
+func (r *guardRequestReader) Method() (method string) {
+	r.g.do(func() { method = r.r.Method() })
+	return
+}
+
+func (r *guardRequestReader) URL() (u *url.URL) {
+	r.g.do(func() { u = r.r.URL() })
+	return
+}
+
+func (r *guardRequestReader) RequestURI() (uri string) {
+	r.g.do(func() { uri = r.r.RequestURI() })
+	return
+}
+
+func (r *guardRequestReader) Host() (host string) {
+	r.g.do(func() { host = r.r.Host() })
+	return
+}
+
+func (r *guardRequestReader) RemoteAddr() (addr string) {
+	r.g.do(func() { addr = r.r.RemoteAddr() })
+	return
+}
+
+func (r *guardRequestReader) Proto() (proto string) {
+	r.g.do(func() { proto = r.r.Proto() })
+	return
+}
+
+// guardRequest 转发请求
+type guardRequest struct {
+	guardCookieReader
+	guardHeaderReader
+	guardHeaderWriter
+	guardBodyReader
+	guardBodyWriter
+	g *guard
+	r goku_plugin.Request
+}
+
+func (r *guardRequest) AddCookie(c *http.Cookie) {
+	r.g.do(func() { r.r.AddCookie(c) })
+}
+
+func (r *guardRequest) Querys() (querys url.Values) {
+	r.g.do(func() { querys = r.r.Querys() })
+	return
+}
+
+func (r *guardRequest) TargetServer() (server string) {
+	r.g.do(func() { server = r.r.TargetServer() })
+	return
+}
+
+func (r *guardRequest) TargetURL() (u string) {
+	r.g.do(func() { u = r.r.TargetURL() })
+	return
+}
+
+// guardResponseReader 转发结果
+type guardResponseReader struct {
+	guardCookieReader
+	guardHeaderReader
+	g *guard
+	r goku_plugin.ResponseReader
+}
+
+func (r *guardResponseReader) GetBody() (body []byte) {
+	r.g.do(func() { body = r.r.GetBody() })
+	return
+}
+
+func (r *guardResponseReader) StatusCode() (code int) {
+	r.g.do(func() { code = r.r.StatusCode() })
+	return
+}
+
+func (r *guardResponseReader) Status() (status string) {
+	r.g.do(func() { status = r.r.Status() })
+	return
+}
+
+type guardStore struct {
+	g *guard
+	s goku_plugin.Store
+}
+
+func (s *guardStore) Set(value interface{}) {
+	s.g.do(func() { s.s.Set(value) })
+}
+
+func (s *guardStore) Get() (value interface{}) {
+	s.g.do(func() { value = s.s.Get() })
+	return
+}
+
+// guardContext 设置了超时的插件使用的上下文，执行器返回后插件的读写均被丢弃
+type guardContext struct {
+	guardCookieReader
+	guardHeaderReader
+	guardHeaderWriter
+	g   *guard
+	ctx *common.Context
+}
+
+func newGuardContext(ctx *common.Context) *guardContext {
+	g := new(guard)
+	return &guardContext{
+		guardCookieReader: guardCookieReader{g: g, r: ctx},
+		guardHeaderReader: guardHeaderReader{g: g, r: ctx},
+		guardHeaderWriter: guardHeaderWriter{g: g, w: ctx},
+		g:                 g,
+		ctx:               ctx,
+	}
+}
+
+func (c *guardContext) close() {
+	c.g.close()
+}
+
+func (c *guardContext) Set() goku_plugin.Header {
+	var h goku_plugin.Header
+	c.g.do(func() { h = c.ctx.Set() })
+	return newGuardHeader(c.g, h)
+}
+
+func (c *guardContext) Append() goku_plugin.Header {
+	var h goku_plugin.Header
+	c.g.do(func() { h = c.ctx.Append() })
+	return newGuardHeader(c.g, h)
+}
+
+func (c *guardContext) AddCookie(cookie *http.Cookie) {
+	c.g.do(func() { c.ctx.AddCookie(cookie) })
+}
+
+func (c *guardContext) StatusCode() (code int) {
+	c.g.do(func() { code = c.ctx.StatusCode() })
+	return
+}
+
+func (c *guardContext) Status() (status string) {
+	c.g.do(func() { status = c.ctx.Status() })
+	return
+}
+
+func (c *guardContext) SetStatus(code int, status string) {
+	c.g.do(func() { c.ctx.SetStatus(code, status) })
+}
+
+func (c *guardContext) SetBody(body []byte) {
+	c.g.do(func() { c.ctx.SetBody(body) })
+}
+
+func (c *guardContext) GetBody() (body []byte) {
+	c.g.do(func() { body = c.ctx.GetBody() })
+	return
+}
+
+func (c *guardContext) Store() goku_plugin.Store {
+	var s goku_plugin.Store
+	c.g.do(func() { s = c.ctx.Store() })
+	return &guardStore{g: c.g, s: s}
+}
+
+func (c *guardContext) SetCache(name string, value interface{}) {
+	c.g.do(func() { c.ctx.SetCache(name, value) })
+}
+
+func (c *guardContext) GetCache(name string) (value interface{}, has bool) {
+	c.g.do(func() { value, has = c.ctx.GetCache(name) })
+	return
+}
+
+func (c *guardContext) RequestId() string {
+	return c.ctx.RequestId()
+}
+
+func (c *guardContext) FinalTargetServer() (server string) {
+	c.g.do(func() { server = c.ctx.FinalTargetServer() })
+	return
+}
+
+func (c *guardContext) RetryTargetServers() (servers string) {
+	c.g.do(func() { servers = c.ctx.RetryTargetServers() })
+	return
+}
+
+func (c *guardContext) StrategyId() string {
+	return c.ctx.StrategyId()
+}
+
+func (c *guardContext) StrategyName() string {
+	return c.ctx.StrategyName()
+}
+
+func (c *guardContext) ApiID() int {
+	return c.ctx.ApiID()
+}
+
+//Span 插件的span由执行器创建，插件只用于创建子span
+func (c *guardContext) Span() *goku_trace.Span {
+	return c.ctx.Span()
+}
+
+func (c *guardContext) Request() goku_plugin.RequestReader {
+	var r *common.RequestReader
+	c.g.do(func() { r = c.ctx.RequestOrg })
+	if r == nil {
+		return nil
+	}
+	return &guardRequestReader{
+		guardCookieReader: guardCookieReader{g: c.g, r: r},
+		guardHeaderReader: guardHeaderReader{g: c.g, r: r},
+		guardBodyReader:   guardBodyReader{g: c.g, r: r},
+		g:                 c.g,
+		r:                 r,
+	}
+}
+
+func (c *guardContext) Proxy() goku_plugin.Request {
+	var r *common.Request
+	c.g.do(func() { r = c.ctx.ProxyRequest })
+	if r == nil {
+		return nil
+	}
+	return &guardRequest{
+		guardCookieReader: guardCookieReader{g: c.g, r: r},
+		guardHeaderReader: guardHeaderReader{g: c.g, r: r},
+		guardHeaderWriter: guardHeaderWriter{g: c.g, w: r},
+		guardBodyReader:   guardBodyReader{g: c.g, r: r},
+		guardBodyWriter:   guardBodyWriter{g: c.g, w: r},
+		g:                 c.g,
+		r:                 r,
+	}
+}
+
+func (c *guardContext) ProxyResponse() goku_plugin.ResponseReader {
+	var r *common.ResponseReader
+	c.g.do(func() { r = c.ctx.ProxyResponseHandler })
+	if r == nil {
+		return nil
+	}
+	return &guardResponseReader{
+		guardCookieReader: guardCookieReader{g: c.g, r: r},
+		guardHeaderReader: guardHeaderReader{g: c.g, r: r},
+		g:                 c.g,
+		r:                 r,
+	}
+}
@@ -3,22 +3,65 @@ package gateway
 import (
 	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
 	plugin_executor "github.com/eolinker/goku-api-gateway/node/gateway/plugin-executor"
 	plugin "github.com/eolinker/goku-api-gateway/node/plugin-loader"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
 )
 
+var (
+	pluginErrorsLocker sync.Mutex
+	pluginErrors       []*entity.PluginError
+	parsingErrors      []*entity.PluginError
+)
+
+// pluginFailed 记录本次解析中加载或创建失败的插件，该插件不会生效
+func pluginFailed(name, strategyID string, apiID int, err error) {
+	log.Warn("plugin ", name, " of strategy ", strategyID, " api ", apiID, " disabled:", err)
+	pluginErrorsLocker.Lock()
+	parsingErrors = append(parsingErrors, &entity.PluginError{
+		PluginName: name,
+		StrategyID: strategyID,
+		APIID:      apiID,
+		Error:      err.Error(),
+	})
+	pluginErrorsLocker.Unlock()
+}
+
+func resetPluginErrors() {
+	pluginErrorsLocker.Lock()
+	parsingErrors = nil
+	pluginErrorsLocker.Unlock()
+}
+
+func commitPluginErrors() {
+	pluginErrorsLocker.Lock()
+	pluginErrors, parsingErrors = parsingErrors, nil
+	pluginErrorsLocker.Unlock()
+}
+
+//PluginErrors 当前配置中加载或创建失败的插件
+func PluginErrors() []*entity.PluginError {
+	pluginErrorsLocker.Lock()
+	defer pluginErrorsLocker.Unlock()
+	return pluginErrors
+}
+
 func genBeforPlugin(cfgs []*config.PluginConfig, cluster string) []plugin_executor.Executor {
 	ps := make([]plugin_executor.Executor, 0, len(cfgs))
 	for _, cfg := range cfgs {
 
 		factory, e := plugin.LoadPlugin(cfg.Name)
 		if e != nil {
+			pluginFailed(cfg.Name, "", 0, e)
 			continue
 		}
 		obj, err := factory.Create(cfg.Config, cluster, cfg.UpdateTag, "", 0)
 		if err != nil {
+			pluginFailed(cfg.Name, "", 0, err)
 			continue
 		}
 
@@ -37,10 +80,12 @@ func genPlugins(cfgs []*config.PluginConfig, cluster string, strategyID string,
 
 		factory, e := plugin.LoadPlugin(cfg.Name)
 		if e != nil {
+			pluginFailed(cfg.Name, strategyID, apiID, e)
 			continue
 		}
 		obj, err := factory.Create(cfg.Config, cluster, cfg.UpdateTag, strategyID, apiID)
 		if err != nil {
+			pluginFailed(cfg.Name, strategyID, apiID, err)
 			continue
 		}
 
@@ -0,0 +1,173 @@
+package response
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/eolinker/goku-api-gateway/utils"
+)
+
+const (
+	//JSON json
+	JSON = "json"
+	//XML xml
+	XML = "xml"
+	//String string
+	String = "string"
+	//JSONNoQuote 非标准json（key不带双引号）
+	JSONNoQuote = "json-noquote"
+	//Form application/x-www-form-urlencoded
+	Form = "form"
+	//Multipart multipart/form-data
+	Multipart = "multipart"
+	//MsgPack MessagePack
+	MsgPack = "msgpack"
+)
+
+var (
+	codecLocker sync.RWMutex
+	decoders    = make(map[string]DecodeHandle)
+	encoders    = make(map[string]Encoder)
+)
+
+var (
+	jsonDecoder = func(data []byte, v interface{}) error {
+		err := json.Unmarshal(data, v)
+		return err
+	}
+	jsonNoQuoteDecoder = func(data []byte, v interface{}) error {
+		d, err := utils.JSObjectToJSON(string(data))
+		if err != nil {
+			return err
+		}
+		err = json.Unmarshal(d, v)
+		return err
+	}
+	stringDecoder = func(data []byte, v interface{}) error {
+		return setValue(v, string(data))
+	}
+
+	jsonEncoder = &EncoderH{
+		contentType: "application/json",
+		handleFunc: func(v interface{}, org []byte) ([]byte, error) {
+			return json.Marshal(v)
+		},
+	}
+	xmlEncoder = &EncoderH{
+		contentType: "text/xml; charset=utf-8",
+		handleFunc: func(v interface{}, org []byte) ([]byte, error) {
+			return encodeXML(v)
+		},
+	}
+	stringEncoder = &EncoderH{
+		contentType: "text/plain",
+		handleFunc: func(v interface{}, org []byte) ([]byte, error) {
+
+			return org, nil
+		},
+	}
+	notEncoder = &EncoderH{
+		contentType: "",
+		handleFunc: func(v interface{}, org []byte) (bytes []byte, e error) {
+			return org, nil
+		},
+	}
+)
+
+func init() {
+	RegisterDecoder(JSON, jsonDecoder)
+	RegisterDecoder(JSONNoQuote, jsonNoQuoteDecoder)
+	RegisterDecoder(String, stringDecoder)
+	RegisterDecoder(XML, decodeXML)
+	RegisterDecoder(Form, decodeForm)
+	RegisterDecoder(Multipart, decodeMultipart)
+	RegisterDecoder(MsgPack, decodeMsgPack)
+
+	RegisterEncoder(JSON, jsonEncoder)
+	RegisterEncoder(XML, xmlEncoder)
+	RegisterEncoder(String, stringEncoder)
+	RegisterEncoder(Form, NewEncoder("application/x-www-form-urlencoded", encodeForm))
+	RegisterEncoder(Multipart, NewEncoder("multipart/form-data; boundary="+multipartBoundary, encodeMultipart))
+	RegisterEncoder(MsgPack, NewEncoder("application/msgpack", encodeMsgPack))
+}
+
+//RegisterDecoder 注册解码器，名称不区分大小写，重复注册时覆盖
+func RegisterDecoder(name string, handle DecodeHandle) {
+	codecLocker.Lock()
+	decoders[strings.ToLower(name)] = handle
+	codecLocker.Unlock()
+}
+
+//RegisterEncoder 注册编码器，名称不区分大小写，重复注册时覆盖
+func RegisterEncoder(name string, encoder Encoder) {
+	codecLocker.Lock()
+	encoders[strings.ToLower(name)] = encoder
+	codecLocker.Unlock()
+}
+
+//GetDecoder getDecoder
+func GetDecoder(decoder string) DecodeHandle {
+	codecLocker.RLock()
+	defer codecLocker.RUnlock()
+	return decoders[strings.ToLower(decoder)]
+}
+
+//HasDecoder 判断解码器是否已注册
+func HasDecoder(decoder string) bool {
+	return GetDecoder(decoder) != nil
+}
+
+//GetEncoder 获取编码器，未注册时原样输出
+func GetEncoder(encoder string) Encoder {
+	codecLocker.RLock()
+	defer codecLocker.RUnlock()
+	if e, has := encoders[strings.ToLower(encoder)]; has {
+		return e
+	}
+	return notEncoder
+}
+
+//HasEncoder 判断编码器是否已注册
+func HasEncoder(encoder string) bool {
+	codecLocker.RLock()
+	defer codecLocker.RUnlock()
+	_, has := encoders[strings.ToLower(encoder)]
+	return has
+}
+
+//EncoderH encodeH
+type EncoderH struct {
+	contentType string
+	handleFunc  EncodeHandle
+}
+
+//NewEncoder 通过content-type及处理函数创建编码器
+func NewEncoder(contentType string, handle func(v interface{}) ([]byte, error)) *EncoderH {
+	return &EncoderH{
+		contentType: contentType,
+		handleFunc: func(v interface{}, org []byte) ([]byte, error) {
+			return handle(v)
+		},
+	}
+}
+
+//Encode encode
+func (e *EncoderH) Encode(v interface{}, org []byte) ([]byte, error) {
+	return e.handleFunc(v, org)
+}
+
+//ContentType contentType
+func (e *EncoderH) ContentType() string {
+	return e.contentType
+}
+
+// setValue 解码结果写入Decode传入的*interface{}
+func setValue(v interface{}, value interface{}) error {
+	p, ok := v.(*interface{})
+	if !ok {
+		return ErrorInvalidDecoder
+	}
+	*p = value
+	return nil
+}
@@ -0,0 +1,92 @@
+package response
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func decodeString(t *testing.T, name, data string) interface{} {
+	rp, err := Decode([]byte(data), GetDecoder(name))
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	return rp.Data
+}
+
+func TestXML(t *testing.T) {
+	data := decodeString(t, XML, `<?xml version="1.0"?><user id="1"><name>goku</name><tag>a</tag><tag>b</tag><note lang="en">hi</note></user>`)
+	want := map[string]interface{}{
+		"user": map[string]interface{}{
+			"-id":  "1",
+			"name": "goku",
+			"tag":  []interface{}{"a", "b"},
+			"note": map[string]interface{}{"-lang": "en", "#text": "hi"},
+		},
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Fatalf("decode: got %v", data)
+	}
+	out, err := GetEncoder(XML).Encode(data, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(string(out), `<user id="1"><name>goku</name><note lang="en">hi</note><tag>a</tag><tag>b</tag></user>`) {
+		t.Fatalf("encode: got %s", out)
+	}
+	out, _ = GetEncoder(XML).Encode(map[string]interface{}{"a": 1.5, "b": []interface{}{true}}, nil)
+	if !strings.HasSuffix(string(out), `<root><a>1.5</a><b>true</b></root>`) {
+		t.Fatalf("encode: got %s", out)
+	}
+}
+
+func TestForm(t *testing.T) {
+	data := decodeString(t, Form, "a=1&b=x&b=y")
+	want := map[string]interface{}{"a": "1", "b": []interface{}{"x", "y"}}
+	if !reflect.DeepEqual(data, want) {
+		t.Fatalf("decode: got %v", data)
+	}
+	out, err := GetEncoder(Form).Encode(map[string]interface{}{"a": 1.0, "b": []interface{}{"x", "y"}, "c": map[string]interface{}{"d": true}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "a=1&b=x&b=y&c=%7B%22d%22%3Atrue%7D" {
+		t.Fatalf("encode: got %s", out)
+	}
+}
+
+func TestMultipart(t *testing.T) {
+	v := map[string]interface{}{
+		"name": "goku",
+		"file": map[string]interface{}{"filename": "a.txt", "contentType": "text/plain", "content": "hello"},
+	}
+	out, err := GetEncoder(Multipart).Encode(v, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data := decodeString(t, Multipart, string(out)); !reflect.DeepEqual(data, v) {
+		t.Fatalf("roundtrip: got %v", data)
+	}
+}
+
+func TestMsgPack(t *testing.T) {
+	var v interface{}
+	json.Unmarshal([]byte(`{"a":1,"b":-200,"c":1.5,"d":"text","e":[true,false,null],"f":{"g":70000}}`), &v)
+	out, err := GetEncoder(MsgPack).Encode(v, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := decodeString(t, MsgPack, string(out))
+	want := map[string]interface{}{
+		"a": int64(1), "b": int64(-200), "c": 1.5, "d": "text",
+		"e": []interface{}{true, false, nil},
+		"f": map[string]interface{}{"g": int64(70000)},
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Fatalf("roundtrip: got %v", data)
+	}
+	if _, err := Decode(out[:len(out)-1], GetDecoder(MsgPack)); err == nil {
+		t.Fatal("expected error for truncated data")
+	}
+}
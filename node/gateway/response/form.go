@@ -0,0 +1,149 @@
+package response
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"strings"
+)
+
+const multipartBoundary = "GokuAPIGatewayFormBoundary7MA4YWxkTrZu0gW"
+
+var errFormObject = errors.New("form encoder requires an object")
+
+func decodeForm(data []byte, v interface{}) error {
+	values, err := url.ParseQuery(strings.TrimSpace(string(data)))
+	if err != nil {
+		return err
+	}
+	m := make(map[string]interface{}, len(values))
+	for key, list := range values {
+		for _, value := range list {
+			addValue(m, key, value)
+		}
+	}
+	return setValue(v, m)
+}
+
+// formValues 数组展开为同名的多个值，其他值转为文本
+func formValues(v interface{}) (url.Values, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, errFormObject
+	}
+	values := make(url.Values, len(m))
+	for key, value := range m {
+		if list, ok := value.([]interface{}); ok {
+			for _, item := range list {
+				values.Add(key, scalarString(item))
+			}
+			continue
+		}
+		values.Set(key, scalarString(value))
+	}
+	return values, nil
+}
+
+func encodeForm(v interface{}) ([]byte, error) {
+	values, err := formValues(v)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(values.Encode()), nil
+}
+
+// multipart中的文件解码为{"filename","contentType","content"}对象，编码时同样识别该结构
+
+func decodeMultipart(data []byte, v interface{}) error {
+	if !bytes.HasPrefix(data, []byte("--")) {
+		return errors.New("multipart: missing boundary")
+	}
+	line := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		line = data[:i]
+	}
+	boundary := strings.TrimSpace(string(line[2:]))
+	r := multipart.NewReader(bytes.NewReader(data), boundary)
+	m := make(map[string]interface{})
+	for {
+		part, err := r.NextPart()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+		content, err := ioutil.ReadAll(part)
+		if err != nil {
+			return err
+		}
+		if part.FileName() == "" {
+			addValue(m, part.FormName(), string(content))
+			continue
+		}
+		addValue(m, part.FormName(), map[string]interface{}{
+			"filename":    part.FileName(),
+			"contentType": part.Header.Get("Content-Type"),
+			"content":     string(content),
+		})
+	}
+	return setValue(v, m)
+}
+
+func encodeMultipart(v interface{}) ([]byte, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, errFormObject
+	}
+	buf := new(bytes.Buffer)
+	w := multipart.NewWriter(buf)
+	if err := w.SetBoundary(multipartBoundary); err != nil {
+		return nil, err
+	}
+	for _, key := range sortedKeys(m) {
+		list, ok := m[key].([]interface{})
+		if !ok {
+			list = []interface{}{m[key]}
+		}
+		for _, item := range list {
+			if err := writeMultipartField(w, key, item); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeMultipartField(w *multipart.Writer, name string, v interface{}) error {
+	file, ok := v.(map[string]interface{})
+	filename, isFile := file["filename"].(string)
+	if !ok || !isFile {
+		return w.WriteField(name, scalarString(v))
+	}
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", `form-data; name="`+escapeQuotes(name)+`"; filename="`+escapeQuotes(filename)+`"`)
+	contentType := scalarString(file["contentType"])
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	h.Set("Content-Type", contentType)
+	part, err := w.CreatePart(h)
+	if err != nil {
+		return err
+	}
+	_, err = part.Write([]byte(scalarString(file["content"])))
+	return err
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
@@ -0,0 +1,203 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// xml与map的转换约定：
+// 元素解码为以元素名为key的对象，属性以"-"为前缀，存在属性或子元素时文本存放在"#text"，
+// 同名的兄弟元素解码为数组；只有文本的元素解码为字符串
+const (
+	xmlAttrPrefix = "-"
+	xmlTextKey    = "#text"
+	xmlRootName   = "root"
+	xmlItemName   = "item"
+)
+
+func decodeXML(data []byte, v interface{}) error {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		t, err := d.Token()
+		if err != nil {
+			if err == io.EOF {
+				return fmt.Errorf("xml: no root element")
+			}
+			return err
+		}
+		if start, ok := t.(xml.StartElement); ok {
+			value, err := decodeXMLElement(d, start)
+			if err != nil {
+				return err
+			}
+			return setValue(v, map[string]interface{}{start.Name.Local: value})
+		}
+	}
+}
+
+func decodeXMLElement(d *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	m := make(map[string]interface{})
+	for _, attr := range start.Attr {
+		// 命名空间声明不作为数据
+		if attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns") {
+			continue
+		}
+		m[xmlAttrPrefix+attr.Name.Local] = attr.Value
+	}
+	text := new(strings.Builder)
+	for {
+		t, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch token := t.(type) {
+		case xml.StartElement:
+			child, err := decodeXMLElement(d, token)
+			if err != nil {
+				return nil, err
+			}
+			addValue(m, token.Name.Local, child)
+		case xml.CharData:
+			text.Write(token)
+		case xml.EndElement:
+			s := strings.TrimSpace(text.String())
+			if len(m) == 0 {
+				return s, nil
+			}
+			if s != "" {
+				m[xmlTextKey] = s
+			}
+			return m, nil
+		}
+	}
+}
+
+// addValue 同名的值合并为数组
+func addValue(m map[string]interface{}, key string, value interface{}) {
+	old, has := m[key]
+	if !has {
+		m[key] = value
+		return
+	}
+	if list, ok := old.([]interface{}); ok {
+		m[key] = append(list, value)
+		return
+	}
+	m[key] = []interface{}{old, value}
+}
+
+func encodeXML(v interface{}) ([]byte, error) {
+	buf := bytes.NewBufferString(xml.Header)
+	e := xml.NewEncoder(buf)
+	name, value := xmlRootName, v
+	if m, ok := v.(map[string]interface{}); ok && len(m) == 1 {
+		for key, child := range m {
+			if _, isList := child.([]interface{}); !isList {
+				name, value = key, child
+			}
+		}
+	}
+	if err := encodeXMLElement(e, name, value); err != nil {
+		return nil, err
+	}
+	if err := e.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeXMLElement(e *xml.Encoder, name string, v interface{}) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	switch value := v.(type) {
+	case map[string]interface{}:
+		keys := sortedKeys(value)
+		children := make([]string, 0, len(keys))
+		for _, key := range keys {
+			if strings.HasPrefix(key, xmlAttrPrefix) {
+				start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: key[len(xmlAttrPrefix):]}, Value: scalarString(value[key])})
+			} else if key != xmlTextKey {
+				children = append(children, key)
+			}
+		}
+		if err := e.EncodeToken(start); err != nil {
+			return err
+		}
+		if text, has := value[xmlTextKey]; has {
+			if err := e.EncodeToken(xml.CharData(scalarString(text))); err != nil {
+				return err
+			}
+		}
+		for _, key := range children {
+			if list, ok := value[key].([]interface{}); ok {
+				for _, item := range list {
+					if err := encodeXMLElement(e, key, item); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if err := encodeXMLElement(e, key, value[key]); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		if err := e.EncodeToken(start); err != nil {
+			return err
+		}
+		for _, item := range value {
+			if err := encodeXMLElement(e, xmlItemName, item); err != nil {
+				return err
+			}
+		}
+	default:
+		if err := e.EncodeToken(start); err != nil {
+			return err
+		}
+		if text := scalarString(value); text != "" {
+			if err := e.EncodeToken(xml.CharData(text)); err != nil {
+				return err
+			}
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// scalarString 将值转换为文本，对象及数组转为JSON
+func scalarString(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case []byte:
+		return string(value)
+	case bool:
+		return strconv.FormatBool(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(value), 'f', -1, 32)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, json.Number:
+		return fmt.Sprint(value)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
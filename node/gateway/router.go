@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
 	"github.com/eolinker/goku-api-gateway/goku-service/balance"
 	"github.com/eolinker/goku-api-gateway/goku-service/discovery"
 	"github.com/eolinker/goku-api-gateway/node/gateway/application"
@@ -27,9 +28,12 @@ func Parse(config *config.GokuConfig, factory router.Factory) (http.Handler, err
 		return nil, errorConfig
 	}
 
+	resetPluginErrors()
 	f := genFactory(config, factory)
+	handler := &HTTPHandler{router: f.create()}
+	commitPluginErrors()
 
-	return &HTTPHandler{router: f.create()}, nil
+	return handler, nil
 }
 
 type _RootFactory struct {
@@ -93,10 +97,12 @@ func (f *_RootFactory) genStrategy(cfg *config.StrategyConfig) *Strategy {
 		if has {
 			pluginFactory, e := plugin_loader.LoadPlugin(pluginName)
 			if e != nil {
+				pluginFailed(pluginName, s.ID, 0, e)
 				continue
 			}
 			pluginObj, err := pluginFactory.Create(authCfg, f.cluster, "", s.ID, 0)
 			if err != nil {
+				pluginFailed(pluginName, s.ID, 0, err)
 				continue
 			}
 
@@ -122,10 +128,18 @@ func (f *_RootFactory) genStrategy(cfg *config.StrategyConfig) *Strategy {
 			continue
 		}
 		for _, method := range apiContent.Methods {
-
+			if priorityRouter, ok := s.apiRouter.(router.PriorityRouter); ok && apiContent.Priority != 0 {
+				priorityRouter.AddRouterWithPriority(strings.ToUpper(method), apiContent.RequestURL, apiContent.Priority, iRouter)
+				continue
+			}
 			s.apiRouter.AddRouter(strings.ToUpper(method), apiContent.RequestURL, iRouter)
 		}
 	}
+	if reporter, ok := s.apiRouter.(router.ConflictReporter); ok {
+		for _, conflict := range reporter.Conflicts() {
+			log.Warn("strategy ", s.ID, " ignore router ", conflict)
+		}
+	}
 
 	return s
 }
@@ -172,6 +186,7 @@ func genFactory(cfg *config.GokuConfig, factory router.Factory) *_RootFactory {
 
 	discovery.ResetAllServiceConfig(cfg.DiscoverConfig)
 	balance.ResetBalances(cfg.Balance)
+	plugin_loader.SetScripts(cfg.Scripts)
 
 	beforePlugin := genBeforPlugin(cfg.Plugins.BeforePlugins, cfg.Cluster)
 
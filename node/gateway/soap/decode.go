@@ -0,0 +1,106 @@
+package soap
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/node/gateway/response"
+)
+
+var (
+	//ErrInvalidEnvelope 响应不是SOAP Envelope
+	ErrInvalidEnvelope = errors.New("soap: invalid envelope")
+)
+
+//FaultError 服务返回了SOAP Fault，StatusCode为服务响应的状态码，Fault为Decode的结果
+type FaultError struct {
+	StatusCode int
+	Fault      interface{}
+}
+
+//Error error
+func (e *FaultError) Error() string {
+	if m, ok := e.Fault.(map[string]interface{}); ok {
+		if fault, ok := m["fault"].(map[string]interface{}); ok {
+			return fmt.Sprint("soap: fault ", fault["code"], ": ", fault["message"])
+		}
+	}
+	return "soap: fault"
+}
+
+//Decode 将SOAP响应解码为JSON结构，Body只有一个元素时去掉外层的响应元素，
+// Fault统一为{"fault":{"code","message","detail"}}，此时isFault为true
+func Decode(data []byte) (result interface{}, isFault bool, err error) {
+	rp, err := response.Decode(data, response.GetDecoder(response.XML))
+	if err != nil {
+		return nil, false, err
+	}
+	root, _ := rp.Data.(map[string]interface{})
+	envelope, ok := root["Envelope"].(map[string]interface{})
+	if !ok {
+		return nil, false, ErrInvalidEnvelope
+	}
+	body, ok := envelope["Body"].(map[string]interface{})
+	if !ok {
+		return make(map[string]interface{}), false, nil
+	}
+	elements := withoutAttrs(body)
+	if fault, has := elements["Fault"]; has {
+		return map[string]interface{}{"fault": decodeFault(fault)}, true, nil
+	}
+	if len(elements) == 1 {
+		for key, value := range elements {
+			if m, ok := value.(map[string]interface{}); ok {
+				return withoutAttrs(m), false, nil
+			}
+			return map[string]interface{}{key: value}, false, nil
+		}
+	}
+	return elements, false, nil
+}
+
+func withoutAttrs(m map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if !strings.HasPrefix(k, "-") {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// decodeFault 兼容SOAP 1.1(faultcode/faultstring/detail)及1.2(Code/Reason/Detail)
+func decodeFault(v interface{}) map[string]interface{} {
+	fault, _ := v.(map[string]interface{})
+	result := map[string]interface{}{
+		"code":    text(fault["faultcode"]),
+		"message": text(fault["faultstring"]),
+		"detail":  fault["detail"],
+	}
+	if code, ok := fault["Code"].(map[string]interface{}); ok {
+		result["code"] = text(code["Value"])
+	}
+	if reason, ok := fault["Reason"].(map[string]interface{}); ok {
+		result["message"] = text(reason["Text"])
+	}
+	if detail, has := fault["Detail"]; has {
+		result["detail"] = detail
+	}
+	return result
+}
+
+// text 取元素文本，多个值时取第一个
+func text(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case map[string]interface{}:
+		return text(value["#text"])
+	case []interface{}:
+		if len(value) > 0 {
+			return text(value[0])
+		}
+	}
+	return ""
+}
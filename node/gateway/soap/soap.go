@@ -0,0 +1,282 @@
+package soap
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+const (
+	envelope11 = "http://schemas.xmlsoap.org/soap/envelope/"
+	envelope12 = "http://www.w3.org/2003/05/soap-envelope"
+
+	nsWSSE         = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-secext-1.0.xsd"
+	nsWSU          = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-utility-1.0.xsd"
+	passwordText   = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-username-token-profile-1.0#PasswordText"
+	passwordDigest = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-username-token-profile-1.0#PasswordDigest"
+	base64Binary   = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-soap-message-security-1.0#Base64Binary"
+
+	// 请求元素的命名空间前缀
+	operationPrefix = "m"
+)
+
+var (
+	//ErrInvalidParams 请求参数必须为JSON对象
+	ErrInvalidParams = errors.New("soap: request params must be a JSON object")
+)
+
+//Client 将JSON参数封装为SOAP请求
+type Client struct {
+	cfg *config.SOAPConfig
+}
+
+//New 创建SOAP客户端，未配置时返回nil
+func New(cfg *config.SOAPConfig) *Client {
+	if cfg == nil {
+		return nil
+	}
+	return &Client{cfg: cfg}
+}
+
+func (c *Client) isSOAP12() bool {
+	return c.cfg.Version == "1.2"
+}
+
+//SetHeader 设置SOAP请求的Content-Type及SOAPAction
+func (c *Client) SetHeader(header http.Header) {
+	if c.isSOAP12() {
+		contentType := "application/soap+xml; charset=utf-8"
+		if c.cfg.Action != "" {
+			contentType += `; action="` + c.cfg.Action + `"`
+		}
+		header.Set("Content-Type", contentType)
+		header.Del("SOAPAction")
+		return
+	}
+	header.Set("Content-Type", "text/xml; charset=utf-8")
+	header.Set("SOAPAction", `"`+c.cfg.Action+`"`)
+}
+
+//Envelope 生成SOAP请求，params为JSON对象，字段顺序即元素顺序
+// 与xml编码约定一致："-"前缀的字段为属性，"#text"为文本，数组展开为同名元素
+func (c *Client) Envelope(params []byte) ([]byte, error) {
+	var members []member
+	if len(bytes.TrimSpace(params)) > 0 {
+		d := json.NewDecoder(bytes.NewReader(params))
+		d.UseNumber()
+		v, err := readJSON(d)
+		if err != nil {
+			return nil, err
+		}
+		object, ok := v.([]member)
+		if !ok {
+			return nil, ErrInvalidParams
+		}
+		members = object
+	}
+
+	ns := envelope11
+	if c.isSOAP12() {
+		ns = envelope12
+	}
+	buf := bytes.NewBufferString(xml.Header)
+	w := &writer{e: xml.NewEncoder(buf)}
+	w.start("soap:Envelope", attr("xmlns:soap", ns))
+	if s := c.cfg.Security; s != nil && s.Username != "" {
+		w.start("soap:Header")
+		c.writeSecurity(w, s)
+		w.end("soap:Header")
+	}
+	w.start("soap:Body")
+	element := operationPrefix + ":" + c.cfg.Element
+	w.start(element, attr("xmlns:"+operationPrefix, c.cfg.Namespace))
+	prefix := ""
+	if c.cfg.Qualified {
+		prefix = operationPrefix + ":"
+	}
+	w.members(prefix, members)
+	w.end(element)
+	w.end("soap:Body")
+	w.end("soap:Envelope")
+	if w.err != nil {
+		return nil, w.err
+	}
+	if err := w.e.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeSecurity 写入WS-Security UsernameToken，digest模式下密码为Base64(SHA1(nonce+created+password))
+func (c *Client) writeSecurity(w *writer, s *config.WSSecurityConfig) {
+	w.start("wsse:Security", attr("xmlns:wsse", nsWSSE), attr("xmlns:wsu", nsWSU), attr("soap:mustUnderstand", "1"))
+	w.start("wsse:UsernameToken")
+	w.text("wsse:Username", s.Username)
+	if !s.Digest {
+		w.text("wsse:Password", s.Password, attr("Type", passwordText))
+	} else {
+		nonce := make([]byte, 16)
+		io.ReadFull(rand.Reader, nonce)
+		created := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+		h := sha1.New()
+		h.Write(nonce)
+		h.Write([]byte(created))
+		h.Write([]byte(s.Password))
+		w.text("wsse:Password", base64.StdEncoding.EncodeToString(h.Sum(nil)), attr("Type", passwordDigest))
+		w.text("wsse:Nonce", base64.StdEncoding.EncodeToString(nonce), attr("EncodingType", base64Binary))
+		w.text("wsu:Created", created)
+	}
+	w.end("wsse:UsernameToken")
+	w.end("wsse:Security")
+}
+
+// member 保持顺序的JSON对象字段
+type member struct {
+	key   string
+	value interface{}
+}
+
+// readJSON 读取JSON值，对象为[]member，数组为[]interface{}，其他为token
+func readJSON(d *json.Decoder) (interface{}, error) {
+	t, err := d.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := t.(json.Delim)
+	if !ok {
+		return t, nil
+	}
+	switch delim {
+	case '{':
+		object := make([]member, 0)
+		for d.More() {
+			key, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := readJSON(d)
+			if err != nil {
+				return nil, err
+			}
+			object = append(object, member{key: key.(string), value: value})
+		}
+		_, err = d.Token()
+		return object, err
+	case '[':
+		list := make([]interface{}, 0)
+		for d.More() {
+			value, err := readJSON(d)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err = d.Token()
+		return list, err
+	}
+	return nil, errors.New("soap: invalid json")
+}
+
+func attr(name, value string) xml.Attr {
+	return xml.Attr{Name: xml.Name{Local: name}, Value: value}
+}
+
+// writer 写入带前缀的元素，记录第一个错误
+type writer struct {
+	e   *xml.Encoder
+	err error
+}
+
+func (w *writer) token(t xml.Token) {
+	if w.err == nil {
+		w.err = w.e.EncodeToken(t)
+	}
+}
+
+func (w *writer) start(name string, attrs ...xml.Attr) {
+	w.token(xml.StartElement{Name: xml.Name{Local: name}, Attr: attrs})
+}
+
+func (w *writer) end(name string) {
+	w.token(xml.EndElement{Name: xml.Name{Local: name}})
+}
+
+func (w *writer) text(name, text string, attrs ...xml.Attr) {
+	w.start(name, attrs...)
+	if text != "" {
+		w.token(xml.CharData(text))
+	}
+	w.end(name)
+}
+
+func (w *writer) members(prefix string, members []member) {
+	for _, m := range members {
+		if strings.HasPrefix(m.key, "-") || m.key == "#text" {
+			continue
+		}
+		if list, ok := m.value.([]interface{}); ok {
+			for _, item := range list {
+				w.value(prefix, m.key, item)
+			}
+			continue
+		}
+		w.value(prefix, m.key, m.value)
+	}
+}
+
+func (w *writer) value(prefix, name string, v interface{}) {
+	name = prefix + name
+	switch value := v.(type) {
+	case []member:
+		attrs := make([]xml.Attr, 0)
+		text := ""
+		for _, m := range value {
+			if strings.HasPrefix(m.key, "-") {
+				attrs = append(attrs, attr(m.key[1:], scalar(m.value)))
+			} else if m.key == "#text" {
+				text = scalar(m.value)
+			}
+		}
+		w.start(name, attrs...)
+		if text != "" {
+			w.token(xml.CharData(text))
+		}
+		w.members(prefix, value)
+		w.end(name)
+	case []interface{}:
+		w.start(name)
+		for _, item := range value {
+			w.value(prefix, "item", item)
+		}
+		w.end(name)
+	default:
+		w.text(name, scalar(value))
+	}
+}
+
+func scalar(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case bool:
+		if value {
+			return "true"
+		}
+		return "false"
+	}
+	return ""
+}
@@ -0,0 +1,81 @@
+package soap
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+func TestEnvelope(t *testing.T) {
+	c := New(&config.SOAPConfig{Version: "1.1", Action: "urn:GetUser", Element: "GetUser", Namespace: "urn:user", Qualified: true})
+	data, err := c.Envelope([]byte(`{"id":12,"name":{"-lang":"en","#text":"goku"},"tag":["a","b"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><m:GetUser xmlns:m="urn:user"><m:id>12</m:id><m:name lang="en">goku</m:name><m:tag>a</m:tag><m:tag>b</m:tag></m:GetUser></soap:Body></soap:Envelope>`
+	if !strings.HasSuffix(string(data), want) {
+		t.Errorf("envelope: got %s", data)
+	}
+	header := make(http.Header)
+	c.SetHeader(header)
+	if header.Get("SOAPAction") != `"urn:GetUser"` || !strings.HasPrefix(header.Get("Content-Type"), "text/xml") {
+		t.Errorf("header: got %v", header)
+	}
+	if _, err := c.Envelope([]byte(`[1]`)); err != ErrInvalidParams {
+		t.Errorf("expected ErrInvalidParams, got %v", err)
+	}
+}
+
+func TestEnvelopeSecurity(t *testing.T) {
+	c := New(&config.SOAPConfig{Version: "1.2", Action: "urn:GetUser", Element: "GetUser", Namespace: "urn:user",
+		Security: &config.WSSecurityConfig{Username: "admin", Password: "secret", Digest: true}})
+	data, err := c.Envelope(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{`xmlns:soap="http://www.w3.org/2003/05/soap-envelope"`, "<wsse:Username>admin</wsse:Username>", "#PasswordDigest", "<wsse:Nonce", "<wsu:Created>", `<m:GetUser xmlns:m="urn:user"></m:GetUser>`} {
+		if !strings.Contains(string(data), s) {
+			t.Errorf("envelope does not contain %s: %s", s, data)
+		}
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Error("digest envelope contains the plain password")
+	}
+	header := make(http.Header)
+	c.SetHeader(header)
+	if header.Get("Content-Type") != `application/soap+xml; charset=utf-8; action="urn:GetUser"` {
+		t.Errorf("header: got %v", header)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	v, isFault, err := Decode([]byte(`<?xml version="1.0"?><soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><GetUserResponse xmlns="urn:user"><GetUserResult><id>12</id><name>goku</name></GetUserResult></GetUserResponse></soap:Body></soap:Envelope>`))
+	if err != nil || isFault {
+		t.Fatal(err, isFault)
+	}
+	want := map[string]interface{}{"GetUserResult": map[string]interface{}{"id": "12", "name": "goku"}}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("decode: got %v", v)
+	}
+
+	v, isFault, err = Decode([]byte(`<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope"><env:Body><env:Fault><env:Code><env:Value>env:Sender</env:Value></env:Code><env:Reason><env:Text xml:lang="en">invalid id</env:Text></env:Reason></env:Fault></env:Body></env:Envelope>`))
+	if err != nil || !isFault {
+		t.Fatal(err, isFault)
+	}
+	fault := v.(map[string]interface{})["fault"].(map[string]interface{})
+	if fault["code"] != "env:Sender" || fault["message"] != "invalid id" {
+		t.Errorf("fault 1.2: got %v", fault)
+	}
+
+	v, _, _ = Decode([]byte(`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body><s:Fault><faultcode>s:Client</faultcode><faultstring>bad</faultstring></s:Fault></s:Body></s:Envelope>`))
+	fault = v.(map[string]interface{})["fault"].(map[string]interface{})
+	if fault["code"] != "s:Client" || fault["message"] != "bad" {
+		t.Errorf("fault 1.1: got %v", fault)
+	}
+	if _, _, err := Decode([]byte(`<html></html>`)); err != ErrInvalidEnvelope {
+		t.Errorf("expected ErrInvalidEnvelope, got %v", err)
+	}
+}
@@ -63,14 +63,16 @@ func (r *Strategy) Router(w http.ResponseWriter, req *http.Request, ctx *common.
 		if strategyAccess.IsAuth() {
 			continue
 		}
-		fmt.Println(strategyAccess.IsAuth())
 		isContinued, err := strategyAccess.Execute(ctx)
 		if isContinued {
 			continue
 		}
 		if err != nil {
-			ctx.SetStatus(403, "403")
-			ctx.SetBody([]byte(err.Error()))
+			// 插件或执行策略已设置响应时保留
+			if ctx.StatusCode() == 0 {
+				ctx.SetStatus(403, "403")
+				ctx.SetBody([]byte(err.Error()))
+			}
 			return
 		}
 	}
@@ -87,6 +89,7 @@ func (r *Strategy) auth(ctx *common.Context) (bool, error) {
 		return false, errInfo
 	}
 
+	consumer := consumerOf(authType, ctx)
 	isContinue, err := authPlugin.Execute(ctx)
 	if isContinue == false {
 		pluginName := authNames[authType]
@@ -103,14 +106,17 @@ func (r *Strategy) auth(ctx *common.Context) (bool, error) {
 		return false, nil
 	}
 	log.Debug(requestID, " auth [", authType, "] pass")
+	// 鉴权插件自行识别出调用方时以插件为准
+	if _, has := ctx.GetCache(common.CacheConsumer); !has && consumer != "" {
+		ctx.SetCache(common.CacheConsumer, consumer)
+	}
 	return true, nil
 }
 func (r *Strategy) accessFlow(ctx *common.Context) bool {
 	for _, handler := range r.accessPlugin {
 
-		flag, _ := handler.Execute(ctx)
-
-		if flag == false && handler.IsStop() {
+		flag, err := handler.Execute(ctx)
+		if handler.IsBreak(flag, err) {
 
 			return false
 		}
@@ -119,10 +125,9 @@ func (r *Strategy) accessFlow(ctx *common.Context) bool {
 }
 
 func (r *Strategy) accessGlobalFlow(ctx *common.Context) {
-	// 全局插件不中断
+	// 全局插件不中断，错误由执行器记录
 	for _, handler := range r.globalAccessPlugin {
 		_, _ = handler.Execute(ctx)
-
 	}
 }
 
@@ -10,6 +10,26 @@ var (
 	APIMonitor diting.Histogram
 	//ProxyMonitor diting.Histogram
 	ProxyMonitor diting.Histogram
+	//AccessLogCounter access日志外部输出计数，result为sent、dropped、failed
+	AccessLogCounter diting.Counter
+	//AccessLogQueue access日志外部输出的缓冲队列长度
+	AccessLogQueue diting.Gauge
+	//PluginMonitor 插件执行耗时，result为ok、error、timeout、panic
+	PluginMonitor diting.Histogram
+	//PluginErrorCounter 插件执行出错计数，result为error、timeout、panic
+	PluginErrorCounter diting.Counter
+	//TrafficSplitCounter 流量拆分计数，split为命中的拆分标签
+	TrafficSplitCounter diting.Counter
+	//MirrorMonitor 影子请求耗时
+	MirrorMonitor diting.Histogram
+	//MirrorCounter 影子请求计数，result为sent、failed、dropped
+	MirrorCounter diting.Counter
+	//MirrorDiffCounter 影子响应比较计数，result为match、diff
+	MirrorDiffCounter diting.Counter
+	//RetryCounter 转发重试计数，result为重试原因：connect、timeout、reset、other、status，或因预算不足放弃重试的budget
+	RetryCounter diting.Counter
+	//HedgeCounter 对冲请求计数，result为sent、won，或因预算不足放弃对冲的budget
+	HedgeCounter diting.Counter
 )
 
 func initCollector(constLabels diting.Labels) {
@@ -24,9 +44,39 @@ func initCollector(constLabels diting.Labels) {
 	//APICount = diting.NewCounter(apiCounterOpt)
 
 	apiHistogramOpt := diting.NewHistogramOpts(goku_labels.Namespace, goku_labels.Subsystem, goku_labels.APIName, "api整体请求统计", constLabels, goku_labels.APIDelayLabelNames, goku_labels.APIBuckets)
-	APIMonitor = diting.NewHistogram(apiHistogramOpt)
+	APIMonitor = histograms{diting.NewHistogram(apiHistogramOpt), statistics}
 
 	proxyMonitorOpt := diting.NewHistogramOpts(goku_labels.Namespace, goku_labels.Subsystem, goku_labels.ProxyName, "转发统计", constLabels, goku_labels.ProxyDelayLabelNames, goku_labels.ProxyBuckets)
-	ProxyMonitor = diting.NewHistogram(proxyMonitorOpt)
+	ProxyMonitor = histograms{diting.NewHistogram(proxyMonitorOpt), proxyLatencies}
+
+	accessLogOpt := diting.NewCounterOpts(goku_labels.Namespace, goku_labels.Subsystem, goku_labels.AccessLogName, "access日志输出统计", constLabels, goku_labels.AccessLogLabelNames)
+	AccessLogCounter = diting.NewCounter(accessLogOpt)
+
+	accessLogQueueOpt := diting.NewGaugeOpts(goku_labels.Namespace, goku_labels.Subsystem, goku_labels.AccessLogQueueName, "access日志缓冲队列长度", constLabels, goku_labels.AccessLogQueueLabelNames)
+	AccessLogQueue = diting.NewGauge(accessLogQueueOpt)
+
+	pluginMonitorOpt := diting.NewHistogramOpts(goku_labels.Namespace, goku_labels.Subsystem, goku_labels.PluginName, "插件执行统计", constLabels, goku_labels.PluginLabelNames, goku_labels.PluginBuckets)
+	PluginMonitor = diting.NewHistogram(pluginMonitorOpt)
+
+	pluginErrorOpt := diting.NewCounterOpts(goku_labels.Namespace, goku_labels.Subsystem, goku_labels.PluginErrorName, "插件执行出错统计", constLabels, goku_labels.PluginLabelNames)
+	PluginErrorCounter = diting.NewCounter(pluginErrorOpt)
+
+	trafficSplitOpt := diting.NewCounterOpts(goku_labels.Namespace, goku_labels.Subsystem, goku_labels.TrafficSplitName, "流量拆分统计", constLabels, goku_labels.TrafficSplitLabelNames)
+	TrafficSplitCounter = diting.NewCounter(trafficSplitOpt)
+
+	mirrorMonitorOpt := diting.NewHistogramOpts(goku_labels.Namespace, goku_labels.Subsystem, goku_labels.MirrorName, "流量镜像统计", constLabels, goku_labels.MirrorLabelNames, goku_labels.ProxyBuckets)
+	MirrorMonitor = diting.NewHistogram(mirrorMonitorOpt)
+
+	mirrorCountOpt := diting.NewCounterOpts(goku_labels.Namespace, goku_labels.Subsystem, goku_labels.MirrorCountName, "流量镜像发送统计", constLabels, goku_labels.MirrorResultLabelNames)
+	MirrorCounter = diting.NewCounter(mirrorCountOpt)
+
+	mirrorDiffOpt := diting.NewCounterOpts(goku_labels.Namespace, goku_labels.Subsystem, goku_labels.MirrorDiffName, "流量镜像响应比较统计", constLabels, goku_labels.MirrorResultLabelNames)
+	MirrorDiffCounter = diting.NewCounter(mirrorDiffOpt)
+
+	retryOpt := diting.NewCounterOpts(goku_labels.Namespace, goku_labels.Subsystem, goku_labels.RetryName, "转发重试统计", constLabels, goku_labels.RetryLabelNames)
+	RetryCounter = diting.NewCounter(retryOpt)
+
+	hedgeOpt := diting.NewCounterOpts(goku_labels.Namespace, goku_labels.Subsystem, goku_labels.HedgeName, "对冲请求统计", constLabels, goku_labels.HedgeLabelNames)
+	HedgeCounter = diting.NewCounter(hedgeOpt)
 
 }
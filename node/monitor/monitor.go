@@ -6,6 +6,7 @@ import (
 
 	"github.com/eolinker/goku-api-gateway/diting"
 	goku_labels "github.com/eolinker/goku-api-gateway/goku-labels"
+	goku_trace "github.com/eolinker/goku-api-gateway/goku-trace"
 )
 
 var (
@@ -21,5 +22,8 @@ func Init(cluster string, instance string) {
 		constLabels[goku_labels.Instance] = strings.ReplaceAll(instance, ".", "_")
 
 		initCollector(constLabels)
+
+		goku_trace.SetResource("service.instance.id", instance)
+		goku_trace.SetResource("goku.cluster", cluster)
 	})
 }
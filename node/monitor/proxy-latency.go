@@ -0,0 +1,106 @@
+package monitor
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/diting"
+	goku_labels "github.com/eolinker/goku-api-gateway/goku-labels"
+	observe "github.com/eolinker/goku-api-gateway/goku-observe"
+)
+
+const (
+	// latencyPeriod 转发耗时的统计周期，分位值取自上一个完整周期
+	latencyPeriod = time.Minute
+	// latencyMinSamples 计算分位值需要的最少样本数
+	latencyMinSamples = 20
+	// statusCanceled 被主动取消的转发，不计入耗时统计
+	statusCanceled = "499"
+)
+
+var (
+	proxyLatencies = newProxyLatency(goku_labels.ProxyBuckets)
+)
+
+//proxyLatency 按接口统计的转发耗时
+type proxyLatency struct {
+	buckets []float64
+	apis    map[string]*latencyWindow
+	locker  sync.RWMutex
+}
+
+type latencyWindow struct {
+	start   time.Time
+	current observe.HistogramObserve
+	last    observe.HistogramObserve
+	locker  sync.Mutex
+}
+
+func newProxyLatency(buckets []float64) *proxyLatency {
+	return &proxyLatency{
+		buckets: buckets,
+		apis:    make(map[string]*latencyWindow),
+	}
+}
+
+//Observe observe
+func (p *proxyLatency) Observe(value float64, labels diting.Labels) {
+	if labels[goku_labels.Status] == statusCanceled {
+		return
+	}
+	api := labels[goku_labels.API]
+	p.locker.RLock()
+	w, has := p.apis[api]
+	p.locker.RUnlock()
+	if !has {
+		p.locker.Lock()
+		if w, has = p.apis[api]; !has {
+			w = &latencyWindow{start: time.Now(), current: observe.NewHistogramObserve(len(p.buckets))}
+			p.apis[api] = w
+		}
+		p.locker.Unlock()
+	}
+
+	now := time.Now()
+	w.locker.Lock()
+	if now.Sub(w.start) >= latencyPeriod {
+		w.last = w.current
+		if now.Sub(w.start) >= 2*latencyPeriod {
+			w.last = nil
+		}
+		w.current = observe.NewHistogramObserve(len(p.buckets))
+		w.start = now
+	}
+	h := w.current
+	w.locker.Unlock()
+	h.Observe(p.buckets, value)
+}
+
+func (p *proxyLatency) quantile(api string, q float64) (float64, bool) {
+	p.locker.RLock()
+	w, has := p.apis[api]
+	p.locker.RUnlock()
+	if !has {
+		return 0, false
+	}
+	w.locker.Lock()
+	last := w.last
+	if time.Since(w.start) >= 2*latencyPeriod {
+		last = nil
+	}
+	w.locker.Unlock()
+	if last == nil {
+		return 0, false
+	}
+	values, _, max, _, count := last.Collapse()
+	if count < latencyMinSamples {
+		return 0, false
+	}
+	return observe.Quantile(q, p.buckets, values, max, count), true
+}
+
+//ProxyQuantile 接口上一统计周期的转发耗时分位值，单位毫秒，样本不足时返回false
+func ProxyQuantile(apiID int, q float64) (float64, bool) {
+	return proxyLatencies.quantile(strconv.Itoa(apiID), q)
+}
@@ -0,0 +1,91 @@
+package monitor
+
+import (
+	"sync"
+
+	goku_labels "github.com/eolinker/goku-api-gateway/goku-labels"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+var (
+	reporter = newTargetStatistics(goku_labels.APIBuckets)
+)
+
+type reportKey struct {
+	api      int
+	strategy string
+	balance  string
+}
+
+// targetStatistics 按接口、策略、负载统计请求，供控制台告警计算
+type targetStatistics struct {
+	buckets []float64
+	items   map[reportKey]*entity.MonitorItem
+	locker  sync.Mutex
+}
+
+func newTargetStatistics(buckets []float64) *targetStatistics {
+	return &targetStatistics{
+		buckets: buckets,
+		items:   make(map[reportKey]*entity.MonitorItem),
+	}
+}
+
+func (s *targetStatistics) observe(api int, strategy, balance string, status int, upstreamFailed bool, delay float64) {
+	key := reportKey{api: api, strategy: strategy, balance: balance}
+
+	s.locker.Lock()
+	item, has := s.items[key]
+	if !has {
+		item = &entity.MonitorItem{
+			API:      api,
+			Strategy: strategy,
+			Balance:  balance,
+			Values:   make([]uint64, len(s.buckets)),
+		}
+		s.items[key] = item
+	}
+	item.Requests++
+	if status >= 500 {
+		item.Errors++
+	}
+	if upstreamFailed {
+		item.UpstreamErrors++
+	}
+	if delay > item.Max {
+		item.Max = delay
+	}
+	for i := len(s.buckets) - 1; i >= 0; i-- {
+		if delay >= s.buckets[i] {
+			break
+		}
+		item.Values[i]++
+	}
+	s.locker.Unlock()
+}
+
+func (s *targetStatistics) collapse() *entity.MonitorReport {
+	s.locker.Lock()
+	items := s.items
+	s.items = make(map[reportKey]*entity.MonitorItem)
+	s.locker.Unlock()
+
+	report := &entity.MonitorReport{
+		Buckets: s.buckets,
+		Items:   make([]*entity.MonitorItem, 0, len(items)),
+	}
+	for _, item := range items {
+		report.Items = append(report.Items, item)
+	}
+	return report
+}
+
+//Report 记录一次请求，upstreamFailed表示转发到负载时连接失败或返回502~504
+func Report(api int, strategy, balance string, status int, upstreamFailed bool, delay float64) {
+	reporter.observe(api, strategy, balance, status, upstreamFailed, delay)
+}
+
+//CollapseReport 获取并重置按接口、策略、负载的请求统计
+func CollapseReport() *entity.MonitorReport {
+	return reporter.collapse()
+}
@@ -0,0 +1,83 @@
+package monitor
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/eolinker/goku-api-gateway/diting"
+	goku_labels "github.com/eolinker/goku-api-gateway/goku-labels"
+	observe "github.com/eolinker/goku-api-gateway/goku-observe"
+)
+
+var (
+	statistics = newAPIStatistics(goku_labels.APIBuckets)
+)
+
+//Snapshot 一个统计周期内的请求统计
+type Snapshot struct {
+	Requests uint64
+	Errors   uint64
+	P50      float64
+	P99      float64
+}
+
+//apiStatistics 本地请求统计，用于心跳上报
+type apiStatistics struct {
+	buckets   []float64
+	histogram observe.HistogramObserve
+	errors    uint64
+	locker    sync.Mutex
+}
+
+func newAPIStatistics(buckets []float64) *apiStatistics {
+	return &apiStatistics{
+		buckets:   buckets,
+		histogram: observe.NewHistogramObserve(len(buckets)),
+	}
+}
+
+//Observe observe
+func (s *apiStatistics) Observe(value float64, labels diting.Labels) {
+	isError := false
+	if status, err := strconv.Atoi(labels[goku_labels.Status]); err == nil && status >= 500 {
+		isError = true
+	}
+	// 与Collapse互斥，避免记录到已被取走的统计周期
+	s.locker.Lock()
+	s.histogram.Observe(s.buckets, value)
+	if isError {
+		s.errors++
+	}
+	s.locker.Unlock()
+}
+
+//Collapse 返回上次调用以来的统计，并重置
+func (s *apiStatistics) Collapse() *Snapshot {
+	s.locker.Lock()
+	h := s.histogram
+	errors := s.errors
+	s.histogram = observe.NewHistogramObserve(len(s.buckets))
+	s.errors = 0
+	s.locker.Unlock()
+
+	values, _, max, _, count := h.Collapse()
+	return &Snapshot{
+		Requests: count,
+		Errors:   errors,
+		P50:      observe.Quantile(0.5, s.buckets, values, max, count),
+		P99:      observe.Quantile(0.99, s.buckets, values, max, count),
+	}
+}
+
+//CollapseStatistics 获取并重置本地请求统计
+func CollapseStatistics() *Snapshot {
+	return statistics.Collapse()
+}
+
+type histograms []diting.Histogram
+
+func (hs histograms) Observe(value float64, labels diting.Labels) {
+	for _, h := range hs {
+		h.Observe(value, labels)
+	}
+}
@@ -0,0 +1,79 @@
+package monitor
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/diting"
+	goku_labels "github.com/eolinker/goku-api-gateway/goku-labels"
+)
+
+func TestAPIStatistics_Collapse(t *testing.T) {
+	s := newAPIStatistics([]float64{10, 20, 50, 100})
+
+	for i := 0; i < 98; i++ {
+		s.Observe(5, diting.Labels{goku_labels.Status: "200"})
+	}
+	s.Observe(60, diting.Labels{goku_labels.Status: "502"})
+	s.Observe(80, diting.Labels{goku_labels.Status: "500"})
+
+	snapshot := s.Collapse()
+	if snapshot.Requests != 100 {
+		t.Errorf("requests want 100, got %d", snapshot.Requests)
+	}
+	if snapshot.Errors != 2 {
+		t.Errorf("errors want 2, got %d", snapshot.Errors)
+	}
+	if snapshot.P50 <= 0 || snapshot.P50 > 10 {
+		t.Errorf("p50 want in (0,10], got %f", snapshot.P50)
+	}
+	if snapshot.P99 <= 50 || snapshot.P99 > 100 {
+		t.Errorf("p99 want in (50,100], got %f", snapshot.P99)
+	}
+
+	empty := s.Collapse()
+	if empty.Requests != 0 || empty.P99 != 0 {
+		t.Errorf("collapse should reset statistics, got %+v", empty)
+	}
+}
+
+func TestAPIStatistics_ConcurrentCollapse(t *testing.T) {
+	s := newAPIStatistics([]float64{10, 20, 50, 100})
+
+	const workers, observes = 4, 1000
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < observes; j++ {
+				s.Observe(5, diting.Labels{goku_labels.Status: "500"})
+			}
+		}()
+	}
+	stop, done := make(chan struct{}), make(chan struct{})
+	var requests, errors uint64
+	go func() {
+		defer close(done)
+		for {
+			snapshot := s.Collapse()
+			requests += snapshot.Requests
+			errors += snapshot.Errors
+			select {
+			case <-stop:
+				return
+			default:
+			}
+		}
+	}()
+	wg.Wait()
+	close(stop)
+	<-done
+	snapshot := s.Collapse()
+	requests += snapshot.Requests
+	errors += snapshot.Errors
+
+	if requests != workers*observes || errors != workers*observes {
+		t.Errorf("want %d requests and errors, got %d %d", workers*observes, requests, errors)
+	}
+}
@@ -0,0 +1,121 @@
+package plugin_loader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+var (
+	packagesLocker sync.RWMutex
+	packages       = make(map[string]*config.PluginPackage)
+)
+
+//SetPackages 设置控制台分发的插件包清单，清单内的插件仅在本地插件包校验一致时加载
+func SetPackages(p map[string]*config.PluginPackage) {
+	m := make(map[string]*config.PluginPackage, len(p))
+	for name, pkg := range p {
+		if pkg != nil {
+			m[name] = pkg
+		}
+	}
+	packagesLocker.Lock()
+	packages = m
+	packagesLocker.Unlock()
+}
+
+//Packages 当前的插件包清单
+func Packages() map[string]*config.PluginPackage {
+	packagesLocker.RLock()
+	defer packagesLocker.RUnlock()
+	m := make(map[string]*config.PluginPackage, len(packages))
+	for name, pkg := range packages {
+		m[name] = pkg
+	}
+	return m
+}
+
+func expectedChecksum(name string) (string, bool) {
+	packagesLocker.RLock()
+	defer packagesLocker.RUnlock()
+	pkg, has := packages[name]
+	if !has {
+		return "", false
+	}
+	return pkg.Checksum, true
+}
+
+func pluginPath(name string) string {
+	path, _ := filepath.Abs(fmt.Sprintf("plugin/%s.so", name))
+	return path
+}
+
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+//Checksum 本地插件包的sha256，插件包不存在时返回空
+func Checksum(name string) string {
+	checksum, _ := fileChecksum(pluginPath(name))
+	return checksum
+}
+
+//Install 校验并写入插件包，写入临时文件后替换，不影响已加载的插件
+func Install(name string, pkg *config.PluginPackage, data []byte) error {
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != pkg.Checksum {
+		return fmt.Errorf("plugin:%s checksum mismatch", name)
+	}
+	path := pluginPath(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if e := tmp.Close(); err == nil {
+		err = e
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	packagesLocker.Lock()
+	packages[name] = pkg
+	packagesLocker.Unlock()
+	return nil
+}
+
+//Status 插件的加载状态，已加载的插件包与本地插件包不一致时needRestart为true
+func Status(name string) (int, bool, error) {
+	if _, has := globalPluginManager.getPluginHandle(name); !has {
+		_, code, err := globalPluginManager.loadPlugin(name)
+		return code, false, err
+	}
+	globalPluginManager.gloadPluginLocker.RLock()
+	loaded := globalPluginManager.checksums[name]
+	globalPluginManager.gloadPluginLocker.RUnlock()
+	return LoadOk, loaded != Checksum(name), nil
+}
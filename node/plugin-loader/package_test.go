@@ -0,0 +1,33 @@
+package plugin_loader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+func TestInstall(t *testing.T) {
+	t.Chdir(t.TempDir())
+	defer SetPackages(nil)
+
+	data := []byte("not a real plugin")
+	sum := sha256.Sum256(data)
+	pkg := &config.PluginPackage{Version: "1.0", Checksum: hex.EncodeToString(sum[:])}
+
+	if err := Install("demo", &config.PluginPackage{Checksum: "bad"}, data); err == nil {
+		t.Fatal("install with wrong checksum should fail")
+	}
+	if err := Install("demo", pkg, data); err != nil {
+		t.Fatal(err)
+	}
+	if Checksum("demo") != pkg.Checksum {
+		t.Fatal("checksum of installed package mismatch")
+	}
+
+	SetPackages(map[string]*config.PluginPackage{"demo": {Version: "2.0", Checksum: "other"}})
+	if _, code, err := globalPluginManager.loadPlugin("demo"); code != LoadFileError || err == nil {
+		t.Fatalf("unsynced package should not be loaded, got code %d err %v", code, err)
+	}
+}
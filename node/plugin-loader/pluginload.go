@@ -2,7 +2,6 @@ package plugin_loader
 
 import (
 	"fmt"
-	"path/filepath"
 	"plugin"
 	"reflect"
 	"sync"
@@ -16,6 +15,7 @@ var (
 		gloadPluginLocker: sync.RWMutex{},
 		errors:            make(map[string]error),
 		errorCodes:        make(map[string]int),
+		checksums:         make(map[string]string),
 	}
 )
 
@@ -24,6 +24,7 @@ type _GlodPluginManager struct {
 	gloadPluginLocker sync.RWMutex
 	errors            map[string]error
 	errorCodes        map[string]int
+	checksums         map[string]string
 }
 
 func (m *_GlodPluginManager) check(name string) (int, error) {
@@ -56,8 +57,11 @@ func (m *_GlodPluginManager) getPluginHandle(name string) (goku_plugin.PluginFac
 	return p, has
 }
 
-//LoadPlugin 加载插件
+//LoadPlugin 加载插件，优先使用同名的JavaScript插件
 func LoadPlugin(name string) (goku_plugin.PluginFactory, error) {
+	if factory, has, err := globalScriptManager.load(name); has {
+		return factory, err
+	}
 	factory, _, err := globalPluginManager.loadPlugin(name)
 
 	return factory, err
@@ -73,11 +77,19 @@ func (m *_GlodPluginManager) loadPlugin(name string) (goku_plugin.PluginFactory,
 	m.gloadPluginLocker.Lock()
 	defer m.gloadPluginLocker.Unlock()
 
-	path, _ := filepath.Abs(fmt.Sprintf("plugin/%s.so", name))
+	path := pluginPath(name)
+	if expected, has := expectedChecksum(name); has {
+		if checksum, _ := fileChecksum(path); checksum != expected {
+			e := fmt.Errorf("plugin:%s package is not synced from console", name)
+			m.errors[name] = e
+			m.errorCodes[name] = LoadFileError
+			return nil, LoadFileError, e
+		}
+	}
 
 	pdll, err := plugin.Open(path)
 	if err != nil {
-		e := fmt.Errorf("plugin:%s ", name, err.Error())
+		e := fmt.Errorf("plugin:%s %s", name, err.Error())
 		m.errors[name] = e
 		m.errorCodes[name] = LoadFileError
 		return nil, LoadFileError, e
@@ -114,6 +126,7 @@ func (m *_GlodPluginManager) loadPlugin(name string) (goku_plugin.PluginFactory,
 	m.gloadPlugin[name] = factory
 	m.errorCodes[name] = LoadOk
 	m.errors[name] = nil
+	m.checksums[name], _ = fileChecksum(path)
 	return factory, LoadOk, nil
 
 }
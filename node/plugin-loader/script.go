@@ -0,0 +1,81 @@
+package plugin_loader
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	plugin_script "github.com/eolinker/goku-api-gateway/node/plugin-script"
+	goku_plugin "github.com/eolinker/goku-plugin"
+)
+
+var (
+	globalScriptManager = &_ScriptManager{
+		scripts:   make(map[string]*config.PluginScript),
+		factories: make(map[string]goku_plugin.PluginFactory),
+	}
+)
+
+// _ScriptManager JavaScript插件，同名时优先于动态库插件
+type _ScriptManager struct {
+	locker    sync.RWMutex
+	scripts   map[string]*config.PluginScript
+	factories map[string]goku_plugin.PluginFactory
+}
+
+//SetScripts 设置当前配置中的JavaScript插件，脚本内容未变化的插件复用已编译的工厂
+func SetScripts(scripts map[string]*config.PluginScript) {
+	globalScriptManager.reset(scripts)
+}
+
+func scriptKey(name string, s *config.PluginScript) string {
+	sum := md5.Sum([]byte(fmt.Sprintf("%s|%d|%d|%s", name, s.Timeout, s.MemoryLimit, s.Source)))
+	return hex.EncodeToString(sum[:])
+}
+
+func (m *_ScriptManager) reset(scripts map[string]*config.PluginScript) {
+	m.locker.Lock()
+	defer m.locker.Unlock()
+
+	factories := make(map[string]goku_plugin.PluginFactory)
+	for name, s := range scripts {
+		if s == nil {
+			continue
+		}
+		key := scriptKey(name, s)
+		if f, has := m.factories[key]; has {
+			factories[key] = f
+		}
+	}
+	m.scripts = scripts
+	m.factories = factories
+}
+
+func (m *_ScriptManager) load(name string) (goku_plugin.PluginFactory, bool, error) {
+	m.locker.RLock()
+	s, has := m.scripts[name]
+	if !has || s == nil {
+		m.locker.RUnlock()
+		return nil, false, nil
+	}
+	key := scriptKey(name, s)
+	f, has := m.factories[key]
+	m.locker.RUnlock()
+	if has {
+		return f, true, nil
+	}
+
+	m.locker.Lock()
+	defer m.locker.Unlock()
+	if f, has := m.factories[key]; has {
+		return f, true, nil
+	}
+	f, err := plugin_script.NewFactory(name, s)
+	if err != nil {
+		return nil, true, fmt.Errorf("plugin script:%s %s", name, err.Error())
+	}
+	m.factories[key] = f
+	return f, true, nil
+}
@@ -0,0 +1,199 @@
+package plugin_script
+
+import (
+	"net/http"
+	"time"
+
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	goku_plugin "github.com/eolinker/goku-plugin"
+	goredis "github.com/go-redis/redis"
+)
+
+// jsObject 传入脚本的对象，函数通过otto的反射调用
+type jsObject = map[string]interface{}
+
+func headerObject(h goku_plugin.HeaderReader) jsObject {
+	return jsObject{
+		"getHeader": h.GetHeader,
+		"headers":   func() map[string]string { return flatHeader(h.Headers()) },
+	}
+}
+
+func headerWriterObject(o jsObject, h goku_plugin.HeaderWriter) jsObject {
+	o["setHeader"] = h.SetHeader
+	o["addHeader"] = h.AddHeader
+	o["delHeader"] = h.DelHeader
+	return o
+}
+
+func flatHeader(header http.Header) map[string]string {
+	m := make(map[string]string, len(header))
+	for key := range header {
+		m[key] = header.Get(key)
+	}
+	return m
+}
+
+func cookieObject(o jsObject, c goku_plugin.CookieReader) jsObject {
+	o["cookie"] = func(name string) string {
+		cookie, err := c.Cookie(name)
+		if err != nil {
+			return ""
+		}
+		return cookie.Value
+	}
+	return o
+}
+
+func rawBody(b goku_plugin.BodyDataReader) string {
+	body, err := b.RawBody()
+	if err != nil {
+		return ""
+	}
+	return string(body)
+}
+
+// requestObject 原始请求，只读
+func requestObject(r goku_plugin.RequestReader) jsObject {
+	o := cookieObject(headerObject(r), r)
+	o["method"] = r.Method
+	o["url"] = func() string { return r.URL().String() }
+	o["path"] = func() string { return r.URL().Path }
+	o["query"] = func(name string) string { return r.URL().Query().Get(name) }
+	o["host"] = r.Host
+	o["remoteAddr"] = r.RemoteAddr
+	o["body"] = func() string { return rawBody(r) }
+	o["form"] = r.GetForm
+	return o
+}
+
+// proxyObject 转发请求，可修改
+func proxyObject(r goku_plugin.Request) jsObject {
+	o := headerWriterObject(cookieObject(headerObject(r), r), r)
+	o["query"] = func(name string) string { return r.Querys().Get(name) }
+	o["body"] = func() string { return rawBody(r) }
+	o["setBody"] = func(contentType, body string) { r.SetRaw(contentType, []byte(body)) }
+	o["setForm"] = func(key, value string) bool { return r.SetToForm(key, value) == nil }
+	o["targetURL"] = r.TargetURL
+	o["targetServer"] = r.TargetServer
+	return o
+}
+
+// responseObject 返回给客户端的响应
+func responseObject(ctx goku_plugin.Context) jsObject {
+	o := headerWriterObject(headerObject(ctx), ctx)
+	o["statusCode"] = ctx.StatusCode
+	o["setStatus"] = func(code int) { ctx.SetStatus(code, http.StatusText(code)) }
+	o["body"] = func() string { return string(ctx.GetBody()) }
+	o["setBody"] = func(body string) { ctx.SetBody([]byte(body)) }
+	return o
+}
+
+// proxyResponseObject 转发后的响应，只读
+func proxyResponseObject(r goku_plugin.ResponseReader) jsObject {
+	if r == nil {
+		return nil
+	}
+	o := cookieObject(headerObject(r), r)
+	o["statusCode"] = r.StatusCode
+	o["body"] = func() string { return string(r.GetBody()) }
+	return o
+}
+
+func storeObject(ctx goku_plugin.StoreContainer) jsObject {
+	return jsObject{
+		"get": func() interface{} { return ctx.Store().Get() },
+		"set": func(value interface{}) { ctx.Store().Set(value) },
+	}
+}
+
+// redisObject 默认Redis的常用命令，未配置Redis时为null
+// 命令出错时记录日志并返回零值，脚本中不需要处理多返回值
+func redisObject() jsObject {
+	redis := goku_plugin.GetRedis()
+	if redis == nil {
+		return nil
+	}
+	return jsObject{
+		"get": func(key string) string { return redisString(redis.Get(key).Result()) },
+		"set": func(key, value string, seconds int64) bool {
+			return redisStatus(redis.Set(key, value, time.Duration(seconds)*time.Second).Result())
+		},
+		"del":    func(key string) int64 { return redisInt(redis.Del(key).Result()) },
+		"incr":   func(key string) int64 { return redisInt(redis.Incr(key).Result()) },
+		"incrBy": func(key string, value int64) int64 { return redisInt(redis.IncrBy(key, value).Result()) },
+		"expire": func(key string, seconds int64) bool {
+			return redisBool(redis.Expire(key, time.Duration(seconds)*time.Second).Result())
+		},
+		"hget": func(key, field string) string { return redisString(redis.HGet(key, field).Result()) },
+		"hset": func(key, field, value string) bool { return redisBool(redis.HSet(key, field, value).Result()) },
+	}
+}
+
+func redisOK(err error) bool {
+	if err != nil {
+		log.Warn("plugin script redis:", err)
+		return false
+	}
+	return true
+}
+
+func redisStatus(_ string, err error) bool {
+	return redisOK(err)
+}
+
+func redisBool(v bool, err error) bool {
+	return redisOK(err) && v
+}
+
+func redisString(v string, err error) string {
+	if err != nil && err != goredis.Nil {
+		log.Warn("plugin script redis:", err)
+	}
+	return v
+}
+
+func redisInt(v int64, err error) int64 {
+	redisOK(err)
+	return v
+}
+
+func contextObject(ctx goku_plugin.Context) jsObject {
+	return jsObject{
+		"requestId":          ctx.RequestId,
+		"response":           responseObject(ctx),
+		"store":              storeObject(ctx),
+		"setCache":           ctx.SetCache,
+		"getCache":           func(name string) interface{} { v, _ := ctx.GetCache(name); return v },
+		"finalTargetServer":  ctx.FinalTargetServer,
+		"retryTargetServers": ctx.RetryTargetServers,
+		"redis":              redisObject(),
+	}
+}
+
+func apiInfoObject(o jsObject, info goku_plugin.ContextApiInfo) jsObject {
+	o["apiId"] = info.ApiID
+	o["strategyId"] = info.StrategyId
+	o["strategyName"] = info.StrategyName
+	return o
+}
+
+func beforeMatchContext(ctx goku_plugin.ContextBeforeMatch) jsObject {
+	o := contextObject(ctx)
+	o["request"] = requestObject(ctx.Request())
+	o["proxy"] = proxyObject(ctx.Proxy())
+	return o
+}
+
+func accessContext(ctx goku_plugin.ContextAccess) jsObject {
+	o := apiInfoObject(contextObject(ctx), ctx)
+	o["request"] = requestObject(ctx.Request())
+	o["proxy"] = proxyObject(ctx.Proxy())
+	return o
+}
+
+func proxyContext(ctx goku_plugin.ContextProxy) jsObject {
+	o := apiInfoObject(contextObject(ctx), ctx)
+	o["proxyResponse"] = proxyResponseObject(ctx.ProxyResponse())
+	return o
+}
@@ -0,0 +1,258 @@
+package plugin_script
+
+import (
+	"errors"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/robertkrimen/otto"
+)
+
+const (
+	//DefaultDeadline 单次执行的默认期限，按实际经过的时间计算，包含脚本调用原生方法的耗时
+	DefaultDeadline = time.Millisecond * 100
+	//DefaultMemoryLimit 脚本变量默认允许占用的内存上限
+	DefaultMemoryLimit = 64 << 20
+
+	stackDepthLimit = 256
+	watchInterval   = time.Millisecond * 5
+	// memoryCheckTicks 每隔多少次检查统计一次脚本变量占用的内存
+	memoryCheckTicks = 4
+)
+
+var (
+	errDeadline    = errors.New("script execution exceeds deadline")
+	errMemoryLimit = errors.New("script exceeds memory limit")
+)
+
+// vmPool 已执行过脚本的虚拟机池，执行超时或超限的虚拟机被丢弃
+type vmPool struct {
+	script      *otto.Script
+	vms         chan *sandbox
+	deadline    time.Duration
+	memoryLimit uint64
+}
+
+func newVMPool(script *otto.Script, deadline time.Duration, memoryLimit uint64) *vmPool {
+	if deadline <= 0 {
+		deadline = DefaultDeadline
+	}
+	if memoryLimit == 0 {
+		memoryLimit = DefaultMemoryLimit
+	}
+	return &vmPool{
+		script:      script,
+		vms:         make(chan *sandbox, runtime.NumCPU()*2),
+		deadline:    deadline,
+		memoryLimit: memoryLimit,
+	}
+}
+
+// sandbox 虚拟机及其全局对象，全局对象用于统计脚本变量占用的内存
+type sandbox struct {
+	vm     *otto.Otto
+	global *otto.Object
+}
+
+func (p *vmPool) get() (*sandbox, error) {
+	select {
+	case s := <-p.vms:
+		return s, nil
+	default:
+	}
+	s, err := newSandbox()
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.vm.Run(p.script)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (p *vmPool) put(s *sandbox) {
+	// 丢弃执行结束后才送达的内存统计
+	select {
+	case <-s.vm.Interrupt:
+	default:
+	}
+	select {
+	case p.vms <- s:
+	default:
+	}
+}
+
+// newSandbox 创建沙箱虚拟机，脚本只能访问传入的ctx
+func newSandbox() (*sandbox, error) {
+	vm := otto.New()
+	vm.SetStackDepthLimit(stackDepthLimit)
+	vm.Interrupt = make(chan func(), 1)
+	global, err := vm.Object("this")
+	if err != nil {
+		return nil, err
+	}
+	return &sandbox{vm: vm, global: global}, nil
+}
+
+// run 在虚拟机中执行fn，超过执行期限或脚本变量占用的内存超过上限时中断
+// 超过期限的虚拟机不再放回池中
+func (p *vmPool) run(fn func(vm *otto.Otto) (otto.Value, error)) (result otto.Value, err error) {
+	s, err := p.get()
+	if err != nil {
+		return otto.UndefinedValue(), err
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan bool, 1)
+	go p.watch(s, done, stopped)
+
+	defer func() {
+		close(done)
+		interrupted := <-stopped
+		if r := recover(); r != nil {
+			e, ok := r.(error)
+			if !ok || (e != errDeadline && e != errMemoryLimit) {
+				panic(r)
+			}
+			result, err = otto.UndefinedValue(), e
+			return
+		}
+		if interrupted {
+			// 在原生方法中超过期限，返回后未再执行语句，中断没有执行
+			result, err = otto.UndefinedValue(), errDeadline
+			return
+		}
+		p.put(s)
+	}()
+	return fn(s.vm)
+}
+
+// watch 定时检查是否超过执行期限，并通过Interrupt在虚拟机内统计脚本变量占用的内存，超限时中断脚本
+func (p *vmPool) watch(s *sandbox, done chan struct{}, stopped chan bool) {
+	deadline := time.Now().Add(p.deadline)
+	measure := func() {
+		if !newMeter(p.memoryLimit).sandbox(s) {
+			panic(errMemoryLimit)
+		}
+	}
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	for tick := 1; ; tick++ {
+		select {
+		case <-done:
+			stopped <- false
+			return
+		case now := <-ticker.C:
+			if !now.After(deadline) {
+				if tick%memoryCheckTicks == 0 {
+					// 上次统计尚未执行时跳过
+					select {
+					case s.vm.Interrupt <- measure:
+					default:
+					}
+				}
+				continue
+			}
+			// 脚本在原生方法中时，尚未执行的内存统计会占用中断通道，以超时中断替换
+			select {
+			case <-s.vm.Interrupt:
+			default:
+			}
+			select {
+			case s.vm.Interrupt <- func() {
+				panic(errDeadline)
+			}:
+			case <-done:
+				stopped <- false
+				return
+			}
+			<-done
+			stopped <- true
+			return
+		}
+	}
+}
+
+// meter 估算脚本可访问的变量占用的内存，只在虚拟机所在的goroutine中使用
+// 字符串按长度计算，数组和对象递归统计其元素
+type meter struct {
+	limit   uint64
+	size    uint64
+	visited map[otto.Value]bool
+}
+
+const (
+	valueSize  = 16
+	objectSize = 64
+)
+
+func newMeter(limit uint64) *meter {
+	return &meter{
+		limit:   limit,
+		visited: make(map[otto.Value]bool),
+	}
+}
+
+// sandbox 统计全局变量及当前调用栈中的局部变量，未超过上限时返回true
+func (m *meter) sandbox(s *sandbox) bool {
+	if !m.value(s.global.Value()) {
+		return false
+	}
+	for _, v := range s.vm.ContextSkip(-1, false).Symbols {
+		if !m.value(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *meter) add(size uint64) bool {
+	m.size += size
+	return m.size <= m.limit
+}
+
+func (m *meter) value(v otto.Value) bool {
+	if v.IsString() {
+		return m.add(valueSize + uint64(len(v.String())))
+	}
+	if !v.IsObject() {
+		return m.add(valueSize)
+	}
+	if m.visited[v] {
+		return true
+	}
+	m.visited[v] = true
+	if !m.add(objectSize) {
+		return false
+	}
+
+	o := v.Object()
+	switch o.Class() {
+	case "Array":
+		length, _ := o.Get("length")
+		n, _ := length.ToInteger()
+		for i := int64(0); i < n; i++ {
+			item, err := o.Get(strconv.FormatInt(i, 10))
+			if err != nil {
+				return true
+			}
+			if !m.value(item) {
+				return false
+			}
+		}
+	case "Object", "environment":
+		for _, key := range o.Keys() {
+			item, err := o.Get(key)
+			if err != nil {
+				return true
+			}
+			if !m.add(uint64(len(key))) || !m.value(item) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,142 @@
+package plugin_script
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	goku_plugin "github.com/eolinker/goku-plugin"
+	"github.com/robertkrimen/otto"
+)
+
+const (
+	phaseBeforeMatch = "beforeMatch"
+	phaseAccess      = "access"
+	phaseProxy       = "proxy"
+)
+
+var phases = []string{phaseBeforeMatch, phaseAccess, phaseProxy}
+
+//Check 检查脚本语法并确认至少定义了beforeMatch、access、proxy中的一个函数
+func Check(source string) error {
+	_, err := compile("check", source, DefaultDeadline, DefaultMemoryLimit)
+	return err
+}
+
+//NewFactory 根据脚本创建插件工厂
+func NewFactory(name string, conf *config.PluginScript) (goku_plugin.PluginFactory, error) {
+	if conf == nil {
+		return nil, fmt.Errorf("plugin script %s is empty", name)
+	}
+	return compile(name, conf.Source, time.Duration(conf.Timeout)*time.Millisecond, uint64(conf.MemoryLimit)<<20)
+}
+
+func compile(name, source string, deadline time.Duration, memoryLimit uint64) (*scriptFactory, error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, fmt.Errorf("plugin script %s is empty", name)
+	}
+	script, err := otto.New().Compile(name+".js", source)
+	if err != nil {
+		return nil, err
+	}
+	f := &scriptFactory{
+		name:   name,
+		pool:   newVMPool(script, deadline, memoryLimit),
+		phases: make(map[string]bool),
+	}
+
+	_, err = f.pool.run(func(vm *otto.Otto) (otto.Value, error) {
+		for _, phase := range phases {
+			fn, err := vm.Get(phase)
+			if err != nil {
+				return otto.UndefinedValue(), err
+			}
+			f.phases[phase] = fn.IsFunction()
+		}
+		return otto.UndefinedValue(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(f.phases) == 0 || !(f.phases[phaseBeforeMatch] || f.phases[phaseAccess] || f.phases[phaseProxy]) {
+		return nil, errors.New("plugin script must define function beforeMatch, access or proxy")
+	}
+	return f, nil
+}
+
+type scriptFactory struct {
+	name   string
+	pool   *vmPool
+	phases map[string]bool
+}
+
+//Create 创建插件实例，配置为json时以对象传入脚本，否则以字符串传入
+func (f *scriptFactory) Create(conf string, clusterName string, updateTag string, strategyID string, apiID int) (*goku_plugin.PluginObj, error) {
+	var value interface{} = conf
+	if strings.TrimSpace(conf) != "" {
+		var v interface{}
+		if err := json.Unmarshal([]byte(conf), &v); err == nil {
+			value = v
+		}
+	}
+	p := &scriptPlugin{
+		factory: f,
+		config:  value,
+	}
+
+	obj := new(goku_plugin.PluginObj)
+	if f.phases[phaseBeforeMatch] {
+		obj.BeforeMatch = p
+	}
+	if f.phases[phaseAccess] {
+		obj.Access = p
+	}
+	if f.phases[phaseProxy] {
+		obj.Proxy = p
+	}
+	return obj, nil
+}
+
+type scriptPlugin struct {
+	factory *scriptFactory
+	config  interface{}
+}
+
+//BeforeMatch beforeMatch
+func (p *scriptPlugin) BeforeMatch(ctx goku_plugin.ContextBeforeMatch) (bool, error) {
+	return p.call(phaseBeforeMatch, beforeMatchContext(ctx))
+}
+
+//Access access
+func (p *scriptPlugin) Access(ctx goku_plugin.ContextAccess) (bool, error) {
+	return p.call(phaseAccess, accessContext(ctx))
+}
+
+//Proxy proxy
+func (p *scriptPlugin) Proxy(ctx goku_plugin.ContextProxy) (bool, error) {
+	return p.call(phaseProxy, proxyContext(ctx))
+}
+
+// call 执行脚本函数，返回false时中断后续处理，脚本抛出的异常作为错误返回
+func (p *scriptPlugin) call(phase string, ctx jsObject) (bool, error) {
+	result, err := p.factory.pool.run(func(vm *otto.Otto) (otto.Value, error) {
+		fn, err := vm.Get(phase)
+		if err != nil {
+			return otto.UndefinedValue(), err
+		}
+		return fn.Call(otto.NullValue(), ctx, p.config)
+	})
+	if err != nil {
+		log.Warn("plugin script ", p.factory.name, " ", phase, ":", err)
+		return false, err
+	}
+	if result.IsBoolean() {
+		isContinue, _ := result.ToBoolean()
+		return isContinue, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,110 @@
+package plugin_script
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+func newTestPlugin(t *testing.T, source string, conf string) *scriptPlugin {
+	f, err := NewFactory("test", &config.PluginScript{Source: source, Timeout: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, err := f.Create(conf, "", "", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.Access == nil {
+		t.Fatal("access not found")
+	}
+	return obj.Access.(*scriptPlugin)
+}
+
+func TestCheck(t *testing.T) {
+	if err := Check("function access(ctx, config) { return true }"); err != nil {
+		t.Error(err)
+	}
+	if err := Check("var a = 1"); err == nil {
+		t.Error("script without phase function should be rejected")
+	}
+	if err := Check("function access(ctx {"); err == nil {
+		t.Error("syntax error should be rejected")
+	}
+}
+
+func TestCall(t *testing.T) {
+	p := newTestPlugin(t, `function access(ctx, config) {
+		ctx.response.setStatus(config.status)
+		return false
+	}`, `{"status":403}`)
+
+	var status int
+	isContinue, err := p.call(phaseAccess, jsObject{
+		"response": jsObject{"setStatus": func(code int) { status = code }},
+	})
+	if err != nil || isContinue || status != 403 {
+		t.Errorf("got continue=%v status=%d err=%v", isContinue, status, err)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	p := newTestPlugin(t, "function access(ctx, config) { while (true) {} }", "")
+
+	start := time.Now()
+	isContinue, err := p.call(phaseAccess, jsObject{})
+	if err != errDeadline || isContinue {
+		t.Errorf("got continue=%v err=%v", isContinue, err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("script not interrupted in time")
+	}
+}
+
+func TestMemoryLimit(t *testing.T) {
+	sources := []string{
+		// 全局变量
+		`var data = []
+		function access(ctx, config) { while (true) { data.push(new Array(1024).join("x")) } }`,
+		// 局部变量
+		`function access(ctx, config) { var a = []; while (true) { a.push({key: "abcdefghij"}) } }`,
+	}
+	for _, source := range sources {
+		f, err := NewFactory("test", &config.PluginScript{Source: source, Timeout: 30000, MemoryLimit: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		obj, err := f.Create("", "", "", "", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		isContinue, err := obj.Access.(*scriptPlugin).call(phaseAccess, jsObject{})
+		if err != errMemoryLimit || isContinue {
+			t.Errorf("got continue=%v err=%v", isContinue, err)
+		}
+	}
+}
+
+func TestDeadlineInNativeCall(t *testing.T) {
+	p := newTestPlugin(t, "function access(ctx, config) { return ctx.sleep() }", "")
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := p.call(phaseAccess, jsObject{
+			"sleep": func() bool {
+				time.Sleep(150 * time.Millisecond)
+				return true
+			},
+		})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != errDeadline {
+			t.Errorf("got err=%v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("script blocked after deadline in native call")
+	}
+}
@@ -0,0 +1,48 @@
+package router
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+//DefaultFactory 未配置路由实现时使用的路由
+const DefaultFactory = "httprouter"
+
+var (
+	factories = make(map[string]Factory)
+	locker    sync.RWMutex
+)
+
+//Register 注册路由实现
+func Register(name string, factory Factory) {
+	locker.Lock()
+	factories[name] = factory
+	locker.Unlock()
+}
+
+//GetFactory 获取路由实现，name为空时使用默认路由
+func GetFactory(name string) (Factory, error) {
+	if name == "" {
+		name = DefaultFactory
+	}
+	locker.RLock()
+	factory, has := factories[name]
+	locker.RUnlock()
+	if !has {
+		return nil, fmt.Errorf("router %s not found", name)
+	}
+	return factory, nil
+}
+
+//Names 已注册的路由实现
+func Names() []string {
+	locker.RLock()
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	locker.RUnlock()
+	sort.Strings(names)
+	return names
+}
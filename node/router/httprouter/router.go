@@ -1,6 +1,7 @@
 package httprouter
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/eolinker/goku-api-gateway/goku-node/common"
@@ -12,6 +13,13 @@ import (
 type HTTPRouter struct {
 }
 
+//Name 路由名称
+const Name = "httprouter"
+
+func init() {
+	router.Register(Name, Factory())
+}
+
 //Factory factory
 func Factory() router.Factory {
 	return &HTTPRouter{}
@@ -25,12 +33,55 @@ func (*HTTPRouter) New() router.APIRouter {
 //Engine engine
 type Engine struct {
 	router httprouter.Router
+	// scratch 包含与router相同的规则，新规则先加入scratch检查冲突
+	scratch   *httprouter.Router
+	routes    []route
+	conflicts []string
+}
+
+type route struct {
+	method string
+	path   string
+	handle httprouter.Handle
+}
+
+// handle httprouter在规则冲突时panic，此时路由树可能已被部分修改，
+// 因此规则先加入scratch，无冲突时才加入router；冲突时记录并忽略该规则，按已加入的规则重建scratch
+func (r *Engine) handle(method, path string, handle httprouter.Handle) {
+	if r.scratch == nil {
+		r.scratch = new(httprouter.Router)
+	}
+	if err := tryHandle(r.scratch, method, path, handle); err != nil {
+		r.conflicts = append(r.conflicts, fmt.Sprintf("%s %s: %v", method, path, err))
+		r.scratch = new(httprouter.Router)
+		for _, rt := range r.routes {
+			r.scratch.Handle(rt.method, rt.path, rt.handle)
+		}
+		return
+	}
+	r.routes = append(r.routes, route{method: method, path: path, handle: handle})
+	r.router.Handle(method, path, handle)
+}
+
+func tryHandle(router *httprouter.Router, method, path string, handle httprouter.Handle) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("%v", e)
+		}
+	}()
+	router.Handle(method, path, handle)
+	return nil
+}
+
+//Conflicts 冲突的规则
+func (r *Engine) Conflicts() []string {
+	return r.conflicts
 }
 
 //AddRouter addRouter
 func (r *Engine) AddRouter(method, path string, router router.IRouter) {
 
-	r.router.Handle(method, path,
+	r.handle(method, path,
 		func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
 			ctx := ContextFromRequest(req)
 			ctx.RestfulParam = make(map[string]string)
@@ -44,7 +95,7 @@ func (r *Engine) AddRouter(method, path string, router router.IRouter) {
 
 //HandleFunc handleFunc
 func (r *Engine) HandleFunc(method, path string, handler router.HandleFunc) {
-	r.router.Handle(method, path, func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	r.handle(method, path, func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
 		ctx := ContextFromRequest(req)
 		handler(ctx)
 	})
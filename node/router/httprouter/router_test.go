@@ -0,0 +1,37 @@
+package httprouter
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+)
+
+func TestConflicts(t *testing.T) {
+	r := new(Engine)
+	var matched string
+	for _, path := range []string{"/user/:id", "/user/:name/posts", "/user/:id/orders", "/src/*filepath", "/src/main.go"} {
+		path := path
+		r.HandleFunc("GET", path, func(ctx *common.Context) {
+			matched = path
+		})
+	}
+	if len(r.Conflicts()) != 2 {
+		t.Fatalf("conflicts: got %v", r.Conflicts())
+	}
+
+	for url, want := range map[string]string{
+		"/user/1":        "/user/:id",
+		"/user/1/orders": "/user/:id/orders",
+		"/user/1/posts":  "",
+		"/src/main.go":   "/src/*filepath",
+	} {
+		matched = ""
+		req := httptest.NewRequest("GET", url, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req, common.NewContext(req, "1", w))
+		if matched != want {
+			t.Errorf("%s: got %q, want %q", url, matched, want)
+		}
+	}
+}
@@ -31,3 +31,13 @@ type APIRouter interface {
 type Factory interface {
 	New() APIRouter
 }
+
+//PriorityRouter 支持显式优先级的路由，优先级高的规则先匹配
+type PriorityRouter interface {
+	AddRouterWithPriority(method, path string, priority int, router IRouter)
+}
+
+//ConflictReporter 能够报告冲突规则的路由，冲突的规则不会生效
+type ConflictReporter interface {
+	Conflicts() []string
+}
@@ -0,0 +1,186 @@
+package tolerant
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type segmentKind int
+
+const (
+	kindCatchAll segmentKind = iota
+	kindParam
+	kindRegex
+	kindStatic
+)
+
+type segment struct {
+	kind  segmentKind
+	value string
+	name  string
+	regex *regexp.Regexp
+}
+
+// pattern 解析后的路由规则
+type pattern struct {
+	host     string
+	segments []segment
+}
+
+// parsePattern 解析路由规则，规则不以"/"开头时"/"之前的部分为host，支持"*.example.com"
+// 路径段支持静态值、:name、{name}、{name:正则}以及位于末尾的*name
+func parsePattern(path string) (*pattern, error) {
+	p := new(pattern)
+	if !strings.HasPrefix(path, "/") {
+		i := strings.Index(path, "/")
+		if i < 0 {
+			p.host, path = path, "/"
+		} else {
+			p.host, path = path[:i], path[i:]
+		}
+		p.host = strings.ToLower(p.host)
+	}
+	parts := splitPath(path)
+	p.segments = make([]segment, 0, len(parts))
+	for i, part := range parts {
+		switch {
+		case strings.HasPrefix(part, "*"):
+			if i != len(parts)-1 {
+				return nil, fmt.Errorf("catch-all must be the last segment in %s", path)
+			}
+			p.segments = append(p.segments, segment{kind: kindCatchAll, name: part[1:]})
+		case strings.HasPrefix(part, ":"):
+			if len(part) == 1 {
+				return nil, fmt.Errorf("empty param name in %s", path)
+			}
+			p.segments = append(p.segments, segment{kind: kindParam, name: part[1:]})
+		case strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}"):
+			inner := part[1 : len(part)-1]
+			name, expr := inner, ""
+			if i := strings.Index(inner, ":"); i >= 0 {
+				name, expr = inner[:i], inner[i+1:]
+			}
+			if name == "" {
+				return nil, fmt.Errorf("empty param name in %s", path)
+			}
+			if expr == "" {
+				p.segments = append(p.segments, segment{kind: kindParam, name: name})
+				continue
+			}
+			regex, err := regexp.Compile("^(?:" + expr + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("invalid regex of param %s in %s:%s", name, path, err)
+			}
+			p.segments = append(p.segments, segment{kind: kindRegex, name: name, value: expr, regex: regex})
+		default:
+			p.segments = append(p.segments, segment{kind: kindStatic, value: part})
+		}
+	}
+	return p, nil
+}
+
+// splitPath 拆分路径，末尾的"/"可有可无
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// shape 路由规则的形状，参数名不同但形状相同的规则互相冲突
+func (p *pattern) shape() string {
+	var b strings.Builder
+	b.WriteString(p.host)
+	for _, s := range p.segments {
+		b.WriteString("/")
+		switch s.kind {
+		case kindStatic:
+			b.WriteString(s.value)
+		case kindParam:
+			b.WriteString(":")
+		case kindRegex:
+			b.WriteString("{" + s.value + "}")
+		case kindCatchAll:
+			b.WriteString("*")
+		}
+	}
+	return b.String()
+}
+
+// firstStatic 第一个路径段为静态值时返回该值，用于索引
+func (p *pattern) firstStatic() (string, bool) {
+	if len(p.segments) == 0 || p.segments[0].kind != kindStatic {
+		return "", false
+	}
+	return p.segments[0].value, true
+}
+
+// hostRank 精确host优先于通配host，通配host优先于不限host
+func (p *pattern) hostRank() int {
+	switch {
+	case p.host == "":
+		return 0
+	case strings.HasPrefix(p.host, "*."):
+		return 1
+	}
+	return 2
+}
+
+// moreSpecific 逐段比较，静态值优先于正则参数，正则参数优先于参数，参数优先于*
+func (p *pattern) moreSpecific(o *pattern) bool {
+	if p.hostRank() != o.hostRank() {
+		return p.hostRank() > o.hostRank()
+	}
+	for i := 0; i < len(p.segments) && i < len(o.segments); i++ {
+		if p.segments[i].kind != o.segments[i].kind {
+			return p.segments[i].kind > o.segments[i].kind
+		}
+	}
+	return len(p.segments) > len(o.segments)
+}
+
+func (p *pattern) matchHost(host string) bool {
+	if p.host == "" {
+		return true
+	}
+	if strings.HasPrefix(p.host, "*.") {
+		return strings.HasSuffix(host, p.host[1:])
+	}
+	return host == p.host
+}
+
+// match 匹配host及路径段，成功时返回参数
+func (p *pattern) match(host string, parts []string) (map[string]string, bool) {
+	if !p.matchHost(host) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, s := range p.segments {
+		if s.kind == kindCatchAll {
+			params[s.name] = "/" + strings.Join(parts[i:], "/")
+			return params, true
+		}
+		if i >= len(parts) {
+			return nil, false
+		}
+		switch s.kind {
+		case kindStatic:
+			if parts[i] != s.value {
+				return nil, false
+			}
+		case kindParam:
+			params[s.name] = parts[i]
+		case kindRegex:
+			if !s.regex.MatchString(parts[i]) {
+				return nil, false
+			}
+			params[s.name] = parts[i]
+		}
+	}
+	if len(parts) != len(p.segments) {
+		return nil, false
+	}
+	return params, true
+}
@@ -0,0 +1,189 @@
+package tolerant
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	"github.com/eolinker/goku-api-gateway/node/router"
+)
+
+//Name 路由名称
+const Name = "tolerant"
+
+func init() {
+	router.Register(Name, Factory())
+}
+
+//TolerantRouter 允许静态路径与参数路径共存、支持正则参数、host及优先级的路由
+type TolerantRouter struct {
+}
+
+//Factory factory
+func Factory() router.Factory {
+	return &TolerantRouter{}
+}
+
+//New new
+func (*TolerantRouter) New() router.APIRouter {
+	return &Engine{
+		methods: make(map[string]*routes),
+	}
+}
+
+type route struct {
+	pattern  *pattern
+	path     string
+	priority int
+	index    int
+	router   router.IRouter
+}
+
+// before 优先级高的先匹配，优先级相同时更具体的先匹配，再按添加顺序
+func (r *route) before(o *route) bool {
+	if r.priority != o.priority {
+		return r.priority > o.priority
+	}
+	if r.pattern.moreSpecific(o.pattern) {
+		return true
+	}
+	if o.pattern.moreSpecific(r.pattern) {
+		return false
+	}
+	return r.index < o.index
+}
+
+// routes 同一请求方法的路由，按第一个静态路径段索引
+type routes struct {
+	static  map[string][]*route
+	dynamic []*route
+	shapes  map[string]*route
+}
+
+func newRoutes() *routes {
+	return &routes{
+		static: make(map[string][]*route),
+		shapes: make(map[string]*route),
+	}
+}
+
+func insert(list []*route, r *route) []*route {
+	i := sort.Search(len(list), func(i int) bool { return r.before(list[i]) })
+	list = append(list, nil)
+	copy(list[i+1:], list[i:])
+	list[i] = r
+	return list
+}
+
+func (rs *routes) add(r *route) {
+	if first, ok := r.pattern.firstStatic(); ok {
+		rs.static[first] = insert(rs.static[first], r)
+		return
+	}
+	rs.dynamic = insert(rs.dynamic, r)
+}
+
+// find 合并静态索引及动态列表，按匹配顺序逐个尝试
+func (rs *routes) find(host string, parts []string) (*route, map[string]string) {
+	var static []*route
+	if len(parts) > 0 {
+		static = rs.static[parts[0]]
+	}
+	dynamic := rs.dynamic
+	for len(static) > 0 || len(dynamic) > 0 {
+		var r *route
+		if len(dynamic) == 0 || (len(static) > 0 && static[0].before(dynamic[0])) {
+			r, static = static[0], static[1:]
+		} else {
+			r, dynamic = dynamic[0], dynamic[1:]
+		}
+		if params, ok := r.pattern.match(host, parts); ok {
+			return r, params
+		}
+	}
+	return nil, nil
+}
+
+//Engine engine
+type Engine struct {
+	methods   map[string]*routes
+	notFound  router.HandleFunc
+	conflicts []string
+	count     int
+}
+
+//AddRouter addRouter
+func (e *Engine) AddRouter(method, path string, router router.IRouter) {
+	e.AddRouterWithPriority(method, path, 0, router)
+}
+
+//AddRouterWithPriority 添加路由，规则解析失败或与已有规则冲突时记录冲突并忽略该规则
+func (e *Engine) AddRouterWithPriority(method, path string, priority int, router router.IRouter) {
+	p, err := parsePattern(path)
+	if err != nil {
+		e.conflicts = append(e.conflicts, fmt.Sprintf("%s %s: %s", method, path, err))
+		return
+	}
+	rs, has := e.methods[method]
+	if !has {
+		rs = newRoutes()
+		e.methods[method] = rs
+	}
+	shape := fmt.Sprintf("%d|%s", priority, p.shape())
+	if exist, has := rs.shapes[shape]; has {
+		e.conflicts = append(e.conflicts, fmt.Sprintf("%s %s conflicts with %s", method, path, exist.path))
+		return
+	}
+	e.count++
+	r := &route{
+		pattern:  p,
+		path:     path,
+		priority: priority,
+		index:    e.count,
+		router:   router,
+	}
+	rs.shapes[shape] = r
+	rs.add(r)
+}
+
+//HandleFunc handleFunc
+func (e *Engine) HandleFunc(method, path string, handler router.HandleFunc) {
+	e.AddRouter(method, path, handler)
+}
+
+//AddNotFound addNotFound
+func (e *Engine) AddNotFound(handler router.HandleFunc) {
+	e.notFound = handler
+}
+
+//Conflicts 冲突或无效的规则
+func (e *Engine) Conflicts() []string {
+	return e.conflicts
+}
+
+func (e *Engine) ServeHTTP(w http.ResponseWriter, req *http.Request, ctx *common.Context) {
+	if rs, has := e.methods[req.Method]; has {
+		r, params := rs.find(requestHost(req), splitPath(req.URL.Path))
+		if r != nil {
+			ctx.RestfulParam = params
+			r.router.Router(ctx)
+			return
+		}
+	}
+	if e.notFound != nil {
+		e.notFound(ctx)
+		return
+	}
+	http.NotFound(w, req)
+}
+
+func requestHost(req *http.Request) string {
+	host := req.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(host)
+}
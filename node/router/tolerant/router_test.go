@@ -0,0 +1,59 @@
+package tolerant
+
+import (
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	"github.com/eolinker/goku-api-gateway/node/router"
+)
+
+type named string
+
+func (n named) Router(ctx *common.Context) {}
+
+func TestEngine(t *testing.T) {
+	e := Factory().New().(*Engine)
+	e.AddRouter("GET", "/users/:id", named("param"))
+	e.AddRouter("GET", "/users/export", named("static"))
+	e.AddRouter("GET", "/users/{id:[0-9]+}/", named("regex"))
+	e.AddRouter("GET", "/files/*path", named("catch"))
+	e.AddRouter("GET", "api.example.com/users/:id", named("host"))
+	e.AddRouterWithPriority("GET", "/orders/:id", 0, named("order"))
+	e.AddRouterWithPriority("GET", "/orders/{id:[a-z]+}", -1, named("low"))
+	e.AddRouter("GET", "/users/:name", named("conflict"))
+
+	cases := []struct {
+		host, path, want string
+		param, value     string
+	}{
+		{"gw", "/users/export", "static", "", ""},
+		{"gw", "/users/12", "regex", "id", "12"},
+		{"gw", "/users/12/", "regex", "id", "12"},
+		{"gw", "/users/tom", "param", "id", "tom"},
+		{"api.example.com", "/users/12", "host", "id", "12"},
+		{"gw", "/files/a/b.txt", "catch", "path", "/a/b.txt"},
+		{"gw", "/orders/abc", "order", "id", "abc"},
+		{"gw", "/none", "", "", ""},
+	}
+	for _, c := range cases {
+		r, params := e.methods["GET"].find(c.host, splitPath(c.path))
+		if c.want == "" {
+			if r != nil {
+				t.Errorf("%s should not match, got %s", c.path, r.path)
+			}
+			continue
+		}
+		if r == nil || r.router.(named) != named(c.want) {
+			t.Errorf("%s%s want %s, got %v", c.host, c.path, c.want, r)
+			continue
+		}
+		if c.param != "" && params[c.param] != c.value {
+			t.Errorf("%s param %s want %s, got %s", c.path, c.param, c.value, params[c.param])
+		}
+	}
+
+	var reporter router.ConflictReporter = e
+	if len(reporter.Conflicts()) != 1 {
+		t.Errorf("want 1 conflict, got %v", reporter.Conflicts())
+	}
+}
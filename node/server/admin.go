@@ -5,7 +5,7 @@ import (
 	"net/http"
 	"sync"
 
-	//"github.com/eolinker/goku-api-gateway/common/endless"
+	"github.com/eolinker/goku-api-gateway/common/endless"
 
 	"github.com/eolinker/goku-api-gateway/node/admin"
 )
@@ -17,11 +17,10 @@ var (
 //StartAdmin 启动节点管理端
 func StartAdmin(address string) {
 	go adminOnce.Do(func() {
-		//server := endless.NewServer(address, admin.Handler())
-		//endless.SetAdminServer(server)
-		//err := server.ListenAndServe()
-		err := http.ListenAndServe(address, admin.Handler())
-		if err != nil {
+		// 管理端与业务端一同由endless管理，重启时监听socket一并交给新进程
+		server := endless.NewServer(address, admin.Handler())
+		err := server.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatal(err)
 		}
 	})
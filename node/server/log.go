@@ -57,7 +57,9 @@ func SetAccessLog(c *config.AccessLogConfig) {
 		access_log.SetFields(fields)
 	}
 
+	access_log.SetFormat(c.Format)
 	access_log.SetOutput(enable, c.Dir, c.File, period, c.Expire)
+	access_log.SetSinks(c.Sinks)
 }
 
 func defaultAccessLogConfig() *config.AccessLogConfig {
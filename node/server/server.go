@@ -5,8 +5,9 @@ import (
 	"fmt"
 	"net/http"
 	"runtime/debug"
+	"syscall"
 
-	//"github.com/eolinker/goku-api-gateway/common/endless"
+	"github.com/eolinker/goku-api-gateway/common/endless"
 
 	"github.com/eolinker/goku-api-gateway/goku-service/application"
 
@@ -25,14 +26,16 @@ import (
 	log "github.com/eolinker/goku-api-gateway/goku-log"
 	"github.com/eolinker/goku-api-gateway/node/console"
 	"github.com/eolinker/goku-api-gateway/node/gateway"
-	"github.com/eolinker/goku-api-gateway/node/router/httprouter"
+	"github.com/eolinker/goku-api-gateway/node/router"
+	_ "github.com/eolinker/goku-api-gateway/node/router/httprouter"
+	_ "github.com/eolinker/goku-api-gateway/node/router/tolerant"
 )
 
 //Server server
 type Server struct {
 	//port    int
-	//console *console.Console
-	router http.Handler
+	console console.ConfigConsole
+	router  http.Handler
 }
 
 //NewServer newServer
@@ -70,6 +73,7 @@ func (s *Server) ServerWidthConsole(console console.ConfigConsole) error {
 		console.AddListen(s.FlushGatewayBasicConfig)
 
 		console.Listen()
+		s.console = console
 
 		return s.ServerWidthConfig(conf)
 	}
@@ -84,7 +88,7 @@ func (s *Server) ServerWidthConfig(conf *config.GokuConfig) error {
 	}
 	s.FlushRedisConfig(conf)
 
-	r, err := gateway.Parse(conf, httprouter.Factory())
+	r, err := gateway.Parse(conf, routerFactory(conf))
 	if err != nil {
 		log.Panic("parse config error:", err)
 	}
@@ -111,13 +115,23 @@ func (s *Server) ServerWidthConfig(conf *config.GokuConfig) error {
 		StartAdmin(conf.AdminAddress)
 	}
 
-	//return endless.ListenAndServe(conf.BindAddress, s)
-	return http.ListenAndServe(conf.BindAddress, s)
+	server := endless.NewServer(conf.BindAddress, s)
+	if s.console != nil {
+		// 重启或停止前先断开与控制台的连接，使新进程可以用同一个instance注册
+		for _, sig := range []syscall.Signal{syscall.SIGHUP, syscall.SIGUSR2, syscall.SIGINT, syscall.SIGTERM} {
+			_ = server.RegisterSignalHook(endless.PRE_SIGNAL, sig, s.console.Close)
+		}
+	}
+	err = server.ListenAndServe()
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
 }
 
 //FlushRouter flushConfig
 func (s *Server) FlushRouter(config *config.GokuConfig) {
-	r, err := gateway.Parse(config, httprouter.Factory())
+	r, err := gateway.Parse(config, routerFactory(config))
 	if err != nil {
 		log.Error("parse config error:", err)
 		return
@@ -125,6 +139,20 @@ func (s *Server) FlushRouter(config *config.GokuConfig) {
 	_ = s.SetRouter(r)
 }
 
+// routerFactory 根据网关配置选择路由实现，未配置或不存在时使用httprouter
+func routerFactory(conf *config.GokuConfig) router.Factory {
+	name := ""
+	if conf.GatewayBasicInfo != nil {
+		name = conf.GatewayBasicInfo.Router
+	}
+	factory, err := router.GetFactory(name)
+	if err != nil {
+		log.Warn(err, ", use ", router.DefaultFactory)
+		factory, _ = router.GetFactory(router.DefaultFactory)
+	}
+	return factory
+}
+
 //FlushRouterRule flushConfig
 func (s *Server) FlushRouterRule(config *config.GokuConfig) {
 	routerRule.Load(config.Routers)
@@ -46,8 +46,44 @@ const (
 	ProxyStatusCode = "$proxy_status_code"
 	//Host 主机信息
 	Host = "$host"
+	//UpstreamResponseTime 转发耗时，单位毫秒，多次转发以逗号分隔
+	UpstreamResponseTime = "$upstream_response_time"
+	//UpstreamConnectTime 与上游建立连接的耗时，单位毫秒，复用连接时为0
+	UpstreamConnectTime = "$upstream_connect_time"
+	//RequestLength 请求长度，包括请求行、请求头和请求体
+	RequestLength = "$request_length"
+	//Consumer 调用方，由鉴权插件识别
+	Consumer = "$consumer"
+	//TraceID 链路追踪的traceID
+	TraceID = "$trace_id"
+	//PluginTime 各插件的执行耗时
+	PluginTime = "$plugin_time"
+	//TrafficSplit 流量拆分命中的标签，未命中时为default
+	TrafficSplit = "$traffic_split"
 )
 
+const (
+	//HTTPHeaderPrefix 请求头，如$http_x_token
+	HTTPHeaderPrefix = "$http_"
+	//SentHTTPHeaderPrefix 响应头，如$sent_http_content_type
+	SentHTTPHeaderPrefix = "$sent_http_"
+	//CookiePrefix cookie，如$cookie_session
+	CookiePrefix = "$cookie_"
+	//ArgPrefix query参数，如$arg_page
+	ArgPrefix = "$arg_"
+	//PluginTimePrefix 单个插件的执行耗时，如$plugin_time_goku-rate_limiting
+	PluginTimePrefix = "$plugin_time_"
+)
+
+// dynamicPrefixes 长前缀在前，保证$sent_http_不会被识别为其他前缀
+var dynamicPrefixes = []string{
+	SentHTTPHeaderPrefix,
+	PluginTimePrefix,
+	HTTPHeaderPrefix,
+	CookiePrefix,
+	ArgPrefix,
+}
+
 //Info 获取域信息
 func (k AccessFieldKey) Info() string {
 	key := strings.ToLower(string(k))
@@ -55,18 +91,47 @@ func (k AccessFieldKey) Info() string {
 	if has {
 		return v
 	}
+	if prefix, name, ok := k.Dynamic(); ok {
+		return dynamicInfos[prefix] + "：" + name
+	}
 	return "unknown"
 }
 
-//Key 获取key
+//Key 获取key，动态域保留名称的大小写
 func (k AccessFieldKey) Key() string {
-
+	if prefix, name, ok := k.Dynamic(); ok {
+		return prefix + name
+	}
 	return strings.ToLower(string(k))
 }
 
+//Dynamic 判断是否为动态域，返回前缀及名称
+func (k AccessFieldKey) Dynamic() (prefix string, name string, ok bool) {
+	key := string(k)
+	lower := strings.ToLower(key)
+	if _, has := infos[lower]; has {
+		return "", "", false
+	}
+	for _, p := range dynamicPrefixes {
+		if strings.HasPrefix(lower, p) && len(key) > len(p) {
+			return p, key[len(p):], true
+		}
+	}
+	return "", "", false
+}
+
 //Parse 解析
 func Parse(key string) AccessFieldKey {
-	return AccessFieldKey(strings.ToLower(key))
+	return AccessFieldKey(AccessFieldKey(key).Key())
+}
+
+//IsValid 是否为可用的域，包括固定域和动态域
+func IsValid(key string) bool {
+	if Has(key) {
+		return true
+	}
+	_, _, ok := AccessFieldKey(key).Dynamic()
+	return ok
 }
 
 //CopyKey copy
@@ -22,6 +22,13 @@ var (
 		BodyBytesSent,
 		HTTPReferer,
 		HTTPUserAgent,
+		UpstreamResponseTime,
+		UpstreamConnectTime,
+		RequestLength,
+		Consumer,
+		TraceID,
+		PluginTime,
+		TrafficSplit,
 	}
 	size = len(all)
 )
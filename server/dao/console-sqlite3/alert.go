@@ -0,0 +1,164 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const alertRuleColumns = "ruleID,ruleName,targetType,IFNULL(target,''),metric,threshold,period,IFNULL(webhook,''),IFNULL(receivers,''),enable,IFNULL(silenceUntil,''),createTime,updateTime"
+
+//AlertDao AlertDao
+type AlertDao struct {
+	db *SQL.DB
+}
+
+//NewAlertDao new AlertDao
+func NewAlertDao() *AlertDao {
+	return &AlertDao{}
+}
+
+//Create create
+func (d *AlertDao) Create(db *SQL.DB) (interface{}, error) {
+	d.db = db
+	var i dao.AlertDao = d
+	return &i, nil
+}
+
+//AddAlertRule 新增告警规则
+func (d *AlertDao) AddAlertRule(rule *entity.AlertRule) (int, error) {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	sql := "INSERT INTO goku_alert_rule (ruleName,targetType,target,metric,threshold,period,webhook,receivers,enable,silenceUntil,createTime,updateTime) VALUES (?,?,?,?,?,?,?,?,?,'',?,?);"
+	result, err := d.db.Exec(sql, rule.RuleName, rule.TargetType, rule.Target, rule.Metric, rule.Threshold, rule.Window, rule.Webhook, rule.Receivers, rule.Enable, now, now)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+//EditAlertRule 修改告警规则
+func (d *AlertDao) EditAlertRule(rule *entity.AlertRule) error {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	sql := "UPDATE goku_alert_rule SET ruleName = ?,targetType = ?,target = ?,metric = ?,threshold = ?,period = ?,webhook = ?,receivers = ?,enable = ?,updateTime = ? WHERE ruleID = ?;"
+	_, err := d.db.Exec(sql, rule.RuleName, rule.TargetType, rule.Target, rule.Metric, rule.Threshold, rule.Window, rule.Webhook, rule.Receivers, rule.Enable, now, rule.RuleID)
+	return err
+}
+
+//DeleteAlertRule 删除告警规则
+func (d *AlertDao) DeleteAlertRule(ruleID int) error {
+	_, err := d.db.Exec("DELETE FROM goku_alert_rule WHERE ruleID = ?;", ruleID)
+	return err
+}
+
+//GetAlertRule 获取告警规则
+func (d *AlertDao) GetAlertRule(ruleID int) (*entity.AlertRule, error) {
+	sql := "SELECT " + alertRuleColumns + " FROM goku_alert_rule WHERE ruleID = ?;"
+	rule := new(entity.AlertRule)
+	err := d.db.QueryRow(sql, ruleID).Scan(&rule.RuleID, &rule.RuleName, &rule.TargetType, &rule.Target, &rule.Metric, &rule.Threshold, &rule.Window, &rule.Webhook, &rule.Receivers, &rule.Enable, &rule.SilenceUntil, &rule.CreateTime, &rule.UpdateTime)
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+//GetAlertRules 获取告警规则列表
+func (d *AlertDao) GetAlertRules() ([]*entity.AlertRule, error) {
+	sql := "SELECT " + alertRuleColumns + " FROM goku_alert_rule ORDER BY ruleID ASC;"
+	rows, err := d.db.Query(sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	rules := make([]*entity.AlertRule, 0)
+	for rows.Next() {
+		rule := new(entity.AlertRule)
+		err = rows.Scan(&rule.RuleID, &rule.RuleName, &rule.TargetType, &rule.Target, &rule.Metric, &rule.Threshold, &rule.Window, &rule.Webhook, &rule.Receivers, &rule.Enable, &rule.SilenceUntil, &rule.CreateTime, &rule.UpdateTime)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+//SetAlertRuleSilence 设置告警规则的静默截止时间
+func (d *AlertDao) SetAlertRuleSilence(ruleID int, silenceUntil string) error {
+	_, err := d.db.Exec("UPDATE goku_alert_rule SET silenceUntil = ? WHERE ruleID = ?;", silenceUntil, ruleID)
+	return err
+}
+
+//AddAlertHistory 新增告警记录
+func (d *AlertDao) AddAlertHistory(history *entity.AlertHistory) error {
+	sql := "INSERT INTO goku_alert_history (ruleID,ruleName,targetType,target,metric,value,threshold,status,silenced,notifyResult,createTime) VALUES (?,?,?,?,?,?,?,?,?,?,?);"
+	_, err := d.db.Exec(sql, history.RuleID, history.RuleName, history.TargetType, history.Target, history.Metric, history.Value, history.Threshold, history.Status, history.Silenced, history.NotifyResult, history.CreateTime)
+	return err
+}
+
+//GetAlertHistory 分页获取告警记录
+func (d *AlertDao) GetAlertHistory(ruleID int, page, pageSize int) ([]*entity.AlertHistory, int, error) {
+	sql := "SELECT alertID,ruleID,ruleName,targetType,target,metric,value,threshold,status,silenced,IFNULL(notifyResult,''),createTime FROM goku_alert_history"
+	args := make([]interface{}, 0, 1)
+	if ruleID > 0 {
+		sql += " WHERE ruleID = ?"
+		args = append(args, ruleID)
+	}
+	count := getCountSQL(d.db, sql, args...)
+	rows, err := getPageSQL(d.db, sql, "alertID", "DESC", page, pageSize, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	histories := make([]*entity.AlertHistory, 0, pageSize)
+	for rows.Next() {
+		h := new(entity.AlertHistory)
+		err = rows.Scan(&h.AlertID, &h.RuleID, &h.RuleName, &h.TargetType, &h.Target, &h.Metric, &h.Value, &h.Threshold, &h.Status, &h.Silenced, &h.NotifyResult, &h.CreateTime)
+		if err != nil {
+			return nil, 0, err
+		}
+		histories = append(histories, h)
+	}
+	return histories, count, nil
+}
+
+//GetAlertConfig 获取告警通知配置
+func (d *AlertDao) GetAlertConfig() (*entity.AlertConfig, error) {
+	sql := "SELECT alertStatus,IFNULL(alertAddress,''),IFNULL(receiverList,''),IFNULL(smtpAddress,''),smtpPort,smtpProtocol,IFNULL(sender,''),IFNULL(senderPassword,'') FROM goku_gateway WHERE id = 1;"
+	c := new(entity.AlertConfig)
+	err := d.db.QueryRow(sql).Scan(&c.AlertStatus, &c.Webhook, &c.Receivers, &c.SMTPAddress, &c.SMTPPort, &c.SMTPProtocol, &c.Sender, &c.Password)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+//SetAlertConfig 设置告警通知配置
+func (d *AlertDao) SetAlertConfig(c *entity.AlertConfig) error {
+	sql := "UPDATE goku_gateway SET alertStatus = ?,alertAddress = ?,receiverList = ?,smtpAddress = ?,smtpPort = ?,smtpProtocol = ?,sender = ?,senderPassword = ? WHERE id = 1;"
+	_, err := d.db.Exec(sql, c.AlertStatus, c.Webhook, c.Receivers, c.SMTPAddress, c.SMTPPort, c.SMTPProtocol, c.Sender, c.Password)
+	return err
+}
+
+//GetAPIAlertValves 获取设置了告警阈值的接口，返回接口ID及阈值
+func (d *AlertDao) GetAPIAlertValves() (map[int]int, error) {
+	rows, err := d.db.Query("SELECT apiID,alertValve FROM goku_gateway_api WHERE alertValve > 0;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	valves := make(map[int]int)
+	for rows.Next() {
+		var apiID, valve int
+		err = rows.Scan(&apiID, &valve)
+		if err != nil {
+			return nil, err
+		}
+		valves[apiID] = valve
+	}
+	return valves, nil
+}
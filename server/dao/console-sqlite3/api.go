@@ -3,6 +3,7 @@ package console_sqlite3
 import (
 	SQL "database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -76,11 +77,11 @@ func (d *APIDao) EditAPI(apiName, alias, requestURL, targetURL, requestMethod, t
 // GetAPIInfo 获取接口信息
 func (d *APIDao) GetAPIInfo(apiID int) (bool, *entity.API, error) {
 	db := d.db
-	sql := `SELECT A.apiID,A.groupID,A.apiName,A.requestURL,A.targetURL,A.requestMethod,A.targetMethod,IFNULL(A.protocol,"http"),IFNULL(A.balanceName,""),A.isFollow,A.timeout,A.retryCount,A.alertValve,A.createTime,A.updateTime,A.managerID,A.lastUpdateUserID,A.createUserID,IFNULL(goku_gateway_api_group.groupPath,"0"),A.apiType,IFNULL(A.linkAPIs,''),IFNULL(A.staticResponse,''),IFNULL(A.responseDataType,'origin') FROM goku_gateway_api A LEFT JOIN goku_gateway_api_group ON A.groupID = goku_gateway_api_group.groupID WHERE A.apiID = ?`
+	sql := `SELECT A.apiID,A.groupID,A.apiName,A.requestURL,A.targetURL,A.requestMethod,A.targetMethod,IFNULL(A.protocol,"http"),IFNULL(A.balanceName,""),A.isFollow,A.timeout,A.retryCount,A.alertValve,A.createTime,A.updateTime,A.managerID,A.lastUpdateUserID,A.createUserID,IFNULL(goku_gateway_api_group.groupPath,"0"),A.apiType,IFNULL(A.linkAPIs,''),IFNULL(A.staticResponse,''),IFNULL(A.responseDataType,'origin'),A.routePriority FROM goku_gateway_api A LEFT JOIN goku_gateway_api_group ON A.groupID = goku_gateway_api_group.groupID WHERE A.apiID = ?`
 	api := &entity.API{}
 	var managerInfo entity.ManagerInfo
 	var linkAPIs string
-	err := db.QueryRow(sql, apiID).Scan(&api.APIID, &api.GroupID, &api.APIName, &api.RequestURL, &api.ProxyURL, &api.RequestMethod, &api.TargetMethod, &api.Protocol, &api.BalanceName, &api.IsFollow, &api.Timeout, &api.RetryConut, &api.Valve, &api.CreateTime, &api.UpdateTime, &managerInfo.ManagerID, &managerInfo.UpdaterID, &managerInfo.CreateUserID, &api.GroupPath, &api.APIType, &linkAPIs, &api.StaticResponse, &api.ResponseDataType)
+	err := db.QueryRow(sql, apiID).Scan(&api.APIID, &api.GroupID, &api.APIName, &api.RequestURL, &api.ProxyURL, &api.RequestMethod, &api.TargetMethod, &api.Protocol, &api.BalanceName, &api.IsFollow, &api.Timeout, &api.RetryConut, &api.Valve, &api.CreateTime, &api.UpdateTime, &managerInfo.ManagerID, &managerInfo.UpdaterID, &managerInfo.CreateUserID, &api.GroupPath, &api.APIType, &linkAPIs, &api.StaticResponse, &api.ResponseDataType, &api.RoutePriority)
 	if err != nil {
 		return false, &entity.API{}, err
 	}
@@ -324,6 +325,51 @@ func (d *APIDao) GetAPIList(projectID int, groupID int, keyword string, conditio
 	return true, apiList, count, nil
 }
 
+//EditAPIRoutePriority 修改接口的路由优先级
+func (d *APIDao) EditAPIRoutePriority(apiID, priority int) error {
+	db := d.db
+	now := time.Now().Format("2006-01-02 15:04:05")
+	_, err := db.Exec("UPDATE goku_gateway_api SET routePriority = ?,updateTime = ? WHERE apiID = ?;", priority, now, apiID)
+	return err
+}
+
+// apiPolicyColumns 接口上以JSON保存策略的列
+var apiPolicyColumns = map[string]bool{
+	"transform":   true,
+	"traffic":     true,
+	"mirror":      true,
+	"retryPolicy": true,
+	"hedge":       true,
+}
+
+//GetAPIPolicy 获取接口保存在column列的策略
+func (d *APIDao) GetAPIPolicy(apiID int, column string) (string, error) {
+	if !apiPolicyColumns[column] {
+		return "", fmt.Errorf("[ERROR]Illegal api policy column %s", column)
+	}
+	db := d.db
+	var policy string
+	err := db.QueryRow("SELECT IFNULL("+column+",'') FROM goku_gateway_api WHERE apiID = ?;", apiID).Scan(&policy)
+	return policy, err
+}
+
+//EditAPIPolicy 修改接口保存在column列的策略
+func (d *APIDao) EditAPIPolicy(apiID int, column, policy string) error {
+	if !apiPolicyColumns[column] {
+		return fmt.Errorf("[ERROR]Illegal api policy column %s", column)
+	}
+	db := d.db
+	now := time.Now().Format("2006-01-02 15:04:05")
+	result, err := db.Exec("UPDATE goku_gateway_api SET "+column+" = ?,updateTime = ? WHERE apiID = ?;", policy, now, apiID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.New("[ERROR]The api does not exist")
+	}
+	return nil
+}
+
 //CheckURLIsExist 接口路径是否存在
 func (d *APIDao) CheckURLIsExist(requestURL, requestMethod string, projectID, apiID int) bool {
 	db := d.db
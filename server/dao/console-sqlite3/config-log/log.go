@@ -7,8 +7,8 @@ import (
 	entity "github.com/eolinker/goku-api-gateway/server/entity/config-log"
 )
 
-const sqlSelect = "SELECT `name`,`enable`,`dir`,`file`,`level`,`period`,`expire`,`fields` FROM `goku_config_log` WHERE `name` = ? LIMIT 1;"
-const sqlInsert = "REPLACE INTO `goku_config_log`(`name`,`enable`,`dir`,`file`,`level`,`period`,`expire`,`fields`)VALUES(?,?,?,?,?,?,?,?);"
+const sqlSelect = "SELECT `name`,`enable`,`dir`,`file`,`level`,`period`,`expire`,`fields`,IFNULL(`format`,''),IFNULL(`sinks`,'') FROM `goku_config_log` WHERE `name` = ? LIMIT 1;"
+const sqlInsert = "REPLACE INTO `goku_config_log`(`name`,`enable`,`dir`,`file`,`level`,`period`,`expire`,`fields`,`format`,`sinks`)VALUES(?,?,?,?,?,?,?,?,?,?);"
 
 //ConfigLogDao ConfigLogDao
 type ConfigLogDao struct {
@@ -44,6 +44,8 @@ func (d *ConfigLogDao) Get(name string) (*entity.LogConfig, error) {
 		&ent.Period,
 		&ent.Expire,
 		&ent.Fields,
+		&ent.Format,
+		&ent.Sinks,
 	)
 	if err != nil {
 		return nil, err
@@ -67,6 +69,8 @@ func (d *ConfigLogDao) Set(ent *entity.LogConfig) error {
 		ent.Period,
 		ent.Expire,
 		ent.Fields,
+		ent.Format,
+		ent.Sinks,
 	)
 
 	return err
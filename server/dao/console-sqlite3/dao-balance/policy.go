@@ -0,0 +1,30 @@
+package dao_balance
+
+import "fmt"
+
+// policyColumns 负载上以JSON保存策略的列
+var policyColumns = map[string]bool{
+	"subset":    true,
+	"transport": true,
+}
+
+//GetPolicy 获取负载保存在column列的策略
+func (b *BalanceDao) GetPolicy(name, column string) (string, error) {
+	if !policyColumns[column] {
+		return "", fmt.Errorf("[ERROR]Illegal balance policy column %s", column)
+	}
+	sql := "SELECT IFNULL(`" + column + "`,'') FROM `goku_balance` WHERE `balanceName` = ?;"
+	policy := ""
+	err := b.db.QueryRow(sql, name).Scan(&policy)
+	return policy, err
+}
+
+//SavePolicy 保存负载保存在column列的策略，为空时清除
+func (b *BalanceDao) SavePolicy(name, column, policy, now string) error {
+	if !policyColumns[column] {
+		return fmt.Errorf("[ERROR]Illegal balance policy column %s", column)
+	}
+	sql := "UPDATE `goku_balance` SET `" + column + "` = ?,`updateTime` = ? WHERE `balanceName` = ?;"
+	_, err := b.db.Exec(sql, policy, now, name)
+	return err
+}
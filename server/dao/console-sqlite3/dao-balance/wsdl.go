@@ -0,0 +1,16 @@
+package dao_balance
+
+//GetWSDL 获取负载的WSDL文档
+func (b *BalanceDao) GetWSDL(name string) (string, error) {
+	const sql = "SELECT IFNULL(`wsdl`,'') FROM `goku_balance` WHERE `balanceName` = ?;"
+	wsdl := ""
+	err := b.db.QueryRow(sql, name).Scan(&wsdl)
+	return wsdl, err
+}
+
+//SaveWSDL 保存负载的WSDL文档，为空时清除
+func (b *BalanceDao) SaveWSDL(name, wsdl, now string) error {
+	const sql = "UPDATE `goku_balance` SET `wsdl` = ?,`updateTime` = ? WHERE `balanceName` = ?;"
+	_, err := b.db.Exec(sql, wsdl, now, name)
+	return err
+}
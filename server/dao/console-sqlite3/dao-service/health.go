@@ -0,0 +1,16 @@
+package dao_service
+
+//GetHealthCheckOptions 获取健康检查扩展配置
+func (d *ServiceDao) GetHealthCheckOptions(name string) (string, error) {
+	const sql = "SELECT IFNULL(`healthCheckOptions`,'') FROM `goku_service_config` WHERE `name` = ?;"
+	options := ""
+	err := d.db.QueryRow(sql, name).Scan(&options)
+	return options, err
+}
+
+//SaveHealthCheckOptions 保存健康检查扩展配置，为空时清除
+func (d *ServiceDao) SaveHealthCheckOptions(name, options, now string) error {
+	const sql = "UPDATE `goku_service_config` SET `healthCheckOptions` = ?,`updateTime` = ? WHERE `name` = ?;"
+	_, err := d.db.Exec(sql, options, now, name)
+	return err
+}
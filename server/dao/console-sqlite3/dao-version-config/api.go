@@ -2,31 +2,68 @@ package dao_version_config
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/eolinker/goku-api-gateway/config"
 )
 
 //GetAPIContent 获取接口信息
-func (d *VersionConfigDao)GetAPIContent() ([]*config.APIContent, error) {
+func (d *VersionConfigDao) GetAPIContent() ([]*config.APIContent, error) {
 	db := d.db
-	sql := "SELECT apiID,apiName,IFNULL(protocol,'http'),IFNULL(balanceName,''),IFNULL(targetURL,''),CASE WHEN isFollow = 'true' THEN 'FOLLOW' ELSE targetMethod END targetMethod,responseDataType,requestURL,requestMethod,timeout,alertValve,retryCount,IFNULL(linkApis,''),IFNULL(staticResponse,'') FROM goku_gateway_api"
+	sql := "SELECT apiID,apiName,IFNULL(protocol,'http'),IFNULL(balanceName,''),IFNULL(targetURL,''),CASE WHEN isFollow = 'true' THEN 'FOLLOW' ELSE targetMethod END targetMethod,responseDataType,requestURL,requestMethod,timeout,alertValve,retryCount,IFNULL(linkApis,''),IFNULL(staticResponse,''),routePriority,IFNULL(transform,''),IFNULL(traffic,''),IFNULL(mirror,''),IFNULL(retryPolicy,''),IFNULL(hedge,'') FROM goku_gateway_api"
 	rows, err := db.Query(sql)
 	if err != nil {
 		return nil, err
 	}
 
 	apiContents := make([]*config.APIContent, 0, 100)
+	soapResolver := newSOAPResolver(db)
 	defer rows.Close()
 	for rows.Next() {
 		var apiContent config.APIContent
-		var linkApisStr, protocol, balance, targetURL, targetMethod, requestMethod string
+		var linkApisStr, protocol, balance, targetURL, targetMethod, requestMethod, transform, traffic, mirror, retryPolicy, hedge string
 		var retryCount int
 		linkApis := make([]config.APIStepUIConfig, 0)
-		err = rows.Scan(&apiContent.ID, &apiContent.Name, &protocol, &balance, &targetURL, &targetMethod, &apiContent.OutPutEncoder, &apiContent.RequestURL, &requestMethod, &apiContent.TimeOutTotal, &apiContent.AlertThreshold, &retryCount, &linkApisStr, &apiContent.StaticResponse)
+		err = rows.Scan(&apiContent.ID, &apiContent.Name, &protocol, &balance, &targetURL, &targetMethod, &apiContent.OutPutEncoder, &apiContent.RequestURL, &requestMethod, &apiContent.TimeOutTotal, &apiContent.AlertThreshold, &retryCount, &linkApisStr, &apiContent.StaticResponse, &apiContent.Priority, &transform, &traffic, &mirror, &retryPolicy, &hedge)
 		if err != nil {
 			return nil, err
 		}
+		if transform != "" {
+			apiContent.Transform = new(config.TransformConfig)
+			err = json.Unmarshal([]byte(transform), apiContent.Transform)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if traffic != "" {
+			apiContent.Traffic = new(config.TrafficConfig)
+			err = json.Unmarshal([]byte(traffic), apiContent.Traffic)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if mirror != "" {
+			apiContent.Mirror = new(config.MirrorConfig)
+			err = json.Unmarshal([]byte(mirror), apiContent.Mirror)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if retryPolicy != "" {
+			apiContent.RetryPolicy = new(config.RetryConfig)
+			err = json.Unmarshal([]byte(retryPolicy), apiContent.RetryPolicy)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if hedge != "" {
+			apiContent.Hedge = new(config.HedgeConfig)
+			err = json.Unmarshal([]byte(hedge), apiContent.Hedge)
+			if err != nil {
+				return nil, err
+			}
+		}
 		if linkApisStr != "" {
 			err = json.Unmarshal([]byte(linkApisStr), &linkApis)
 			if err != nil {
@@ -69,11 +106,19 @@ func (d *VersionConfigDao)GetAPIContent() ([]*config.APIContent, error) {
 						Target:     rename.Target,
 					})
 				}
-				apiContent.Steps = append(apiContent.Steps, &config.APIStepConfig{
+				for _, set := range api.Set {
+					actions = append(actions, &config.ActionConfig{
+						ActionType: "set",
+						Original:   set.Origin,
+						Target:     set.Target,
+					})
+				}
+				step := &config.APIStepConfig{
 					Proto:     api.Proto,
 					Balance:   api.Balance,
 					Path:      api.Path,
 					Body:      api.Body,
+					Headers:   api.Headers,
 					Method:    api.Method,
 					Encode:    api.Encode,
 					Decode:    api.Decode,
@@ -84,7 +129,18 @@ func (d *VersionConfigDao)GetAPIContent() ([]*config.APIContent, error) {
 					WhiteList: api.WhiteList,
 					BlackList: api.BlackList,
 					Actions:   actions,
-				})
+				}
+				if api.SOAP != nil {
+					soapConfig, path, err := soapResolver.resolve(api.Balance, api.SOAP)
+					if err != nil {
+						return nil, fmt.Errorf("api %d: %s", apiContent.ID, err)
+					}
+					step.SOAP = soapConfig
+					if step.Path == "" {
+						step.Path = path
+					}
+				}
+				apiContent.Steps = append(apiContent.Steps, step)
 			}
 		}
 		apiContents = append(apiContents, &apiContent)
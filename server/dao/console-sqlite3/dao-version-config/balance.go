@@ -10,7 +10,7 @@ import (
 //GetBalances 获取balance信息
 func (d *VersionConfigDao)GetBalances(clusters []*entity.Cluster) (map[string]map[string]*config.BalanceConfig, error) {
 	db := d.db
-	sql := "SELECT goku_balance.balanceName,goku_balance.static,goku_balance.staticCluster,goku_balance.serviceName,goku_balance.appName,goku_service_config.driver FROM goku_balance INNER JOIN goku_service_config ON goku_service_config.`name` = goku_balance.serviceName"
+	sql := "SELECT goku_balance.balanceName,goku_balance.static,goku_balance.staticCluster,goku_balance.serviceName,goku_balance.appName,goku_service_config.driver,IFNULL(goku_balance.subset,''),IFNULL(goku_balance.transport,'') FROM goku_balance INNER JOIN goku_service_config ON goku_service_config.`name` = goku_balance.serviceName"
 	rows, err := db.Query(sql)
 	if err != nil {
 		return nil, err
@@ -18,8 +18,22 @@ func (d *VersionConfigDao)GetBalances(clusters []*entity.Cluster) (map[string]ma
 	defer rows.Close()
 	balanceMaps := make(map[string]map[string]*config.BalanceConfig)
 	for rows.Next() {
-		var balanceName, static, staticCluster, serviceName, appName, driver string
-		err = rows.Scan(&balanceName, &static, &staticCluster, &serviceName, &appName, &driver)
+		var balanceName, static, staticCluster, serviceName, appName, driver, subsetStr, transportStr string
+		err = rows.Scan(&balanceName, &static, &staticCluster, &serviceName, &appName, &driver, &subsetStr, &transportStr)
+		var subset *config.SubsetConfig
+		if subsetStr != "" {
+			subset = new(config.SubsetConfig)
+			if err := json.Unmarshal([]byte(subsetStr), subset); err != nil {
+				return nil, err
+			}
+		}
+		var transport *config.TransportConfig
+		if transportStr != "" {
+			transport = new(config.TransportConfig)
+			if err := json.Unmarshal([]byte(transportStr), transport); err != nil {
+				return nil, err
+			}
+		}
 		staticMap := make(map[string]string)
 		if staticCluster != "" {
 			err := json.Unmarshal([]byte(staticCluster), &staticMap)
@@ -37,6 +51,8 @@ func (d *VersionConfigDao)GetBalances(clusters []*entity.Cluster) (map[string]ma
 					Name:         balanceName,
 					DiscoverName: serviceName,
 					Config:       appName,
+					Subset:       subset,
+					Transport:    transport,
 				}
 				continue
 			}
@@ -49,6 +65,8 @@ func (d *VersionConfigDao)GetBalances(clusters []*entity.Cluster) (map[string]ma
 				Name:         balanceName,
 				DiscoverName: serviceName,
 				Config:       staticBalance,
+				Subset:       subset,
+				Transport:    transport,
 			}
 		}
 
@@ -11,7 +11,7 @@ import (
 //GetDiscoverConfig 获取服务发现信息
 func (d *VersionConfigDao)GetDiscoverConfig(clusters []*entity.Cluster) (map[string]map[string]*config.DiscoverConfig, error) {
 	db := d.db
-	sql := "SELECT `name`,`driver`,`config`,`clusterConfig`,`healthCheck`,`healthCheckPath`,`healthCheckPeriod`,`healthCheckCode`,`healthCheckTimeOut` FROM goku_service_config"
+	sql := "SELECT `name`,`driver`,`config`,`clusterConfig`,`healthCheck`,`healthCheckPath`,`healthCheckPeriod`,`healthCheckCode`,`healthCheckTimeOut`,IFNULL(`healthCheckOptions`,'') FROM goku_service_config"
 	rows, err := db.Query(sql)
 	if err != nil {
 		return nil, err
@@ -19,10 +19,10 @@ func (d *VersionConfigDao)GetDiscoverConfig(clusters []*entity.Cluster) (map[str
 	defer rows.Close()
 	discoverMaps := make(map[string]map[string]*config.DiscoverConfig)
 	for rows.Next() {
-		var name, discoverConfig, clusterConfig, healthCheckPath, healthCheckCode, driver string
+		var name, discoverConfig, clusterConfig, healthCheckPath, healthCheckCode, driver, healthCheckOptions string
 		var healthCheck bool
 		var healthCheckPeriod, healthCheckTimeOut int
-		err = rows.Scan(&name, &driver, &discoverConfig, &clusterConfig, &healthCheck, &healthCheckPath, &healthCheckPeriod, &healthCheckCode, &healthCheckTimeOut)
+		err = rows.Scan(&name, &driver, &discoverConfig, &clusterConfig, &healthCheck, &healthCheckPath, &healthCheckPeriod, &healthCheckCode, &healthCheckTimeOut, &healthCheckOptions)
 
 		configMap := make(map[string]string)
 		if clusterConfig != "" {
@@ -31,6 +31,13 @@ func (d *VersionConfigDao)GetDiscoverConfig(clusters []*entity.Cluster) (map[str
 				return nil, err
 			}
 		}
+		var options config.HealthCheckOptions
+		if healthCheckOptions != "" {
+			err := json.Unmarshal([]byte(healthCheckOptions), &options)
+			if err != nil {
+				return nil, err
+			}
+		}
 
 		for _, c := range clusters {
 			if _, ok := discoverMaps[c.Name]; !ok {
@@ -46,6 +53,8 @@ func (d *VersionConfigDao)GetDiscoverConfig(clusters []*entity.Cluster) (map[str
 						Second:        healthCheckPeriod,
 						TimeOutMill:   healthCheckTimeOut,
 						StatusCode:    healthCheckCode,
+
+						HealthCheckOptions: options,
 					},
 				}
 				continue
@@ -64,6 +73,8 @@ func (d *VersionConfigDao)GetDiscoverConfig(clusters []*entity.Cluster) (map[str
 					Second:        healthCheckPeriod,
 					TimeOutMill:   healthCheckTimeOut,
 					StatusCode:    healthCheckCode,
+
+					HealthCheckOptions: options,
 				},
 			}
 		}
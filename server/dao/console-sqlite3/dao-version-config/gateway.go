@@ -7,10 +7,10 @@ import (
 //GetGatewayBasicConfig GetGatewayBasicConfig
 func (d *VersionConfigDao) GetGatewayBasicConfig() (*config.Gateway, error) {
 	db := d.db
-	sql := "SELECT skipCertificate FROM goku_gateway;"
+	sql := "SELECT skipCertificate,router FROM goku_gateway;"
 
 	var g config.Gateway
-	err := db.QueryRow(sql).Scan(&g.SkipCertificate)
+	err := db.QueryRow(sql).Scan(&g.SkipCertificate, &g.Router)
 	if err != nil {
 		return nil, err
 	}
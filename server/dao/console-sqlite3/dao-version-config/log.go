@@ -7,9 +7,9 @@ import (
 )
 
 //GetLogInfo 获取日志信息
-func (d *VersionConfigDao)GetLogInfo() (*config.LogConfig, *config.AccessLogConfig, error) {
+func (d *VersionConfigDao) GetLogInfo() (*config.LogConfig, *config.AccessLogConfig, error) {
 	db := d.db
-	sql := "SELECT `name`,`enable`,`dir`,`file`,`period`,IFNULL(`level`,''),IFNULL(`fields`,''),`expire` FROM goku_config_log;"
+	sql := "SELECT `name`,`enable`,`dir`,`file`,`period`,IFNULL(`level`,''),IFNULL(`fields`,''),`expire`,IFNULL(`format`,''),IFNULL(`sinks`,'') FROM goku_config_log;"
 	rows, err := db.Query(sql)
 	if err != nil {
 		return nil, nil, err
@@ -18,9 +18,9 @@ func (d *VersionConfigDao)GetLogInfo() (*config.LogConfig, *config.AccessLogConf
 	var logCf *config.LogConfig
 	var accessCf *config.AccessLogConfig
 	for rows.Next() {
-		var name, dir, file, level, fields, period string
+		var name, dir, file, level, fields, period, format, sinks string
 		var enable, expire int
-		err = rows.Scan(&name, &enable, &dir, &file, &period, &level, &fields, &expire)
+		err = rows.Scan(&name, &enable, &dir, &file, &period, &level, &fields, &expire, &format, &sinks)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -44,6 +44,13 @@ func (d *VersionConfigDao)GetLogInfo() (*config.LogConfig, *config.AccessLogConf
 				Period: period,
 				Expire: expire,
 				Fields: fields,
+				Format: format,
+			}
+			if sinks != "" {
+				err = json.Unmarshal([]byte(sinks), &accessCf.Sinks)
+				if err != nil {
+					return nil, nil, err
+				}
 			}
 		} else if name == "node" {
 			logCf = &config.LogConfig{
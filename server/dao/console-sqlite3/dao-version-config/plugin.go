@@ -1,15 +1,28 @@
 package dao_version_config
 
 import (
+	"encoding/json"
 	"strconv"
 
 	"github.com/eolinker/goku-api-gateway/config"
 )
 
+// parsePluginPolicy 解析插件执行策略，保存时已校验，解析失败按未设置处理
+func parsePluginPolicy(policy string) *config.PluginPolicy {
+	if policy == "" {
+		return nil
+	}
+	p := new(config.PluginPolicy)
+	if err := json.Unmarshal([]byte(policy), p); err != nil {
+		return nil
+	}
+	return p
+}
+
 //GetGlobalPlugin 获取全局插件
 func (d *VersionConfigDao) GetGlobalPlugin() (*config.GatewayPluginConfig, error) {
 	db := d.db
-	sql := "SELECT pluginName,isStop,IFNULL(pluginConfig,''),pluginType FROM goku_plugin"
+	sql := "SELECT pluginName,isStop,IFNULL(pluginConfig,''),pluginType,IFNULL(errorPolicy,'') FROM goku_plugin"
 	rows, err := db.Query(sql)
 	if err != nil {
 		return nil, err
@@ -20,10 +33,10 @@ func (d *VersionConfigDao) GetGlobalPlugin() (*config.GatewayPluginConfig, error
 		GlobalPlugins: make([]*config.PluginConfig, 0, 20),
 	}
 	for rows.Next() {
-		var pluginName, pluginConfig string
+		var pluginName, pluginConfig, policy string
 		var isStop bool
 		var pluginType int
-		err = rows.Scan(&pluginName, &isStop, &pluginConfig, &pluginType)
+		err = rows.Scan(&pluginName, &isStop, &pluginConfig, &pluginType, &policy)
 		if err != nil {
 			return nil, err
 		}
@@ -32,12 +45,14 @@ func (d *VersionConfigDao) GetGlobalPlugin() (*config.GatewayPluginConfig, error
 				Name:   pluginName,
 				IsStop: isStop,
 				Config: pluginConfig,
+				Policy: parsePluginPolicy(policy),
 			})
 		} else {
 			pluginConfigs.BeforePlugins = append(pluginConfigs.BeforePlugins, &config.PluginConfig{
 				Name:   pluginName,
 				IsStop: isStop,
 				Config: pluginConfig,
+				Policy: parsePluginPolicy(policy),
 			})
 		}
 	}
@@ -47,7 +62,7 @@ func (d *VersionConfigDao) GetGlobalPlugin() (*config.GatewayPluginConfig, error
 //GetAPIPlugins 获取接口插件
 func (d *VersionConfigDao) GetAPIPlugins() (map[string][]*config.PluginConfig, error) {
 	db := d.db
-	sql := "SELECT goku_conn_plugin_api.apiID,goku_conn_plugin_api.strategyID,goku_conn_plugin_api.pluginName,goku_conn_plugin_api.pluginConfig,goku_plugin.isStop FROM goku_conn_plugin_api INNER JOIN goku_plugin ON goku_conn_plugin_api.pluginName = goku_plugin.pluginName"
+	sql := "SELECT goku_conn_plugin_api.apiID,goku_conn_plugin_api.strategyID,goku_conn_plugin_api.pluginName,goku_conn_plugin_api.pluginConfig,goku_plugin.isStop,IFNULL(goku_conn_plugin_api.errorPolicy,'') FROM goku_conn_plugin_api INNER JOIN goku_plugin ON goku_conn_plugin_api.pluginName = goku_plugin.pluginName"
 	rows, err := db.Query(sql)
 	if err != nil {
 		return nil, err
@@ -57,8 +72,8 @@ func (d *VersionConfigDao) GetAPIPlugins() (map[string][]*config.PluginConfig, e
 	for rows.Next() {
 		var apiID int
 		var isStop bool
-		var pluginName, pluginConfig, strategyID string
-		err = rows.Scan(&apiID, &strategyID, &pluginName, &pluginConfig, &isStop)
+		var pluginName, pluginConfig, strategyID, policy string
+		err = rows.Scan(&apiID, &strategyID, &pluginName, &pluginConfig, &isStop, &policy)
 		if err != nil {
 			return nil, err
 		}
@@ -70,6 +85,7 @@ func (d *VersionConfigDao) GetAPIPlugins() (map[string][]*config.PluginConfig, e
 			Name:   pluginName,
 			IsStop: isStop,
 			Config: pluginConfig,
+			Policy: parsePluginPolicy(policy),
 		})
 	}
 	return pluginMaps, nil
@@ -79,7 +95,7 @@ func (d *VersionConfigDao) GetAPIPlugins() (map[string][]*config.PluginConfig, e
 //GetStrategyPlugins 获取策略插件
 func (d *VersionConfigDao) GetStrategyPlugins() (map[string][]*config.PluginConfig, map[string]map[string]string, error) {
 	db := d.db
-	sql := "SELECT goku_conn_plugin_strategy.strategyID,goku_conn_plugin_strategy.pluginName,goku_conn_plugin_strategy.pluginConfig,goku_plugin.isStop FROM goku_conn_plugin_strategy INNER JOIN goku_plugin ON goku_conn_plugin_strategy.pluginName = goku_plugin.pluginName WHERE goku_plugin.pluginStatus = 1 AND goku_conn_plugin_strategy.pluginStatus = 1"
+	sql := "SELECT goku_conn_plugin_strategy.strategyID,goku_conn_plugin_strategy.pluginName,goku_conn_plugin_strategy.pluginConfig,goku_plugin.isStop,IFNULL(goku_conn_plugin_strategy.errorPolicy,'') FROM goku_conn_plugin_strategy INNER JOIN goku_plugin ON goku_conn_plugin_strategy.pluginName = goku_plugin.pluginName WHERE goku_plugin.pluginStatus = 1 AND goku_conn_plugin_strategy.pluginStatus = 1"
 	rows, err := db.Query(sql)
 	if err != nil {
 		return nil, nil, err
@@ -89,8 +105,8 @@ func (d *VersionConfigDao) GetStrategyPlugins() (map[string][]*config.PluginConf
 	authMaps := make(map[string]map[string]string)
 	for rows.Next() {
 		var isStop bool
-		var pluginName, pluginConfig, strategyID string
-		err = rows.Scan(&strategyID, &pluginName, &pluginConfig, &isStop)
+		var pluginName, pluginConfig, strategyID, policy string
+		err = rows.Scan(&strategyID, &pluginName, &pluginConfig, &isStop, &policy)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -109,8 +125,31 @@ func (d *VersionConfigDao) GetStrategyPlugins() (map[string][]*config.PluginConf
 			Name:   pluginName,
 			IsStop: isStop,
 			Config: pluginConfig,
+			Policy: parsePluginPolicy(policy),
 		})
 	}
 	return pluginMaps, authMaps, nil
 
 }
+
+//GetPluginScripts 获取JavaScript插件
+func (d *VersionConfigDao) GetPluginScripts() (map[string]*config.PluginScript, error) {
+	db := d.db
+	sql := "SELECT goku_plugin_script.pluginName,goku_plugin_script.script,goku_plugin_script.timeout,goku_plugin_script.memoryLimit FROM goku_plugin_script INNER JOIN goku_plugin ON goku_plugin_script.pluginName = goku_plugin.pluginName"
+	rows, err := db.Query(sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	scripts := make(map[string]*config.PluginScript)
+	for rows.Next() {
+		var pluginName string
+		script := new(config.PluginScript)
+		err = rows.Scan(&pluginName, &script.Source, &script.Timeout, &script.MemoryLimit)
+		if err != nil {
+			return nil, err
+		}
+		scripts[pluginName] = script
+	}
+	return scripts, nil
+}
@@ -0,0 +1,66 @@
+package dao_version_config
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/eolinker/goku-api-gateway/common/wsdl"
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+// soapResolver 根据负载的WSDL解析编排步骤选择的SOAP操作，按负载缓存解析结果
+type soapResolver struct {
+	db   *sql.DB
+	docs map[string]*wsdl.Document
+}
+
+func newSOAPResolver(db *sql.DB) *soapResolver {
+	return &soapResolver{db: db, docs: make(map[string]*wsdl.Document)}
+}
+
+func (r *soapResolver) document(balance string) (*wsdl.Document, error) {
+	if doc, has := r.docs[balance]; has {
+		return doc, nil
+	}
+	text := ""
+	err := r.db.QueryRow("SELECT IFNULL(`wsdl`,'') FROM `goku_balance` WHERE `balanceName` = ?;", balance).Scan(&text)
+	if err != nil {
+		return nil, fmt.Errorf("balance %s: %s", balance, err)
+	}
+	if text == "" {
+		return nil, fmt.Errorf("balance %s has no wsdl", balance)
+	}
+	doc, err := wsdl.Parse([]byte(text))
+	if err != nil {
+		return nil, fmt.Errorf("balance %s: %s", balance, err)
+	}
+	r.docs[balance] = doc
+	return doc, nil
+}
+
+// resolve 返回节点使用的SOAP配置及WSDL中的服务路径
+func (r *soapResolver) resolve(balance string, ui *config.SOAPUIConfig) (*config.SOAPConfig, string, error) {
+	doc, err := r.document(balance)
+	if err != nil {
+		return nil, "", err
+	}
+	op, err := doc.Find(ui.Operation, ui.Version)
+	if err != nil {
+		return nil, "", err
+	}
+	cfg := &config.SOAPConfig{
+		Version:   op.Version,
+		Action:    op.Action,
+		Element:   op.Element,
+		Namespace: op.Namespace,
+		Qualified: op.Qualified,
+	}
+	if ui.Username != "" {
+		cfg.Security = &config.WSSecurityConfig{
+			Username: ui.Username,
+			Password: ui.Password,
+			Digest:   ui.PasswordType == "digest",
+		}
+	}
+	return cfg, op.Path(), nil
+}
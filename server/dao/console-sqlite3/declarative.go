@@ -0,0 +1,746 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//DeclarativeDao DeclarativeDao
+type DeclarativeDao struct {
+	db *SQL.DB
+}
+
+//NewDeclarativeDao new DeclarativeDao
+func NewDeclarativeDao() *DeclarativeDao {
+	return &DeclarativeDao{}
+}
+
+//Create create
+func (d *DeclarativeDao) Create(db *SQL.DB) (interface{}, error) {
+	d.db = db
+	var i dao.DeclarativeDao = d
+	return &i, nil
+}
+
+type declarativeQuerier interface {
+	Query(query string, args ...interface{}) (*SQL.Rows, error)
+}
+
+// declarativeState 当前配置及其数据库ID
+type declarativeState struct {
+	doc        *entity.Declarative
+	projectIDs map[string]int
+	apiIDs     map[string]map[string]int
+	strategies map[string]*entity.DeclarativeStrategy
+	strategyOf map[string]map[int]*entity.DeclarativeStrategyAPI
+}
+
+func eachRow(q declarativeQuerier, sql string, scan func(rows *SQL.Rows) error, args ...interface{}) error {
+	rows, err := q.Query(sql, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+//ExportDeclarative 导出当前全部配置
+func (d *DeclarativeDao) ExportDeclarative() (*entity.Declarative, error) {
+	state, err := loadDeclarative(d.db)
+	if err != nil {
+		return nil, err
+	}
+	return state.doc, nil
+}
+
+func loadDeclarative(q declarativeQuerier) (*declarativeState, error) {
+	doc := &entity.Declarative{Version: 1}
+	state := &declarativeState{
+		doc:        doc,
+		projectIDs: make(map[string]int),
+		apiIDs:     make(map[string]map[string]int),
+		strategies: make(map[string]*entity.DeclarativeStrategy),
+		strategyOf: make(map[string]map[int]*entity.DeclarativeStrategyAPI),
+	}
+
+	err := eachRow(q, "SELECT name,IFNULL(`default`,0),driver,`desc`,config,clusterConfig,healthCheck,healthCheckPath,healthCheckPeriod,healthCheckCode,healthCheckTimeOut,IFNULL(healthCheckOptions,'') FROM goku_service_config ORDER BY name;", func(rows *SQL.Rows) error {
+		s := new(entity.DeclarativeService)
+		err := rows.Scan(&s.Name, &s.IsDefault, &s.Driver, &s.Desc, &s.Config, &s.ClusterConfig, &s.HealthCheck, &s.HealthCheckPath, &s.HealthCheckPeriod, &s.HealthCheckCode, &s.HealthCheckTimeOut, &s.HealthCheckOptions)
+		doc.Services = append(doc.Services, s)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = eachRow(q, "SELECT balanceName,serviceName,IFNULL(appName,''),IFNULL(static,''),IFNULL(staticCluster,''),IFNULL(balanceDesc,''),IFNULL(subset,''),IFNULL(transport,'') FROM goku_balance ORDER BY balanceName;", func(rows *SQL.Rows) error {
+		b := new(entity.DeclarativeBalance)
+		err := rows.Scan(&b.Name, &b.ServiceName, &b.AppName, &b.Static, &b.StaticCluster, &b.Desc, &b.Subset, &b.Transport)
+		doc.Balances = append(doc.Balances, b)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = eachRow(q, "SELECT pluginName,pluginStatus,isStop,IFNULL(pluginConfig,''),IFNULL(errorPolicy,'') FROM goku_plugin ORDER BY pluginName;", func(rows *SQL.Rows) error {
+		p := new(entity.DeclarativePlugin)
+		var status int
+		err := rows.Scan(&p.Name, &status, &p.IsStop, &p.Config, &p.Policy)
+		p.Enable = status == 1
+		doc.Plugins = append(doc.Plugins, p)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make(map[int]*entity.DeclarativeProject)
+	err = eachRow(q, "SELECT projectID,projectName FROM goku_gateway_project ORDER BY projectID;", func(rows *SQL.Rows) error {
+		var id int
+		p := new(entity.DeclarativeProject)
+		if err := rows.Scan(&id, &p.Name); err != nil {
+			return err
+		}
+		if _, has := state.projectIDs[p.Name]; has {
+			// 同名项目只导出第一个
+			return nil
+		}
+		projects[id] = p
+		state.projectIDs[p.Name] = id
+		state.apiIDs[p.Name] = make(map[string]int)
+		doc.Projects = append(doc.Projects, p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	type group struct {
+		name   string
+		parent int
+	}
+	groups := make(map[int]*group)
+	err = eachRow(q, "SELECT groupID,groupName,parentGroupID FROM goku_gateway_api_group;", func(rows *SQL.Rows) error {
+		var id int
+		g := new(group)
+		err := rows.Scan(&id, &g.name, &g.parent)
+		groups[id] = g
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	groupPath := func(id int) string {
+		names := make([]string, 0, 3)
+		for i := 0; i < 10; i++ {
+			g, has := groups[id]
+			if !has {
+				break
+			}
+			names = append([]string{g.name}, names...)
+			id = g.parent
+		}
+		return strings.Join(names, "/")
+	}
+
+	err = eachRow(q, "SELECT apiID,projectID,groupID,apiName,requestURL,requestMethod,IFNULL(targetURL,''),IFNULL(targetMethod,''),IFNULL(isFollow,'false'),IFNULL(balanceName,''),IFNULL(protocol,'http'),IFNULL(timeout,0),IFNULL(retryCount,0),alertValve,routePriority,apiType,IFNULL(linkApis,''),IFNULL(staticResponse,''),IFNULL(responseDataType,'origin'),IFNULL(transform,''),IFNULL(traffic,''),IFNULL(mirror,''),IFNULL(retryPolicy,''),IFNULL(hedge,'') FROM goku_gateway_api ORDER BY apiID;", func(rows *SQL.Rows) error {
+		var apiID, projectID, groupID int
+		var isFollow string
+		a := new(entity.DeclarativeAPI)
+		err := rows.Scan(&apiID, &projectID, &groupID, &a.Name, &a.RequestURL, &a.RequestMethod, &a.TargetURL, &a.TargetMethod, &isFollow, &a.Balance, &a.Protocol, &a.Timeout, &a.RetryCount, &a.AlertValve, &a.RoutePriority, &a.APIType, &a.LinkAPIs, &a.StaticResponse, &a.ResponseDataType, &a.Transform, &a.Traffic, &a.Mirror, &a.RetryPolicy, &a.Hedge)
+		if err != nil {
+			return err
+		}
+		p, has := projects[projectID]
+		if !has {
+			return nil
+		}
+		a.IsFollow = isFollow == "true"
+		a.Group = groupPath(groupID)
+		p.APIs = append(p.APIs, a)
+		state.apiIDs[p.Name][a.Key()] = apiID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = eachRow(q, "SELECT S.strategyID,S.strategyName,IFNULL(G.groupName,''),S.enableStatus FROM goku_gateway_strategy S LEFT JOIN goku_gateway_strategy_group G ON S.groupID = G.groupID ORDER BY S.strategyID;", func(rows *SQL.Rows) error {
+		s := new(entity.DeclarativeStrategy)
+		var enable int
+		err := rows.Scan(&s.ID, &s.Name, &s.Group, &enable)
+		s.Enable = enable == 1
+		doc.Strategies = append(doc.Strategies, s)
+		state.strategies[s.ID] = s
+		state.strategyOf[s.ID] = make(map[int]*entity.DeclarativeStrategyAPI)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = eachRow(q, "SELECT strategyID,pluginName,IFNULL(pluginConfig,''),IFNULL(pluginStatus,0),IFNULL(errorPolicy,'') FROM goku_conn_plugin_strategy ORDER BY connID;", func(rows *SQL.Rows) error {
+		var strategyID string
+		var status int
+		b := new(entity.DeclarativeBinding)
+		if err := rows.Scan(&strategyID, &b.Name, &b.Config, &status, &b.Policy); err != nil {
+			return err
+		}
+		b.Enable = status == 1
+		if s, has := state.strategies[strategyID]; has {
+			s.Plugins = append(s.Plugins, b)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = eachRow(q, "SELECT C.strategyID,C.apiID,P.projectName,A.requestMethod,A.requestURL,IFNULL(C.target,'') FROM goku_conn_strategy_api C INNER JOIN goku_gateway_api A ON C.apiID = A.apiID INNER JOIN goku_gateway_project P ON A.projectID = P.projectID ORDER BY C.connID;", func(rows *SQL.Rows) error {
+		var strategyID, method, url string
+		var apiID int
+		a := new(entity.DeclarativeStrategyAPI)
+		if err := rows.Scan(&strategyID, &apiID, &a.Project, &method, &url, &a.Target); err != nil {
+			return err
+		}
+		s, has := state.strategies[strategyID]
+		if !has || state.apiIDs[a.Project][method+" "+url] != apiID {
+			return nil
+		}
+		a.API = method + " " + url
+		s.APIs = append(s.APIs, a)
+		state.strategyOf[strategyID][apiID] = a
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = eachRow(q, "SELECT strategyID,apiID,pluginName,IFNULL(pluginConfig,''),IFNULL(pluginStatus,0),IFNULL(errorPolicy,'') FROM goku_conn_plugin_api ORDER BY connID;", func(rows *SQL.Rows) error {
+		var strategyID string
+		var apiID, status int
+		b := new(entity.DeclarativeBinding)
+		if err := rows.Scan(&strategyID, &apiID, &b.Name, &b.Config, &status, &b.Policy); err != nil {
+			return err
+		}
+		b.Enable = status == 1
+		if a, has := state.strategyOf[strategyID][apiID]; has {
+			a.Plugins = append(a.Plugins, b)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func boolStatus(enable bool) int {
+	if enable {
+		return 1
+	}
+	return 0
+}
+
+// declarativeApplier 在同一事务中应用声明式配置
+type declarativeApplier struct {
+	tx      *SQL.Tx
+	current *declarativeState
+	prune   bool
+	userID  int
+	now     string
+	tag     string
+}
+
+//ApplyDeclarative 在同一事务中应用声明式配置，只修改与当前不一致的内容，prune时删除文档中未声明的配置
+func (d *DeclarativeDao) ApplyDeclarative(doc *entity.Declarative, prune bool, userID int, check func(current *entity.Declarative) (bool, error)) (err error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	current, err := loadDeclarative(tx)
+	if err != nil {
+		return err
+	}
+	changed, err := check(current.doc)
+	if err != nil || !changed {
+		return err
+	}
+	t := time.Now()
+	a := &declarativeApplier{
+		tx:      tx,
+		current: current,
+		prune:   prune,
+		userID:  userID,
+		now:     t.Format("2006-01-02 15:04:05"),
+		tag:     t.Format("20060102150405"),
+	}
+	if err = a.services(doc.Services); err != nil {
+		return err
+	}
+	if err = a.balances(doc.Balances); err != nil {
+		return err
+	}
+	if err = a.plugins(doc.Plugins); err != nil {
+		return err
+	}
+	apiIDs, err := a.projects(doc.Projects)
+	if err != nil {
+		return err
+	}
+	return a.strategies(doc.Strategies, apiIDs)
+}
+
+func (a *declarativeApplier) exec(sql string, args ...interface{}) error {
+	_, err := a.tx.Exec(sql, args...)
+	if err != nil {
+		return fmt.Errorf("%s:%s", strings.Fields(sql)[0], err.Error())
+	}
+	return nil
+}
+
+func (a *declarativeApplier) services(services []*entity.DeclarativeService) error {
+	current := make(map[string]*entity.DeclarativeService)
+	for _, s := range a.current.doc.Services {
+		current[s.Name] = s
+	}
+	declared := make(map[string]bool)
+	for _, s := range services {
+		declared[s.Name] = true
+		old, has := current[s.Name]
+		if has && *old == *s {
+			continue
+		}
+		var err error
+		if has {
+			err = a.exec("UPDATE goku_service_config SET `default` = ?,driver = ?,`desc` = ?,config = ?,clusterConfig = ?,healthCheck = ?,healthCheckPath = ?,healthCheckPeriod = ?,healthCheckCode = ?,healthCheckTimeOut = ?,healthCheckOptions = ?,updateTime = ? WHERE name = ?;", boolStatus(s.IsDefault), s.Driver, s.Desc, s.Config, s.ClusterConfig, boolStatus(s.HealthCheck), s.HealthCheckPath, s.HealthCheckPeriod, s.HealthCheckCode, s.HealthCheckTimeOut, s.HealthCheckOptions, a.now, s.Name)
+		} else {
+			err = a.exec("INSERT INTO goku_service_config (name,`default`,driver,`desc`,config,clusterConfig,healthCheck,healthCheckPath,healthCheckPeriod,healthCheckCode,healthCheckTimeOut,healthCheckOptions,createTime,updateTime) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?);", s.Name, boolStatus(s.IsDefault), s.Driver, s.Desc, s.Config, s.ClusterConfig, boolStatus(s.HealthCheck), s.HealthCheckPath, s.HealthCheckPeriod, s.HealthCheckCode, s.HealthCheckTimeOut, s.HealthCheckOptions, a.now, a.now)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if !a.prune {
+		return nil
+	}
+	for name := range current {
+		if !declared[name] {
+			if err := a.exec("DELETE FROM goku_service_config WHERE name = ?;", name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (a *declarativeApplier) balances(balances []*entity.DeclarativeBalance) error {
+	current := make(map[string]*entity.DeclarativeBalance)
+	for _, b := range a.current.doc.Balances {
+		current[b.Name] = b
+	}
+	declared := make(map[string]bool)
+	for _, b := range balances {
+		declared[b.Name] = true
+		old, has := current[b.Name]
+		if has && *old == *b {
+			continue
+		}
+		var err error
+		if has {
+			err = a.exec("UPDATE goku_balance SET serviceName = ?,appName = ?,static = ?,staticCluster = ?,balanceDesc = ?,subset = ?,transport = ?,updateTime = ? WHERE balanceName = ?;", b.ServiceName, b.AppName, b.Static, b.StaticCluster, b.Desc, b.Subset, b.Transport, a.now, b.Name)
+		} else {
+			err = a.exec("INSERT INTO goku_balance (balanceName,serviceName,appName,static,staticCluster,balanceDesc,subset,transport,createTime,updateTime,defaultConfig,clusterConfig,balanceConfig) VALUES (?,?,?,?,?,?,?,?,?,?,'','','');", b.Name, b.ServiceName, b.AppName, b.Static, b.StaticCluster, b.Desc, b.Subset, b.Transport, a.now, a.now)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if !a.prune {
+		return nil
+	}
+	for name := range current {
+		if !declared[name] {
+			if err := a.exec("DELETE FROM goku_balance WHERE balanceName = ?;", name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// plugins 全局插件由插件包安装，只更新状态及配置，不新增也不删除
+func (a *declarativeApplier) plugins(plugins []*entity.DeclarativePlugin) error {
+	current := make(map[string]*entity.DeclarativePlugin)
+	for _, p := range a.current.doc.Plugins {
+		current[p.Name] = p
+	}
+	for _, p := range plugins {
+		old, has := current[p.Name]
+		if !has {
+			return fmt.Errorf("plugin %s does not exist", p.Name)
+		}
+		if *old == *p {
+			continue
+		}
+		// 未启用的插件保留原有的非启用状态
+		err := a.exec("UPDATE goku_plugin SET pluginStatus = CASE WHEN ? = 1 THEN 1 WHEN pluginStatus = 1 THEN 0 ELSE pluginStatus END,isStop = ?,pluginConfig = ?,errorPolicy = ? WHERE pluginName = ?;", boolStatus(p.Enable), boolStatus(p.IsStop), p.Config, p.Policy, p.Name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *declarativeApplier) deleteAPIs(apiIDs []int) error {
+	if len(apiIDs) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(apiIDs))
+	for _, id := range apiIDs {
+		ids = append(ids, strconv.Itoa(id))
+	}
+	list := strings.Join(ids, ",")
+	for _, table := range []string{"goku_gateway_api", "goku_conn_strategy_api", "goku_conn_plugin_api"} {
+		if err := a.exec("DELETE FROM " + table + " WHERE apiID IN (" + list + ");"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// projects 应用项目及接口，返回项目名称-接口标识到接口ID的映射
+func (a *declarativeApplier) projects(projects []*entity.DeclarativeProject) (map[string]map[string]int, error) {
+	current := make(map[string]*entity.DeclarativeProject)
+	for _, p := range a.current.doc.Projects {
+		current[p.Name] = p
+	}
+	apiIDs := make(map[string]map[string]int)
+	for _, p := range projects {
+		projectID, has := a.current.projectIDs[p.Name]
+		if !has {
+			result, err := a.tx.Exec("INSERT INTO goku_gateway_project (projectName,createTime,updateTime) VALUES (?,?,?);", p.Name, a.now, a.now)
+			if err != nil {
+				return nil, err
+			}
+			id, _ := result.LastInsertId()
+			projectID = int(id)
+		}
+		ids, err := a.apis(projectID, p, current[p.Name])
+		if err != nil {
+			return nil, err
+		}
+		apiIDs[p.Name] = ids
+	}
+	if !a.prune {
+		return apiIDs, nil
+	}
+	for name, p := range current {
+		if _, has := apiIDs[name]; has {
+			continue
+		}
+		ids := make([]int, 0, len(p.APIs))
+		for _, api := range p.APIs {
+			ids = append(ids, a.current.apiIDs[name][api.Key()])
+		}
+		if err := a.deleteAPIs(ids); err != nil {
+			return nil, err
+		}
+		projectID := a.current.projectIDs[name]
+		if err := a.exec("DELETE FROM goku_gateway_api_group WHERE projectID = ?;", projectID); err != nil {
+			return nil, err
+		}
+		if err := a.exec("DELETE FROM goku_gateway_project WHERE projectID = ?;", projectID); err != nil {
+			return nil, err
+		}
+	}
+	return apiIDs, nil
+}
+
+func (a *declarativeApplier) apis(projectID int, p, old *entity.DeclarativeProject) (map[string]int, error) {
+	current := make(map[string]*entity.DeclarativeAPI)
+	if old != nil {
+		for _, api := range old.APIs {
+			current[api.Key()] = api
+		}
+	}
+	currentIDs := a.current.apiIDs[p.Name]
+	groups := make(map[string]int)
+	ids := make(map[string]int)
+	changed := false
+	for _, api := range p.APIs {
+		key := api.Key()
+		if oldAPI, has := current[key]; has && *oldAPI == *api {
+			ids[key] = currentIDs[key]
+			continue
+		}
+		groupID, err := a.group(projectID, api.Group, groups)
+		if err != nil {
+			return nil, err
+		}
+		isFollow := strconv.FormatBool(api.IsFollow)
+		if apiID, has := currentIDs[key]; has {
+			err = a.exec("UPDATE goku_gateway_api SET groupID = ?,apiName = ?,targetURL = ?,targetMethod = ?,isFollow = ?,balanceName = ?,protocol = ?,timeout = ?,retryCount = ?,alertValve = ?,routePriority = ?,apiType = ?,linkAPIs = ?,staticResponse = ?,responseDataType = ?,transform = ?,traffic = ?,mirror = ?,retryPolicy = ?,hedge = ?,updateTime = ?,lastUpdateUserID = ? WHERE apiID = ?;", groupID, api.Name, api.TargetURL, api.TargetMethod, isFollow, api.Balance, api.Protocol, api.Timeout, api.RetryCount, api.AlertValve, api.RoutePriority, api.APIType, api.LinkAPIs, api.StaticResponse, api.ResponseDataType, api.Transform, api.Traffic, api.Mirror, api.RetryPolicy, api.Hedge, a.now, a.userID, apiID)
+			ids[key] = apiID
+		} else {
+			var result SQL.Result
+			result, err = a.tx.Exec("INSERT INTO goku_gateway_api (projectID,groupID,apiName,requestURL,requestMethod,targetURL,targetMethod,isFollow,balanceName,protocol,timeout,retryCount,alertValve,routePriority,apiType,linkAPIs,staticResponse,responseDataType,transform,traffic,mirror,retryPolicy,hedge,stripPrefix,stripSlash,createTime,updateTime,managerID,lastUpdateUserID,createUserID) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,'true','true',?,?,?,?,?);", projectID, groupID, api.Name, api.RequestURL, api.RequestMethod, api.TargetURL, api.TargetMethod, isFollow, api.Balance, api.Protocol, api.Timeout, api.RetryCount, api.AlertValve, api.RoutePriority, api.APIType, api.LinkAPIs, api.StaticResponse, api.ResponseDataType, api.Transform, api.Traffic, api.Mirror, api.RetryPolicy, api.Hedge, a.now, a.now, a.userID, a.userID, a.userID)
+			if err == nil {
+				id, _ := result.LastInsertId()
+				ids[key] = int(id)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+	if a.prune {
+		removed := make([]int, 0)
+		for key, id := range currentIDs {
+			if _, has := ids[key]; !has {
+				removed = append(removed, id)
+			}
+		}
+		sort.Ints(removed)
+		if err := a.deleteAPIs(removed); err != nil {
+			return nil, err
+		}
+		changed = changed || len(removed) > 0
+	}
+	if changed {
+		// 更新项目更新时间
+		if err := a.exec("UPDATE goku_gateway_project SET updateTime = ? WHERE projectID = ?;", a.now, projectID); err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+// group 按分组路径查找或逐级创建接口分组
+func (a *declarativeApplier) group(projectID int, path string, cache map[string]int) (int, error) {
+	if path == "" {
+		return 0, nil
+	}
+	if id, has := cache[path]; has {
+		return id, nil
+	}
+	parentID, groupPath := 0, ""
+	names := strings.Split(path, "/")
+	for depth, name := range names {
+		var id int
+		var p string
+		err := a.tx.QueryRow("SELECT groupID,IFNULL(groupPath,'') FROM goku_gateway_api_group WHERE projectID = ? AND parentGroupID = ? AND groupName = ?;", projectID, parentID, name).Scan(&id, &p)
+		if err == SQL.ErrNoRows {
+			result, err := a.tx.Exec("INSERT INTO goku_gateway_api_group (projectID,groupName,groupDepth,parentGroupID) VALUES (?,?,?,?);", projectID, name, depth+1, parentID)
+			if err != nil {
+				return 0, err
+			}
+			lastID, _ := result.LastInsertId()
+			id = int(lastID)
+			p = strconv.Itoa(id)
+			if groupPath != "" {
+				p = groupPath + "," + p
+			}
+			if err := a.exec("UPDATE goku_gateway_api_group SET groupPath = ? WHERE groupID = ?;", p, id); err != nil {
+				return 0, err
+			}
+		} else if err != nil {
+			return 0, err
+		}
+		parentID, groupPath = id, p
+	}
+	cache[path] = parentID
+	return parentID, nil
+}
+
+func (a *declarativeApplier) strategyGroup(name string, cache map[string]int) (int, error) {
+	if name == "" {
+		return 0, nil
+	}
+	if id, has := cache[name]; has {
+		return id, nil
+	}
+	var id int
+	err := a.tx.QueryRow("SELECT groupID FROM goku_gateway_strategy_group WHERE groupName = ?;", name).Scan(&id)
+	if err == SQL.ErrNoRows {
+		result, err := a.tx.Exec("INSERT INTO goku_gateway_strategy_group (groupName,groupType) VALUES (?,0);", name)
+		if err != nil {
+			return 0, err
+		}
+		lastID, _ := result.LastInsertId()
+		id = int(lastID)
+	} else if err != nil {
+		return 0, err
+	}
+	cache[name] = id
+	return id, nil
+}
+
+func (a *declarativeApplier) strategies(strategies []*entity.DeclarativeStrategy, apiIDs map[string]map[string]int) error {
+	groups := make(map[string]int)
+	declared := make(map[string]bool)
+	for _, s := range strategies {
+		declared[s.ID] = true
+		old, has := a.current.strategies[s.ID]
+		if !has || old.Name != s.Name || old.Group != s.Group || old.Enable != s.Enable {
+			groupID, err := a.strategyGroup(s.Group, groups)
+			if err != nil {
+				return err
+			}
+			if has {
+				err = a.exec("UPDATE goku_gateway_strategy SET strategyName = ?,groupID = ?,enableStatus = ?,updateTime = ? WHERE strategyID = ?;", s.Name, groupID, boolStatus(s.Enable), a.now, s.ID)
+			} else {
+				err = a.exec("INSERT INTO goku_gateway_strategy (strategyID,strategyName,groupID,enableStatus,createTime,updateTime) VALUES (?,?,?,?,?,?);", s.ID, s.Name, groupID, boolStatus(s.Enable), a.now, a.now)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		var oldPlugins []*entity.DeclarativeBinding
+		if has {
+			oldPlugins = old.Plugins
+		}
+		err := a.bindings(s.Plugins, oldPlugins, "goku_conn_plugin_strategy", "strategyID = ?", []interface{}{s.ID}, "strategyID", s.ID)
+		if err != nil {
+			return err
+		}
+		if err := a.strategyAPIs(s, apiIDs); err != nil {
+			return err
+		}
+	}
+	if !a.prune {
+		return nil
+	}
+	for id := range a.current.strategies {
+		if declared[id] {
+			continue
+		}
+		// 开放策略不可删除
+		for _, table := range []string{"goku_conn_strategy_api", "goku_conn_plugin_strategy", "goku_conn_plugin_api"} {
+			if err := a.exec("DELETE FROM "+table+" WHERE strategyID = ? AND strategyID NOT IN (SELECT strategyID FROM goku_gateway_strategy WHERE strategyType = 1);", id); err != nil {
+				return err
+			}
+		}
+		if err := a.exec("DELETE FROM goku_gateway_strategy WHERE strategyID = ? AND strategyType != 1;", id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *declarativeApplier) strategyAPIs(s *entity.DeclarativeStrategy, apiIDs map[string]map[string]int) error {
+	current := a.current.strategyOf[s.ID]
+	declared := make(map[int]bool)
+	for _, api := range s.APIs {
+		apiID, has := apiIDs[api.Project][api.API]
+		if !has {
+			apiID, has = a.current.apiIDs[api.Project][api.API]
+		}
+		if !has {
+			return fmt.Errorf("strategy %s: api %s of project %s does not exist", s.ID, api.API, api.Project)
+		}
+		declared[apiID] = true
+		old, has := current[apiID]
+		if !has {
+			err := a.exec("INSERT INTO goku_conn_strategy_api (strategyID,apiID,target,updateTime) VALUES (?,?,?,?);", s.ID, apiID, api.Target, a.now)
+			if err != nil {
+				return err
+			}
+		} else if old.Target != api.Target {
+			err := a.exec("UPDATE goku_conn_strategy_api SET target = ?,updateTime = ? WHERE strategyID = ? AND apiID = ?;", api.Target, a.now, s.ID, apiID)
+			if err != nil {
+				return err
+			}
+		}
+		var oldPlugins []*entity.DeclarativeBinding
+		if old != nil {
+			oldPlugins = old.Plugins
+		}
+		err := a.bindings(api.Plugins, oldPlugins, "goku_conn_plugin_api", "strategyID = ? AND apiID = ?", []interface{}{s.ID, apiID}, "strategyID,apiID", s.ID, apiID)
+		if err != nil {
+			return err
+		}
+	}
+	if !a.prune {
+		return nil
+	}
+	for apiID := range current {
+		if declared[apiID] {
+			continue
+		}
+		if err := a.exec("DELETE FROM goku_conn_strategy_api WHERE strategyID = ? AND apiID = ?;", s.ID, apiID); err != nil {
+			return err
+		}
+		if err := a.exec("DELETE FROM goku_conn_plugin_api WHERE strategyID = ? AND apiID = ?;", s.ID, apiID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bindings 应用策略或接口绑定的插件，配置变化时更新updateTag使节点重新创建插件
+func (a *declarativeApplier) bindings(bindings, old []*entity.DeclarativeBinding, table, where string, whereArgs []interface{}, columns string, values ...interface{}) error {
+	current := make(map[string]*entity.DeclarativeBinding)
+	for _, b := range old {
+		current[b.Name] = b
+	}
+	declared := make(map[string]bool)
+	for _, b := range bindings {
+		declared[b.Name] = true
+		o, has := current[b.Name]
+		if has && *o == *b {
+			continue
+		}
+		var err error
+		if has {
+			tag := ""
+			if o.Config != b.Config {
+				tag = a.tag
+			}
+			args := append([]interface{}{b.Config, boolStatus(b.Enable), b.Policy, a.now, tag, tag}, whereArgs...)
+			err = a.exec("UPDATE "+table+" SET pluginConfig = ?,pluginStatus = ?,errorPolicy = ?,updateTime = ?,updateTag = CASE WHEN ? = '' THEN updateTag ELSE ? END WHERE "+where+" AND pluginName = ?;", append(args, b.Name)...)
+		} else {
+			placeholders := strings.Repeat(",?", len(values))
+			args := append(append([]interface{}{}, values...), b.Name, b.Config, boolStatus(b.Enable), b.Policy, a.now, a.now, a.tag)
+			err = a.exec("INSERT INTO "+table+" ("+columns+",pluginName,pluginConfig,pluginStatus,errorPolicy,createTime,updateTime,updateTag) VALUES ("+placeholders[1:]+",?,?,?,?,?,?,?);", args...)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if !a.prune {
+		return nil
+	}
+	for name := range current {
+		if declared[name] {
+			continue
+		}
+		if err := a.exec("DELETE FROM "+table+" WHERE "+where+" AND pluginName = ?;", append(append([]interface{}{}, whereArgs...), name)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
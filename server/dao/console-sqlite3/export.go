@@ -0,0 +1,66 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//ExportDao ExportDao
+type ExportDao struct {
+	db *SQL.DB
+}
+
+//NewExportDao new ExportDao
+func NewExportDao() *ExportDao {
+	return &ExportDao{}
+}
+
+//Create create
+func (d *ExportDao) Create(db *SQL.DB) (interface{}, error) {
+	d.db = db
+	var i dao.ExportDao = d
+	return &i, nil
+}
+
+//GetExportAPIList 获取项目下待导出的接口及其策略路由
+func (d *ExportDao) GetExportAPIList(projectID int) ([]*entity.ExportAPI, error) {
+	db := d.db
+	sql := `SELECT A.apiID,A.apiName,IFNULL(G.groupName,''),A.requestURL,IFNULL(A.targetURL,''),A.requestMethod,IFNULL(A.targetMethod,''),IFNULL(A.balanceName,''),IFNULL(A.protocol,'http'),IFNULL(A.timeout,0),IFNULL(A.retryCount,0),A.routePriority FROM goku_gateway_api A LEFT JOIN goku_gateway_api_group G ON A.groupID = G.groupID WHERE A.projectID = ? ORDER BY A.apiID;`
+	rows, err := db.Query(sql, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	apiList := make([]*entity.ExportAPI, 0)
+	apis := make(map[int]*entity.ExportAPI)
+	for rows.Next() {
+		api := new(entity.ExportAPI)
+		err = rows.Scan(&api.APIID, &api.APIName, &api.GroupName, &api.RequestURL, &api.TargetURL, &api.RequestMethod, &api.TargetMethod, &api.BalanceName, &api.Protocol, &api.Timeout, &api.RetryCount, &api.RoutePriority)
+		if err != nil {
+			return nil, err
+		}
+		apiList = append(apiList, api)
+		apis[api.APIID] = api
+	}
+
+	sql = `SELECT C.apiID,C.strategyID,S.strategyName,IFNULL(C.target,'') FROM goku_conn_strategy_api C INNER JOIN goku_gateway_strategy S ON C.strategyID = S.strategyID INNER JOIN goku_gateway_api A ON C.apiID = A.apiID WHERE A.projectID = ? ORDER BY C.connID;`
+	routeRows, err := db.Query(sql, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer routeRows.Close()
+	for routeRows.Next() {
+		var apiID int
+		route := new(entity.APIRoute)
+		err = routeRows.Scan(&apiID, &route.StrategyID, &route.StrategyName, &route.Target)
+		if err != nil {
+			return nil, err
+		}
+		if api, has := apis[apiID]; has {
+			api.Routes = append(api.Routes, route)
+		}
+	}
+	return apiList, nil
+}
@@ -104,3 +104,19 @@ func (d *GatewayDao) GetGatewayInfo() (nodeStartCount, nodeStopCount, projectCou
 	}
 	return
 }
+
+//GetRouter 获取节点使用的路由实现
+func (d *GatewayDao) GetRouter() (string, error) {
+	var router string
+	err := d.db.QueryRow("SELECT router FROM goku_gateway WHERE id = 1;").Scan(&router)
+	if err != nil {
+		return "", err
+	}
+	return router, nil
+}
+
+//SetRouter 设置节点使用的路由实现
+func (d *GatewayDao) SetRouter(router string) error {
+	_, err := d.db.Exec("UPDATE goku_gateway SET router = ? WHERE id = 1;", router)
+	return err
+}
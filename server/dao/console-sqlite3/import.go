@@ -173,3 +173,59 @@ func (d *ImportDao) ImportAPIFromAms(projectID, groupID, userID int, apiList []e
 	Tx.Commit()
 	return true, "", nil
 }
+
+//ImportAPIList 导入接口列表，按分组名称归入项目的一级分组，分组不存在时新建
+func (d *ImportDao) ImportAPIList(projectID, userID int, apiList []*entity.ImportAPI) (bool, string, error) {
+	db := d.db
+	Tx, _ := db.Begin()
+	now := time.Now().Format("2006-01-02 15:04:05")
+	groups := make(map[string]int)
+	rows, err := Tx.Query("SELECT groupID,groupName FROM goku_gateway_api_group WHERE projectID = ? AND parentGroupID = 0;", projectID)
+	if err != nil {
+		Tx.Rollback()
+		return false, "[ERROR]Fail to get api group!", err
+	}
+	for rows.Next() {
+		var groupID int
+		var groupName string
+		if err = rows.Scan(&groupID, &groupName); err != nil {
+			rows.Close()
+			Tx.Rollback()
+			return false, "[ERROR]Fail to get api group!", err
+		}
+		groups[groupName] = groupID
+	}
+	rows.Close()
+
+	for _, api := range apiList {
+		groupID, has := groups[api.GroupName]
+		if !has {
+			result, err := Tx.Exec("INSERT INTO goku_gateway_api_group (projectID,groupName,groupDepth,parentGroupID) VALUES (?,?,1,0);", projectID, api.GroupName)
+			if err != nil {
+				Tx.Rollback()
+				return false, "[ERROR]Fail to insert api group!", err
+			}
+			id, _ := result.LastInsertId()
+			groupID = int(id)
+			_, err = Tx.Exec("UPDATE goku_gateway_api_group SET groupPath = ? WHERE groupID = ?;", strconv.Itoa(groupID), groupID)
+			if err != nil {
+				Tx.Rollback()
+				return false, "[ERROR]Fail to update api group!", err
+			}
+			groups[api.GroupName] = groupID
+		}
+		_, err = Tx.Exec("INSERT INTO goku_gateway_api (projectID,groupID,apiName,requestURL,targetURL,requestMethod,targetMethod,isFollow,stripPrefix,timeout,retryCount,createTime,updateTime,protocol,balanceName,stripSlash,responseDataType,managerID,lastUpdateUserID,createUserID) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?);", projectID, groupID, api.APIName, api.RequestURL, api.TargetURL, api.RequestMethod, api.TargetMethod, "false", "true", api.Timeout, api.RetryCount, now, now, api.Protocol, api.BalanceName, "true", "origin", userID, userID, userID)
+		if err != nil {
+			Tx.Rollback()
+			return false, "[ERROR]Fail to insert api:" + api.RequestMethod + " " + api.RequestURL, err
+		}
+	}
+	// 更新项目更新时间
+	_, err = Tx.Exec("UPDATE goku_gateway_project SET updateTime = ? WHERE projectID = ?;", now, projectID)
+	if err != nil {
+		Tx.Rollback()
+		return false, "[ERROR]Fail to update data!", err
+	}
+	Tx.Commit()
+	return true, "", nil
+}
@@ -0,0 +1,18 @@
+package goku314
+
+import (
+	SQL "database/sql"
+
+	"github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/updater"
+)
+
+// updateGokuAPIPolicy 增加接口的请求及响应转换、流量拆分、流量镜像、重试策略及对冲请求
+func updateGokuAPIPolicy(db *SQL.DB, updaterDao *updater.Dao) error {
+	for _, column := range []string{"transform", "traffic", "mirror", "retryPolicy", "hedge"} {
+		err := addTextColumn(db, updaterDao, "goku_gateway_api", column)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
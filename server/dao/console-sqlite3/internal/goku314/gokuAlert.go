@@ -0,0 +1,47 @@
+package goku314
+
+import SQL "database/sql"
+
+const gokuAlertRuleSQL = `CREATE TABLE IF NOT EXISTS "goku_alert_rule" (
+  "ruleID" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "ruleName" TEXT(255) NOT NULL,
+  "targetType" TEXT(32) NOT NULL,
+  "target" TEXT(255),
+  "metric" TEXT(32) NOT NULL,
+  "threshold" REAL NOT NULL,
+  "period" INTEGER NOT NULL,
+  "webhook" TEXT(255),
+  "receivers" TEXT,
+  "enable" INTEGER(4) NOT NULL DEFAULT 1,
+  "silenceUntil" TEXT,
+  "createTime" TEXT NOT NULL,
+  "updateTime" TEXT NOT NULL
+);`
+
+const gokuAlertHistorySQL = `CREATE TABLE IF NOT EXISTS "goku_alert_history" (
+  "alertID" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "ruleID" INTEGER NOT NULL,
+  "ruleName" TEXT(255) NOT NULL,
+  "targetType" TEXT(32) NOT NULL,
+  "target" TEXT(255) NOT NULL,
+  "metric" TEXT(32) NOT NULL,
+  "value" REAL NOT NULL,
+  "threshold" REAL NOT NULL,
+  "status" TEXT(16) NOT NULL,
+  "silenced" INTEGER(4) NOT NULL DEFAULT 0,
+  "notifyResult" TEXT,
+  "createTime" TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS "goku_alert_history_ruleID" ON "goku_alert_history" ("ruleID");`
+
+func createGokuAlert(db *SQL.DB) error {
+	_, err := db.Exec(gokuAlertRuleSQL)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(gokuAlertHistorySQL)
+	if err != nil {
+		return err
+	}
+	return nil
+}
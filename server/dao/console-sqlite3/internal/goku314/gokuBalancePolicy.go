@@ -0,0 +1,18 @@
+package goku314
+
+import (
+	SQL "database/sql"
+
+	"github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/updater"
+)
+
+// updateGokuBalancePolicy 增加负载的实例子集及转发连接配置
+func updateGokuBalancePolicy(db *SQL.DB, updaterDao *updater.Dao) error {
+	for _, column := range []string{"subset", "transport"} {
+		err := addTextColumn(db, updaterDao, "goku_balance", column)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
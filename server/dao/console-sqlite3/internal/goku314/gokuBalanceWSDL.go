@@ -0,0 +1,18 @@
+package goku314
+
+import (
+	SQL "database/sql"
+
+	"github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/updater"
+)
+
+// updateGokuBalanceWSDL 增加负载的WSDL文档
+func updateGokuBalanceWSDL(db *SQL.DB, updaterDao *updater.Dao) error {
+	if !updaterDao.IsColumnExist("goku_balance", "wsdl") {
+		_, err := db.Exec("ALTER TABLE goku_balance ADD COLUMN \"wsdl\" TEXT NOT NULL DEFAULT ''")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
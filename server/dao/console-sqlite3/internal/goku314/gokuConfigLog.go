@@ -0,0 +1,22 @@
+package goku314
+
+import (
+	SQL "database/sql"
+
+	"github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/updater"
+)
+
+// updateGokuConfigLog 增加access日志的输出格式及外部输出目标
+func updateGokuConfigLog(db *SQL.DB, updaterDao *updater.Dao) error {
+	columns := []string{"format", "sinks"}
+	for _, column := range columns {
+		if updaterDao.IsColumnExist("goku_config_log", column) {
+			continue
+		}
+		_, err := db.Exec("ALTER TABLE goku_config_log ADD COLUMN \"" + column + "\" TEXT NOT NULL DEFAULT ''")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package goku314
+
+import SQL "database/sql"
+
+const gokuNodeHeartbeatSQL = `CREATE TABLE IF NOT EXISTS "goku_node_heartbeat" (
+  "nodeKey" TEXT(32) NOT NULL PRIMARY KEY,
+  "heartbeat" TEXT NOT NULL,
+  "updateTime" TEXT NOT NULL
+);`
+
+func createGokuNodeHeartbeat(db *SQL.DB) error {
+	_, err := db.Exec(gokuNodeHeartbeatSQL)
+	if err != nil {
+		return err
+	}
+	return nil
+}
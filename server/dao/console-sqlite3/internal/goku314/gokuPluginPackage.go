@@ -0,0 +1,36 @@
+package goku314
+
+import SQL "database/sql"
+
+const gokuPluginPackageSQL = `CREATE TABLE IF NOT EXISTS "goku_plugin_package" (
+  "pluginName" TEXT(255) NOT NULL PRIMARY KEY,
+  "version" TEXT(64) NOT NULL,
+  "checksum" TEXT(64) NOT NULL,
+  "size" INTEGER NOT NULL,
+  "content" BLOB NOT NULL,
+  "updateTime" TEXT NOT NULL
+);`
+
+const gokuPluginNodeStatusSQL = `CREATE TABLE IF NOT EXISTS "goku_plugin_node_status" (
+  "nodeKey" TEXT(32) NOT NULL,
+  "pluginName" TEXT(255) NOT NULL,
+  "version" TEXT(64) NOT NULL,
+  "checksum" TEXT(64) NOT NULL,
+  "code" INTEGER NOT NULL,
+  "error" TEXT,
+  "needRestart" INTEGER(4) NOT NULL DEFAULT 0,
+  "updateTime" TEXT NOT NULL,
+  PRIMARY KEY ("nodeKey", "pluginName")
+);`
+
+func createGokuPluginPackage(db *SQL.DB) error {
+	_, err := db.Exec(gokuPluginPackageSQL)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(gokuPluginNodeStatusSQL)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package goku314
+
+import (
+	SQL "database/sql"
+
+	"github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/updater"
+)
+
+// updateGokuPluginPolicy 为全局插件及策略、接口绑定的插件增加执行策略
+func updateGokuPluginPolicy(db *SQL.DB, updaterDao *updater.Dao) error {
+	for _, table := range []string{"goku_plugin", "goku_conn_plugin_strategy", "goku_conn_plugin_api"} {
+		if updaterDao.IsColumnExist(table, "errorPolicy") {
+			continue
+		}
+		_, err := db.Exec("ALTER TABLE " + table + " ADD COLUMN \"errorPolicy\" TEXT NOT NULL DEFAULT ''")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package goku314
+
+import (
+	SQL "database/sql"
+
+	"github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/updater"
+)
+
+// updateGokuPluginSchema 增加插件配置的JSON Schema
+func updateGokuPluginSchema(db *SQL.DB, updaterDao *updater.Dao) error {
+	if updaterDao.IsColumnExist("goku_plugin", "configSchema") {
+		return nil
+	}
+	_, err := db.Exec("ALTER TABLE goku_plugin ADD COLUMN \"configSchema\" TEXT NOT NULL DEFAULT ''")
+	return err
+}
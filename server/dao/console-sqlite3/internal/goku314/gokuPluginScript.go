@@ -0,0 +1,16 @@
+package goku314
+
+import SQL "database/sql"
+
+const gokuPluginScriptSQL = `CREATE TABLE IF NOT EXISTS "goku_plugin_script" (
+  "pluginName" TEXT(255) NOT NULL PRIMARY KEY,
+  "script" TEXT NOT NULL,
+  "timeout" INTEGER NOT NULL DEFAULT 0,
+  "memoryLimit" INTEGER NOT NULL DEFAULT 0,
+  "updateTime" TEXT NOT NULL
+);`
+
+func createGokuPluginScript(db *SQL.DB) error {
+	_, err := db.Exec(gokuPluginScriptSQL)
+	return err
+}
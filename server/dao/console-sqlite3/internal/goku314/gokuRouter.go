@@ -0,0 +1,24 @@
+package goku314
+
+import (
+	SQL "database/sql"
+
+	"github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/updater"
+)
+
+// updateGokuRouter 增加网关的路由实现及接口的路由优先级
+func updateGokuRouter(db *SQL.DB, updaterDao *updater.Dao) error {
+	if !updaterDao.IsColumnExist("goku_gateway", "router") {
+		_, err := db.Exec("ALTER TABLE goku_gateway ADD COLUMN \"router\" TEXT NOT NULL DEFAULT ''")
+		if err != nil {
+			return err
+		}
+	}
+	if !updaterDao.IsColumnExist("goku_gateway_api", "routePriority") {
+		_, err := db.Exec("ALTER TABLE goku_gateway_api ADD COLUMN \"routePriority\" INTEGER NOT NULL DEFAULT 0")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
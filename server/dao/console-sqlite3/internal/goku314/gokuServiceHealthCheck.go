@@ -0,0 +1,18 @@
+package goku314
+
+import (
+	SQL "database/sql"
+
+	"github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/updater"
+)
+
+// updateGokuServiceHealthCheck 增加服务发现的健康检查扩展配置
+func updateGokuServiceHealthCheck(db *SQL.DB, updaterDao *updater.Dao) error {
+	if !updaterDao.IsColumnExist("goku_service_config", "healthCheckOptions") {
+		_, err := db.Exec("ALTER TABLE goku_service_config ADD COLUMN \"healthCheckOptions\" TEXT NOT NULL DEFAULT ''")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
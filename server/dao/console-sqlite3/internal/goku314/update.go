@@ -0,0 +1,137 @@
+package goku314
+
+import (
+	"database/sql"
+
+	"github.com/eolinker/goku-api-gateway/common/pdao"
+	"github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/updater"
+)
+
+//Version 版本号
+const Version = "3.1.4"
+
+//DBDriver dbDriver
+const DBDriver = "sqlite3"
+
+//RegisterUpdate RegisterUpdate
+func RegisterUpdate() {
+	pdao.RegisterDBBuilder(DBDriver, new(factory))
+}
+
+type factory struct {
+}
+
+func (f *factory) Build(db *sql.DB) error {
+	return Exec(db)
+}
+
+//Exec 执行3.1.4版本的表更新
+func Exec(db *sql.DB) error {
+
+	updaterDao := updater.NewUpdaterDaoWidthDB(db)
+
+	if version := updaterDao.GetTableVersion("goku_node_heartbeat"); version != Version {
+		err := createGokuNodeHeartbeat(db)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_node_heartbeat", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_config_log"); version != Version {
+		err := updateGokuConfigLog(db, updaterDao)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_config_log", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_alert_rule"); version != Version {
+		err := createGokuAlert(db)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_alert_rule", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_gateway"); version != Version {
+		err := updateGokuRouter(db, updaterDao)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_gateway", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_plugin_script"); version != Version {
+		err := createGokuPluginScript(db)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_plugin_script", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_plugin_package"); version != Version {
+		err := createGokuPluginPackage(db)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_plugin_package", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_plugin"); version != Version {
+		err := updateGokuPluginSchema(db, updaterDao)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_plugin", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_conn_plugin_api"); version != Version {
+		err := updateGokuPluginPolicy(db, updaterDao)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_conn_plugin_api", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_gateway_api"); version != Version {
+		err := updateGokuAPIPolicy(db, updaterDao)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_gateway_api", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_balance"); version != Version {
+		err := updateGokuBalanceWSDL(db, updaterDao)
+		if err != nil {
+			return err
+		}
+		err = updateGokuBalancePolicy(db, updaterDao)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_balance", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_service_config"); version != Version {
+		err := updateGokuServiceHealthCheck(db, updaterDao)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_service_config", Version)
+	}
+
+	updaterDao.SetGokuVersion(Version)
+
+	return nil
+}
+
+// addTextColumn 表中没有column列时增加，以空字符串为默认值
+func addTextColumn(db *sql.DB, updaterDao *updater.Dao, table, column string) error {
+	if updaterDao.IsColumnExist(table, column) {
+		return nil
+	}
+	_, err := db.Exec("ALTER TABLE " + table + " ADD COLUMN \"" + column + "\" TEXT NOT NULL DEFAULT ''")
+	return err
+}
@@ -2,6 +2,7 @@ package console_sqlite3
 
 import (
 	SQL "database/sql"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -297,3 +298,33 @@ func (d *NodeDao) SetHeartBeatTime(nodeKey string, heartBeatTime time.Time) erro
 	}
 	return nil
 }
+
+//SaveHeartbeat 保存节点最近一次心跳
+func (d *NodeDao) SaveHeartbeat(nodeKey string, heartbeat *entity.NodeHeartbeat) error {
+	db := d.db
+	data, err := json.Marshal(heartbeat)
+	if err != nil {
+		return err
+	}
+	now := time.Now().Format("2006-01-02 15:04:05")
+	sql := "REPLACE INTO goku_node_heartbeat (`nodeKey`,`heartbeat`,`updateTime`) VALUES (?,?,?);"
+	_, err = db.Exec(sql, nodeKey, string(data), now)
+	return err
+}
+
+//GetHeartbeat 获取节点最近一次心跳
+func (d *NodeDao) GetHeartbeat(nodeKey string) (*entity.NodeHeartbeat, error) {
+	db := d.db
+	data := ""
+	sql := "SELECT heartbeat FROM goku_node_heartbeat WHERE nodeKey = ?;"
+	err := db.QueryRow(sql, nodeKey).Scan(&data)
+	if err != nil {
+		return nil, err
+	}
+	heartbeat := new(entity.NodeHeartbeat)
+	err = json.Unmarshal([]byte(data), heartbeat)
+	if err != nil {
+		return nil, err
+	}
+	return heartbeat, nil
+}
@@ -158,6 +158,21 @@ func (d *PluginDao) DeletePlugin(pluginName string) (bool, string, error) {
 		Tx.Rollback()
 		return false, "[ERROR]Failed to delete data!", err
 	}
+	_, err = Tx.Exec(`DELETE FROM goku_plugin_script WHERE pluginName = ?`, pluginName)
+	if err != nil {
+		Tx.Rollback()
+		return false, "[ERROR]Failed to delete data!", err
+	}
+	_, err = Tx.Exec(`DELETE FROM goku_plugin_package WHERE pluginName = ?`, pluginName)
+	if err != nil {
+		Tx.Rollback()
+		return false, "[ERROR]Failed to delete data!", err
+	}
+	_, err = Tx.Exec(`DELETE FROM goku_plugin_node_status WHERE pluginName = ?`, pluginName)
+	if err != nil {
+		Tx.Rollback()
+		return false, "[ERROR]Failed to delete data!", err
+	}
 
 	Tx.Commit()
 	return true, "", nil
@@ -315,3 +330,81 @@ func (d *PluginDao) EditPluginCheckStatus(pluginName string, isCheck int) (bool,
 	}
 	return true, "", nil
 }
+
+//GetPluginScript 获取插件的JavaScript脚本
+func (d *PluginDao) GetPluginScript(pluginName string) (*entity.PluginScript, error) {
+	db := d.db
+	sql := "SELECT pluginName,script,timeout,memoryLimit,updateTime FROM goku_plugin_script WHERE pluginName = ?;"
+	script := new(entity.PluginScript)
+	err := db.QueryRow(sql, pluginName).Scan(&script.PluginName, &script.Script, &script.Timeout, &script.MemoryLimit, &script.UpdateTime)
+	if err != nil {
+		return nil, err
+	}
+	return script, nil
+}
+
+//SetPluginScript 设置插件的JavaScript脚本，脚本为空时删除
+func (d *PluginDao) SetPluginScript(script *entity.PluginScript) error {
+	db := d.db
+	if script.Script == "" {
+		_, err := db.Exec("DELETE FROM goku_plugin_script WHERE pluginName = ?;", script.PluginName)
+		return err
+	}
+	sql := "REPLACE INTO goku_plugin_script (pluginName,script,timeout,memoryLimit,updateTime) VALUES (?,?,?,?,?);"
+	_, err := db.Exec(sql, script.PluginName, script.Script, script.Timeout, script.MemoryLimit, script.UpdateTime)
+	return err
+}
+
+//GetPluginSchema 获取插件配置的JSON Schema
+func (d *PluginDao) GetPluginSchema(pluginName string) (string, error) {
+	db := d.db
+	var schema string
+	err := db.QueryRow("SELECT IFNULL(configSchema,'') FROM goku_plugin WHERE pluginName = ?;", pluginName).Scan(&schema)
+	if err != nil {
+		return "", err
+	}
+	return schema, nil
+}
+
+//SetPluginSchema 设置插件配置的JSON Schema
+func (d *PluginDao) SetPluginSchema(pluginName, schema string) error {
+	db := d.db
+	_, err := db.Exec("UPDATE goku_plugin SET configSchema = ? WHERE pluginName = ?;", schema, pluginName)
+	return err
+}
+
+func pluginPolicyTarget(pluginName, strategyID string, apiID int) (string, string, []interface{}) {
+	if strategyID == "" {
+		return "goku_plugin", "pluginName = ?", []interface{}{pluginName}
+	}
+	if apiID == 0 {
+		return "goku_conn_plugin_strategy", "pluginName = ? AND strategyID = ?", []interface{}{pluginName, strategyID}
+	}
+	return "goku_conn_plugin_api", "pluginName = ? AND strategyID = ? AND apiID = ?", []interface{}{pluginName, strategyID, apiID}
+}
+
+//GetPluginPolicy 获取插件的执行策略，strategyID为空时为全局插件，apiID为0时为策略插件
+func (d *PluginDao) GetPluginPolicy(pluginName, strategyID string, apiID int) (string, error) {
+	db := d.db
+	table, where, args := pluginPolicyTarget(pluginName, strategyID, apiID)
+	var policy string
+	err := db.QueryRow("SELECT IFNULL(errorPolicy,'') FROM "+table+" WHERE "+where+";", args...).Scan(&policy)
+	if err != nil {
+		return "", err
+	}
+	return policy, nil
+}
+
+//SetPluginPolicy 设置插件的执行策略
+func (d *PluginDao) SetPluginPolicy(pluginName, strategyID string, apiID int, policy string) error {
+	db := d.db
+	table, where, args := pluginPolicyTarget(pluginName, strategyID, apiID)
+	result, err := db.Exec("UPDATE "+table+" SET errorPolicy = ? WHERE "+where+";", append([]interface{}{policy}, args...)...)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.New("[ERROR]The plugin binding does not exist")
+	}
+	return nil
+}
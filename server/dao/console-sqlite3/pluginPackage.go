@@ -0,0 +1,103 @@
+package console_sqlite3
+
+import (
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//GetPluginPackages 获取插件包列表，不包含插件包内容
+func (d *PluginDao) GetPluginPackages() ([]*entity.PluginPackage, error) {
+	db := d.db
+	sql := "SELECT pluginName,version,checksum,size,updateTime FROM goku_plugin_package ORDER BY pluginName;"
+	rows, err := db.Query(sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	packages := make([]*entity.PluginPackage, 0)
+	for rows.Next() {
+		pkg := new(entity.PluginPackage)
+		err = rows.Scan(&pkg.PluginName, &pkg.Version, &pkg.Checksum, &pkg.Size, &pkg.UpdateTime)
+		if err != nil {
+			return nil, err
+		}
+		packages = append(packages, pkg)
+	}
+	return packages, nil
+}
+
+//GetPluginPackage 获取插件包
+func (d *PluginDao) GetPluginPackage(pluginName string) (*entity.PluginPackage, error) {
+	db := d.db
+	sql := "SELECT pluginName,version,checksum,size,content,updateTime FROM goku_plugin_package WHERE pluginName = ?;"
+	pkg := new(entity.PluginPackage)
+	err := db.QueryRow(sql, pluginName).Scan(&pkg.PluginName, &pkg.Version, &pkg.Checksum, &pkg.Size, &pkg.Content, &pkg.UpdateTime)
+	if err != nil {
+		return nil, err
+	}
+	return pkg, nil
+}
+
+//SetPluginPackage 上传插件包，覆盖旧版本
+func (d *PluginDao) SetPluginPackage(pkg *entity.PluginPackage) error {
+	db := d.db
+	sql := "REPLACE INTO goku_plugin_package (pluginName,version,checksum,size,content,updateTime) VALUES (?,?,?,?,?,?);"
+	_, err := db.Exec(sql, pkg.PluginName, pkg.Version, pkg.Checksum, pkg.Size, pkg.Content, pkg.UpdateTime)
+	return err
+}
+
+//DeletePluginPackage 删除插件包及节点加载状态
+func (d *PluginDao) DeletePluginPackage(pluginName string) error {
+	db := d.db
+	Tx, _ := db.Begin()
+	_, err := Tx.Exec("DELETE FROM goku_plugin_package WHERE pluginName = ?;", pluginName)
+	if err != nil {
+		Tx.Rollback()
+		return err
+	}
+	_, err = Tx.Exec("DELETE FROM goku_plugin_node_status WHERE pluginName = ?;", pluginName)
+	if err != nil {
+		Tx.Rollback()
+		return err
+	}
+	return Tx.Commit()
+}
+
+//SetNodePluginStatus 保存节点上报的插件加载状态
+func (d *PluginDao) SetNodePluginStatus(nodeKey string, status []*entity.NodePluginStatus) error {
+	db := d.db
+	Tx, _ := db.Begin()
+	sql := "REPLACE INTO goku_plugin_node_status (nodeKey,pluginName,version,checksum,code,error,needRestart,updateTime) VALUES (?,?,?,?,?,?,?,?);"
+	for _, s := range status {
+		needRestart := 0
+		if s.NeedRestart {
+			needRestart = 1
+		}
+		_, err := Tx.Exec(sql, nodeKey, s.PluginName, s.Version, s.Checksum, s.Code, s.Error, needRestart, s.UpdateTime)
+		if err != nil {
+			Tx.Rollback()
+			return err
+		}
+	}
+	return Tx.Commit()
+}
+
+//GetNodePluginStatus 获取各节点上插件的加载状态
+func (d *PluginDao) GetNodePluginStatus(pluginName string) ([]*entity.NodePluginStatus, error) {
+	db := d.db
+	sql := "SELECT nodeKey,pluginName,version,checksum,code,IFNULL(error,''),needRestart,updateTime FROM goku_plugin_node_status WHERE pluginName = ? ORDER BY nodeKey;"
+	rows, err := db.Query(sql, pluginName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := make([]*entity.NodePluginStatus, 0)
+	for rows.Next() {
+		s := new(entity.NodePluginStatus)
+		err = rows.Scan(&s.NodeKey, &s.PluginName, &s.Version, &s.Checksum, &s.Code, &s.Error, &s.NeedRestart, &s.UpdateTime)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+	}
+	return list, nil
+}
@@ -13,6 +13,7 @@ import (
 	dao_service "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/dao-service"
 	dao_version_config "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/dao-version-config"
 	"github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/internal/goku311"
+	"github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/internal/goku314"
 	"github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/updater"
 )
 
@@ -24,13 +25,16 @@ func DoRegister() {
 
 	pdao.RegisterDBBuilder(DBDriver, new(TableBuilder))
 	goku311.RegisterUpdate()
+	goku314.RegisterUpdate()
 
+	pdao.RegisterDao(DBDriver, NewAlertDao())
 	pdao.RegisterDao(DBDriver, NewAPIDao(), NewAPIGroupDao(), NewAPIPluginDao(), NewAPIStrategyDao())
 	pdao.RegisterDao(DBDriver, NewAuthDao())
 	pdao.RegisterDao(DBDriver, NewClusterDao())
 	pdao.RegisterDao(DBDriver, NewGatewayDao())
 	pdao.RegisterDao(DBDriver, NewGuestDao())
-	pdao.RegisterDao(DBDriver, NewImportDao())
+	pdao.RegisterDao(DBDriver, NewImportDao(), NewExportDao())
+	pdao.RegisterDao(DBDriver, NewDeclarativeDao())
 	pdao.RegisterDao(DBDriver, NewMonitorModulesDao())
 	pdao.RegisterDao(DBDriver, NewNodeDao(), NewNodeGroupDao())
 	pdao.RegisterDao(DBDriver, NewPluginDao())
@@ -150,6 +150,23 @@ func (d *VersionDao) GetPublishVersionID() int {
 	return id
 }
 
+//GetVersionConfigByID 获取指定版本的网关配置
+func (d *VersionDao) GetVersionConfigByID(id int) (*config.GokuConfig, error) {
+	db := d.db
+	sql := "SELECT IFNULL(config,'{}') FROM goku_gateway_version_config WHERE versionID = ?"
+	var cf string
+	err := db.QueryRow(sql, id).Scan(&cf)
+	if err != nil {
+		return nil, err
+	}
+	var c config.GokuConfig
+	err = json.Unmarshal([]byte(cf), &c)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
 //GetVersionConfig 获取当前版本配置
 func (d *VersionDao) GetVersionConfig() (*config.GokuConfig, map[string]map[string]*config.BalanceConfig, map[string]map[string]*config.DiscoverConfig, error) {
 	db := d.db
@@ -38,4 +38,13 @@ type BalanceDao interface {
 	Search(keyword string) ([]*entity.Balance, error)
 
 	GetUseBalanceNames() (map[string]int, error)
+
+	//GetWSDL 获取负载的WSDL文档
+	GetWSDL(name string) (string, error)
+	//SaveWSDL 保存负载的WSDL文档，为空时清除
+	SaveWSDL(name, wsdl, now string) error
+	//GetPolicy 获取负载保存在column列的策略，如subset、transport
+	GetPolicy(name, column string) (string, error)
+	//SavePolicy 保存负载保存在column列的策略，为空时清除
+	SavePolicy(name, column, policy, now string) error
 }
@@ -36,6 +36,12 @@ type APIDao interface {
 	BatchEditAPIGroup(apiIDList []string, groupID int) (string, error)
 	//BatchDeleteAPI 批量修改接口
 	BatchDeleteAPI(apiIDList string) (bool, string, error)
+	//EditAPIRoutePriority 修改接口的路由优先级
+	EditAPIRoutePriority(apiID, priority int) error
+	//GetAPIPolicy 获取接口保存在column列的策略，如transform、traffic、mirror、retryPolicy、hedge
+	GetAPIPolicy(apiID int, column string) (string, error)
+	//EditAPIPolicy 修改接口保存在column列的策略
+	EditAPIPolicy(apiID int, column, policy string) error
 }
 
 //APIGroupDao apiGroupDao
@@ -166,6 +172,10 @@ type ServiceDao interface {
 	List(keyword string) ([]*entity.Service, error)
 	//Save 存储服务发现信息
 	Save(name, desc, config, clusterConfig string, healthCheck bool, healthCheckPath string, healthCheckCode string, healthCheckPeriod, healthCheckTimeOut int) error
+	//GetHealthCheckOptions 获取健康检查扩展配置
+	GetHealthCheckOptions(name string) (string, error)
+	//SaveHealthCheckOptions 保存健康检查扩展配置
+	SaveHealthCheckOptions(name, options, now string) error
 }
 
 //VersionConfigDao dao-version-config
@@ -194,6 +204,8 @@ type VersionConfigDao interface {
 	GetRouterRules(enable int) ([]*config.Router, error)
 
 	GetGatewayBasicConfig() (*config.Gateway, error)
+	//GetPluginScripts 获取JavaScript插件
+	GetPluginScripts() (map[string]*config.PluginScript, error)
 }
 
 //GatewayDao gateway.go
@@ -204,6 +216,10 @@ type GatewayDao interface {
 	EditGatewayBaseConfig(config entity.GatewayBasicConfig) (bool, string, error)
 	//GetGatewayInfo 获取网关信息
 	GetGatewayInfo() (nodeStartCount, nodeStopCount, projectCount, apiCount, strategyCount int, err error)
+	//GetRouter 获取节点使用的路由实现
+	GetRouter() (string, error)
+	//SetRouter 设置节点使用的路由实现
+	SetRouter(router string) error
 }
 
 //GuestDao guest.go
@@ -224,6 +240,48 @@ type ImportDao interface {
 	ImportProjectFromAms(userID int, projectInfo entity.AmsProject) (bool, string, error)
 	//ImportAPIFromAms 从ams中导入接口
 	ImportAPIFromAms(projectID, groupID, userID int, apiList []entity.AmsAPIInfo) (bool, string, error)
+	//ImportAPIList 导入接口列表，按分组名称归入项目的一级分组
+	ImportAPIList(projectID, userID int, apiList []*entity.ImportAPI) (bool, string, error)
+}
+
+//ExportDao export.go
+type ExportDao interface {
+	//GetExportAPIList 获取项目下待导出的接口及其策略路由
+	GetExportAPIList(projectID int) ([]*entity.ExportAPI, error)
+}
+
+//DeclarativeDao declarative.go
+type DeclarativeDao interface {
+	//ExportDeclarative 导出当前全部配置
+	ExportDeclarative() (*entity.Declarative, error)
+	//ApplyDeclarative 在同一事务中应用声明式配置，prune时删除文档中未声明的配置，check在事务中读取当前配置后调用，返回false时不写入
+	ApplyDeclarative(doc *entity.Declarative, prune bool, userID int, check func(current *entity.Declarative) (bool, error)) error
+}
+
+//AlertDao alert.go
+type AlertDao interface {
+	//AddAlertRule 新增告警规则
+	AddAlertRule(rule *entity.AlertRule) (int, error)
+	//EditAlertRule 修改告警规则
+	EditAlertRule(rule *entity.AlertRule) error
+	//DeleteAlertRule 删除告警规则
+	DeleteAlertRule(ruleID int) error
+	//GetAlertRule 获取告警规则
+	GetAlertRule(ruleID int) (*entity.AlertRule, error)
+	//GetAlertRules 获取告警规则列表
+	GetAlertRules() ([]*entity.AlertRule, error)
+	//SetAlertRuleSilence 设置告警规则的静默截止时间
+	SetAlertRuleSilence(ruleID int, silenceUntil string) error
+	//AddAlertHistory 新增告警记录
+	AddAlertHistory(history *entity.AlertHistory) error
+	//GetAlertHistory 分页获取告警记录
+	GetAlertHistory(ruleID int, page, pageSize int) ([]*entity.AlertHistory, int, error)
+	//GetAlertConfig 获取告警通知配置
+	GetAlertConfig() (*entity.AlertConfig, error)
+	//SetAlertConfig 设置告警通知配置
+	SetAlertConfig(config *entity.AlertConfig) error
+	//GetAPIAlertValves 获取设置了告警阈值的接口，返回接口ID及阈值
+	GetAPIAlertValves() (map[int]int, error)
 }
 
 //MonitorModulesDao monitorModule.go
@@ -264,6 +322,10 @@ type NodeDao interface {
 	GetHeartBeatTime(nodeKey string) (time.Time, error)
 
 	SetHeartBeatTime(nodeKey string, heartBeatTime time.Time) error
+	//SaveHeartbeat 保存节点最近一次心跳
+	SaveHeartbeat(nodeKey string, heartbeat *entity.NodeHeartbeat) error
+	//GetHeartbeat 获取节点最近一次心跳
+	GetHeartbeat(nodeKey string) (*entity.NodeHeartbeat, error)
 }
 
 //NodeGroupDao nodeGroup.go
@@ -314,6 +376,30 @@ type PluginDao interface {
 	BatchStartPlugin(pluginNameList string) (bool, string, error)
 	//EditPluginCheckStatus 更新插件检测状态
 	EditPluginCheckStatus(pluginName string, isCheck int) (bool, string, error)
+	//GetPluginScript 获取插件的JavaScript脚本
+	GetPluginScript(pluginName string) (*entity.PluginScript, error)
+	//SetPluginScript 设置插件的JavaScript脚本，脚本为空时删除
+	SetPluginScript(script *entity.PluginScript) error
+	//GetPluginPackages 获取插件包列表，不包含插件包内容
+	GetPluginPackages() ([]*entity.PluginPackage, error)
+	//GetPluginPackage 获取插件包
+	GetPluginPackage(pluginName string) (*entity.PluginPackage, error)
+	//SetPluginPackage 上传插件包，覆盖旧版本
+	SetPluginPackage(pkg *entity.PluginPackage) error
+	//DeletePluginPackage 删除插件包及节点加载状态
+	DeletePluginPackage(pluginName string) error
+	//SetNodePluginStatus 保存节点上报的插件加载状态
+	SetNodePluginStatus(nodeKey string, status []*entity.NodePluginStatus) error
+	//GetNodePluginStatus 获取各节点上插件的加载状态
+	GetNodePluginStatus(pluginName string) ([]*entity.NodePluginStatus, error)
+	//GetPluginSchema 获取插件配置的JSON Schema
+	GetPluginSchema(pluginName string) (string, error)
+	//SetPluginSchema 设置插件配置的JSON Schema
+	SetPluginSchema(pluginName, schema string) error
+	//GetPluginPolicy 获取插件的执行策略，strategyID为空时为全局插件，apiID为0时为策略插件
+	GetPluginPolicy(pluginName, strategyID string, apiID int) (string, error)
+	//SetPluginPolicy 设置插件的执行策略
+	SetPluginPolicy(pluginName, strategyID string, apiID int, policy string) error
 }
 
 //ProjectDao project.go
@@ -453,6 +539,8 @@ type VersionDao interface {
 	GetVersionConfigCount() int
 	//GetPublishVersionID 获取发布版本ID
 	GetPublishVersionID() int
+	//GetVersionConfigByID 获取指定版本的网关配置
+	GetVersionConfigByID(id int) (*config.GokuConfig, error)
 	//GetVersionConfig 获取当前版本配置
 	GetVersionConfig() (*config.GokuConfig, map[string]map[string]*config.BalanceConfig, map[string]map[string]*config.DiscoverConfig, error)
 }
@@ -46,6 +46,12 @@ func init() {
 			Title: "Consul",
 			Desc:  "Consul catalog",
 		},
+		{
+			Name:  "dns",
+			Type:  Discovery,
+			Title: "DNS",
+			Desc:  "DNS A/AAAA及SRV记录",
+		},
 	}
 
 	drivers = make(map[string]*Driver)
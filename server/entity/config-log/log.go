@@ -10,4 +10,6 @@ type LogConfig struct {
 	Period string
 	Expire int
 	Fields string
+	Format string
+	Sinks  string
 }
@@ -0,0 +1,46 @@
+package entity
+
+//AlertRule 告警规则
+type AlertRule struct {
+	RuleID       int     `json:"ruleID"`
+	RuleName     string  `json:"ruleName"`
+	TargetType   string  `json:"targetType"`
+	Target       string  `json:"target"`
+	Metric       string  `json:"metric"`
+	Threshold    float64 `json:"threshold"`
+	Window       int     `json:"window"`
+	Webhook      string  `json:"webhook"`
+	Receivers    string  `json:"receivers"`
+	Enable       int     `json:"enable"`
+	SilenceUntil string  `json:"silenceUntil"`
+	CreateTime   string  `json:"createTime"`
+	UpdateTime   string  `json:"updateTime"`
+}
+
+//AlertHistory 告警记录
+type AlertHistory struct {
+	AlertID      int     `json:"alertID"`
+	RuleID       int     `json:"ruleID"`
+	RuleName     string  `json:"ruleName"`
+	TargetType   string  `json:"targetType"`
+	Target       string  `json:"target"`
+	Metric       string  `json:"metric"`
+	Value        float64 `json:"value"`
+	Threshold    float64 `json:"threshold"`
+	Status       string  `json:"status"`
+	Silenced     int     `json:"silenced"`
+	NotifyResult string  `json:"notifyResult"`
+	CreateTime   string  `json:"createTime"`
+}
+
+//AlertConfig 告警通知的全局配置
+type AlertConfig struct {
+	AlertStatus  int    `json:"alertStatus"`
+	Webhook      string `json:"webhook"`
+	Receivers    string `json:"receivers"`
+	SMTPAddress  string `json:"smtpAddress"`
+	SMTPPort     int    `json:"smtpPort"`
+	SMTPProtocol int    `json:"smtpProtocol"`
+	Sender       string `json:"sender"`
+	Password     string `json:"senderPassword"`
+}
@@ -31,6 +31,7 @@ type API struct {
 	LinkAPIs         []config.APIStepUIConfig `json:"linkApis"`
 	StaticResponse   string                   `json:"staticResponse"`
 	ResponseDataType string                   `json:"responseDataType"`
+	RoutePriority    int                      `json:"routePriority"`
 	*ManagerInfo
 }
 
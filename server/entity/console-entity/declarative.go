@@ -0,0 +1,142 @@
+package entity
+
+//Declarative 声明式配置文档，可导出为YAML/JSON并整体应用到控制台
+type Declarative struct {
+	Version    int                    `json:"version" yaml:"version"`
+	Services   []*DeclarativeService  `json:"services,omitempty" yaml:"services,omitempty"`
+	Balances   []*DeclarativeBalance  `json:"balances,omitempty" yaml:"balances,omitempty"`
+	Plugins    []*DeclarativePlugin   `json:"plugins,omitempty" yaml:"plugins,omitempty"`
+	Projects   []*DeclarativeProject  `json:"projects,omitempty" yaml:"projects,omitempty"`
+	Strategies []*DeclarativeStrategy `json:"strategies,omitempty" yaml:"strategies,omitempty"`
+}
+
+//DeclarativeService 服务发现
+type DeclarativeService struct {
+	Name               string `json:"name" yaml:"name"`
+	Driver             string `json:"driver" yaml:"driver"`
+	Desc               string `json:"desc,omitempty" yaml:"desc,omitempty"`
+	IsDefault          bool   `json:"isDefault,omitempty" yaml:"isDefault,omitempty"`
+	Config             string `json:"config,omitempty" yaml:"config,omitempty"`
+	ClusterConfig      string `json:"clusterConfig,omitempty" yaml:"clusterConfig,omitempty"`
+	HealthCheck        bool   `json:"healthCheck,omitempty" yaml:"healthCheck,omitempty"`
+	HealthCheckPath    string `json:"healthCheckPath,omitempty" yaml:"healthCheckPath,omitempty"`
+	HealthCheckPeriod  int    `json:"healthCheckPeriod,omitempty" yaml:"healthCheckPeriod,omitempty"`
+	HealthCheckCode    string `json:"healthCheckCode,omitempty" yaml:"healthCheckCode,omitempty"`
+	HealthCheckTimeOut int    `json:"healthCheckTimeOut,omitempty" yaml:"healthCheckTimeOut,omitempty"`
+	//HealthCheckOptions 健康检查扩展配置，JSON格式
+	HealthCheckOptions string `json:"healthCheckOptions,omitempty" yaml:"healthCheckOptions,omitempty"`
+}
+
+//DeclarativeBalance 负载
+type DeclarativeBalance struct {
+	Name          string `json:"name" yaml:"name"`
+	ServiceName   string `json:"serviceName" yaml:"serviceName"`
+	AppName       string `json:"appName,omitempty" yaml:"appName,omitempty"`
+	Static        string `json:"static,omitempty" yaml:"static,omitempty"`
+	StaticCluster string `json:"staticCluster,omitempty" yaml:"staticCluster,omitempty"`
+	Desc          string `json:"desc,omitempty" yaml:"desc,omitempty"`
+	//Subset 实例子集，JSON格式
+	Subset string `json:"subset,omitempty" yaml:"subset,omitempty"`
+	//Transport 转发连接配置，JSON格式
+	Transport string `json:"transport,omitempty" yaml:"transport,omitempty"`
+}
+
+//DeclarativePlugin 全局插件，插件本身由插件包安装，这里只声明启用状态及配置
+type DeclarativePlugin struct {
+	Name   string `json:"name" yaml:"name"`
+	Enable bool   `json:"enable" yaml:"enable"`
+	IsStop bool   `json:"isStop,omitempty" yaml:"isStop,omitempty"`
+	Config string `json:"config,omitempty" yaml:"config,omitempty"`
+	Policy string `json:"policy,omitempty" yaml:"policy,omitempty"`
+}
+
+//DeclarativeProject 项目
+type DeclarativeProject struct {
+	Name string            `json:"name" yaml:"name"`
+	APIs []*DeclarativeAPI `json:"apis,omitempty" yaml:"apis,omitempty"`
+}
+
+//DeclarativeAPI 接口，以项目内的请求方式及请求路径标识
+type DeclarativeAPI struct {
+	Name string `json:"name" yaml:"name"`
+	//Group 分组路径，多级分组以"/"分隔
+	Group            string `json:"group,omitempty" yaml:"group,omitempty"`
+	RequestURL       string `json:"requestURL" yaml:"requestURL"`
+	RequestMethod    string `json:"requestMethod" yaml:"requestMethod"`
+	TargetURL        string `json:"targetURL,omitempty" yaml:"targetURL,omitempty"`
+	TargetMethod     string `json:"targetMethod,omitempty" yaml:"targetMethod,omitempty"`
+	IsFollow         bool   `json:"isFollow,omitempty" yaml:"isFollow,omitempty"`
+	Balance          string `json:"balance,omitempty" yaml:"balance,omitempty"`
+	Protocol         string `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+	Timeout          int    `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	RetryCount       int    `json:"retryCount,omitempty" yaml:"retryCount,omitempty"`
+	AlertValve       int    `json:"alertValve,omitempty" yaml:"alertValve,omitempty"`
+	RoutePriority    int    `json:"routePriority,omitempty" yaml:"routePriority,omitempty"`
+	APIType          int    `json:"apiType,omitempty" yaml:"apiType,omitempty"`
+	LinkAPIs         string `json:"linkApis,omitempty" yaml:"linkApis,omitempty"`
+	StaticResponse   string `json:"staticResponse,omitempty" yaml:"staticResponse,omitempty"`
+	ResponseDataType string `json:"responseDataType,omitempty" yaml:"responseDataType,omitempty"`
+	//Transform 请求及响应转换，JSON格式
+	Transform string `json:"transform,omitempty" yaml:"transform,omitempty"`
+	//Traffic 流量拆分，JSON格式
+	Traffic string `json:"traffic,omitempty" yaml:"traffic,omitempty"`
+	//Mirror 流量镜像，JSON格式
+	Mirror string `json:"mirror,omitempty" yaml:"mirror,omitempty"`
+	//RetryPolicy 重试策略，JSON格式
+	RetryPolicy string `json:"retryPolicy,omitempty" yaml:"retryPolicy,omitempty"`
+	//Hedge 对冲请求，JSON格式
+	Hedge string `json:"hedge,omitempty" yaml:"hedge,omitempty"`
+}
+
+//Key 接口在项目内的标识
+func (a *DeclarativeAPI) Key() string {
+	return a.RequestMethod + " " + a.RequestURL
+}
+
+//DeclarativeStrategy 策略
+type DeclarativeStrategy struct {
+	ID     string `json:"id" yaml:"id"`
+	Name   string `json:"name" yaml:"name"`
+	Group  string `json:"group,omitempty" yaml:"group,omitempty"`
+	Enable bool   `json:"enable" yaml:"enable"`
+	//Auth 鉴权方式，保存为策略绑定的鉴权插件
+	Auth    []*DeclarativeAuth        `json:"auth,omitempty" yaml:"auth,omitempty"`
+	Plugins []*DeclarativeBinding     `json:"plugins,omitempty" yaml:"plugins,omitempty"`
+	APIs    []*DeclarativeStrategyAPI `json:"apis,omitempty" yaml:"apis,omitempty"`
+}
+
+//DeclarativeAuth 策略的鉴权方式
+type DeclarativeAuth struct {
+	//Type Basic、Apikey、Jwt或Oauth2
+	Type   string `json:"type" yaml:"type"`
+	Enable bool   `json:"enable" yaml:"enable"`
+	Config string `json:"config,omitempty" yaml:"config,omitempty"`
+	Policy string `json:"policy,omitempty" yaml:"policy,omitempty"`
+}
+
+//DeclarativeBinding 策略或接口绑定的插件
+type DeclarativeBinding struct {
+	Name   string `json:"name" yaml:"name"`
+	Enable bool   `json:"enable" yaml:"enable"`
+	Config string `json:"config,omitempty" yaml:"config,omitempty"`
+	Policy string `json:"policy,omitempty" yaml:"policy,omitempty"`
+}
+
+//DeclarativeStrategyAPI 策略绑定的接口
+type DeclarativeStrategyAPI struct {
+	Project string `json:"project" yaml:"project"`
+	//API 接口标识，格式为"请求方式 请求路径"
+	API     string                `json:"api" yaml:"api"`
+	Target  string                `json:"target,omitempty" yaml:"target,omitempty"`
+	Plugins []*DeclarativeBinding `json:"plugins,omitempty" yaml:"plugins,omitempty"`
+}
+
+//DeclarativeChange 声明式配置与当前配置的差异
+type DeclarativeChange struct {
+	//Action create、update或delete
+	Action string `json:"action"`
+	//Kind service、balance、plugin、project、api、strategy、strategyAuth、strategyPlugin、strategyAPI或apiPlugin
+	Kind   string   `json:"kind"`
+	Key    string   `json:"key"`
+	Fields []string `json:"fields,omitempty"`
+}
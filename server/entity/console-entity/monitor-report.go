@@ -0,0 +1,20 @@
+package entity
+
+//MonitorReport 节点按接口、策略、负载上报的请求统计
+type MonitorReport struct {
+	Buckets    []float64      `json:"buckets"`
+	Items      []*MonitorItem `json:"items"`
+	ReportTime string         `json:"reportTime"`
+}
+
+//MonitorItem 单个统计对象在一个上报周期内的数据
+type MonitorItem struct {
+	API            int      `json:"api"`
+	Strategy       string   `json:"strategy"`
+	Balance        string   `json:"balance,omitempty"`
+	Requests       uint64   `json:"requests"`
+	Errors         uint64   `json:"errors"`
+	UpstreamErrors uint64   `json:"upstreamErrors"`
+	Values         []uint64 `json:"values"`
+	Max            float64  `json:"max"`
+}
@@ -2,22 +2,23 @@ package entity
 
 //Node 节点信息
 type Node struct {
-	NodeID        int    `json:"nodeID"`
-	NodeName      string `json:"nodeName"`
-	NodeKey       string `json:"nodeKey"`
-	ListenAddress string `json:"listenAddress"`
-	AdminAddress  string `json:"adminAddress"`
-	Cluster       string `json:"cluster,omitempty"`
-	ClusterTitle  string `json:"cluster_title,omitempty"`
-	Version       string `json:"version"`
-	NodeStatus    int    `json:"nodeStatus"`
-	GroupID       int    `json:"groupID,omitempty"`
-	GroupName     string `json:"groupName,omitempty"`
-	IsUpdate      bool   `json:"isUpdate"`
-	GatewayPath   string `json:"gatewayPath"`
-	CreateTime    string `json:"createTime"`
-	UpdateTime    string `json:"updateTime"`
-	UpdatePeriod  int    `json:"updatePeriod,omitempty"`
+	NodeID        int            `json:"nodeID"`
+	NodeName      string         `json:"nodeName"`
+	NodeKey       string         `json:"nodeKey"`
+	ListenAddress string         `json:"listenAddress"`
+	AdminAddress  string         `json:"adminAddress"`
+	Cluster       string         `json:"cluster,omitempty"`
+	ClusterTitle  string         `json:"cluster_title,omitempty"`
+	Version       string         `json:"version"`
+	NodeStatus    int            `json:"nodeStatus"`
+	GroupID       int            `json:"groupID,omitempty"`
+	GroupName     string         `json:"groupName,omitempty"`
+	IsUpdate      bool           `json:"isUpdate"`
+	GatewayPath   string         `json:"gatewayPath"`
+	CreateTime    string         `json:"createTime"`
+	UpdateTime    string         `json:"updateTime"`
+	UpdatePeriod  int            `json:"updatePeriod,omitempty"`
+	Heartbeat     *NodeHeartbeat `json:"heartbeat,omitempty"`
 	*SSHInfo
 }
 
@@ -30,3 +31,27 @@ type SSHInfo struct {
 	AuthMethod int    `json:"authMethod"`
 	IsSave     int    `json:"isSave"`
 }
+
+//NodeHeartbeat 节点心跳上报信息
+type NodeHeartbeat struct {
+	Uptime        int64   `json:"uptime"`
+	ConfigVersion string  `json:"configVersion"`
+	Requests      uint64  `json:"requests"`
+	Errors        uint64  `json:"errors"`
+	P50           float64 `json:"p50"`
+	P99           float64 `json:"p99"`
+	Goroutines    int     `json:"goroutines"`
+	MemAlloc      uint64  `json:"memAlloc"`
+	MemSys        uint64  `json:"memSys"`
+	ReportTime    string  `json:"reportTime"`
+	//PluginErrors 当前配置中加载或创建失败的插件
+	PluginErrors []*PluginError `json:"pluginErrors,omitempty"`
+}
+
+//PluginError 节点上加载或创建失败的插件
+type PluginError struct {
+	PluginName string `json:"pluginName"`
+	StrategyID string `json:"strategyID,omitempty"`
+	APIID      int    `json:"apiID,omitempty"`
+	Error      string `json:"error"`
+}
@@ -0,0 +1,39 @@
+package entity
+
+//ImportAPI 从OpenAPI/Swagger文档导入的接口
+type ImportAPI struct {
+	APIName       string `json:"apiName"`
+	GroupName     string `json:"groupName"`
+	RequestURL    string `json:"requestURL"`
+	RequestMethod string `json:"requestMethod"`
+	TargetURL     string `json:"targetURL"`
+	TargetMethod  string `json:"targetMethod"`
+	BalanceName   string `json:"balanceName"`
+	Protocol      string `json:"protocol"`
+	Timeout       int    `json:"timeout"`
+	RetryCount    int    `json:"retryCount"`
+}
+
+//ExportAPI 导出为OpenAPI文档的接口
+type ExportAPI struct {
+	APIID         int
+	APIName       string
+	GroupName     string
+	RequestURL    string
+	RequestMethod string
+	TargetURL     string
+	TargetMethod  string
+	BalanceName   string
+	Protocol      string
+	Timeout       int
+	RetryCount    int
+	RoutePriority int
+	Routes        []*APIRoute
+}
+
+//APIRoute 接口在策略中的路由
+type APIRoute struct {
+	StrategyID   string `json:"strategyID"`
+	StrategyName string `json:"strategyName"`
+	Target       string `json:"target,omitempty"`
+}
@@ -55,3 +55,37 @@ type ProxyCachingConf struct {
 	RedisPassword  string `json:"redisPassword"`
 	RedisDatabase  int    `json:"redisDatabase"`
 }
+
+//PluginScript JavaScript插件脚本
+type PluginScript struct {
+	PluginName  string `json:"pluginName"`
+	Script      string `json:"script"`
+	Timeout     int    `json:"timeout"`
+	MemoryLimit int    `json:"memoryLimit"`
+	UpdateTime  string `json:"updateTime"`
+}
+
+//PluginPackage 插件包
+type PluginPackage struct {
+	PluginName string `json:"pluginName"`
+	Version    string `json:"version"`
+	Checksum   string `json:"checksum"`
+	Size       int    `json:"size"`
+	Content    []byte `json:"content,omitempty"`
+	UpdateTime string `json:"updateTime"`
+}
+
+//NodePluginStatus 节点上插件包的加载状态
+type NodePluginStatus struct {
+	NodeKey    string `json:"nodeKey,omitempty"`
+	PluginName string `json:"pluginName"`
+	Version    string `json:"version"`
+	//Checksum 节点本地插件包的sha256
+	Checksum string `json:"checksum"`
+	//Code 加载结果，与plugin_loader的错误码一致
+	Code  int    `json:"code"`
+	Error string `json:"error"`
+	//NeedRestart 已加载旧版本，新版本需重启节点后生效
+	NeedRestart bool   `json:"needRestart"`
+	UpdateTime  string `json:"updateTime"`
+}